@@ -0,0 +1,108 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/mochajutsu/mkcd/internal/utils"
+)
+
+// ownerHandover gives the paths created by one mkcd operation to another
+// user (--as-user), for admins provisioning workspaces on shared servers
+type ownerHandover struct {
+	owner      *utils.Owner
+	targetPath string
+
+	// createdRoot is the topmost directory the operation creates, or empty
+	// when the target already existed
+	createdRoot string
+
+	// hadGitDir records whether the target already was a Git repository
+	hadGitDir bool
+}
+
+// newOwnerHandover resolves the receiving user and records what exists
+// before the pipeline runs, so that only new paths change owner
+func newOwnerHandover(targetPath, name string) (*ownerHandover, error) {
+	owner, err := utils.LookupOwner(name)
+	if err != nil {
+		return nil, err
+	}
+	if !dryRun && !utils.CanChangeOwner() {
+		return nil, fmt.Errorf("--as-user %s requires root privileges (run mkcd with sudo)", name)
+	}
+
+	ancestor, err := utils.ExistingAncestor(targetPath)
+	if err != nil {
+		return nil, err
+	}
+
+	handover := &ownerHandover{
+		owner:      owner,
+		targetPath: targetPath,
+		hadGitDir:  utils.PathExists(filepath.Join(targetPath, ".git")),
+	}
+	if ancestor != targetPath {
+		rel, err := filepath.Rel(ancestor, targetPath)
+		if err != nil {
+			return nil, err
+		}
+		handover.createdRoot = filepath.Join(ancestor, strings.Split(filepath.ToSlash(rel), "/")[0])
+	}
+	return handover, nil
+}
+
+// apply changes the owner of everything the operation created
+func (h *ownerHandover) apply(fsOps *utils.FileSystemOperations, outputMgr *utils.OutputManager) error {
+	if dryRun {
+		root := h.createdRoot
+		if root == "" {
+			root = h.targetPath
+		}
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would give %s to %s", root, h.owner))
+		return nil
+	}
+
+	// Everything below a newly created directory is new
+	if h.createdRoot != "" {
+		if err := h.owner.ChownTree(h.createdRoot); err != nil {
+			return err
+		}
+		outputMgr.Success(fmt.Sprintf("Gave %s to %s", h.createdRoot, h.owner))
+		return nil
+	}
+
+	// In an existing directory only the written files and their parent
+	// directories inside the target change owner
+	paths := map[string]bool{}
+	for _, record := range fsOps.Records() {
+		for path := record.Path; path != h.targetPath && strings.HasPrefix(path, h.targetPath+string(filepath.Separator)); path = filepath.Dir(path) {
+			paths[path] = true
+		}
+	}
+	for _, path := range utils.SortedKeys(paths) {
+		if !utils.PathExists(path) {
+			continue
+		}
+		if err := h.owner.Chown(path); err != nil {
+			return err
+		}
+	}
+
+	gitDir := filepath.Join(h.targetPath, ".git")
+	if !h.hadGitDir && utils.PathExists(gitDir) {
+		if err := h.owner.ChownTree(gitDir); err != nil {
+			return err
+		}
+	}
+
+	outputMgr.Success(fmt.Sprintf("Gave new files in %s to %s", h.targetPath, h.owner))
+	return nil
+}
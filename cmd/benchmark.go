@@ -0,0 +1,159 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// Benchmark command flags
+var (
+	benchIterations int
+	benchTemplate   string
+	benchGit        bool
+	benchReadme     bool
+	benchJSON       bool
+)
+
+// benchmarkCmd represents the hidden benchmark command
+var benchmarkCmd = &cobra.Command{
+	Use:    "benchmark",
+	Short:  "Measure project creation latency",
+	Hidden: true,
+	Long: `Create throwaway projects in a temporary directory and report how long
+validation and each pipeline step took across all runs.
+
+History, the project file, the registry and the editor are disabled so only
+the creation itself is measured. The JSON output is meant to be stored per
+release to track performance regressions.
+
+Examples:
+  mkcd benchmark                                       # 10 runs of a bare directory
+  mkcd benchmark --iterations 50 -t go --git --readme  # A typical Go project
+  mkcd benchmark --json > bench-1.0.0.json             # Machine-readable results`,
+	Args: cobra.NoArgs,
+	RunE: runBenchmark,
+}
+
+func init() {
+	rootCmd.AddCommand(benchmarkCmd)
+
+	benchmarkCmd.Flags().IntVar(&benchIterations, "iterations", 10, "number of projects to create")
+	benchmarkCmd.Flags().StringVarP(&benchTemplate, "template", "t", "", "template to apply to each project")
+	benchmarkCmd.Flags().BoolVarP(&benchGit, "git", "g", false, "initialize a git repository in each project")
+	benchmarkCmd.Flags().BoolVarP(&benchReadme, "readme", "r", false, "create a README.md in each project")
+	benchmarkCmd.Flags().BoolVar(&benchJSON, "json", false, "print the results as JSON")
+
+	registerFlagCompletion(benchmarkCmd, "template", completeTemplates)
+}
+
+// benchmarkReport is the JSON form of the benchmark results
+type benchmarkReport struct {
+	Version    string              `json:"version"`
+	Iterations int                 `json:"iterations"`
+	Template   string              `json:"template,omitempty"`
+	Git        bool                `json:"git"`
+	Readme     bool                `json:"readme"`
+	Steps      []utils.TimingStats `json:"steps"`
+}
+
+// runBenchmark creates the throwaway projects and reports their timings
+func runBenchmark(cmd *cobra.Command, args []string) error {
+	if benchIterations < 1 {
+		return fmt.Errorf("--iterations must be at least 1")
+	}
+
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	base, err := os.MkdirTemp("", "mkcd-benchmark-")
+	if err != nil {
+		return fmt.Errorf("failed to create benchmark directory: %w", err)
+	}
+	defer os.RemoveAll(base)
+
+	// Only the creation itself is measured
+	cfg.Core.TempDir = base
+	cfg.Core.HistoryLimit = 0
+	cfg.Core.ProjectFile = false
+	cfg.Integrations.Tasks.Backend = ""
+
+	// The runs are silent; the report is printed once they are done
+	runOutput := utils.NewOutputManager(false, false, false, true, false, false)
+	pathValidator := utils.NewPathValidator(cfg.Safety.ForbiddenPaths, cfg.Safety.MaxDepth)
+
+	runs := []*utils.Timings{}
+	for i := 0; i < benchIterations; i++ {
+		timings := utils.NewTimings()
+		mkcdConfig := MkcdConfig{
+			Git:      benchGit,
+			Template: benchTemplate,
+			Readme:   benchReadme,
+			Temp:     true,
+			Timings:  timings,
+		}
+
+		fsOps := newMkcdFileSystemOperations(cfg, runOutput)
+		if err := executeMkcd(fmt.Sprintf("bench-%d", i), cfg, mkcdConfig, runOutput, fsOps, pathValidator); err != nil {
+			pterm.EnableOutput()
+			return fmt.Errorf("run %d failed: %w", i+1, err)
+		}
+		runs = append(runs, timings)
+	}
+	pterm.EnableOutput()
+
+	stats := utils.SummarizeTimings(runs)
+
+	if benchJSON {
+		data, err := json.MarshalIndent(benchmarkReport{
+			Version:    rootCmd.Version,
+			Iterations: benchIterations,
+			Template:   benchTemplate,
+			Git:        benchGit,
+			Readme:     benchReadme,
+			Steps:      stats,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode results: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	outputMgr.Header(fmt.Sprintf("Benchmark: %d runs", benchIterations))
+	rows := [][]string{}
+	for _, stat := range stats {
+		rows = append(rows, []string{
+			stat.Name,
+			utils.FormatTiming(stat.Min),
+			utils.FormatTiming(stat.Mean),
+			utils.FormatTiming(stat.P50),
+			utils.FormatTiming(stat.P95),
+			utils.FormatTiming(stat.Max),
+		})
+	}
+	outputMgr.Table([]string{"Step", "Min", "Mean", "P50", "P95", "Max"}, rows)
+	return nil
+}
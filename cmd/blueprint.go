@@ -0,0 +1,447 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/git"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// blueprintNoRollback keeps the results of the completed steps when a step fails
+var blueprintNoRollback bool
+
+// blueprintCmd represents the blueprint command
+var blueprintCmd = &cobra.Command{
+	Use:   "blueprint",
+	Short: "Run multi-step workflows",
+	Long: `Run blueprints: named, ordered lists of mkcd operations defined in the
+configuration file, to stand up environments of several directories and
+repositories in one command.
+
+Each step sets exactly one operation:
+• create  create a directory like 'mkcd <dir>', with profile, template and git
+• clone   clone a repository into dir (shallow = true for a depth of one)
+• apply   apply templates to the existing dir
+• run     run a shell command in dir, like a hook
+
+Paths are relative to the current directory; create and apply steps take
+template variables from a vars table.
+
+  [blueprints.shop]
+  description = "Shop services"
+
+  [[blueprints.shop.steps]]
+  create = "shop/api"
+  profile = "go"
+  git = true
+
+  [[blueprints.shop.steps]]
+  clone = "https://github.com/example/shop-web.git"
+  dir = "shop/web"
+
+  [[blueprints.shop.steps]]
+  apply = "docker"
+  dir = "shop/api"
+
+  [[blueprints.shop.steps]]
+  run = "make setup"
+  dir = "shop/api"
+
+Examples:
+  mkcd blueprint list                  # List the configured blueprints
+  mkcd blueprint run shop --dry-run    # Show what every step would do
+  mkcd blueprint run shop              # Run the steps in order`,
+}
+
+// blueprintListCmd represents the blueprint list command
+var blueprintListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured blueprints",
+	Args:  cobra.NoArgs,
+	RunE:  runBlueprintList,
+}
+
+// blueprintRunCmd represents the blueprint run command
+var blueprintRunCmd = &cobra.Command{
+	Use:   "run <name>",
+	Short: "Run the steps of a blueprint",
+	Long: `Run the steps of a blueprint in order. With --dry-run, every step only
+reports what it would do.
+
+When a step fails, the steps before it are rolled back in reverse order:
+directories they created are removed, and files templates added to existing
+directories are deleted. Files that were overwritten and the effects of run
+steps cannot be undone and are reported instead. --no-rollback keeps
+everything for inspection.
+
+With [integrations.notify] configured, a summary of the steps is posted to
+a webhook or emailed when the run finishes.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeBlueprints,
+	RunE:              runBlueprintRun,
+}
+
+func init() {
+	rootCmd.AddCommand(blueprintCmd)
+
+	blueprintCmd.AddCommand(blueprintListCmd)
+	blueprintCmd.AddCommand(blueprintRunCmd)
+
+	blueprintRunCmd.Flags().BoolVar(&blueprintNoRollback, "no-rollback", false, "keep the results of completed steps when a step fails")
+}
+
+// runBlueprintList lists the blueprints of the configuration
+func runBlueprintList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	if len(cfg.Blueprints) == 0 {
+		outputMgr.Info("No blueprints configured; define them as [blueprints.<name>] in the configuration file")
+		return nil
+	}
+
+	outputMgr.Header("Blueprints")
+	rows := [][]string{}
+	for _, name := range utils.SortedKeys(cfg.Blueprints) {
+		blueprint := cfg.Blueprints[name]
+		rows = append(rows, []string{name, fmt.Sprint(len(blueprint.Steps)), valueOrDash(blueprint.Description)})
+	}
+	outputMgr.Table([]string{"Name", "Steps", "Description"}, rows)
+	return nil
+}
+
+// runBlueprintRun runs the steps of a blueprint, rolling back the completed
+// steps when one fails
+func runBlueprintRun(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	name := args[0]
+	blueprint, exists := cfg.Blueprints[name]
+	if !exists {
+		return fmt.Errorf("blueprint '%s' not found", name)
+	}
+	cmd.SilenceUsage = true
+
+	started := utils.Now()
+	results := utils.NewBatchSummary()
+	err = runBlueprintSteps(name, blueprint, cfg, outputMgr, results)
+	notifyRun(cfg, notifyBlueprint, "blueprint "+name, started, results, err, outputMgr)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would run %d step(s) of blueprint '%s'", len(blueprint.Steps), name))
+		return nil
+	}
+	outputMgr.Success(fmt.Sprintf("Blueprint '%s' finished: %d step(s)", name, len(blueprint.Steps)))
+	return nil
+}
+
+// runBlueprintSteps runs the steps of a blueprint in order, recording their
+// outcome in results, and rolls back the completed steps when one fails
+func runBlueprintSteps(name string, blueprint config.BlueprintConfig, cfg *config.Config, outputMgr *utils.OutputManager, results *utils.BatchSummary) error {
+	pathValidator := utils.NewPathValidator(cfg.Safety.ForbiddenPaths, cfg.Safety.MaxDepth)
+	completed := []blueprintUndo{}
+	for i, step := range blueprint.Steps {
+		description := describeBlueprintStep(step)
+		outputMgr.Section(fmt.Sprintf("Step %d/%d: %s", i+1, len(blueprint.Steps), description))
+
+		undo, err := runBlueprintStep(step, cfg, outputMgr, pathValidator)
+		// A failed step is rolled back as well, it may have got halfway
+		if undo != nil {
+			completed = append(completed, *undo)
+		}
+		if err == nil {
+			results.Succeeded(description)
+			continue
+		}
+
+		results.Failed(description, err)
+		for _, skipped := range blueprint.Steps[i+1:] {
+			results.Skipped(describeBlueprintStep(skipped), "an earlier step failed")
+		}
+		err = fmt.Errorf("blueprint '%s' failed at step %d (%s): %w", name, i+1, description, err)
+		if dryRun {
+			return err
+		}
+		outputMgr.Error(err.Error())
+		if blueprintNoRollback {
+			outputMgr.Warning("Keeping the results of the completed steps (--no-rollback)")
+		} else {
+			rollbackBlueprint(completed, outputMgr)
+		}
+		return err
+	}
+	return nil
+}
+
+// describeBlueprintStep returns a short description of a step for output
+func describeBlueprintStep(step config.BlueprintStep) string {
+	switch step.Kind() {
+	case config.BlueprintCreate:
+		return "create " + step.Create
+	case config.BlueprintClone:
+		return fmt.Sprintf("clone %s into %s", step.Clone, step.Dir)
+	case config.BlueprintApply:
+		return fmt.Sprintf("apply %s to %s", step.Apply, step.Dir)
+	default:
+		return fmt.Sprintf("run '%s' in %s", step.Run, step.Path())
+	}
+}
+
+// blueprintUndo records how to roll back a blueprint step
+type blueprintUndo struct {
+	description string
+
+	// remove is the topmost directory the step created, removed with
+	// everything in it
+	remove string
+
+	// added are the files the step added to a directory that existed, and
+	// overwritten the existing files it changed, which cannot be restored
+	added       []string
+	overwritten []string
+
+	// irreversible is set for run steps, whose effects are unknown
+	irreversible bool
+}
+
+// runBlueprintStep runs a single step and returns how to roll it back,
+// nil in dry runs
+func runBlueprintStep(step config.BlueprintStep, cfg *config.Config, outputMgr *utils.OutputManager, pathValidator *utils.PathValidator) (*blueprintUndo, error) {
+	targetPath, err := determineTargetPath(step.Path(), MkcdConfig{}, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine target path: %w", err)
+	}
+
+	// The target is locked before it is inspected, so that a rollback only
+	// removes what this step created and never what another invocation
+	// working on the same path made meanwhile
+	if !dryRun && step.Kind() != config.BlueprintRun {
+		lock, err := lockTarget(targetPath, cfg, outputMgr)
+		if err != nil {
+			return nil, err
+		}
+		defer lock.Unlock()
+	}
+
+	undo := &blueprintUndo{description: describeBlueprintStep(step), remove: topmostMissing(targetPath)}
+	if dryRun {
+		undo = nil
+	}
+
+	switch step.Kind() {
+	case config.BlueprintCreate:
+		mkcdConfig, err := blueprintMkcdConfig(step, cfg)
+		if err != nil {
+			return nil, err
+		}
+		mkcdConfig.TargetLocked = true
+		existing := existingFiles(targetPath)
+		fsOps := newMkcdFileSystemOperations(cfg, outputMgr)
+		err = executeMkcd(step.Create, cfg, mkcdConfig, outputMgr, fsOps, pathValidator)
+		undo.track(fsOps, existing)
+		return undo, err
+
+	case config.BlueprintClone:
+		if err := checkNotFrozen(targetPath); err != nil {
+			return nil, err
+		}
+		if err := pathValidator.ValidatePath(targetPath); err != nil {
+			if !force {
+				return nil, fmt.Errorf("path validation failed: %w", err)
+			}
+			outputMgr.Warning(fmt.Sprintf("Path validation failed but continuing due to --force: %v", err))
+		}
+		if err := git.ValidateRemoteURL(step.Clone); err != nil {
+			return nil, err
+		}
+		if utils.PathExists(targetPath) && !utils.IsEmptyDir(targetPath) {
+			return nil, fmt.Errorf("%s already exists and is not empty", targetPath)
+		}
+		if dryRun {
+			outputMgr.Info(fmt.Sprintf("[DRY RUN] Would clone %s into %s", step.Clone, targetPath))
+			return nil, nil
+		}
+		gitMgr := git.NewGitManager(false, verbose, cfg.Git.UserName, cfg.Git.UserEmail)
+		return undo, gitMgr.CloneRepository(step.Clone, targetPath, step.Shallow)
+
+	case config.BlueprintApply:
+		if !utils.IsDirectory(targetPath) && !dryRun {
+			return nil, fmt.Errorf("%s does not exist", targetPath)
+		}
+		if err := checkNotFrozen(targetPath); err != nil {
+			return nil, err
+		}
+		mkcdConfig := MkcdConfig{Template: step.Apply, TemplateVars: step.Vars}
+		existing := existingFiles(targetPath)
+		fsOps := newMkcdFileSystemOperations(cfg, outputMgr)
+		err := applyTemplate(targetPath, mkcdConfig, cfg, fsOps, outputMgr)
+		undo.track(fsOps, existing)
+		return undo, err
+
+	default:
+		if dryRun {
+			outputMgr.Info(fmt.Sprintf("[DRY RUN] Would run '%s' in %s", step.Run, targetPath))
+			return nil, nil
+		}
+		undo.irreversible = true
+		return undo, runHook(newHookRunner(cfg), targetPath, step.Run, outputMgr)
+	}
+}
+
+// blueprintMkcdConfig builds the settings of a create step from its profile,
+// falling back to the default profile like 'mkcd <dir>'
+func blueprintMkcdConfig(step config.BlueprintStep, cfg *config.Config) (MkcdConfig, error) {
+	profileName := step.Profile
+	if profileName == "" {
+		profileName = cfg.Core.DefaultProfile
+	}
+	profileConfig, err := cfg.GetProfile(profileName)
+	if err != nil {
+		if step.Profile != "" {
+			return MkcdConfig{}, fmt.Errorf("failed to get profile: %w", err)
+		}
+		profileConfig = config.ProfileConfig{}
+	}
+
+	mkcdConfig := mergeConfigWithFlags(profileConfig)
+	mkcdConfig.Profile = profileName
+	mkcdConfig.Git = mkcdConfig.Git || step.Git
+	if step.Template != "" {
+		mkcdConfig.Template = step.Template
+	}
+	mkcdConfig.TemplateVars = step.Vars
+	return mkcdConfig, nil
+}
+
+// track records which files fsOps added to the existing files of a directory
+func (u *blueprintUndo) track(fsOps *utils.FileSystemOperations, existing map[string]bool) {
+	if u == nil || u.remove != "" {
+		return
+	}
+	for _, record := range fsOps.Records() {
+		if existing[record.Path] {
+			u.overwritten = append(u.overwritten, record.Path)
+		} else {
+			u.added = append(u.added, record.Path)
+		}
+	}
+}
+
+// rollbackBlueprint undoes the completed steps of a blueprint in reverse order
+func rollbackBlueprint(completed []blueprintUndo, outputMgr *utils.OutputManager) {
+	if len(completed) == 0 {
+		return
+	}
+
+	outputMgr.Info("Rolling back the completed steps")
+	for i := len(completed) - 1; i >= 0; i-- {
+		undo := completed[i]
+		switch {
+		case undo.irreversible:
+			outputMgr.Warning(fmt.Sprintf("Cannot undo step (%s)", undo.description))
+		case undo.remove != "":
+			if !utils.PathExists(undo.remove) {
+				continue
+			}
+			if err := os.RemoveAll(undo.remove); err != nil {
+				outputMgr.Warning(fmt.Sprintf("Failed to remove %s: %v", undo.remove, err))
+				continue
+			}
+			outputMgr.Info(fmt.Sprintf("Removed %s", undo.remove))
+		default:
+			for j := len(undo.added) - 1; j >= 0; j-- {
+				if err := os.Remove(undo.added[j]); err != nil && !os.IsNotExist(err) {
+					outputMgr.Warning(fmt.Sprintf("Failed to remove %s: %v", undo.added[j], err))
+				}
+			}
+			if len(undo.added) > 0 {
+				outputMgr.Info(fmt.Sprintf("Removed %d file(s) added by step (%s)", len(undo.added), undo.description))
+			}
+			if len(undo.overwritten) > 0 {
+				outputMgr.Warning(fmt.Sprintf("Cannot restore %d file(s) overwritten by step (%s): %s",
+					len(undo.overwritten), undo.description, strings.Join(undo.overwritten, ", ")))
+			}
+		}
+	}
+}
+
+// topmostMissing returns the outermost directory of path that does not
+// exist yet, which creating path creates, or "" when path exists
+func topmostMissing(path string) string {
+	missing := ""
+	for dir := path; !utils.PathExists(dir); dir = filepath.Dir(dir) {
+		missing = dir
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+	return missing
+}
+
+// existingFiles returns the files below dir, skipping .git directories
+func existingFiles(dir string) map[string]bool {
+	existing := map[string]bool{}
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() {
+			existing[path] = true
+		}
+		return nil
+	})
+	return existing
+}
+
+// completeBlueprints completes the blueprint names of the configuration
+func completeBlueprints(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cfg := loadCompletionConfig()
+	if cfg == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return utils.SortedKeys(cfg.Blueprints), cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,336 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/shell"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/mochajutsu/mkcd/internal/workspaces"
+	"github.com/spf13/cobra"
+)
+
+// bookmarkCmd represents the bookmark command
+var bookmarkCmd = &cobra.Command{
+	Use:   "bookmark",
+	Short: "Name important workspaces",
+	Long: `Give workspaces short names to change into them with 'mkcd go <name>'.
+
+Bookmarks are kept in the mkcd state directory. Their names also work for
+'mkcd open', 'mkcd pin' and the other commands taking a workspace, where
+they come right after paths, and bookmarked workspaces are listed by
+'mkcd find' and completed by the shell wrapper.
+
+Bookmarks, like pins, follow workspaces renamed with 'mkcd rename-project'
+or moved in the registry by 'mkcd registry add' or 'mkcd registry sync',
+and 'mkcd registry gc' removes them together with the projects they point
+to.
+
+Examples:
+  mkcd bookmark add api                    # Bookmark the current directory as 'api'
+  mkcd bookmark add blog ~/src/blog        # Bookmark a directory by path
+  mkcd bookmark list                       # List bookmarks
+  mkcd go api                              # Change into the 'api' bookmark
+  mkcd bookmark rm api                     # Remove a bookmark`,
+}
+
+// bookmarkAddCmd represents the bookmark add command
+var bookmarkAddCmd = &cobra.Command{
+	Use:   "add <name> [dir|name]",
+	Short: "Bookmark a workspace",
+	Long: `Bookmark a workspace under a name. The workspace is resolved like by
+'mkcd open' and defaults to the current directory. An existing bookmark is
+only replaced with --force.`,
+	Args: cobra.RangeArgs(1, 2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 1 {
+			return completeWorkspaces(cmd, nil, toComplete)
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: runBookmarkAdd,
+}
+
+// bookmarkListCmd represents the bookmark list command
+var bookmarkListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List bookmarks",
+	Args:    cobra.NoArgs,
+	RunE:    runBookmarkList,
+}
+
+// bookmarkRmCmd represents the bookmark rm command
+var bookmarkRmCmd = &cobra.Command{
+	Use:               "rm <name>...",
+	Aliases:           []string{"remove"},
+	Short:             "Remove bookmarks",
+	Long:              `Remove bookmarks. The bookmarked directories are not touched.`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeBookmarks,
+	RunE:              runBookmarkRm,
+}
+
+// goCmd represents the go command
+var goCmd = &cobra.Command{
+	Use:   "go <name>",
+	Short: "Change into a bookmarked workspace",
+	Long: `Change into the workspace bookmarked under the name.
+
+The path is emitted like a created one, so with the shell integration the
+shell changes into it:
+  cd "$(mkcd go api --print-path)"
+
+Examples:
+  mkcd go api                              # Change into the 'api' bookmark`,
+	Args: cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeBookmarks(cmd, args, toComplete)
+	},
+	Annotations: map[string]string{changesDirAnnotation: "true"},
+	RunE:        runGo,
+}
+
+func init() {
+	rootCmd.AddCommand(bookmarkCmd)
+	rootCmd.AddCommand(goCmd)
+
+	// Add subcommands
+	bookmarkCmd.AddCommand(bookmarkAddCmd)
+	bookmarkCmd.AddCommand(bookmarkListCmd)
+	bookmarkCmd.AddCommand(bookmarkRmCmd)
+
+	goCmd.Flags().BoolVar(&printPath, "print-path", false, "print only the workspace path on stdout, all other output on stderr")
+}
+
+// runBookmarkAdd bookmarks a workspace under a name
+func runBookmarkAdd(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	name := args[0]
+	if err := workspaces.ValidateBookmarkName(name); err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	query := ""
+	if len(args) > 1 {
+		query = args[1]
+	}
+	dir, err := resolveWorkspace(query, cfg, outputMgr)
+	if err != nil {
+		return err
+	}
+
+	bookmarks, path, err := loadBookmarks()
+	if err != nil {
+		return err
+	}
+	if existing, exists := bookmarks.Get(name); exists {
+		if existing == dir {
+			outputMgr.Info(fmt.Sprintf("Already bookmarked: %s → %s", name, dir))
+			return nil
+		}
+		if !force {
+			return fmt.Errorf("bookmark '%s' already points to %s (use --force to replace it)", name, existing)
+		}
+	}
+
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would bookmark %s as '%s'", dir, name))
+		return nil
+	}
+	bookmarks.Set(name, dir)
+	if err := bookmarks.Save(path); err != nil {
+		return err
+	}
+	outputMgr.Success(fmt.Sprintf("Bookmarked %s as '%s'", dir, name))
+	return nil
+}
+
+// runBookmarkList lists the bookmarks
+func runBookmarkList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	bookmarks, _, err := loadBookmarks()
+	if err != nil {
+		return err
+	}
+	if len(bookmarks.Paths) == 0 {
+		outputMgr.Info("No bookmarks yet (see 'mkcd bookmark add')")
+		return nil
+	}
+
+	rows := [][]string{}
+	for _, name := range bookmarks.Names() {
+		path := bookmarks.Paths[name]
+		status := "ok"
+		if !utils.IsDirectory(path) {
+			status = "missing"
+		}
+		rows = append(rows, []string{name, path, status})
+	}
+	outputMgr.Table([]string{"Name", "Path", "Status"}, rows)
+	return nil
+}
+
+// runBookmarkRm removes bookmarks
+func runBookmarkRm(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	cmd.SilenceUsage = true
+	bookmarks, path, err := loadBookmarks()
+	if err != nil {
+		return err
+	}
+	for _, name := range args {
+		if !bookmarks.Remove(name) {
+			return fmt.Errorf("no bookmark named '%s'", name)
+		}
+	}
+
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would remove bookmark(s) %s", strings.Join(args, ", ")))
+		return nil
+	}
+	if err := bookmarks.Save(path); err != nil {
+		return err
+	}
+	outputMgr.Success(fmt.Sprintf("Removed bookmark(s) %s", strings.Join(args, ", ")))
+	return nil
+}
+
+// runGo changes into a bookmarked workspace
+func runGo(cmd *cobra.Command, args []string) error {
+	// Keep stdout free for the path
+	if printPath {
+		redirectOutput(os.Stderr)
+	}
+
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	cmd.SilenceUsage = true
+	bookmarks, _, err := loadBookmarks()
+	if err != nil {
+		return err
+	}
+	dir, exists := bookmarks.Get(args[0])
+	if !exists {
+		return fmt.Errorf("no bookmark named '%s' (see 'mkcd bookmark list')", args[0])
+	}
+	if !utils.IsDirectory(dir) {
+		return fmt.Errorf("bookmark '%s' points to %s, which no longer exists", args[0], dir)
+	}
+
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would change into %s", dir))
+		return nil
+	}
+	recordVisit(dir, cfg, outputMgr)
+	if err := emitPath(dir); err != nil {
+		return err
+	}
+	if cfg.Output.TerminalCwd {
+		outputMgr.ReportWorkingDirectory(dir)
+	}
+
+	if !printPath && !quiet {
+		if file, _ := pathFD(); file == nil {
+			shellName := shell.Detect()
+			if shellName == "" {
+				shellName = shell.Bash
+			}
+			outputMgr.Info("To change to the directory, run: " + shell.ChangeDirCommand(shellName, dir))
+		}
+	}
+	return nil
+}
+
+// loadBookmarks loads the bookmarks and returns them with their path
+func loadBookmarks() (*workspaces.Bookmarks, string, error) {
+	path, err := workspaces.DefaultBookmarksPath()
+	if err != nil {
+		return nil, "", err
+	}
+
+	bookmarks, err := workspaces.LoadBookmarks(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return bookmarks, path, nil
+}
+
+// completeBookmarks completes the bookmark names not given yet
+func completeBookmarks(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	bookmarks, _, err := loadBookmarks()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := []string{}
+	for _, name := range bookmarks.Names() {
+		if strings.HasPrefix(name, toComplete) && !slices.Contains(args, name) {
+			names = append(names, name)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,86 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+// TestBookmarksFollowWorkspaces checks that bookmarks and pins follow a
+// renamed project and go away with it in registry gc
+func TestBookmarksFollowWorkspaces(t *testing.T) {
+	ws, conf := newTestEnv(t)
+
+	execute(t, "mkcd", "api", "--config", conf)
+	execute(t, "bookmark", "add", "api", ws.Path("api"), "--config", conf)
+	execute(t, "pin", ws.Path("api"), "--config", conf)
+
+	execute(t, "rename-project", ws.Path("api"), "billing-api", "--config", conf)
+	assertRefs(t, map[string]string{"api": ws.Path("billing-api")}, []string{ws.Path("billing-api")})
+
+	if err := os.RemoveAll(ws.Path("billing-api")); err != nil {
+		t.Fatalf("failed to remove workspace: %v", err)
+	}
+	execute(t, "registry", "gc", "--auto", "--config", conf)
+	assertRefs(t, map[string]string{}, []string{})
+}
+
+// assertRefs checks the saved bookmarks and pins
+func assertRefs(t *testing.T, bookmarks map[string]string, pins []string) {
+	t.Helper()
+
+	savedBookmarks, _, err := loadBookmarks()
+	if err != nil {
+		t.Fatalf("failed to load bookmarks: %v", err)
+	}
+	if len(savedBookmarks.Paths) != len(bookmarks) {
+		t.Errorf("expected bookmarks %v, got %v", bookmarks, savedBookmarks.Paths)
+	}
+	for name, path := range bookmarks {
+		if savedBookmarks.Paths[name] != path {
+			t.Errorf("bookmark %s points to %s, expected %s", name, savedBookmarks.Paths[name], path)
+		}
+	}
+
+	savedPins, _, err := loadPins()
+	if err != nil {
+		t.Fatalf("failed to load pins: %v", err)
+	}
+	if !slices.Equal(savedPins.Paths, pins) && len(savedPins.Paths)+len(pins) > 0 {
+		t.Errorf("expected pins %v, got %v", pins, savedPins.Paths)
+	}
+}
+
+func TestRegistryMoves(t *testing.T) {
+	existing := t.TempDir()
+	before := map[string]string{
+		"moved":    "/src/old",
+		"same":     "/src/same",
+		"gone":     "/src/gone",
+		"forgot":   existing,
+		"unmoved2": "/src/x",
+	}
+	after := map[string]string{
+		"moved":    "/src/new",
+		"same":     "/src/same",
+		"unmoved2": "/src/x",
+		"added":    "/src/added",
+	}
+
+	moves := registryMoves(before, after)
+	expected := map[string]string{"/src/old": "/src/new", "/src/gone": ""}
+	if len(moves) != len(expected) {
+		t.Errorf("expected moves %v, got %v", expected, moves)
+	}
+	for oldPath, newPath := range expected {
+		if got, exists := moves[oldPath]; !exists || got != newPath {
+			t.Errorf("expected %s to move to %q, got %q", oldPath, newPath, got)
+		}
+	}
+}
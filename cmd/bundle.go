@@ -0,0 +1,215 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mochajutsu/mkcd/internal/bundle"
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// Bundle command flags
+var (
+	bundleTemplates  []string
+	bundleGenerators []string
+	bundleProfiles   []string
+	bundleForce      bool
+)
+
+// bundleCmd represents the bundle command
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Package templates and configuration for offline machines",
+	Long: `Package templates, shared partials, custom generators and profiles into a
+single archive, and install such an archive on another machine without
+network access.
+
+By default a bundle contains every template, generator and profile; use the
+selection flags to restrict it. Installed files go into the templates
+directory and profiles and generators are merged into the configuration.
+Existing files and definitions are kept unless --force is given.
+
+Examples:
+  mkcd bundle create team.tar.gz                          # Bundle everything
+  mkcd bundle create go.tar.gz --templates go,docker --profiles go
+  mkcd bundle install team.tar.gz                         # Install on the offline machine
+  mkcd bundle install team.tar.gz --force                 # Overwrite existing content`,
+}
+
+// bundleCreateCmd represents the bundle create command
+var bundleCreateCmd = &cobra.Command{
+	Use:   "create <archive>",
+	Short: "Create a bundle archive",
+	Long:  `Create a gzip-compressed bundle of templates, partials, generators and profiles.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runBundleCreate,
+}
+
+// bundleInstallCmd represents the bundle install command
+var bundleInstallCmd = &cobra.Command{
+	Use:   "install <archive>",
+	Short: "Install a bundle archive",
+	Long:  `Install the templates, generators and profiles of a bundle archive.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runBundleInstall,
+}
+
+func init() {
+	rootCmd.AddCommand(bundleCmd)
+
+	// Add subcommands
+	bundleCmd.AddCommand(bundleCreateCmd)
+	bundleCmd.AddCommand(bundleInstallCmd)
+
+	// Create flags
+	bundleCreateCmd.Flags().StringSliceVar(&bundleTemplates, "templates", nil, "templates to include (default: all)")
+	bundleCreateCmd.Flags().StringSliceVar(&bundleGenerators, "generators", nil, "custom generators to include (default: all)")
+	bundleCreateCmd.Flags().StringSliceVar(&bundleProfiles, "profiles", nil, "profiles to include (default: all)")
+
+	// Install flags
+	bundleInstallCmd.Flags().BoolVar(&bundleForce, "force", false, "overwrite existing files, profiles and generators")
+}
+
+// runBundleCreate creates a bundle archive
+func runBundleCreate(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	opts := bundle.CreateOptions{}
+	if cmd.Flags().Changed("templates") {
+		opts.Templates = bundleTemplates
+	}
+	if cmd.Flags().Changed("generators") {
+		opts.Generators = bundleGenerators
+	}
+	if cmd.Flags().Changed("profiles") {
+		opts.Profiles = bundleProfiles
+	}
+
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would create bundle: %s", args[0]))
+		return nil
+	}
+
+	manifest, err := bundle.Create(args[0], cfg, opts)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+
+	outputMgr.Success(fmt.Sprintf("Created bundle: %s", args[0]))
+	printBundleSummary(manifest, outputMgr)
+	return nil
+}
+
+// runBundleInstall installs a bundle archive
+func runBundleInstall(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	result, err := bundle.Install(args[0], cfg.Templates.Directory, bundle.InstallOptions{
+		Force:  bundleForce,
+		DryRun: dryRun,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to install bundle: %w", err)
+	}
+
+	for _, path := range result.Written {
+		if dryRun {
+			outputMgr.Info(fmt.Sprintf("[DRY RUN] Would install: %s", path))
+		} else {
+			outputMgr.Verbose(fmt.Sprintf("Installed: %s", path))
+		}
+	}
+	for _, path := range result.Skipped {
+		outputMgr.Warning(fmt.Sprintf("Skipped existing file: %s (use --force to overwrite)", path))
+	}
+
+	// Merge profiles and generators into the configuration
+	changed := false
+	for _, name := range utils.SortedKeys(result.Manifest.Profiles) {
+		if cfg.IsRemoteProfile(name) {
+			outputMgr.Warning(fmt.Sprintf("Skipped read-only remote profile: %s", name))
+			continue
+		}
+		if _, exists := cfg.Profiles[name]; exists && !bundleForce {
+			outputMgr.Warning(fmt.Sprintf("Skipped existing profile: %s", name))
+			continue
+		}
+		cfg.SetProfile(name, result.Manifest.Profiles[name])
+		changed = true
+	}
+	for _, name := range utils.SortedKeys(result.Manifest.Generators) {
+		if _, exists := cfg.Generators[name]; exists && !bundleForce {
+			outputMgr.Warning(fmt.Sprintf("Skipped existing generator: %s", name))
+			continue
+		}
+		if cfg.Generators == nil {
+			cfg.Generators = make(map[string]config.GeneratorConfig)
+		}
+		cfg.Generators[name] = result.Manifest.Generators[name]
+		changed = true
+	}
+
+	if changed {
+		if dryRun {
+			outputMgr.Info("[DRY RUN] Would add bundled profiles and generators to the configuration")
+		} else if err := cfg.Save(cfgFile); err != nil {
+			return fmt.Errorf("failed to save configuration: %w", err)
+		}
+	}
+
+	if !dryRun {
+		outputMgr.Success(fmt.Sprintf("Installed bundle: %s (%d files)", args[0], len(result.Written)))
+	}
+	printBundleSummary(result.Manifest, outputMgr)
+	return nil
+}
+
+// printBundleSummary lists the content of a bundle
+func printBundleSummary(manifest *bundle.Manifest, outputMgr *utils.OutputManager) {
+	none := func(names []string) string {
+		if len(names) == 0 {
+			return "-"
+		}
+		return strings.Join(names, ", ")
+	}
+
+	outputMgr.List([]string{
+		fmt.Sprintf("Templates: %s", none(manifest.Templates)),
+		fmt.Sprintf("Partials: %t", manifest.Partials),
+		fmt.Sprintf("Generators: %s", none(utils.SortedKeys(manifest.Generators))),
+		fmt.Sprintf("Profiles: %s", none(utils.SortedKeys(manifest.Profiles))),
+		fmt.Sprintf("Files: %d", len(manifest.Files)),
+	})
+}
@@ -0,0 +1,200 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/mochajutsu/mkcd/internal/check"
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/files"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// checkFix enables generation of missing pieces
+var checkFix bool
+
+// checkCmd represents the check command
+var checkCmd = &cobra.Command{
+	Use:   "check [directory]",
+	Short: "Check the health of a workspace",
+	Long: `Check an existing workspace against the conventions mkcd sets up.
+
+The following checks are performed and summarised in a scorecard:
+
+• license    A license file is present
+• gitignore  .gitignore exists and covers the detected languages
+• remote     The directory is a Git repository with a remote configured
+• ci         A CI configuration exists
+
+With --fix, missing pieces are generated using the generators and settings of
+the selected profile. The command exits with an error if any check fails.
+
+Examples:
+  mkcd check                           # Check the current directory
+  mkcd check ~/projects/api            # Check another workspace
+  mkcd check --fix                     # Generate missing license, gitignore and CI
+  mkcd check --fix --profile python    # Fix using the 'python' profile settings`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCheck,
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+
+	checkCmd.Flags().BoolVar(&checkFix, "fix", false, "generate missing license, gitignore entries and CI configuration")
+}
+
+// runCheck checks a workspace and prints the scorecard
+func runCheck(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	targetPath, err := utils.GetAbsolutePath(dir)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	if !utils.IsDirectory(targetPath) {
+		return fmt.Errorf("not a directory: %s", targetPath)
+	}
+
+	// Failed checks are reported in the scorecard, not as usage errors
+	cmd.SilenceUsage = true
+
+	// The profile supplies the policy used for fixes
+	profileName := profile
+	if profileName == "" {
+		profileName = cfg.Core.DefaultProfile
+	}
+	profileConfig, _ := cfg.GetProfile(profileName)
+
+	opts := check.Options{
+		License:   profileConfig.License,
+		Gitignore: profileConfig.Gitignore,
+	}
+
+	results := check.Run(targetPath, opts)
+
+	if checkFix {
+		if err := fixCheckResults(targetPath, results, cfg, profileConfig, outputMgr); err != nil {
+			return err
+		}
+		if !dryRun {
+			results = check.Run(targetPath, opts)
+		}
+	}
+
+	outputMgr.Header(fmt.Sprintf("Workspace Health: %s", targetPath))
+
+	if languages := check.DetectLanguages(targetPath); len(languages) > 0 {
+		outputMgr.Info(fmt.Sprintf("Detected languages: %s", strings.Join(languages, ", ")))
+	}
+
+	rows := [][]string{}
+	for _, result := range results {
+		status := "PASS"
+		if !result.Passed {
+			status = "FAIL"
+			if result.Fix != "" || len(result.Missing) > 0 {
+				status = "FAIL (fixable)"
+			}
+		}
+		rows = append(rows, []string{result.Name, status, result.Detail})
+	}
+	outputMgr.Table([]string{"Check", "Status", "Details"}, rows)
+
+	passed := check.Score(results)
+	outputMgr.Printf("Score: %d/%d (%d%%)\n", passed, len(results), passed*100/len(results))
+
+	if passed < len(results) {
+		if !checkFix {
+			outputMgr.Info("Run 'mkcd check --fix' to generate fixable pieces")
+		}
+		return fmt.Errorf("%d of %d checks failed", len(results)-passed, len(results))
+	}
+
+	outputMgr.Success("All checks passed")
+	return nil
+}
+
+// fixCheckResults generates the missing pieces for failed checks
+func fixCheckResults(targetPath string, results []check.Result, cfg *config.Config, profileConfig config.ProfileConfig, outputMgr *utils.OutputManager) error {
+	fsOps := utils.NewFileSystemOperations(dryRun, backup || cfg.Core.BackupEnabled)
+	fsOps.Output = outputMgr
+	fsOps.Encoding = fileEncoding(cfg)
+	fsOps.EnableRetry(retryPolicy(cfg, outputMgr))
+	fileGen := files.NewFileGenerator(fsOps, dryRun, verbose)
+	registry, err := newGeneratorRegistry(cfg, fileGen)
+	if err != nil {
+		return err
+	}
+
+	ctx := files.NewGenerationContext(targetPath)
+	ctx.Author = cfg.Git.UserName
+	ctx.Email = cfg.Git.UserEmail
+	ctx.License = profileConfig.License
+	ctx.EOL = profileConfig.EOL
+
+	for _, result := range results {
+		if result.Passed {
+			continue
+		}
+
+		switch {
+		case result.Fix != "":
+			name, option := files.ParseGeneratorSpec(result.Fix)
+			outputMgr.Verbose(fmt.Sprintf("Fixing %s with generator %s", result.Name, result.Fix))
+			if err := registry.Run(ctx, name, option); err != nil {
+				return fmt.Errorf("failed to fix %s: %w", result.Name, err)
+			}
+		case len(result.Missing) > 0:
+			if err := appendGitignorePatterns(targetPath, result.Missing, fsOps); err != nil {
+				return fmt.Errorf("failed to fix %s: %w", result.Name, err)
+			}
+		default:
+			outputMgr.Warning(fmt.Sprintf("Cannot fix %s automatically: %s", result.Name, result.Detail))
+		}
+	}
+
+	return nil
+}
+
+// appendGitignorePatterns adds missing patterns to an existing .gitignore
+func appendGitignorePatterns(targetPath string, patterns []string, fsOps *utils.FileSystemOperations) error {
+	path := filepath.Join(targetPath, ".gitignore")
+
+	content, err := fsOps.FS.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	updated := string(content)
+	if updated != "" && !strings.HasSuffix(updated, "\n") {
+		updated += "\n"
+	}
+	updated += "\n# Added by mkcd check\n" + strings.Join(patterns, "\n") + "\n"
+
+	return fsOps.CreateFile(path, updated, 0644)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/templates"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// completionFunc completes a flag or argument value
+type completionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// registerFlagCompletion registers a completion function for a flag of cmd
+func registerFlagCompletion(cmd *cobra.Command, flag string, complete completionFunc) {
+	if err := cmd.RegisterFlagCompletionFunc(flag, complete); err != nil {
+		panic(err)
+	}
+}
+
+// loadCompletionConfig loads the configuration for a completion callback.
+// Anything printed would end up among the completions, so output is
+// disabled; a broken config simply yields no completions.
+func loadCompletionConfig() *config.Config {
+	pterm.DisableOutput()
+	defer pterm.EnableOutput()
+
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return nil
+	}
+	return cfg
+}
+
+// completeProfiles completes profile names from the loaded config
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg := loadCompletionConfig()
+	if cfg == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return utils.SortedKeys(cfg.Profiles), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProfileArg completes the profile name arguments of the profile
+// subcommands; copy takes a source profile and a new name
+func completeProfileArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeProfiles(cmd, args, toComplete)
+}
+
+// completeTemplateArg completes a template name as the first positional
+// argument
+func completeTemplateArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeTemplates(cmd, args, toComplete)
+}
+
+// completeTemplates completes template names from the templates directory.
+// Templates are layered with commas, so each name after a comma is
+// completed as well.
+func completeTemplates(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg := loadCompletionConfig()
+	if cfg == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names, err := templates.ListAll(cfg.Templates.Directory, cfg.GetPartialsDirectory())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	prefix := ""
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix = toComplete[:i+1]
+	}
+
+	used := map[string]bool{}
+	for _, name := range templates.ParseNames(prefix) {
+		used[name] = true
+	}
+
+	completions := []string{}
+	for _, name := range names {
+		if !used[name] {
+			completions = append(completions, prefix+name)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// completeValues completes a fixed list of values
+func completeValues(values []string) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strings"
 
 	"github.com/mochajutsu/mkcd/internal/config"
 	"github.com/mochajutsu/mkcd/internal/utils"
@@ -145,9 +146,16 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 		fmt.Sprintf("Default Profile: %s", cfg.Core.DefaultProfile),
 		fmt.Sprintf("Editor: %s", cfg.Core.Editor),
 		fmt.Sprintf("Shell Integration: %t", cfg.Core.ShellIntegration),
+		fmt.Sprintf("Integration Hint: %t", cfg.Core.IntegrationHint),
 		fmt.Sprintf("History Limit: %d", cfg.Core.HistoryLimit),
 		fmt.Sprintf("Backup Enabled: %t", cfg.Core.BackupEnabled),
 		fmt.Sprintf("Temp Directory: %s", cfg.Core.TempDir),
+		fmt.Sprintf("Project File: %t", cfg.Core.ProjectFile),
+		fmt.Sprintf("Workspace Manifest: %t", cfg.Core.WorkspaceManifest),
+		fmt.Sprintf("Cd On Failure: %s", valueOrDash(cfg.Core.CdOnFailure)),
+		fmt.Sprintf("Lock Timeout: %s", valueOrDash(cfg.Core.LockTimeout)),
+		fmt.Sprintf("Spawn Shell: %s", valueOrDash(cfg.Core.SpawnShell)),
+		fmt.Sprintf("Profiles URL: %s", valueOrDash(cfg.Core.ProfilesURL)),
 	}
 	outputMgr.List(coreSettings)
 
@@ -159,6 +167,10 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 		fmt.Sprintf("User Name: %s", cfg.Git.UserName),
 		fmt.Sprintf("User Email: %s", cfg.Git.UserEmail),
 		fmt.Sprintf("Default Remote Name: %s", cfg.Git.DefaultRemoteName),
+		fmt.Sprintf("Initial Commit: %t", cfg.Git.InitialCommit),
+		fmt.Sprintf("Initial Commit Message: %s", cfg.Git.InitialCommitMessage),
+		fmt.Sprintf("Initial Commit Content: %s", cfg.Git.InitialCommitContent),
+		fmt.Sprintf("Conventional Commits: %t", cfg.Git.ConventionalCommits),
 	}
 	outputMgr.List(gitSettings)
 
@@ -167,6 +179,11 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 	templateSettings := []string{
 		fmt.Sprintf("Directory: %s", cfg.Templates.Directory),
 		fmt.Sprintf("Auto Update: %t", cfg.Templates.AutoUpdate),
+		fmt.Sprintf("Conflict Strategy: %s", cfg.Templates.Conflict),
+		fmt.Sprintf("Partials: %s", cfg.GetPartialsDirectory()),
+		fmt.Sprintf("Render: %s", valueOrDash(cfg.Templates.Render)),
+		fmt.Sprintf("Registry: %s", valueOrDash(strings.Join(utils.SortedKeys(cfg.Templates.Registry), ", "))),
+		fmt.Sprintf("Pins: %s", valueOrDash(strings.Join(utils.SortedKeys(cfg.Templates.Pins), ", "))),
 	}
 	outputMgr.List(templateSettings)
 
@@ -177,6 +194,8 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 		fmt.Sprintf("Confirm Deletes: %t", cfg.Safety.ConfirmDeletes),
 		fmt.Sprintf("Max Depth: %d", cfg.Safety.MaxDepth),
 		fmt.Sprintf("Forbidden Paths: %v", cfg.Safety.ForbiddenPaths),
+		fmt.Sprintf("Enforce Modes: %t", cfg.Safety.EnforceModes),
+		fmt.Sprintf("Allow Root: %t", cfg.Safety.AllowRoot),
 	}
 	outputMgr.List(safetySettings)
 
@@ -186,16 +205,84 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 		fmt.Sprintf("Colors: %t", cfg.Output.Colors),
 		fmt.Sprintf("Icons: %t", cfg.Output.Icons),
 		fmt.Sprintf("Progress Bars: %t", cfg.Output.ProgressBars),
+		fmt.Sprintf("Terminal Cwd: %t", cfg.Output.TerminalCwd),
+		fmt.Sprintf("Tree Depth: %d", cfg.Output.TreeDepth),
+		fmt.Sprintf("Summary: %t", cfg.Output.Summary),
 	}
 	outputMgr.List(outputSettings)
 
+	// File settings
+	outputMgr.Section("File Settings")
+	fileSettings := []string{
+		fmt.Sprintf("Line Endings: %s", valueOrDash(cfg.Files.LineEndings)),
+		fmt.Sprintf("BOM: %s", valueOrDash(strings.Join(cfg.Files.BOM, ", "))),
+		fmt.Sprintf("Retry Attempts: %d", cfg.Files.RetryAttempts),
+		fmt.Sprintf("Retry Delay: %s", valueOrDash(cfg.Files.RetryDelay)),
+		fmt.Sprintf("Detect Conventions: %t", cfg.Files.DetectConventions),
+	}
+	outputMgr.List(fileSettings)
+
+	// Hook settings
+	outputMgr.Section("Hook Settings")
+	outputMgr.List([]string{
+		fmt.Sprintf("Timeout: %s", valueOrDash(cfg.Hooks.Timeout)),
+		fmt.Sprintf("Env: %s", valueOrDash(strings.Join(cfg.Hooks.Env, ", "))),
+		fmt.Sprintf("Restricted: %t", cfg.Hooks.Restricted),
+		fmt.Sprintf("Template Hooks: %s", valueOrDash(cfg.Hooks.Templates)),
+	})
+
+	// Journal settings
+	outputMgr.Section("Journal Settings")
+	outputMgr.List([]string{
+		fmt.Sprintf("Root: %s", valueOrDash(cfg.Journal.Root)),
+		fmt.Sprintf("Layout: %s", valueOrDash(cfg.Journal.Layout)),
+		fmt.Sprintf("Note: %s", valueOrDash(cfg.Journal.Note)),
+		fmt.Sprintf("Note Template: %s", valueOrDash(cfg.Journal.NoteTemplate)),
+		fmt.Sprintf("Latest: %s", valueOrDash(cfg.Journal.Latest)),
+	})
+
+	// Integration settings
+	outputMgr.Section("Task Integration")
+	outputMgr.List([]string{
+		fmt.Sprintf("Backend: %s", valueOrDash(cfg.Integrations.Tasks.Backend)),
+		fmt.Sprintf("File: %s", valueOrDash(cfg.Integrations.Tasks.File)),
+		fmt.Sprintf("Profiles: %s", valueOrDash(strings.Join(cfg.Integrations.Tasks.Profiles, ", "))),
+		fmt.Sprintf("Tasks: %s", valueOrDash(strings.Join(cfg.Integrations.Tasks.Tasks, "; "))),
+	})
+
+	outputMgr.Section("Notifications")
+	outputMgr.List([]string{
+		fmt.Sprintf("Runs: %s", valueOrDash(strings.Join(cfg.Integrations.Notify.Runs, ", "))),
+		fmt.Sprintf("Only Failures: %t", cfg.Integrations.Notify.OnlyFailures),
+		fmt.Sprintf("Min Duration: %s", valueOrDash(cfg.Integrations.Notify.MinDuration)),
+		fmt.Sprintf("Webhook: %s", valueOrDash(cfg.Integrations.Notify.Webhook)),
+		fmt.Sprintf("Format: %s", valueOrDash(cfg.Integrations.Notify.Format)),
+		fmt.Sprintf("Email: %s", valueOrDash(strings.Join(cfg.Integrations.Notify.Email, ", "))),
+		fmt.Sprintf("SMTP Host: %s", valueOrDash(cfg.Integrations.Notify.SMTPHost)),
+	})
+
+	// Sync settings
+	outputMgr.Section("Sync Settings")
+	syncSettings := []string{
+		fmt.Sprintf("Backend: %s", valueOrDash(cfg.Sync.Backend)),
+		fmt.Sprintf("URL: %s", valueOrDash(cfg.Sync.URL)),
+		fmt.Sprintf("Username: %s", valueOrDash(cfg.Sync.Username)),
+	}
+	outputMgr.List(syncSettings)
+
+	// Telemetry settings
+	outputMgr.Section("Telemetry Settings")
+	outputMgr.List([]string{
+		fmt.Sprintf("Enabled: %t", cfg.Telemetry.Enabled),
+	})
+
 	// Profiles
 	outputMgr.Section("Profiles")
 	if len(cfg.Profiles) == 0 {
 		outputMgr.Info("No profiles configured")
 	} else {
 		profileList := []string{}
-		for name := range cfg.Profiles {
+		for _, name := range utils.SortedKeys(cfg.Profiles) {
 			if name == cfg.Core.DefaultProfile {
 				profileList = append(profileList, fmt.Sprintf("%s (default)", name))
 			} else {
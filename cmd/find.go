@@ -0,0 +1,303 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/registry"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// Find command flags
+var (
+	findPaths bool
+	findLimit int
+)
+
+// findCmd represents the find command
+var findCmd = &cobra.Command{
+	Use:   "find [query]",
+	Short: "Find workspaces created or registered on this machine",
+	Long: `List the existing directories recorded in the history and the registry of
+this machine, and the pinned ones. Pinned workspaces come first, in the
+order they were pinned, followed by the others most recently used first.
+
+A query keeps the workspaces whose name or path contains it, or whose name
+contains its letters in order, ignoring case.
+
+Examples:
+  mkcd find                                # All workspaces
+  mkcd find api                            # Workspaces matching 'api'
+  mkcd find --limit 5                      # The five at the top
+  cd "$(mkcd find --paths | fzf)"          # Pick one with fzf`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runFind,
+}
+
+// pinCmd represents the pin command
+var pinCmd = &cobra.Command{
+	Use:   "pin [dir|name]",
+	Short: "Pin a workspace so it is listed first",
+	Long: `Pin a workspace so that 'mkcd find', 'mkcd open' and the completion of the
+shell wrapper list it before all others. The workspace is resolved like by
+'mkcd open' and defaults to the current directory.
+
+Examples:
+  mkcd pin                                 # Pin the current directory
+  mkcd pin api                             # Pin the workspace 'api'`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeWorkspaces,
+	RunE:              runPin,
+}
+
+// unpinCmd represents the unpin command
+var unpinCmd = &cobra.Command{
+	Use:   "unpin [dir|name]",
+	Short: "Unpin a workspace",
+	Long: `Unpin a workspace pinned with 'mkcd pin'. Pinned paths that no longer
+exist can be unpinned by path or by name.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completePins,
+	RunE:              runUnpin,
+}
+
+func init() {
+	rootCmd.AddCommand(findCmd)
+	rootCmd.AddCommand(pinCmd)
+	rootCmd.AddCommand(unpinCmd)
+
+	findCmd.Flags().BoolVar(&findPaths, "paths", false, "print only the paths, one per line")
+	findCmd.Flags().IntVar(&findLimit, "limit", 0, "show at most this many workspaces (0 for all)")
+}
+
+// runFind lists the known workspaces matching the query
+func runFind(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	if findLimit < 0 {
+		return fmt.Errorf("--limit cannot be negative")
+	}
+	query := ""
+	if len(args) > 0 {
+		query = args[0]
+	}
+
+	names := registeredNames()
+	used := usedDirectories(cfg)
+	paths := []string{}
+	for path := range used {
+		if !utils.IsDirectory(path) {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(path), strings.ToLower(query)) && matchScore(query, workspaceName(path, names)) == 0 {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sortWorkspaces(paths, used)
+	if findLimit > 0 && len(paths) > findLimit {
+		paths = paths[:findLimit]
+	}
+
+	if findPaths {
+		for _, path := range paths {
+			fmt.Println(path)
+		}
+		return nil
+	}
+
+	if len(paths) == 0 {
+		if query != "" {
+			outputMgr.Info(fmt.Sprintf("No workspaces match '%s'", query))
+		} else {
+			outputMgr.Info("No workspaces recorded yet")
+		}
+		return nil
+	}
+
+	pins, _, _ := loadPins()
+	rows := [][]string{}
+	for _, path := range paths {
+		pinned := "-"
+		if pins.Contains(path) {
+			pinned = "yes"
+		}
+		lastUsed := "-"
+		if !used[path].IsZero() {
+			lastUsed = used[path].Format("2006-01-02 15:04")
+		}
+		rows = append(rows, []string{workspaceName(path, names), path, pinned, lastUsed})
+	}
+	outputMgr.Table([]string{"Name", "Path", "Pinned", "Last Used"}, rows)
+	return nil
+}
+
+// runPin pins a workspace
+func runPin(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	cmd.SilenceUsage = true
+	query := ""
+	if len(args) > 0 {
+		query = args[0]
+	}
+	dir, err := resolveWorkspace(query, cfg, outputMgr)
+	if err != nil {
+		return err
+	}
+
+	pins, path, err := loadPins()
+	if err != nil {
+		return err
+	}
+	if !pins.Pin(dir) {
+		outputMgr.Info(fmt.Sprintf("Already pinned: %s", dir))
+		return nil
+	}
+
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would pin %s", dir))
+		return nil
+	}
+	if err := pins.Save(path); err != nil {
+		return err
+	}
+	outputMgr.Success(fmt.Sprintf("Pinned workspace: %s", dir))
+	return nil
+}
+
+// runUnpin unpins a workspace, which may no longer exist
+func runUnpin(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	cmd.SilenceUsage = true
+	pins, path, err := loadPins()
+	if err != nil {
+		return err
+	}
+
+	query := "."
+	if len(args) > 0 {
+		query = args[0]
+	}
+	dir := pinnedPath(pins.Paths, query)
+	if dir == "" {
+		if dir, err = resolveWorkspace(query, cfg, outputMgr); err != nil {
+			return err
+		}
+	}
+	if !pins.Unpin(dir) {
+		return fmt.Errorf("%s is not pinned", dir)
+	}
+
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would unpin %s", dir))
+		return nil
+	}
+	if err := pins.Save(path); err != nil {
+		return err
+	}
+	outputMgr.Success(fmt.Sprintf("Unpinned workspace: %s", dir))
+	return nil
+}
+
+// pinnedPath returns the pinned path that query names by its absolute path
+// or its base name, or an empty string
+func pinnedPath(pinned []string, query string) string {
+	if path, err := utils.ExpandPath(query); err == nil {
+		if abs, err := utils.GetAbsolutePath(path); err == nil {
+			for _, pin := range pinned {
+				if pin == abs {
+					return pin
+				}
+			}
+		}
+	}
+	for _, pin := range pinned {
+		if filepath.Base(pin) == query {
+			return pin
+		}
+	}
+	return ""
+}
+
+// registeredNames maps the paths of registered projects on this machine to
+// their names
+func registeredNames() map[string]string {
+	names := make(map[string]string)
+	if reg, _, err := loadRegistry(); err == nil {
+		host := registry.Hostname()
+		for _, entry := range reg.Entries() {
+			if path, exists := entry.Paths[host]; exists {
+				names[path] = entry.Name
+			}
+		}
+	}
+	return names
+}
+
+// workspaceName returns the registered name of path, or its base name
+func workspaceName(path string, names map[string]string) string {
+	if name := names[path]; name != "" {
+		return name
+	}
+	return filepath.Base(path)
+}
+
+// completePins completes the pinned paths
+func completePins(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	pins, _, err := loadPins()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return pins.Paths, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,248 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/project"
+	"github.com/mochajutsu/mkcd/internal/registry"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// freezeCmd represents the freeze command
+var freezeCmd = &cobra.Command{
+	Use:   "freeze [dir|name]",
+	Short: "Protect a workspace from changes",
+	Long: `Freeze a workspace, such as a reference checkout, so it is not changed by
+accident. The workspace is resolved like by 'mkcd open' and defaults to the
+current directory.
+
+Freezing registers the workspace as a project if it is not registered yet,
+marks it as frozen in the registry and removes the write permissions of its
+files and directories. Where permitted, usually as root on Linux, the
+immutable attribute is set as well (chattr +i), which even root has to
+clear before changing the files.
+
+mkcd refuses to create directories in a frozen workspace, apply templates to
+it or rename it until 'mkcd unfreeze' restores its permissions.
+
+Examples:
+  mkcd freeze                              # Freeze the current directory
+  mkcd freeze ~/src/linux                  # Freeze a reference checkout`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeWorkspaces,
+	RunE:              runFreeze,
+}
+
+// unfreezeCmd represents the unfreeze command
+var unfreezeCmd = &cobra.Command{
+	Use:   "unfreeze [dir|name]",
+	Short: "Allow changes to a frozen workspace again",
+	Long: `Unfreeze a workspace frozen with 'mkcd freeze': clear the immutable
+attribute if it was set and give its files and directories back the
+permissions they had.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeWorkspaces,
+	RunE:              runUnfreeze,
+}
+
+func init() {
+	rootCmd.AddCommand(freezeCmd)
+	rootCmd.AddCommand(unfreezeCmd)
+}
+
+// runFreeze freezes a workspace
+func runFreeze(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	cmd.SilenceUsage = true
+	query := ""
+	if len(args) > 0 {
+		query = args[0]
+	}
+	dir, err := resolveWorkspace(query, cfg, outputMgr)
+	if err != nil {
+		return err
+	}
+
+	// Nothing may be written into the workspace while it is made read-only
+	if !dryRun {
+		lock, err := lockTarget(dir, cfg, outputMgr)
+		if err != nil {
+			return err
+		}
+		defer lock.Unlock()
+	}
+
+	if entry, root := frozenProject(dir); entry != nil {
+		if root == dir {
+			outputMgr.Info(fmt.Sprintf("Already frozen: %s", dir))
+			return nil
+		}
+		return fmt.Errorf("%s is inside the frozen workspace %s", dir, root)
+	}
+
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would freeze %s", dir))
+		return nil
+	}
+
+	// The project file is written while the directory is still writable
+	if !utils.PathExists(project.Path(dir)) {
+		fsOps := utils.NewFileSystemOperations(false, backup)
+		fsOps.Encoding = fileEncoding(cfg)
+		fsOps.Silent = true
+		if err := project.New(dir, "").Write(fsOps, dir); err != nil {
+			return err
+		}
+	}
+	if err := registerProject(dir); err != nil {
+		return err
+	}
+	p, err := project.Load(dir)
+	if err != nil {
+		return err
+	}
+
+	modes, err := registry.MakeReadOnly(dir)
+	if err != nil {
+		return err
+	}
+	frozen := &registry.FrozenModes{Path: dir, Modes: modes}
+	if err := registry.SetImmutable(dir, true); err != nil {
+		outputMgr.Verbose(fmt.Sprintf("Immutable attribute not set: %v", err))
+	} else {
+		frozen.Immutable = true
+	}
+
+	if err := frozen.Save(p.ID); err != nil {
+		return err
+	}
+	reg, path, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+	reg.Freeze(p.ID, registry.Hostname())
+	if err := reg.Save(path); err != nil {
+		return err
+	}
+
+	if frozen.Immutable {
+		outputMgr.Success(fmt.Sprintf("Frozen workspace: %s (read-only and immutable)", dir))
+	} else {
+		outputMgr.Success(fmt.Sprintf("Frozen workspace: %s (read-only)", dir))
+	}
+	return nil
+}
+
+// runUnfreeze unfreezes a workspace
+func runUnfreeze(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	cmd.SilenceUsage = true
+	query := ""
+	if len(args) > 0 {
+		query = args[0]
+	}
+	dir, err := resolveWorkspace(query, cfg, outputMgr)
+	if err != nil {
+		return err
+	}
+
+	entry, root := frozenProject(dir)
+	if entry == nil || root != dir {
+		return fmt.Errorf("%s is not frozen", dir)
+	}
+
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would unfreeze %s", dir))
+		return nil
+	}
+
+	frozen, err := registry.LoadFrozenModes(entry.ID)
+	if err != nil {
+		return err
+	}
+	if frozen != nil {
+		if frozen.Immutable {
+			if err := registry.SetImmutable(dir, false); err != nil {
+				return fmt.Errorf("failed to clear the immutable attribute of %s (run as root): %w", dir, err)
+			}
+		}
+		if err := registry.RestoreModes(dir, frozen.Modes); err != nil {
+			return err
+		}
+	} else {
+		outputMgr.Warning(fmt.Sprintf("The permissions of %s before it was frozen are unknown, so they are left as they are", dir))
+	}
+
+	reg, path, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+	reg.Unfreeze(entry.ID, registry.Hostname())
+	if err := reg.Save(path); err != nil {
+		return err
+	}
+	if err := registry.RemoveFrozenModes(entry.ID); err != nil {
+		outputMgr.Warning(err.Error())
+	}
+
+	outputMgr.Success(fmt.Sprintf("Unfrozen workspace: %s", dir))
+	return nil
+}
+
+// frozenProject returns the project frozen on this machine that is path
+// or contains it, with its path, or nil. An unreadable registry freezes
+// nothing.
+func frozenProject(path string) (*registry.Entry, string) {
+	reg, _, err := loadRegistry()
+	if err != nil {
+		return nil, ""
+	}
+	return reg.FrozenContaining(path, registry.Hostname())
+}
+
+// checkNotFrozen refuses changes to path when it is a frozen workspace or
+// lies inside one
+func checkNotFrozen(path string) error {
+	entry, root := frozenProject(path)
+	if entry == nil {
+		return nil
+	}
+	if root == path {
+		return fmt.Errorf("%s is frozen; run 'mkcd unfreeze %s' to change it", root, root)
+	}
+	return fmt.Errorf("%s is inside the frozen workspace %s; run 'mkcd unfreeze %s' to change it", path, root, root)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/files"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// generatorsCmd represents the generators command
+var generatorsCmd = &cobra.Command{
+	Use:   "generators",
+	Short: "Manage file generators",
+	Long: `Inspect the file generators available to mkcd.
+
+Generators produce common project files such as README.md, .gitignore,
+LICENSE or CI workflows. They can be run with the --generate flag or listed
+in a profile's 'generate' setting, optionally with an option after a colon.
+
+Custom generators can be defined in the configuration file; each renders a
+template file (relative to the templates directory) into a target path:
+
+  [generators.my-docs-skeleton]
+  description = "Documentation skeleton"
+  target = "docs/index.md"
+  template = "docs-skeleton.md"
+  [generators.my-docs-skeleton.variables]
+  team = "platform"
+
+Examples:
+  mkcd generators list                 # List all available generators
+  mkcd myproject --generate ci:go      # Run the 'ci' generator with option 'go'`,
+}
+
+// generatorsListCmd represents the generators list command
+var generatorsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available generators",
+	Long:  `List all registered file generators with their options and descriptions.`,
+	RunE:  runGeneratorsList,
+}
+
+func init() {
+	rootCmd.AddCommand(generatorsCmd)
+
+	// Add subcommands
+	generatorsCmd.AddCommand(generatorsListCmd)
+}
+
+// runGeneratorsList lists all registered generators
+func runGeneratorsList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	fsOps := utils.NewFileSystemOperations(true, false)
+	fsOps.Output = outputMgr
+	registry, err := newGeneratorRegistry(cfg, files.NewFileGenerator(fsOps, true, verbose))
+	if err != nil {
+		return err
+	}
+
+	outputMgr.Header("Available Generators")
+
+	headers := []string{"Name", "Source", "Options", "Description"}
+	rows := [][]string{}
+
+	for _, generator := range registry.List() {
+		options := "-"
+		if len(generator.Options()) > 0 {
+			options = strings.Join(generator.Options(), ", ")
+		}
+		source := "built-in"
+		if _, custom := cfg.Generators[generator.Name()]; custom {
+			source = "config"
+		}
+		rows = append(rows, []string{generator.Name(), source, options, generator.Description()})
+	}
+
+	outputMgr.Table(headers, rows)
+	return nil
+}
+
+// newGeneratorRegistry creates a generator registry containing the built-in
+// generators plus any user-defined generators from the configuration
+func newGeneratorRegistry(cfg *config.Config, fileGen *files.FileGenerator) (*files.Registry, error) {
+	registry := files.NewRegistry(fileGen)
+
+	for _, name := range utils.SortedKeys(cfg.Generators) {
+		generatorConfig := cfg.Generators[name]
+		customGen := files.NewCustomGenerator(
+			fileGen,
+			name,
+			generatorConfig.Description,
+			generatorConfig.Target,
+			cfg.GetGeneratorTemplatePath(generatorConfig),
+			generatorConfig.Variables,
+		)
+		if err := registry.Register(customGen); err != nil {
+			return nil, fmt.Errorf("failed to register generator from config: %w", err)
+		}
+	}
+
+	return registry, nil
+}
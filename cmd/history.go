@@ -0,0 +1,745 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/git"
+	"github.com/mochajutsu/mkcd/internal/history"
+	"github.com/mochajutsu/mkcd/internal/templates"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// historyShowManifest prints the generation manifest instead of the entry
+var historyShowManifest bool
+
+// History list and search filters and output format
+var (
+	historySince    string
+	historyUntil    string
+	historyTemplate string
+	historyPath     string
+	historyOutput   string
+)
+
+// History prune criteria
+var (
+	historyOlderThan string
+	historyMissing   bool
+)
+
+// History export and import formats
+var (
+	historyExportFormat string
+	historyImportFormat string
+)
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Inspect the history of created directories",
+	Long: `Inspect the directories created by mkcd.
+
+Every creation is recorded with a short ID, the profile, template and
+command-line flags it used and the files it wrote, together with a
+manifest of the files generated, what produced them (template, generator or step), template
+versions and SHA-256 checksums. Manifests live in the mkcd state directory,
+not in the project.
+
+Examples:
+  mkcd history list                    # List recorded creations
+  mkcd history list --since 7d -p go   # Last week's creations with profile go
+  mkcd history search api -o json      # Creations whose path contains "api"
+  mkcd history show 3f2a9c             # Show a creation by ID (or ID prefix)
+  mkcd history show --manifest 3f2a9c  # Export its manifest as JSON
+  mkcd history prune --older-than 90d  # Forget creations older than 90 days
+  mkcd history export > history.json   # Export the history to move it elsewhere`,
+}
+
+// historyListCmd represents the history list command
+var historyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recorded creations",
+	Long: `List recorded creations, most recent first.
+
+--since and --until take a date (2006-01-02), an RFC 3339 time or an age
+such as 12h, 7d or 2w; a date given to --until includes that whole day.
+--profile, --template and --path narrow the list further, and --output json
+prints the entries as a JSON array for scripts.`,
+	Args: cobra.NoArgs,
+	RunE: runHistoryList,
+}
+
+// historySearchCmd represents the history search command
+var historySearchCmd = &cobra.Command{
+	Use:   "search <text>",
+	Short: "Search recorded creations by path",
+	Long: `List the recorded creations whose path contains the text, ignoring
+case. The filters and output formats of 'mkcd history list' apply.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHistoryList,
+}
+
+// historyShowCmd represents the history show command
+var historyShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show a recorded creation",
+	Long:  `Show a recorded creation, or export its generation manifest with --manifest.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runHistoryShow,
+}
+
+// historyPruneCmd represents the history prune command
+var historyPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove old or stale entries from the history",
+	Long: `Remove recorded creations from the history together with their
+manifests, and the visits counted for 'mkcd jump'.
+
+--older-than removes what was created or last visited longer ago than the
+age, e.g. 90d or 12w. --missing removes the creations and visits of
+directories that no longer exist. Without either, only the oldest entries
+beyond core.history_limit are removed; the limit is also enforced whenever
+the history is written.
+
+Examples:
+  mkcd history prune --older-than 90d          # Forget creations older than 90 days
+  mkcd history prune --missing                 # Forget deleted directories
+  mkcd history prune --missing --dry-run       # Show what would be removed`,
+	Args: cobra.NoArgs,
+	RunE: runHistoryPrune,
+}
+
+// historyExportCmd represents the history export command
+var historyExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Print the history as JSON or CSV",
+	Long: `Print the recorded creations, oldest first, to move them to another
+machine with 'mkcd history import' or to analyse them with other tools.
+
+Both formats carry a schema version: the "version" field of the JSON
+object, and a "# mkcd history export version N" line in front of the CSV
+header. The version only changes when a field changes meaning or is
+removed; fields may be added within a version. In CSV, flags and files are
+joined by semicolons. Manifests are not exported.
+
+The filters of 'mkcd history list' apply.
+
+Examples:
+  mkcd history export > history.json                   # Everything as JSON
+  mkcd history export --format csv --since 30d > h.csv # Last month as CSV`,
+	Args: cobra.NoArgs,
+	RunE: runHistoryExport,
+}
+
+// historyImportCmd represents the history import command
+var historyImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Merge an exported history into this one",
+	Long: `Merge the creations of an export into the history, in time order. Entries
+already recorded are skipped, so importing the same file twice adds nothing.
+The format follows the file extension unless --format is given; "-" reads
+JSON, or the given format, from standard input. Exports of a newer schema
+version are refused.
+
+Examples:
+  mkcd history import history.json            # Merge an export
+  mkcd history import h.csv --dry-run         # Show how many entries would be added`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHistoryImport,
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	// Add subcommands
+	historyCmd.AddCommand(historyListCmd)
+	historyCmd.AddCommand(historySearchCmd)
+	historyCmd.AddCommand(historyShowCmd)
+	historyCmd.AddCommand(historyPruneCmd)
+	historyCmd.AddCommand(historyExportCmd)
+	historyCmd.AddCommand(historyImportCmd)
+
+	// The global --profile flag selects the profile to filter by
+	for _, cmd := range []*cobra.Command{historyListCmd, historySearchCmd, historyExportCmd} {
+		cmd.Flags().StringVar(&historySince, "since", "", "only creations at or after this date, time or age (e.g. 2025-01-31, 7d)")
+		cmd.Flags().StringVar(&historyUntil, "until", "", "only creations at or before this date, time or age")
+		cmd.Flags().StringVarP(&historyTemplate, "template", "t", "", "only creations that applied this template")
+		_ = cmd.RegisterFlagCompletionFunc("template", completeTemplates)
+	}
+	for _, cmd := range []*cobra.Command{historyListCmd, historySearchCmd} {
+		cmd.Flags().StringVarP(&historyOutput, "output", "o", "table", "output format: table or json")
+		_ = cmd.RegisterFlagCompletionFunc("output", completeValues([]string{"table", "json"}))
+	}
+	historyListCmd.Flags().StringVar(&historyPath, "path", "", "only creations whose path contains this text")
+	historyExportCmd.Flags().StringVar(&historyPath, "path", "", "only creations whose path contains this text")
+
+	historyShowCmd.Flags().BoolVar(&historyShowManifest, "manifest", false, "print the generation manifest as JSON")
+
+	historyPruneCmd.Flags().StringVar(&historyOlderThan, "older-than", "", "remove creations and visits older than this age (e.g. 90d, 12w)")
+	historyPruneCmd.Flags().BoolVar(&historyMissing, "missing", false, "remove creations and visits of directories that no longer exist")
+
+	historyExportCmd.Flags().StringVar(&historyExportFormat, "format", history.ExportJSON, "export format: json or csv")
+	historyImportCmd.Flags().StringVar(&historyImportFormat, "format", "", "import format: json or csv (default: from the file extension)")
+	for _, cmd := range []*cobra.Command{historyExportCmd, historyImportCmd} {
+		_ = cmd.RegisterFlagCompletionFunc("format", completeValues(history.GetExportFormats()))
+	}
+}
+
+// runHistoryList lists recorded creations matching the filters; search
+// takes the path text as its argument
+func runHistoryList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	if historyOutput != "table" && historyOutput != "json" {
+		return fmt.Errorf("invalid output format '%s' (expected table or json)", historyOutput)
+	}
+	filter, err := historyFilter(args)
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	store, err := newHistoryStore(cfg)
+	if err != nil {
+		return err
+	}
+	entries, err := store.Load()
+	if err != nil {
+		return err
+	}
+	entries = filter.Apply(entries)
+	slices.Reverse(entries)
+
+	// JSON goes to stdout as-is, also when nothing matched
+	if historyOutput == "json" {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode history: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(entries) == 0 {
+		if filter == (history.Filter{}) {
+			outputMgr.Info("No history recorded yet")
+		} else {
+			outputMgr.Info("No recorded creations match")
+		}
+		return nil
+	}
+
+	outputMgr.Header("History")
+
+	rows := [][]string{}
+	for _, entry := range entries {
+		rows = append(rows, []string{
+			valueOrDash(entry.ID),
+			entry.Timestamp.Format("2006-01-02 15:04"),
+			entry.Path,
+			valueOrDash(entry.Profile),
+			valueOrDash(entry.Template),
+		})
+	}
+	outputMgr.Table([]string{"ID", "Created", "Path", "Profile", "Template"}, rows)
+	return nil
+}
+
+// historyFilter builds the history filter from the flags and the search text
+func historyFilter(args []string) (history.Filter, error) {
+	filter := history.Filter{
+		Profile:  profile,
+		Template: historyTemplate,
+		Path:     historyPath,
+	}
+	if len(args) > 0 {
+		filter.Path = args[0]
+	}
+
+	now := utils.Now()
+	var err error
+	if historySince != "" {
+		if filter.Since, err = parseHistoryTime(historySince, now, false); err != nil {
+			return filter, fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if historyUntil != "" {
+		if filter.Until, err = parseHistoryTime(historyUntil, now, true); err != nil {
+			return filter, fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+	return filter, nil
+}
+
+// parseHistoryTime parses a date, an RFC 3339 time or an age before now.
+// A date means its start, or its end for the upper bound of a range.
+func parseHistoryTime(value string, now time.Time, end bool) (time.Time, error) {
+	if date, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		if end {
+			return date.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+		}
+		return date, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	age, err := utils.ParseAge(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("'%s' is not a date (2006-01-02), time or age (e.g. 7d)", value)
+	}
+	return now.Add(-age), nil
+}
+
+// runHistoryShow shows a recorded creation or its manifest
+func runHistoryShow(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	store, err := newHistoryStore(cfg)
+	if err != nil {
+		return err
+	}
+	entry, err := store.Find(args[0])
+	if err != nil {
+		return err
+	}
+
+	manifest, manifestErr := store.LoadManifest(entry.ID)
+
+	// The manifest is machine-readable output, printed as-is to stdout
+	if historyShowManifest {
+		if manifestErr != nil {
+			return manifestErr
+		}
+		data, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode manifest: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	outputMgr.Header(fmt.Sprintf("History Entry: %s", entry.ID))
+	outputMgr.List([]string{
+		fmt.Sprintf("Path: %s", entry.Path),
+		fmt.Sprintf("Created: %s", entry.Timestamp.Format("2006-01-02 15:04:05")),
+		fmt.Sprintf("Profile: %s", valueOrDash(entry.Profile)),
+		fmt.Sprintf("Template: %s", valueOrDash(entry.Template)),
+		fmt.Sprintf("Git: %t", entry.Git),
+		fmt.Sprintf("Flags: %s", valueOrDash(strings.Join(entry.Flags, " "))),
+	})
+
+	if manifestErr != nil {
+		outputMgr.Info("No manifest recorded for this entry")
+		if len(entry.Files) > 0 {
+			outputMgr.Section("Generated Files")
+			outputMgr.List(entry.Files)
+		}
+		return nil
+	}
+
+	if len(manifest.Templates) > 0 {
+		outputMgr.Section("Templates")
+		rows := [][]string{}
+		for _, ref := range manifest.Templates {
+			rows = append(rows, []string{ref.Name, ref.Version})
+		}
+		outputMgr.Table([]string{"Template", "Version"}, rows)
+	}
+
+	outputMgr.Section("Generated Files")
+	rows := [][]string{}
+	for _, file := range manifest.Files {
+		rows = append(rows, []string{file.Path, file.Source, file.SHA256[:12], utils.FormatBytes(file.Size)})
+	}
+	outputMgr.Table([]string{"File", "Source", "SHA-256", "Size"}, rows)
+	return nil
+}
+
+// runHistoryPrune removes old, stale and surplus entries from the history
+func runHistoryPrune(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	var cutoff time.Time
+	if historyOlderThan != "" {
+		age, err := utils.ParseAge(historyOlderThan)
+		if err != nil {
+			return fmt.Errorf("invalid --older-than: %w", err)
+		}
+		cutoff = time.Now().Add(-age)
+	}
+	cmd.SilenceUsage = true
+
+	store, err := newHistoryStore(cfg)
+	if err != nil {
+		return err
+	}
+
+	stale := func(path string, last time.Time) bool {
+		if !cutoff.IsZero() && last.Before(cutoff) {
+			return true
+		}
+		return historyMissing && !utils.PathExists(path)
+	}
+	pruned, err := store.Prune(func(entry history.Entry) bool {
+		return stale(entry.Path, entry.Timestamp)
+	}, dryRun)
+	if err != nil {
+		return err
+	}
+	visits := 0
+	if !cutoff.IsZero() || historyMissing {
+		visits, err = store.PruneVisits(func(path string, visit history.Visit) bool {
+			return stale(path, visit.Last)
+		}, dryRun)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(pruned) == 0 && visits == 0 {
+		outputMgr.Info("Nothing to prune")
+		return nil
+	}
+
+	verb := "Removed"
+	if dryRun {
+		verb = "[DRY RUN] Would remove"
+	}
+	for _, entry := range pruned {
+		outputMgr.Verbose(fmt.Sprintf("%s %s %s (%s)", verb, valueOrDash(entry.ID), entry.Path, entry.Timestamp.Format("2006-01-02 15:04")))
+	}
+	summary := fmt.Sprintf("%s %d history entry(s) and %d visited directory(s)", verb, len(pruned), visits)
+	if dryRun {
+		outputMgr.Info(summary)
+	} else {
+		outputMgr.Success(summary)
+	}
+	return nil
+}
+
+// runHistoryExport prints the recorded creations matching the filters
+func runHistoryExport(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if !slices.Contains(history.GetExportFormats(), historyExportFormat) {
+		return fmt.Errorf("invalid export format '%s' (expected %s)", historyExportFormat, strings.Join(history.GetExportFormats(), " or "))
+	}
+	filter, err := historyFilter(args)
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	store, err := newHistoryStore(cfg)
+	if err != nil {
+		return err
+	}
+	entries, err := store.Load()
+	if err != nil {
+		return err
+	}
+
+	// The export is machine-readable output, printed as-is to stdout
+	return history.WriteExport(os.Stdout, historyExportFormat, filter.Apply(entries), utils.Now())
+}
+
+// runHistoryImport merges an exported history into the recorded one
+func runHistoryImport(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	source := args[0]
+	format := historyImportFormat
+	if format == "" {
+		format = history.ExportJSON
+		if strings.EqualFold(filepath.Ext(source), ".csv") {
+			format = history.ExportCSV
+		}
+	}
+	if !slices.Contains(history.GetExportFormats(), format) {
+		return fmt.Errorf("invalid import format '%s' (expected %s)", format, strings.Join(history.GetExportFormats(), " or "))
+	}
+	cmd.SilenceUsage = true
+
+	input := os.Stdin
+	if source != "-" {
+		file, err := os.Open(source)
+		if err != nil {
+			return fmt.Errorf("failed to open export: %w", err)
+		}
+		defer file.Close()
+		input = file
+	}
+	entries, err := history.ReadExport(input, format)
+	if err != nil {
+		return fmt.Errorf("failed to import %s: %w", source, err)
+	}
+
+	store, err := newHistoryStore(cfg)
+	if err != nil {
+		return err
+	}
+	added, err := store.Import(entries, dryRun)
+	if err != nil {
+		return err
+	}
+
+	skipped := len(entries) - len(added)
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would import %d history entry(s), %d already recorded", len(added), skipped))
+		return nil
+	}
+	outputMgr.Success(fmt.Sprintf("Imported %d history entry(s), %d already recorded", len(added), skipped))
+	if cfg.Core.HistoryLimit > 0 && len(added) > 0 {
+		outputMgr.Verbose(fmt.Sprintf("Only the latest %d entries are kept (core.history_limit)", cfg.Core.HistoryLimit))
+	}
+	return nil
+}
+
+// newHistoryStore opens the history store limited by core.history_limit
+func newHistoryStore(cfg *config.Config) (*history.Store, error) {
+	path, err := history.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+
+	return history.NewStore(path, cfg.Core.HistoryLimit), nil
+}
+
+// recordHistory appends a completed mkcd operation to the history together
+// with a manifest of the generated files, which core.workspace_manifest
+// also writes into the workspace. A history limit of 0 disables recording.
+func recordHistory(targetPath string, cfg *config.Config, mkcdConfig MkcdConfig, fsOps *utils.FileSystemOperations) error {
+	if cfg.Core.HistoryLimit == 0 && !cfg.Core.WorkspaceManifest {
+		return nil
+	}
+
+	// Only attribute the operation to profiles that actually exist
+	profileName := ""
+	if _, exists := cfg.Profiles[mkcdConfig.Profile]; exists {
+		profileName = mkcdConfig.Profile
+	}
+
+	// The history keeps real times even in deterministic mode, which only
+	// fixes what is printed
+	timestamp := time.Now()
+	entry := history.Entry{
+		ID:        history.NewID(timestamp, targetPath),
+		Timestamp: timestamp,
+		Path:      targetPath,
+		Profile:   profileName,
+		Template:  mkcdConfig.Template,
+		Git:       mkcdConfig.Git,
+		Flags:     mkcdConfig.Flags,
+	}
+
+	manifest := buildManifest(entry, cfg, mkcdConfig, fsOps)
+	for _, file := range manifest.Files {
+		entry.Files = append(entry.Files, file.Path)
+	}
+
+	// A symlink's manifest would land in its target
+	if cfg.Core.WorkspaceManifest && mkcdConfig.Symlink == "" {
+		if err := history.WriteWorkspaceManifest(manifest); err != nil {
+			return err
+		}
+	}
+	if cfg.Core.HistoryLimit == 0 {
+		return nil
+	}
+
+	store, err := newHistoryStore(cfg)
+	if err != nil {
+		return err
+	}
+	if err := store.SaveManifest(manifest); err != nil {
+		return fmt.Errorf("failed to record manifest: %w", err)
+	}
+
+	if err := store.Append(entry); err != nil {
+		return fmt.Errorf("failed to record history: %w", err)
+	}
+	return nil
+}
+
+// changedFlags returns the flags set on the command line, in order of
+// their names: "--name" for booleans and "--name=value" otherwise, with an
+// entry for every value of repeated flags
+func changedFlags(cmd *cobra.Command) []string {
+	flags := []string{}
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		switch value := flag.Value.(type) {
+		case pflag.SliceValue:
+			for _, item := range value.GetSlice() {
+				flags = append(flags, fmt.Sprintf("--%s=%s", flag.Name, item))
+			}
+		default:
+			if flag.Value.Type() == "bool" && flag.Value.String() == "true" {
+				flags = append(flags, "--"+flag.Name)
+			} else {
+				flags = append(flags, fmt.Sprintf("--%s=%s", flag.Name, flag.Value.String()))
+			}
+		}
+	})
+	return flags
+}
+
+// buildManifest describes the files written during the operation as they
+// are on disk now, after every pipeline step has run
+func buildManifest(entry history.Entry, cfg *config.Config, mkcdConfig MkcdConfig, fsOps *utils.FileSystemOperations) *history.Manifest {
+	manifest := &history.Manifest{
+		ID:        entry.ID,
+		Path:      entry.Path,
+		Created:   entry.Timestamp,
+		Profile:   entry.Profile,
+		Templates: []history.TemplateRef{},
+		Files:     []history.FileRef{},
+		Template:  entry.Template,
+		Variables: mkcdConfig.TemplateVars,
+		Flags:     entry.Flags,
+	}
+
+	// The last writer of a file is its source; every writer counts as used
+	sources := map[string]string{}
+	usedSources := map[string]bool{}
+	for _, record := range fsOps.Records() {
+		sources[record.Path] = record.Source
+		usedSources[record.Source] = true
+	}
+
+	for _, path := range utils.SortedKeys(sources) {
+		checksum, size, err := history.FileChecksum(path)
+		if err != nil {
+			// Files removed by later steps (e.g. hooks) are not part of the result
+			continue
+		}
+
+		manifest.Files = append(manifest.Files, history.FileRef{
+			Path:   manifestPath(entry.Path, path),
+			Source: sources[path],
+			SHA256: checksum,
+			Size:   size,
+		})
+	}
+
+	for _, dir := range fsOps.Directories() {
+		manifest.Directories = append(manifest.Directories, manifestPath(entry.Path, dir))
+	}
+	for _, link := range fsOps.Symlinks() {
+		manifest.Symlinks = append(manifest.Symlinks, manifestPath(entry.Path, link))
+	}
+
+	// A repository in an existing directory may predate the creation
+	if entry.Git && slices.Contains(fsOps.Directories(), entry.Path) && utils.IsDirectory(filepath.Join(entry.Path, ".git")) {
+		manifest.Git = true
+		if info, err := git.NewGitManager(false, false, "", "").GetRepositoryInfo(entry.Path); err == nil && info.LastCommit != nil {
+			manifest.GitHead = info.LastCommit.Hash
+		}
+	}
+
+	// Templates extended by the named ones wrote files too
+	names := templates.ParseNames(mkcdConfig.Template)
+	for _, source := range utils.SortedKeys(usedSources) {
+		if name, found := strings.CutPrefix(source, "template:"); found && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range names {
+		if !usedSources["template:"+name] {
+			continue
+		}
+		version := "unknown"
+		if dir, err := templates.LocalDir(cfg.Templates.Directory, templateSource(cfg, name)); err == nil {
+			if v, err := templates.Version(dir); err == nil {
+				version = v
+			}
+		}
+		manifest.Templates = append(manifest.Templates, history.TemplateRef{Name: name, Version: version})
+	}
+
+	return manifest
+}
+
+// manifestPath returns path as recorded in the manifest of a creation of
+// root: slash-separated and relative to root, or absolute outside it
+func manifestPath(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = path
+	}
+	return filepath.ToSlash(rel)
+}
+
+// valueOrDash returns the value, or "-" when it is empty
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,193 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/journal"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// journalDate is the day to open instead of today
+var journalDate string
+
+// journalCmd represents the journal command
+var journalCmd = &cobra.Command{
+	Use:   "journal",
+	Short: "Create and change into today's journal directory",
+	Long: `Create the directory of a day under the journal root and change into it,
+e.g. ~/notes/2025/06/10. A new directory gets a daily note, and the latest
+symlink in the root points to the newest day.
+
+The [journal] section of the configuration sets the root, the layout of
+the dated directories as a Go time layout, the note file and the template
+it is rendered from. Note templates may use {{.Date}} (2025-06-10),
+{{.Title}} (Tuesday, June 10, 2025), {{.Weekday}}, {{.Year}}, {{.Month}},
+{{.Day}} and {{.Path}}. Existing directories and notes are left alone.
+
+Examples:
+  mkcd journal                             # Today's directory
+  mkcd journal --date yesterday            # Yesterday's directory
+  mkcd journal --date 2025-06-10           # The directory of a given day`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{changesDirAnnotation: "true"},
+	RunE:        runJournal,
+}
+
+func init() {
+	rootCmd.AddCommand(journalCmd)
+
+	journalCmd.Flags().StringVar(&journalDate, "date", "", "day to open: YYYY-MM-DD, yesterday or tomorrow (default: today)")
+	journalCmd.Flags().BoolVar(&printPath, "print-path", false, "print only the journal directory on stdout, all other output on stderr")
+	registerFlagCompletion(journalCmd, "date", completeValues([]string{"today", "yesterday", "tomorrow"}))
+}
+
+// runJournal creates the directory of a day with its note and changes
+// into it
+func runJournal(cmd *cobra.Command, args []string) error {
+	// Keep stdout free for the path
+	if printPath {
+		redirectOutput(os.Stderr)
+	}
+
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	date, err := journal.ParseDate(journalDate, utils.Now())
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	root, err := utils.ExpandPath(cfg.Journal.Root)
+	if err != nil {
+		return fmt.Errorf("failed to expand journal root: %w", err)
+	}
+	dir := journal.Dir(root, cfg.Journal.Layout, date)
+
+	pathValidator := utils.NewPathValidator(cfg.Safety.ForbiddenPaths, cfg.Safety.MaxDepth)
+	if err := pathValidator.ValidatePath(dir); err != nil {
+		if !force {
+			return fmt.Errorf("path validation failed: %w", err)
+		}
+		outputMgr.Warning(fmt.Sprintf("Path validation failed but continuing due to --force: %v", err))
+	}
+
+	fsOps := utils.NewFileSystemOperations(dryRun, backup || cfg.Core.BackupEnabled)
+	fsOps.Output = outputMgr
+	fsOps.Encoding = fileEncoding(cfg)
+	fsOps.EnableRetry(retryPolicy(cfg, outputMgr))
+	fsOps.EnforceModes = cfg.Safety.EnforceModes
+
+	existed := utils.IsDirectory(dir)
+	if err := fsOps.CreateDirectory(dir, 0755); err != nil {
+		return err
+	}
+
+	if note := cfg.Journal.Note; note != "" && !utils.PathExists(filepath.Join(dir, note)) {
+		content, err := renderJournalNote(cfg.Journal.NoteTemplate, date, dir)
+		if err != nil {
+			return err
+		}
+		if err := fsOps.CreateFile(filepath.Join(dir, note), content, 0644); err != nil {
+			return err
+		}
+	}
+
+	// The journal is usable without the link, so a failure only warns
+	if cfg.Journal.Latest != "" {
+		if err := linkLatestJournal(filepath.Join(root, cfg.Journal.Latest), root, dir, cfg.Journal.Layout, fsOps, outputMgr); err != nil {
+			outputMgr.Warning(err.Error())
+		}
+	}
+
+	if dryRun {
+		reportDryRunChanges(dir, fsOps, outputMgr)
+	}
+	if !existed {
+		return generateShellScript(dir, cfg, outputMgr, nil)
+	}
+
+	if !dryRun {
+		if err := emitPath(dir); err != nil {
+			return err
+		}
+		if cfg.Output.TerminalCwd {
+			outputMgr.ReportWorkingDirectory(dir)
+		}
+	}
+	if !printPath {
+		outputMgr.Info(fmt.Sprintf("Journal: %s", dir))
+	}
+	return nil
+}
+
+// renderJournalNote renders the daily note of date from templateFile, or
+// from journal.DefaultNote when it is empty
+func renderJournalNote(templateFile string, date time.Time, dir string) (string, error) {
+	text := journal.DefaultNote
+	if templateFile != "" {
+		path, err := utils.ExpandPath(templateFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to expand journal note template path: %w", err)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read journal note template: %w", err)
+		}
+		text = string(content)
+	}
+
+	return journal.RenderNote(text, date, dir)
+}
+
+// linkLatestJournal points the link to dir, unless it already points to
+// the directory of a later day. Anything but a symlink is left alone.
+func linkLatestJournal(link, root, dir, layout string, fsOps *utils.FileSystemOperations, outputMgr *utils.OutputManager) error {
+	if info, err := os.Lstat(link); err == nil {
+		if info.Mode()&os.ModeSymlink == 0 {
+			return fmt.Errorf("%s exists and is not a symlink; not linking the latest journal directory", link)
+		}
+		target, err := os.Readlink(link)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", link, err)
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(root, target)
+		}
+		if target == dir {
+			return nil
+		}
+
+		// Compare days, as layouts such as 02/01/2006 do not sort by date
+		current, currentErr := journal.Day(root, layout, target)
+		day, dayErr := journal.Day(root, layout, dir)
+		if currentErr == nil && dayErr == nil && current > day {
+			outputMgr.Debug(fmt.Sprintf("%s points to the later %s", link, target))
+			return nil
+		}
+	}
+
+	return fsOps.CreateSymlink(dir, link)
+}
@@ -0,0 +1,206 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/history"
+	"github.com/mochajutsu/mkcd/internal/shell"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// jumpList lists the ranked matches instead of changing into the best one
+var jumpList bool
+
+// jumpBaseScore keeps directories only known from the registry or the pins
+// reachable, below anything created or visited
+const jumpBaseScore = 0.1
+
+// jumpCmd represents the jump command
+var jumpCmd = &cobra.Command{
+	Use:   "jump <query>...",
+	Short: "Change into the most used workspace matching a query",
+	Long: `Change into the workspace created or visited through mkcd that best
+matches the query, ranked by frecency: how often and how recently it was
+used, like zoxide but limited to the workspaces mkcd knows.
+
+The last word is matched against the directory or project name: the same
+name ranks highest, then a name starting with it, containing it, or
+containing its letters in order. Earlier words must appear anywhere in the
+path. Creations count as uses, and so does every jump and 'mkcd open'.
+
+The path is emitted like a created one, so with the shell integration the
+shell changes into it:
+  cd "$(mkcd jump api --print-path)"
+
+Examples:
+  mkcd jump api                            # The most used workspace named like api
+  mkcd jump work api                       # ... with "work" in its path
+  mkcd jump api --list                     # Show the ranking`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeWorkspaces,
+	Annotations:       map[string]string{changesDirAnnotation: "true"},
+	RunE:              runJump,
+}
+
+func init() {
+	rootCmd.AddCommand(jumpCmd)
+
+	jumpCmd.Flags().BoolVarP(&jumpList, "list", "l", false, "list the matching workspaces with their scores")
+	jumpCmd.Flags().BoolVar(&printPath, "print-path", false, "print only the workspace path on stdout, all other output on stderr")
+}
+
+// jumpMatch is a workspace matching a jump query with its score
+type jumpMatch struct {
+	path  string
+	score float64
+}
+
+// runJump changes into the best match of the query
+func runJump(cmd *cobra.Command, args []string) error {
+	// Keep stdout free for the path
+	if printPath {
+		redirectOutput(os.Stderr)
+	}
+
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	cmd.SilenceUsage = true
+	matches := jumpMatches(args, cfg)
+	if len(matches) == 0 {
+		return fmt.Errorf("no workspace created or visited through mkcd matches '%s'", strings.Join(args, " "))
+	}
+
+	if jumpList {
+		rows := [][]string{}
+		for _, match := range matches {
+			rows = append(rows, []string{fmt.Sprintf("%.1f", match.score), match.path})
+		}
+		outputMgr.Table([]string{"Score", "Path"}, rows)
+		return nil
+	}
+
+	dir := matches[0].path
+	outputMgr.Verbose(fmt.Sprintf("'%s' matches %s", strings.Join(args, " "), dir))
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would change into %s", dir))
+		return nil
+	}
+
+	recordVisit(dir, cfg, outputMgr)
+	if err := emitPath(dir); err != nil {
+		return err
+	}
+	if cfg.Output.TerminalCwd {
+		outputMgr.ReportWorkingDirectory(dir)
+	}
+
+	if !printPath && !quiet {
+		if file, _ := pathFD(); file == nil {
+			shellName := shell.Detect()
+			if shellName == "" {
+				shellName = shell.Bash
+			}
+			outputMgr.Info("To change to the directory, run: " + shell.ChangeDirCommand(shellName, dir))
+		}
+	}
+	return nil
+}
+
+// jumpMatches returns the existing workspaces matching the query words,
+// best first. Their frecency is multiplied by how well the last word
+// matches the name; ties keep the pinned, then most recent first.
+func jumpMatches(words []string, cfg *config.Config) []jumpMatch {
+	frecencies := map[string]float64{}
+	if store, err := newHistoryStore(cfg); err == nil {
+		entries, _ := store.Load()
+		visits, _ := store.LoadVisits()
+		frecencies = history.Frecencies(entries, visits, utils.Now())
+	}
+
+	names := registeredNames()
+	used := usedDirectories(cfg)
+	// Directories opened by path are only known from their visits
+	for path := range frecencies {
+		if _, known := used[path]; !known {
+			used[path] = time.Time{}
+		}
+	}
+	paths := []string{}
+	for path := range used {
+		if utils.IsDirectory(path) {
+			paths = append(paths, path)
+		}
+	}
+	sortWorkspaces(paths, used)
+
+	query := words[len(words)-1]
+	matches := []jumpMatch{}
+	for _, path := range paths {
+		if !containsAll(path, words[:len(words)-1]) {
+			continue
+		}
+		nameScore := max(matchScore(query, filepath.Base(path)), matchScore(query, workspaceName(path, names)))
+		if nameScore == 0 {
+			continue
+		}
+		matches = append(matches, jumpMatch{path: path, score: (frecencies[path] + jumpBaseScore) * float64(nameScore)})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+	return matches
+}
+
+// containsAll reports whether path contains every word, ignoring case
+func containsAll(path string, words []string) bool {
+	path = strings.ToLower(path)
+	for _, word := range words {
+		if !strings.Contains(path, strings.ToLower(word)) {
+			return false
+		}
+	}
+	return true
+}
+
+// recordVisit counts a change into dir for the frecency of 'mkcd jump'.
+// Visits are not recorded when the history is disabled, and failing to
+// record one only shows in verbose output.
+func recordVisit(dir string, cfg *config.Config, outputMgr *utils.OutputManager) {
+	if cfg.Core.HistoryLimit == 0 {
+		return
+	}
+
+	store, err := newHistoryStore(cfg)
+	if err == nil {
+		err = store.RecordVisit(dir, time.Now())
+	}
+	if err != nil {
+		outputMgr.Verbose(fmt.Sprintf("Visit not recorded: %v", err))
+	}
+}
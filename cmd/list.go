@@ -0,0 +1,132 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/git"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// listLimit caps the workspaces listed
+var listLimit int
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recent workspaces with their current state",
+	Long: `List the existing workspaces known on this machine, like 'mkcd find',
+with their current state: how long ago they were last used, their size on
+disk, the Git branch with a * when there are uncommitted or untracked
+changes, and the template they were created with.
+
+Pinned workspaces come first, followed by the most recently used. Sizes
+and Git states are read on every run, so --limit keeps large listings
+fast.
+
+Examples:
+  mkcd list                                # The 20 most recent workspaces
+  mkcd list --limit 0                      # All workspaces`,
+	Args: cobra.NoArgs,
+	RunE: runList,
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+
+	listCmd.Flags().IntVar(&listLimit, "limit", 20, "show at most this many workspaces (0 for all)")
+}
+
+// runList lists the recent workspaces with their size, Git state and template
+func runList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	if listLimit < 0 {
+		return fmt.Errorf("--limit cannot be negative")
+	}
+
+	used := usedDirectories(cfg)
+	paths := []string{}
+	for path := range used {
+		if utils.IsDirectory(path) {
+			paths = append(paths, path)
+		}
+	}
+	sortWorkspaces(paths, used)
+	if listLimit > 0 && len(paths) > listLimit {
+		paths = paths[:listLimit]
+	}
+
+	if len(paths) == 0 {
+		outputMgr.Info("No workspaces recorded yet")
+		return nil
+	}
+
+	names := registeredNames()
+	templates := workspaceTemplates(cfg)
+	gitMgr := git.NewGitManager(false, false, "", "")
+	now := utils.Now()
+	rows := [][]string{}
+	for _, path := range paths {
+		age := "-"
+		if !used[path].IsZero() {
+			age = utils.FormatAge(now.Sub(used[path]))
+		}
+
+		size := "-"
+		if bytes, err := utils.GetDirectorySize(path); err == nil {
+			size = utils.FormatBytes(bytes)
+		} else {
+			outputMgr.Verbose(fmt.Sprintf("Size of %s unknown: %v", path, err))
+		}
+
+		branch := "-"
+		if utils.PathExists(filepath.Join(path, ".git")) {
+			if info, err := gitMgr.GetRepositoryInfo(path); err == nil {
+				branch = valueOrDash(info.CurrentBranch)
+				if info.Dirty {
+					branch += "*"
+				}
+			}
+		}
+
+		rows = append(rows, []string{workspaceName(path, names), age, size, branch, valueOrDash(templates[path]), path})
+	}
+	outputMgr.Table([]string{"Name", "Age", "Size", "Git", "Template", "Path"}, rows)
+	return nil
+}
+
+// workspaceTemplates returns the template of the latest recorded creation
+// of every path. An unreadable history yields none.
+func workspaceTemplates(cfg *config.Config) map[string]string {
+	templates := make(map[string]string)
+	if store, err := newHistoryStore(cfg); err == nil {
+		if entries, err := store.Load(); err == nil {
+			// Entries are oldest first, so later creations win
+			for _, entry := range entries {
+				templates[entry.Path] = entry.Template
+			}
+		}
+	}
+	return templates
+}
@@ -7,14 +7,27 @@ Licensed under the MIT License. See LICENSE file for details.
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/mochajutsu/mkcd/internal/config"
 	"github.com/mochajutsu/mkcd/internal/editor"
 	"github.com/mochajutsu/mkcd/internal/files"
 	"github.com/mochajutsu/mkcd/internal/git"
+	"github.com/mochajutsu/mkcd/internal/history"
+	"github.com/mochajutsu/mkcd/internal/hooks"
+	"github.com/mochajutsu/mkcd/internal/project"
+	"github.com/mochajutsu/mkcd/internal/shell"
+	"github.com/mochajutsu/mkcd/internal/tasks"
+	"github.com/mochajutsu/mkcd/internal/templates"
 	"github.com/mochajutsu/mkcd/internal/utils"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
@@ -26,14 +39,27 @@ var (
 	gitInit      bool
 	gitRemote    string
 	template     string
+	templateConflict string
+	templateVars     []string
+	templateVarFile  string
 	editorName   string
 	editorFlag   bool
 
 	// File creation flags
 	touchFiles  []string
-	readme      bool
-	gitignore   string
-	license     string
+	readme        bool
+	gitignore     string
+	license       string
+	gitattributes string
+	generate      []string
+
+	// Pipeline options
+	skipSteps []string
+
+	// Initial commit options
+	noInitialCommit bool
+	commitMessage   string
+	commitContent   string
 
 	// Advanced options
 	mode       string
@@ -41,11 +67,30 @@ var (
 	symlink    string
 	temp       bool
 	expire     string
+	asUser     string
+	then       string
+	noInput    bool
+
+	// Shell integration
+	printPath   bool
+	cdOnFailure string
+	spawn       bool
+	physical    bool
+
+	// Timing breakdown
+	timeSteps bool
+
+	// Machine-readable result
+	jsonOutput bool
 )
 
+// errOperationCancelled is returned by executeMkcd when the user declines
+// the confirmation prompt
+var errOperationCancelled = errors.New("operation cancelled by user")
+
 // mkcdCmd represents the mkcd command
 var mkcdCmd = &cobra.Command{
-	Use:   "mkcd <directory>",
+	Use:   "mkcd <directory>...",
 	Short: "Create directory and prepare workspace",
 	Long: `Create a directory and prepare it for immediate use with optional workspace initialization.
 
@@ -53,7 +98,7 @@ The mkcd command creates a directory (with parent directories as needed) and can
 • Initialize a Git repository with remote setup
 • Apply project templates for different languages/frameworks  
 • Open the directory in your preferred editor
-• Generate common files (README, .gitignore, LICENSE)
+• Generate common files (README, .gitignore, LICENSE, .gitattributes)
 • Set up symbolic links or temporary directories
 
 Examples:
@@ -61,10 +106,62 @@ Examples:
   mkcd myproject --git                     # Create with Git repository
   mkcd myproject --git --remote origin    # Create with Git and remote
   mkcd myproject --template nodejs        # Create using Node.js template
+  mkcd myproject --template go,docker     # Layer the docker template over go
   mkcd myproject --profile dev             # Create using 'dev' profile
   mkcd myproject --editor                  # Create and open in editor
-  mkcd myproject --readme --gitignore go   # Create with README and Go .gitignore`,
-	Args: cobra.ExactArgs(1),
+  mkcd myproject --readme --gitignore go   # Create with README and Go .gitignore
+  mkcd myproject --gitattributes lf        # Enforce LF line endings via .gitattributes
+  mkcd myproject --profile dev --skip-step editor  # Use 'dev' profile without opening an editor
+  mkcd api web worker --profile dev        # Batch: create several directories
+  sudo mkcd /srv/work/alice --as-user alice  # Provision a directory owned by alice
+  mkcd myproject --spawn                   # Start a shell inside, without shell integration
+  mkcd app --template nodejs --then "npm install && npm test"  # Run a command inside afterwards
+
+Steps run in the order dirs → touch → template → files → git → hooks → editor
+unless a profile specifies its own 'steps' order.
+
+Comma-separated templates are layered in order. When several provide the
+same file, --template-conflict (or templates.conflict) decides: 'override'
+(alias 'overwrite') writes the last one, 'keep' (alias 'skip') the first,
+and 'error' aborts; .gitignore-style files are merged instead. With
+--verbose or --dry-run, the merged plan lists the template behind every
+file and the conflicts it resolved.
+
+With several directories, a failing directory does not stop the others. A
+summary of succeeded, failed and skipped directories is printed at the end,
+and the exit code is 2 when only some of them failed.
+
+When a step fails after the directory was created, --cd-on-failure (or
+core.cd_on_failure) decides where the shell wrapper changes to: 'stay' in
+the current directory, the 'partial' directory, or the nearest existing
+'parent'. The path is still written to the channels below.
+
+Inside an existing project (a Git repository, project file or language
+marker such as go.mod above the new directory), generators without an
+option follow its conventions: --generate gitignore and ci use its
+language, license its license and editorconfig its indentation. Explicit
+options win; files.detect_conventions = false turns this off.
+
+Invocations working on the same directory never interleave, neither with
+each other nor with undo, rename-project, template apply or freeze on it:
+the second one waits up to core.lock_timeout for the first to finish, then
+aborts.
+
+Scripts can capture the created paths without parsing the decorated output:
+--print-path prints only the paths on stdout (everything else goes to
+stderr), and MKCD_PATH_FD=<fd> writes them, one per line, to an open file
+descriptor while the regular output is unchanged:
+  dir=$(mkcd mkcd build --print-path)
+  MKCD_PATH_FD=3 mkcd mkcd api web 3>paths.txt
+
+GUI frontends get progress events as JSON lines, one per started and
+finished pipeline step or remote template fetch and per progress update,
+on stdout with --progress-json or on the descriptor in MKCD_PROGRESS_FD:
+  {"event":"started","step":"fetch","target":"https://...","percent":0,...}
+  {"event":"progress","step":"fetch","target":"https://...","percent":45,"message":"Receiving objects",...}
+  {"event":"finished","step":"git","target":"/home/me/app","percent":100,"duration_ms":12,...}
+The "pipeline" step reports the share of steps done for each directory.`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: runMkcd,
 }
 
@@ -74,7 +171,11 @@ func init() {
 	// Workspace setup flags
 	mkcdCmd.Flags().BoolVar(&gitInit, "git", false, "initialize git repository")
 	mkcdCmd.Flags().StringVar(&gitRemote, "git-remote", "", "add remote origin URL")
-	mkcdCmd.Flags().StringVarP(&template, "template", "t", "", "apply project template")
+	mkcdCmd.Flags().StringVarP(&template, "template", "t", "", "apply project template(s), comma-separated to layer in order; Git URLs, gh:org/repo and tar.gz or zip archives are fetched, cookiecutter:<template> applies cookiecutter templates")
+	mkcdCmd.Flags().StringVar(&templateConflict, "template-conflict", "", "when layered templates provide the same file: override, keep or error (aliases: overwrite, skip)")
+	mkcdCmd.Flags().BoolVar(&noInput, "no-input", false, "never prompt for template variables; required variables without a value fail")
+	mkcdCmd.Flags().StringArrayVar(&templateVars, "var", []string{}, "set a template variable, name=value (repeatable; wins over --var-file)")
+	mkcdCmd.Flags().StringVar(&templateVarFile, "var-file", "", "read template variables from a TOML or JSON file")
 	mkcdCmd.Flags().StringVarP(&editorName, "editor", "e", "", "open in editor (specify editor or leave empty for auto-detect)")
 	mkcdCmd.Flags().BoolVar(&editorFlag, "open-editor", false, "open in editor (auto-detect)")
 
@@ -83,6 +184,8 @@ func init() {
 	mkcdCmd.Flags().BoolVar(&readme, "readme", false, "generate README.md")
 	mkcdCmd.Flags().StringVar(&gitignore, "gitignore", "", "generate .gitignore for language/framework")
 	mkcdCmd.Flags().StringVar(&license, "license", "", "generate LICENSE file")
+	mkcdCmd.Flags().StringVar(&gitattributes, "gitattributes", "", "generate .gitattributes with line-ending policy (auto, lf, crlf)")
+	mkcdCmd.Flags().StringSliceVar(&generate, "generate", []string{}, "run named generator(s), e.g. editorconfig or ci:go (see 'mkcd generators list')")
 
 	// Advanced options
 	mkcdCmd.Flags().StringVar(&mode, "mode", "", "set directory permissions (e.g., 755)")
@@ -90,15 +193,61 @@ func init() {
 	mkcdCmd.Flags().StringVarP(&symlink, "symlink", "s", "", "create as symlink to target")
 	mkcdCmd.Flags().BoolVar(&temp, "temp", false, "create in temporary directory")
 	mkcdCmd.Flags().StringVar(&expire, "expire", "", "auto-delete after duration (1h, 30m, etc.)")
+	mkcdCmd.Flags().StringVar(&asUser, "as-user", "", "create the directory owned by this user and their default group (requires root; not with --then)")
+	mkcdCmd.Flags().StringVar(&then, "then", "", "run a shell command inside the directory after all steps; a failure is reported but keeps the directory")
+
+	// Initial commit options
+	mkcdCmd.Flags().BoolVar(&noInitialCommit, "no-initial-commit", false, "initialize git without creating an initial commit")
+	mkcdCmd.Flags().StringVar(&commitMessage, "commit-message", "", "initial commit message template (e.g. 'chore: scaffold {{.ProjectName}}')")
+	mkcdCmd.Flags().StringVar(&commitContent, "commit-content", "", "initial commit content: all or gitignore")
+
+	// Pipeline options
+	mkcdCmd.Flags().StringSliceVar(&skipSteps, "skip-step", []string{}, "skip pipeline step(s): dirs, touch, template, files, git, hooks, editor")
+
+	// Timing breakdown
+	mkcdCmd.Flags().BoolVar(&timeSteps, "time", false, "print how long validation and each pipeline step took")
+	mkcdCmd.Flags().BoolVar(&jsonOutput, "json", false, "print a JSON summary of the created directories on stdout, all other output on stderr")
+
+	// Shell integration
+	mkcdCmd.Flags().BoolVar(&printPath, "print-path", false, "print only the created path on stdout, all other output on stderr (used by 'mkcd shell-init')")
+	mkcdCmd.Flags().BoolVar(&physical, "physical", false, "resolve symlinks in the created path instead of keeping the logical path from $PWD")
+	mkcdCmd.Flags().BoolVar(&spawn, "spawn", false, "start a shell in the new directory (core.spawn_shell or $SHELL), for use without shell integration")
+	mkcdCmd.Flags().StringVar(&cdOnFailure, "cd-on-failure", "", "where the shell wrapper changes to when creation fails midway: "+strings.Join(shell.GetCdOnFailurePolicies(), ", ")+" (default from core.cd_on_failure)")
 
 	// Mark some flags as mutually exclusive
 	mkcdCmd.MarkFlagsMutuallyExclusive("symlink", "temp")
 	mkcdCmd.MarkFlagsMutuallyExclusive("git-remote", "symlink")
+	mkcdCmd.MarkFlagsMutuallyExclusive("spawn", "print-path")
+	mkcdCmd.MarkFlagsMutuallyExclusive("json", "print-path")
+	// --then would run as root and leave what it writes owned by root
+	mkcdCmd.MarkFlagsMutuallyExclusive("as-user", "then")
+
+	// Complete flag values from the configuration and the supported values
+	fileGen := files.NewFileGenerator(nil, false, false)
+	registerFlagCompletion(mkcdCmd, "template", completeTemplates)
+	registerFlagCompletion(mkcdCmd, "template-conflict", completeValues(templates.GetAvailableConflictStrategies()))
+	registerFlagCompletion(mkcdCmd, "gitignore", completeValues(fileGen.GetAvailableGitignoreTypes()))
+	registerFlagCompletion(mkcdCmd, "license", completeValues(fileGen.GetAvailableLicenseTypes()))
+	registerFlagCompletion(mkcdCmd, "gitattributes", completeValues(fileGen.GetAvailableGitattributesPolicies()))
+	registerFlagCompletion(mkcdCmd, "cd-on-failure", completeValues(shell.GetCdOnFailurePolicies()))
 }
 
 // runMkcd executes the main mkcd functionality
 func runMkcd(cmd *cobra.Command, args []string) error {
-	dirName := args[0]
+	// Keep stdout free for the path consumed by the shell wrapper, or for
+	// the JSON summary
+	if printPath || jsonOutput {
+		redirectOutput(os.Stderr)
+	}
+
+	// Fail before creating anything when the path or progress channel is
+	// unusable
+	if _, err := pathFD(); err != nil {
+		return err
+	}
+	if _, err := progressEmitter(); err != nil {
+		return err
+	}
 
 	// Load configuration
 	cfg, err := config.Load(cfgFile)
@@ -132,17 +281,174 @@ func runMkcd(cmd *cobra.Command, args []string) error {
 		debug,
 	)
 
-	// Create filesystem operations manager
-	fsOps := utils.NewFileSystemOperations(dryRun, backup || cfg.Core.BackupEnabled)
-
 	// Create path validator
 	pathValidator := utils.NewPathValidator(cfg.Safety.ForbiddenPaths, cfg.Safety.MaxDepth)
 
 	// Merge command flags with profile settings
 	mergedConfig := mergeConfigWithFlags(profileConfig)
+	if mergedConfig.TemplateVars, err = templateVariables(); err != nil {
+		return err
+	}
+	mergedConfig.Profile = profile
+	if mergedConfig.Profile == "" {
+		mergedConfig.Profile = cfg.Core.DefaultProfile
+	}
+	mergedConfig.Flags = changedFlags(cmd)
+
+	if len(args) > 1 && spawn {
+		return fmt.Errorf("--spawn needs a single directory")
+	}
+
+	if len(args) > 1 {
+		// Per-directory errors are reported in the summary, not with usage help
+		cmd.SilenceUsage = true
+		err := runMkcdBatch(args, cfg, mergedConfig, outputMgr, pathValidator)
+		if jsonOutput {
+			// The directories that were created are listed even if others failed
+			if jsonErr := printSummariesJSON(); jsonErr != nil && err == nil {
+				err = jsonErr
+			}
+		}
+		return err
+	}
+
+	// Only a single directory can be changed into after a failure
+	mergedConfig.CdOnFailure = cdOnFailure
+	if mergedConfig.CdOnFailure == "" {
+		mergedConfig.CdOnFailure = cfg.Core.CdOnFailure
+	}
+	switch mergedConfig.CdOnFailure {
+	case "", shell.CdStay, shell.CdPartial, shell.CdParent:
+	default:
+		return fmt.Errorf("invalid --cd-on-failure '%s' (expected %s)", mergedConfig.CdOnFailure, strings.Join(shell.GetCdOnFailurePolicies(), ", "))
+	}
 
 	// Execute the mkcd operation
-	return executeMkcd(dirName, cfg, mergedConfig, outputMgr, fsOps, pathValidator)
+	started := utils.Now()
+	fsOps := newMkcdFileSystemOperations(cfg, outputMgr)
+	err = executeMkcd(args[0], cfg, mergedConfig, outputMgr, fsOps, pathValidator)
+	if !errors.Is(err, errOperationCancelled) {
+		results := utils.NewBatchSummary()
+		if err != nil {
+			results.Failed(args[0], err)
+		} else {
+			results.Succeeded(args[0])
+		}
+		notifyRun(cfg, notifyCreate, "creation of "+args[0], started, results, err, outputMgr)
+	}
+
+	// The hints already explain what to do, usage help would bury them
+	var notWritable *utils.NotWritableError
+	var locked *utils.LockedError
+	var failedThen *thenError
+	if errors.As(err, &notWritable) || errors.As(err, &locked) || errors.As(err, &failedThen) {
+		cmd.SilenceUsage = true
+	}
+
+	if err != nil && !errors.Is(err, errOperationCancelled) {
+		return err
+	}
+
+	if jsonOutput {
+		if err := printSummariesJSON(); err != nil {
+			return err
+		}
+	}
+
+	if spawn && err == nil {
+		return spawnShell(args[0], mergedConfig, cfg, outputMgr)
+	}
+	return nil
+}
+
+// spawnShell starts a shell in the created directory, replacing mkcd
+func spawnShell(dirName string, mkcdConfig MkcdConfig, cfg *config.Config, outputMgr *utils.OutputManager) error {
+	targetPath, err := determineTargetPath(dirName, mkcdConfig, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to determine target path: %w", err)
+	}
+
+	shellPath := cfg.Core.SpawnShell
+	if shellPath == "" {
+		shellPath = utils.DefaultShell()
+	}
+
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would start %s in %s", shellPath, targetPath))
+		return nil
+	}
+
+	outputMgr.Info(fmt.Sprintf("Starting %s in %s (exit to return)", shellPath, targetPath))
+	return utils.SpawnShell(shellPath, targetPath)
+}
+
+// runMkcdBatch creates several directories, collecting per-directory
+// results instead of stopping at the first failure
+func runMkcdBatch(dirNames []string, cfg *config.Config, mkcdConfig MkcdConfig, outputMgr *utils.OutputManager, pathValidator *utils.PathValidator) error {
+	started := utils.Now()
+	summary := utils.NewBatchSummary()
+	seen := make(map[string]bool)
+
+	for _, dirName := range dirNames {
+		if seen[dirName] {
+			summary.Skipped(dirName, "duplicate argument")
+			continue
+		}
+		seen[dirName] = true
+
+		// The output of each directory is grouped in its own section,
+		// including what packages print through pterm directly
+		task := outputMgr.Task(dirName)
+		release := task.Capture()
+
+		// Each directory gets its own operations so history and manifests stay separate
+		fsOps := newMkcdFileSystemOperations(cfg, task)
+		err := executeMkcd(dirName, cfg, mkcdConfig, task, fsOps, pathValidator)
+		switch {
+		case errors.Is(err, errOperationCancelled):
+			summary.Skipped(dirName, "cancelled by user")
+		case err != nil:
+			task.Error(err.Error())
+			summary.Failed(dirName, err)
+		default:
+			summary.Succeeded(dirName)
+		}
+
+		release()
+		task.Flush()
+	}
+
+	summary.Print(outputMgr)
+	err := summary.Err()
+	notifyRun(cfg, notifyBatch, fmt.Sprintf("creation of %d directories", len(seen)), started, summary, err, outputMgr)
+	return err
+}
+
+// newMkcdFileSystemOperations creates the filesystem operations for one directory
+func newMkcdFileSystemOperations(cfg *config.Config, outputMgr *utils.OutputManager) *utils.FileSystemOperations {
+	fsOps := utils.NewFileSystemOperations(dryRun, backup || cfg.Core.BackupEnabled)
+	fsOps.Output = outputMgr
+	fsOps.Encoding = fileEncoding(cfg)
+	fsOps.EnableRetry(retryPolicy(cfg, outputMgr))
+	fsOps.EnforceModes = cfg.Safety.EnforceModes
+	return fsOps
+}
+
+// reportModeMismatches lists, in verbose output, the created paths whose
+// permissions differ from the requested mode
+func reportModeMismatches(fsOps *utils.FileSystemOperations, outputMgr *utils.OutputManager) {
+	mismatches := fsOps.ModeMismatches()
+	if len(mismatches) == 0 {
+		return
+	}
+
+	outputMgr.Verbose(fmt.Sprintf("Permissions of %d path(s) differ from the requested mode, likely due to the umask:", len(mismatches)))
+	for _, mismatch := range mismatches {
+		outputMgr.Verbose("  " + mismatch.String())
+	}
+	if !fsOps.EnforceModes {
+		outputMgr.Verbose("Set [safety] enforce_modes = true to apply the requested modes exactly")
+	}
 }
 
 // mergeConfigWithFlags merges profile configuration with command-line flags
@@ -151,16 +457,29 @@ func mergeConfigWithFlags(profileConfig config.ProfileConfig) MkcdConfig {
 		Git:       gitInit || profileConfig.Git,
 		GitRemote: gitRemote,
 		Template:  template,
+		TemplateConflict: templateConflict,
 		Editor:    editorFlag || profileConfig.Editor || (editorName != ""),
 		Readme:    readme || profileConfig.Readme,
 		Gitignore: gitignore,
 		License:   license,
+		Gitattributes: gitattributes,
+		EOL:       profileConfig.EOL,
+		Generate:  generate,
 		Touch:     touchFiles,
 		Mode:      mode,
 		ParentMode: parentMode,
 		Symlink:   symlink,
 		Temp:      temp,
 		Expire:    expire,
+		AsUser:    asUser,
+		Then:      then,
+		Steps:     profileConfig.Steps,
+		SkipSteps: append(append([]string{}, profileConfig.SkipSteps...), skipSteps...),
+		Hooks:     profileConfig.Hooks,
+		NoInitialCommit: noInitialCommit,
+		CommitMessage:   commitMessage,
+		CommitContent:   commitContent,
+		Physical:        physical,
 	}
 
 	// Use profile values if command flags are empty
@@ -173,28 +492,127 @@ func mergeConfigWithFlags(profileConfig config.ProfileConfig) MkcdConfig {
 	if merged.License == "" {
 		merged.License = profileConfig.License
 	}
+	if merged.Gitattributes == "" {
+		merged.Gitattributes = profileConfig.Gitattributes
+	}
 	if len(merged.Touch) == 0 {
 		merged.Touch = profileConfig.Touch
 	}
+	if len(merged.Generate) == 0 {
+		merged.Generate = profileConfig.Generate
+	}
+
+	// The editor would open for the admin, not for the user receiving the directory
+	if merged.AsUser != "" {
+		merged.Editor = false
+	}
 
 	return merged
 }
 
+// fileEncoding returns the encoding of generated files from the [files] config
+func fileEncoding(cfg *config.Config) utils.Encoding {
+	return utils.Encoding{
+		LineEndings: cfg.Files.LineEndings,
+		BOM:         cfg.Files.BOM,
+	}
+}
+
+// retryPolicy returns the retry policy for transient filesystem errors from
+// the [files] config, logging every retry as verbose output
+func retryPolicy(cfg *config.Config, outputMgr *utils.OutputManager) utils.RetryPolicy {
+	delay := utils.DefaultRetryDelay
+	if parsed, err := time.ParseDuration(cfg.Files.RetryDelay); err == nil {
+		delay = parsed
+	}
+
+	return utils.RetryPolicy{
+		Attempts: cfg.Files.RetryAttempts,
+		Delay:    delay,
+		Log:      outputMgr.Verbose,
+	}
+}
+
+// lockTarget acquires the lock of targetPath, waiting up to
+// core.lock_timeout for another invocation to finish with it
+func lockTarget(targetPath string, cfg *config.Config, outputMgr *utils.OutputManager) (*utils.PathLock, error) {
+	stateDir, err := history.StateDir()
+	if err != nil {
+		return nil, err
+	}
+	lockDir := filepath.Join(stateDir, "locks")
+
+	lock, err := utils.LockPath(lockDir, targetPath, 0)
+	var locked *utils.LockedError
+	if !errors.As(err, &locked) {
+		return lock, err
+	}
+
+	// Validate has already checked the value
+	wait, _ := time.ParseDuration(cfg.Core.LockTimeout)
+	if wait <= 0 {
+		return nil, err
+	}
+
+	outputMgr.Info(fmt.Sprintf("Waiting up to %s: %s", utils.FormatDuration(wait), locked.Error()))
+	return utils.LockPath(lockDir, targetPath, wait)
+}
+
 // MkcdConfig represents the merged configuration for mkcd operation
 type MkcdConfig struct {
 	Git        bool
 	GitRemote  string
 	Template   string
+	TemplateConflict string
+
+	// TemplateVars are values for template variables, which are then
+	// not asked for
+	TemplateVars map[string]string
+
 	Editor     bool
 	Readme     bool
 	Gitignore  string
 	License    string
+	Gitattributes string
+	EOL        map[string]string
+	Generate   []string
 	Touch      []string
 	Mode       string
 	ParentMode string
 	Symlink    string
 	Temp       bool
 	Expire     string
+	AsUser     string
+	Then       string
+	Steps      []string
+	SkipSteps  []string
+	Hooks      []string
+	Profile    string
+
+	// Initial commit overrides; empty values fall back to the [git] config
+	NoInitialCommit bool
+	CommitMessage   string
+	CommitContent   string
+
+	// CdOnFailure is where the shell wrapper changes to when a pipeline
+	// step fails; empty for batches, which never change directory
+	CdOnFailure string
+
+	// Timings collects the duration of validation and every pipeline step
+	// when set (--time, benchmark)
+	Timings *utils.Timings
+
+	// TargetLocked is set when the caller already holds the lock of the
+	// target path (blueprints)
+	TargetLocked bool
+
+	// Physical resolves symlinks in the target path instead of keeping the
+	// logical path the shell shows in $PWD
+	Physical bool
+
+	// Flags are the command-line flags set for the operation, recorded in
+	// the history
+	Flags []string
 }
 
 // executeMkcd performs the actual mkcd operation
@@ -205,12 +623,45 @@ func executeMkcd(dirName string, cfg *config.Config, mkcdConfig MkcdConfig, outp
 		return fmt.Errorf("failed to determine target path: %w", err)
 	}
 
-	// Validate path
-	if err := pathValidator.ValidatePath(targetPath); err != nil {
-		if !force {
-			return fmt.Errorf("path validation failed: %w", err)
+	// Serialize invocations working on the same directory
+	if !dryRun && !mkcdConfig.TargetLocked {
+		lock, err := lockTarget(targetPath, cfg, outputMgr)
+		if err != nil {
+			return err
+		}
+		defer lock.Unlock()
+	}
+
+	// With --time or usage metrics, each run gets its own breakdown
+	timings := mkcdConfig.Timings
+	if timings == nil && (timeSteps || cfg.Telemetry.Enabled) {
+		timings = utils.NewTimings()
+		if cfg.Telemetry.Enabled {
+			usageTimings = append(usageTimings, timings)
+		}
+	}
+
+	if cfg.Files.DetectConventions {
+		if conventions := project.DetectConventions(filepath.Dir(targetPath)); conventions.Root != "" {
+			outputMgr.Verbose(fmt.Sprintf("Following the conventions of %s: language %s, license %s, indent %s",
+				conventions.Root, valueOrDash(conventions.Language), valueOrDash(conventions.License), valueOrDash(conventions.Indent)))
+		}
+	}
+
+	err = runTimed("validation", timings, outputMgr, func() error {
+		return validateTarget(targetPath, mkcdConfig, cfg, outputMgr, pathValidator)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Resolve the user receiving the directory before anything is created
+	var handover *ownerHandover
+	if mkcdConfig.AsUser != "" {
+		handover, err = newOwnerHandover(targetPath, mkcdConfig.AsUser)
+		if err != nil {
+			return err
 		}
-		outputMgr.Warning(fmt.Sprintf("Path validation failed but continuing due to --force: %v", err))
 	}
 
 	// Check for interactive confirmation if needed
@@ -221,55 +672,241 @@ func executeMkcd(dirName string, cfg *config.Config, mkcdConfig MkcdConfig, outp
 		}
 		if !confirmed {
 			outputMgr.Info("Operation cancelled by user")
-			return nil
+			return errOperationCancelled
 		}
 	}
 
-	// Create directory structure
-	if err := createDirectoryStructure(targetPath, mkcdConfig, fsOps, outputMgr); err != nil {
-		return fmt.Errorf("failed to create directory structure: %w", err)
+	// Resolve the ordered pipeline steps
+	steps, err := resolvePipelineSteps(mkcdConfig.Steps, mkcdConfig.SkipSteps)
+	if err != nil {
+		return fmt.Errorf("invalid pipeline configuration: %w", err)
 	}
+	outputMgr.Verbose(fmt.Sprintf("Pipeline: %s", strings.Join(stepNames(steps), " → ")))
 
-	// Generate files if requested
-	if err := generateProjectFiles(targetPath, mkcdConfig, cfg, fsOps, outputMgr); err != nil {
-		return fmt.Errorf("failed to generate project files: %w", err)
+	// Run the pipeline
+	pc := &pipelineContext{
+		targetPath: targetPath,
+		cfg:        cfg,
+		mkcdConfig: mkcdConfig,
+		outputMgr:  outputMgr,
+		fsOps:      fsOps,
+		timings:    timings,
 	}
+	if err := runPipeline(steps, pc); err != nil {
+		emitFailurePath(targetPath, mkcdConfig.CdOnFailure, outputMgr)
+		return err
+	}
+	reportModeMismatches(fsOps, outputMgr)
 
-	// Initialize Git repository if requested
-	if mkcdConfig.Git {
-		gitMgr := git.NewGitManager(dryRun, verbose, cfg.Git.UserName, cfg.Git.UserEmail)
-		if err := gitMgr.InitRepository(targetPath, cfg.Git.DefaultBranch); err != nil {
-			return fmt.Errorf("failed to initialize Git repository: %w", err)
+	if handover != nil {
+		if err := handover.apply(fsOps, outputMgr); err != nil {
+			emitFailurePath(targetPath, mkcdConfig.CdOnFailure, outputMgr)
+			return err
 		}
+	}
 
-		// Add remote if specified
-		if mkcdConfig.GitRemote != "" {
-			if err := gitMgr.AddRemote(targetPath, cfg.Git.DefaultRemoteName, mkcdConfig.GitRemote); err != nil {
-				return fmt.Errorf("failed to add Git remote: %w", err)
-			}
-		}
+	showCreatedTree(targetPath, cfg, fsOps, outputMgr)
 
-		// Create initial commit if there are files
-		if err := gitMgr.CreateInitialCommit(targetPath, "Initial commit"); err != nil {
-			outputMgr.Warning(fmt.Sprintf("Failed to create initial commit: %v", err))
-		}
+	if dryRun {
+		reportDryRunChanges(targetPath, fsOps, outputMgr)
+	} else if err := recordHistory(targetPath, cfg, mkcdConfig, fsOps); err != nil {
+		// History is informational, so a failure must not fail the operation
+		outputMgr.Warning(err.Error())
 	}
 
-	// Open in editor if requested
-	if mkcdConfig.Editor {
-		if err := openInEditor(targetPath, mkcdConfig, outputMgr); err != nil {
-			outputMgr.Warning(fmt.Sprintf("Failed to open in editor: %v", err))
+	if !dryRun && cfg.Core.ProjectFile && mkcdConfig.Symlink == "" {
+		// The registry is informational as well
+		if err := registerProject(targetPath); err != nil {
+			outputMgr.Warning(err.Error())
 		}
 	}
 
+	// Tasks are a convenience, so a failure only warns
+	if err := createSetupTasks(targetPath, cfg, mkcdConfig, outputMgr); err != nil {
+		outputMgr.Warning(err.Error())
+	}
+
+	// The benchmark collects timings itself
+	if timeSteps && mkcdConfig.Timings == nil {
+		timings.Print(outputMgr)
+	}
+
+	// The directory stays when the follow-up command fails, so the shell
+	// still changes into it before the failure is returned
+	thenErr := runThenCommand(targetPath, mkcdConfig.Then, outputMgr)
+
+	summary := buildCreationSummary(targetPath, cfg, mkcdConfig)
+	if jsonOutput {
+		createdSummaries = append(createdSummaries, summary)
+	}
+
 	// Generate shell script for cd operation
-	if err := generateShellScript(targetPath, outputMgr); err != nil {
+	if err := generateShellScript(targetPath, cfg, outputMgr, &summary); err != nil {
 		return fmt.Errorf("failed to generate shell script: %w", err)
 	}
 
+	return thenErr
+}
+
+// createSetupTasks records the configured setup tasks for a new project in
+// the task manager of integrations.tasks, when its profile is listed
+func createSetupTasks(targetPath string, cfg *config.Config, mkcdConfig MkcdConfig, outputMgr *utils.OutputManager) error {
+	settings := cfg.Integrations.Tasks
+	if settings.Backend == "" || len(settings.Tasks) == 0 {
+		return nil
+	}
+	if len(settings.Profiles) > 0 && !slices.Contains(settings.Profiles, mkcdConfig.Profile) {
+		return nil
+	}
+
+	setupTasks, err := tasks.Render(settings.Tasks, tasks.Data{
+		ProjectName: filepath.Base(targetPath),
+		Profile:     mkcdConfig.Profile,
+		Path:        targetPath,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create tasks: %w", err)
+	}
+
+	if dryRun {
+		for _, task := range setupTasks {
+			outputMgr.Info(fmt.Sprintf("[DRY RUN] Would add task: %s", task.Description))
+		}
+		return nil
+	}
+
+	file, err := utils.ExpandPath(settings.File)
+	if err != nil {
+		return fmt.Errorf("failed to expand tasks file path: %w", err)
+	}
+	if err := tasks.Add(settings.Backend, file, setupTasks, utils.Now()); err != nil {
+		return fmt.Errorf("failed to create tasks: %w", err)
+	}
+	for _, task := range setupTasks {
+		outputMgr.Verbose(fmt.Sprintf("Added task: %s", task.Description))
+	}
+	return nil
+}
+
+// showCreatedTree prints the tree of entries in the new directory, or of
+// the entries a dry run would create, up to output.tree_depth levels deep
+func showCreatedTree(targetPath string, cfg *config.Config, fsOps *utils.FileSystemOperations, outputMgr *utils.OutputManager) {
+	depth := cfg.Output.TreeDepth
+	if depth == 0 {
+		return
+	}
+
+	var entries []utils.TreeEntry
+	if dryRun {
+		entries = utils.ChangeEntries(targetPath, fsOps.DryRunChanges(), depth)
+	} else {
+		entries = utils.DirectoryEntries(targetPath, depth)
+	}
+
+	// An empty directory needs no picture
+	if len(entries) == 0 {
+		return
+	}
+	outputMgr.Section("Structure")
+	outputMgr.Tree(filepath.Base(targetPath), entries)
+}
+
+// reportDryRunChanges lists the entries the dry run created in memory
+func reportDryRunChanges(targetPath string, fsOps *utils.FileSystemOperations, outputMgr *utils.OutputManager) {
+	changes := fsOps.DryRunChanges()
+	if len(changes) == 0 {
+		outputMgr.Info("Dry run finished: no changes would be made")
+		return
+	}
+
+	rows := [][]string{}
+	for _, change := range changes {
+		path := change.Path
+		if rel, err := filepath.Rel(targetPath, change.Path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = filepath.Join(filepath.Base(targetPath), rel)
+		}
+
+		kind, size := "file", utils.FormatBytes(change.Size)
+		switch {
+		case change.IsDir:
+			kind, size = "dir", "-"
+		case change.Mode&os.ModeSymlink != 0:
+			kind, size = "symlink", "-"
+		}
+
+		rows = append(rows, []string{path, kind, fmt.Sprintf("%o", change.Mode.Perm()), size})
+	}
+
+	outputMgr.Section("Dry Run Result")
+	outputMgr.Table([]string{"Path", "Type", "Mode", "Size"}, rows)
+}
+
+// validateTarget checks the target path before anything is created: path
+// rules, write access and the space needed for templates
+func validateTarget(targetPath string, mkcdConfig MkcdConfig, cfg *config.Config, outputMgr *utils.OutputManager, pathValidator *utils.PathValidator) error {
+	if err := pathValidator.ValidatePath(targetPath); err != nil {
+		if !force {
+			return fmt.Errorf("path validation failed: %w", err)
+		}
+		outputMgr.Warning(fmt.Sprintf("Path validation failed but continuing due to --force: %v", err))
+	}
+
+	if err := checkNotFrozen(targetPath); err != nil {
+		return err
+	}
+
+	if err := checkRootTarget(targetPath, mkcdConfig, cfg); err != nil {
+		if !force {
+			return err
+		}
+		outputMgr.Warning(fmt.Sprintf("%v; continuing due to --force", err))
+	}
+
+	// Fail before the pipeline when the target cannot be created at all
+	if err := checkTargetWritable(targetPath, outputMgr); err != nil {
+		return err
+	}
+
+	// Warn before large scaffolds that would not fit
+	if mkcdConfig.Template != "" {
+		checkScaffoldSpace(targetPath, mkcdConfig, cfg, outputMgr)
+	}
 	return nil
 }
 
+// checkRootTarget refuses to create directories as root outside system
+// directories, which after a stray sudo leaves root-owned files in a home
+// directory. Handing the directory over with --as-user is fine.
+func checkRootTarget(targetPath string, mkcdConfig MkcdConfig, cfg *config.Config) error {
+	if os.Geteuid() != 0 || mkcdConfig.AsUser != "" || cfg.Safety.AllowRoot || utils.IsSystemPath(targetPath) {
+		return nil
+	}
+	return fmt.Errorf("refusing to create %s as root outside system directories (use --as-user, --force or set [safety] allow_root = true)", targetPath)
+}
+
+// checkTargetWritable reports a read-only or inaccessible target with
+// remediation hints. A dry run only warns, since nothing is written.
+func checkTargetWritable(targetPath string, outputMgr *utils.OutputManager) error {
+	err := utils.CheckWritable(targetPath)
+
+	var notWritable *utils.NotWritableError
+	if !errors.As(err, &notWritable) {
+		// Anything else surfaces with more context when creating the directory
+		return nil
+	}
+
+	if dryRun {
+		outputMgr.Warning("[DRY RUN] " + notWritable.Error())
+	}
+	outputMgr.Info("To fix this:")
+	outputMgr.List(notWritable.Hints())
+
+	if dryRun {
+		return nil
+	}
+	return notWritable
+}
+
 // determineTargetPath determines the final target path based on configuration
 func determineTargetPath(dirName string, mkcdConfig MkcdConfig, cfg *config.Config) (string, error) {
 	var targetPath string
@@ -282,8 +919,8 @@ func determineTargetPath(dirName string, mkcdConfig MkcdConfig, cfg *config.Conf
 		}
 		targetPath = filepath.Join(tempDir, dirName)
 	} else {
-		// Use current directory as base
-		cwd, err := os.Getwd()
+		// Use current directory as base, as the shell shows it
+		cwd, err := utils.WorkingDir(mkcdConfig.Physical)
 		if err != nil {
 			return "", fmt.Errorf("failed to get current directory: %w", err)
 		}
@@ -296,6 +933,13 @@ func determineTargetPath(dirName string, mkcdConfig MkcdConfig, cfg *config.Conf
 		return "", fmt.Errorf("failed to get absolute path: %w", err)
 	}
 
+	// The temp directory and the argument itself may contain symlinks as well
+	if mkcdConfig.Physical {
+		if absPath, err = utils.PhysicalPath(absPath); err != nil {
+			return "", fmt.Errorf("failed to resolve symlinks: %w", err)
+		}
+	}
+
 	return absPath, nil
 }
 
@@ -304,9 +948,12 @@ func createDirectoryStructure(targetPath string, mkcdConfig MkcdConfig, fsOps *u
 	// Determine directory mode
 	dirMode := os.FileMode(0755) // Default
 	if mkcdConfig.Mode != "" {
-		// Parse mode from string (e.g., "755")
-		// This is a simplified implementation
-		outputMgr.Debug(fmt.Sprintf("Custom mode specified: %s", mkcdConfig.Mode))
+		parsed, err := utils.ParseFileMode(mkcdConfig.Mode)
+		if err != nil {
+			return err
+		}
+		dirMode = parsed
+		outputMgr.Debug(fmt.Sprintf("Custom mode specified: %04o", dirMode))
 	}
 
 	// Handle symlink creation
@@ -315,11 +962,16 @@ func createDirectoryStructure(targetPath string, mkcdConfig MkcdConfig, fsOps *u
 	}
 
 	// Create directory
-	if err := fsOps.CreateDirectory(targetPath, dirMode); err != nil {
-		return err
+	return fsOps.CreateDirectory(targetPath, dirMode)
+}
+
+// createTouchFiles creates the empty files specified in touch
+func createTouchFiles(targetPath string, mkcdConfig MkcdConfig, fsOps *utils.FileSystemOperations, outputMgr *utils.OutputManager) error {
+	// Symlinked workspaces point at existing content
+	if mkcdConfig.Symlink != "" {
+		return nil
 	}
 
-	// Create files specified in touch
 	for _, fileName := range mkcdConfig.Touch {
 		filePath := filepath.Join(targetPath, fileName)
 		if err := fsOps.CreateFile(filePath, "", 0644); err != nil {
@@ -332,38 +984,564 @@ func createDirectoryStructure(targetPath string, mkcdConfig MkcdConfig, fsOps *u
 
 // generateProjectFiles generates project files based on configuration
 func generateProjectFiles(targetPath string, mkcdConfig MkcdConfig, cfg *config.Config, fsOps *utils.FileSystemOperations, outputMgr *utils.OutputManager) error {
-	// Create file generator
+	// Create file generator and the registry of available generators
 	fileGen := files.NewFileGenerator(fsOps, dryRun, verbose)
+	registry, err := newGeneratorRegistry(cfg, fileGen)
+	if err != nil {
+		return err
+	}
 
-	// Create generation context
+	ctx := newGenerationContext(targetPath, mkcdConfig, cfg)
+	for _, spec := range generatorSpecs(mkcdConfig) {
+		name, option := files.ParseGeneratorSpec(spec)
+		outputMgr.Debug(fmt.Sprintf("Running generator %s (option: %q)", name, option))
+		fsOps.SetSource("generator:" + name)
+		if err := registry.Run(ctx, name, option); err != nil {
+			return fmt.Errorf("generator %s failed: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// newGenerationContext creates the project context shared by the file
+// generators and templates
+func newGenerationContext(targetPath string, mkcdConfig MkcdConfig, cfg *config.Config) *files.GenerationContext {
 	ctx := files.NewGenerationContext(targetPath)
 	ctx.Author = cfg.Git.UserName
 	ctx.Email = cfg.Git.UserEmail
+	ctx.License = mkcdConfig.License
+	ctx.GitRemote = mkcdConfig.GitRemote
+	ctx.Profile = mkcdConfig.Profile
+	ctx.EOL = mkcdConfig.EOL
 
-	// Generate README if requested
-	if mkcdConfig.Readme {
-		if err := fileGen.GenerateReadme(ctx); err != nil {
-			return fmt.Errorf("failed to generate README: %w", err)
+	// Inside an existing project, generators without an option follow it.
+	// The new directory itself may already hold a project file.
+	if cfg.Files.DetectConventions {
+		conventions := project.DetectConventions(filepath.Dir(targetPath))
+		ctx.Language = conventions.Language
+		ctx.Indent = conventions.Indent
+		if ctx.License == "" {
+			ctx.License = conventions.License
 		}
 	}
+	return ctx
+}
 
-	// Generate .gitignore if requested
-	if mkcdConfig.Gitignore != "" {
-		if err := fileGen.GenerateGitignore(ctx, mkcdConfig.Gitignore); err != nil {
-			return fmt.Errorf("failed to generate .gitignore: %w", err)
+// generatorSpecs returns the generators to run, in order, for the merged configuration
+func generatorSpecs(mkcdConfig MkcdConfig) []string {
+	specs := []string{}
+	seen := make(map[string]bool)
+
+	add := func(spec string) {
+		name, _ := files.ParseGeneratorSpec(spec)
+		if name == "" || seen[name] {
+			return
 		}
+		seen[name] = true
+		specs = append(specs, spec)
 	}
 
-	// Generate LICENSE if requested
+	if mkcdConfig.Readme {
+		add("readme")
+	}
+	if mkcdConfig.Gitignore != "" {
+		add("gitignore:" + mkcdConfig.Gitignore)
+	}
 	if mkcdConfig.License != "" {
-		if err := fileGen.GenerateLicense(ctx, mkcdConfig.License); err != nil {
-			return fmt.Errorf("failed to generate LICENSE: %w", err)
+		add("license:" + mkcdConfig.License)
+	}
+	if mkcdConfig.Gitattributes != "" {
+		add("gitattributes:" + mkcdConfig.Gitattributes)
+	}
+	for _, spec := range mkcdConfig.Generate {
+		add(spec)
+	}
+
+	return specs
+}
+
+// initializeGitRepository initializes a Git repository, adds the remote and
+// creates the initial commit if Git is enabled
+func initializeGitRepository(targetPath string, mkcdConfig MkcdConfig, cfg *config.Config, outputMgr *utils.OutputManager) error {
+	if !mkcdConfig.Git {
+		return nil
+	}
+
+	gitMgr := git.NewGitManager(dryRun, verbose, cfg.Git.UserName, cfg.Git.UserEmail)
+	gitMgr.Retry = retryPolicy(cfg, outputMgr)
+	if err := gitMgr.InitRepository(targetPath, cfg.Git.DefaultBranch); err != nil {
+		return fmt.Errorf("failed to initialize Git repository: %w", err)
+	}
+
+	// Add remote if specified
+	if mkcdConfig.GitRemote != "" {
+		if err := gitMgr.AddRemote(targetPath, cfg.Git.DefaultRemoteName, mkcdConfig.GitRemote); err != nil {
+			return fmt.Errorf("failed to add Git remote: %w", err)
+		}
+	}
+
+	// Create initial commit if enabled. With --as-user the commit would carry
+	// the admin's identity, so the first commit is left to the user.
+	if mkcdConfig.AsUser != "" {
+		outputMgr.Verbose(fmt.Sprintf("Skipping initial commit: the repository belongs to %s", mkcdConfig.AsUser))
+		return nil
+	}
+	if mkcdConfig.NoInitialCommit || !cfg.Git.InitialCommit {
+		outputMgr.Verbose("Skipping initial commit")
+		return nil
+	}
+
+	message, commitFiles, err := initialCommitSettings(targetPath, mkcdConfig, cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := gitMgr.CreateInitialCommitWithFiles(targetPath, message, commitFiles); err != nil {
+		outputMgr.Warning(fmt.Sprintf("Failed to create initial commit: %v", err))
+	}
+
+	return nil
+}
+
+// initialCommitSettings returns the rendered initial commit message and the
+// files to include (nil for all files) from flags and the [git] config
+func initialCommitSettings(targetPath string, mkcdConfig MkcdConfig, cfg *config.Config) (string, []string, error) {
+	messageTemplate := mkcdConfig.CommitMessage
+	if messageTemplate == "" {
+		messageTemplate = cfg.Git.InitialCommitMessage
+	}
+
+	message, err := git.RenderCommitMessage(messageTemplate, git.CommitMessageData{
+		ProjectName: filepath.Base(targetPath),
+		Profile:     mkcdConfig.Profile,
+		Template:    mkcdConfig.Template,
+		Branch:      cfg.Git.DefaultBranch,
+		Author:      cfg.Git.UserName,
+		Email:       cfg.Git.UserEmail,
+		Date:        utils.Now().Format("2006-01-02"),
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	if cfg.Git.ConventionalCommits {
+		if err := git.ValidateConventionalCommit(message); err != nil {
+			return "", nil, err
+		}
+	}
+
+	content := mkcdConfig.CommitContent
+	if content == "" {
+		content = cfg.Git.InitialCommitContent
+	}
+
+	switch content {
+	case "", "all":
+		return message, nil, nil
+	case "gitignore":
+		return message, []string{".gitignore"}, nil
+	default:
+		return "", nil, fmt.Errorf("invalid initial commit content '%s' (expected all or gitignore)", content)
+	}
+}
+
+// applyTemplate applies the configured templates to the target directory.
+// Several comma-separated templates are layered in order.
+func applyTemplate(targetPath string, mkcdConfig MkcdConfig, cfg *config.Config, fsOps *utils.FileSystemOperations, outputMgr *utils.OutputManager) error {
+	names := templates.ParseNames(mkcdConfig.Template)
+	if len(names) == 0 {
+		return nil
+	}
+
+	conflict := mkcdConfig.TemplateConflict
+	if conflict == "" {
+		conflict = cfg.Templates.Conflict
+	}
+	applier, err := templates.NewApplier(fsOps, conflict, verbose)
+	if err != nil {
+		return err
+	}
+	applier.Partials = cfg.GetPartialsDirectory()
+	applier.Data = templates.NewData(newGenerationContext(targetPath, mkcdConfig, cfg))
+	applier.Data.Git = mkcdConfig.Git
+	applier.Data.Readme = mkcdConfig.Readme
+	applier.Data.Gitignore = mkcdConfig.Gitignore
+	applier.RenderAll = cfg.Templates.Render != "tmpl"
+	applier.Locate = templateLocator(cfg, outputMgr)
+	applier.Vars = mkcdConfig.TemplateVars
+	applier.Ask = templateAsker(outputMgr)
+
+	layers := []templates.Layer{}
+	for _, name := range names {
+		source, cookiecutter := templates.CookiecutterName(templateSource(cfg, name))
+		if templates.IsRemote(source) || templates.IsArchive(source) {
+			// Only the cache is written, so remote templates are fetched in dry runs too
+			templateDir, err := fetchRemoteTemplate(source, cfg, false, outputMgr)
+			if err != nil {
+				return err
+			}
+			layers = append(layers, templates.Layer{Name: name, Dir: templateDir, Cookiecutter: cookiecutter})
+			continue
+		}
+
+		templateDir, err := templates.Find(cfg.Templates.Directory, source)
+		if err != nil {
+			outputMgr.Warning(fmt.Sprintf("Template '%s' not found in %s, skipping", source, cfg.Templates.Directory))
+			continue
+		}
+		layers = append(layers, templates.Layer{Name: name, Dir: templateDir, Cookiecutter: cookiecutter})
+	}
+
+	if len(layers) > 1 {
+		outputMgr.Verbose(fmt.Sprintf("Layering templates %s (conflicts: %s)", strings.Join(names, " → "), applier.Conflict))
+	}
+
+	if err := applier.Apply(targetPath, layers); err != nil {
+		return err
+	}
+	if len(layers) > 1 && (verbose || fsOps.DryRun) && !fsOps.Silent {
+		printTemplateComposition(applier.Plan, outputMgr)
+	}
+	return runTemplateHooks(targetPath, applier.Hooks, cfg, fsOps, outputMgr)
+}
+
+// printTemplateComposition shows which template provides every file of a
+// composition and how conflicts between the templates were resolved
+func printTemplateComposition(plan []templates.PlannedFile, outputMgr *utils.OutputManager) {
+	rows := [][]string{}
+	for _, file := range plan {
+		resolution := []string{}
+		if len(file.Replaced) > 0 {
+			resolution = append(resolution, "overrides "+strings.Join(file.Replaced, ", "))
+		}
+		if len(file.Skipped) > 0 {
+			resolution = append(resolution, "skipped "+strings.Join(file.Skipped, ", "))
+		}
+		if len(file.Merged) > 0 {
+			resolution = append(resolution, "merged with "+strings.Join(file.Merged, ", "))
+		}
+		rows = append(rows, []string{file.Path, file.Template, valueOrDash(strings.Join(resolution, "; "))})
+	}
+
+	outputMgr.Section("Template Plan")
+	outputMgr.Table([]string{"File", "Template", "Conflicts"}, rows)
+}
+
+// runTemplateHooks runs the hooks declared by the applied templates inside
+// the target directory, if hooks.templates and the user allow it. Dry runs
+// list them instead.
+func runTemplateHooks(targetPath string, appliedHooks []templates.AppliedHook, cfg *config.Config, fsOps *utils.FileSystemOperations, outputMgr *utils.OutputManager) error {
+	if len(appliedHooks) == 0 {
+		return nil
+	}
+	if fsOps.DryRun {
+		if !fsOps.Silent {
+			for _, hook := range appliedHooks {
+				outputMgr.Info(fmt.Sprintf("[DRY RUN] Would run hook of template %s: %s", hook.Template, hook.Run))
+			}
+		}
+		return nil
+	}
+
+	confirmed, err := confirmTemplateHooks(appliedHooks, cfg, outputMgr)
+	if err != nil || !confirmed {
+		return err
+	}
+
+	runner := newHookRunner(cfg)
+	for _, hook := range appliedHooks {
+		hookRunner := *runner
+		if hook.Timeout > 0 {
+			hookRunner.Timeout = hook.Timeout
+		}
+		if err := runHook(&hookRunner, targetPath, hook.Run, outputMgr); err != nil {
+			return fmt.Errorf("template %s: %w", hook.Template, err)
 		}
 	}
+	return nil
+}
+
+// confirmTemplateHooks reports whether the hooks of the applied templates
+// may run. Templates are not trusted, so unless hooks.templates is
+// "always" or --force is given, the user confirms them on a terminal;
+// without one they are skipped.
+func confirmTemplateHooks(appliedHooks []templates.AppliedHook, cfg *config.Config, outputMgr *utils.OutputManager) (bool, error) {
+	switch {
+	case cfg.Hooks.Templates == "never":
+		outputMgr.Verbose(fmt.Sprintf("Not running %d template hooks (hooks.templates is never)", len(appliedHooks)))
+		return false, nil
+	case cfg.Hooks.Templates == "always", force:
+		return true, nil
+	case noInput || quiet || !utils.IsTerminal(os.Stdin):
+		outputMgr.Warning(fmt.Sprintf("Skipped %d template hooks: confirm them on a terminal, pass --force or set hooks.templates to always", len(appliedHooks)))
+		return false, nil
+	}
+
+	commands := make([]string, len(appliedHooks))
+	for i, hook := range appliedHooks {
+		commands[i] = fmt.Sprintf("%s (%s)", hook.Run, hook.Template)
+	}
+	outputMgr.Info("The templates declare these hooks:")
+	outputMgr.List(commands)
+
+	confirmed, err := outputMgr.Confirm("Run them in the new directory?", false)
+	if err != nil {
+		return false, fmt.Errorf("failed to get confirmation: %w", err)
+	}
+	if !confirmed {
+		outputMgr.Info("Template hooks skipped")
+	}
+	return confirmed, nil
+}
+
+// templateAsker prompts for template variables on a terminal. Variables
+// with a default are only asked for with --interactive. Nothing is asked
+// with --no-input, in quiet mode or without a terminal, so required
+// variables without a value fail instead.
+func templateAsker(outputMgr *utils.OutputManager) templates.Asker {
+	if noInput || quiet || !utils.IsTerminal(os.Stdin) {
+		return nil
+	}
+
+	return func(name string, variable templates.Variable) (string, error) {
+		if variable.Default != "" && !interactive {
+			return variable.Default, nil
+		}
+
+		message := name
+		if variable.Description != "" {
+			message = fmt.Sprintf("%s (%s)", variable.Description, name)
+		}
+
+		for {
+			var value string
+			var err error
+			if len(variable.Choices) > 0 {
+				// The default is offered first
+				choices := []string{}
+				if slices.Contains(variable.Choices, variable.Default) {
+					choices = append(choices, variable.Default)
+				}
+				for _, choice := range variable.Choices {
+					if choice != variable.Default {
+						choices = append(choices, choice)
+					}
+				}
+				value, err = outputMgr.Select(message, choices)
+			} else {
+				value, err = outputMgr.Input(message, variable.Default)
+			}
+			if err != nil {
+				return "", err
+			}
+
+			if err := variable.Check(value); err != nil {
+				outputMgr.Warning(err.Error())
+				continue
+			}
+			return value, nil
+		}
+	}
+}
+
+// templateVariables returns the template variable values of --var-file
+// and --var, the latter winning
+func templateVariables() (map[string]string, error) {
+	vars := map[string]string{}
+	if templateVarFile != "" {
+		path, err := utils.ExpandPath(templateVarFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand --var-file path: %w", err)
+		}
+		if vars, err = templates.LoadVarsFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	assigned, err := templates.ParseVarAssignments(templateVars)
+	if err != nil {
+		return nil, fmt.Errorf("--var: %w", err)
+	}
+	for name, value := range assigned {
+		vars[name] = value
+	}
+	return vars, nil
+}
+
+// templateLocator finds the templates named by extends: remote and archive
+// templates are fetched, others must exist in the templates directory or
+// be built in
+func templateLocator(cfg *config.Config, outputMgr *utils.OutputManager) templates.Locator {
+	return func(name string) (string, error) {
+		if source := templateSource(cfg, name); templates.IsRemote(source) || templates.IsArchive(source) {
+			return fetchRemoteTemplate(source, cfg, false, outputMgr)
+		}
+		return templates.Find(cfg.Templates.Directory, name)
+	}
+}
+
+// templateSource returns where the template name comes from: the Git
+// repository of a name in templates.registry, or the name itself
+func templateSource(cfg *config.Config, name string) string {
+	return templates.ResolveSource(cfg.Templates.Directory, cfg.Templates.Registry, name)
+}
+
+// fetchRemoteTemplate returns the cached clone of a remote template, or the
+// extracted copy of an archive template, fetching or updating it as
+// templates.auto_update and refresh ask
+func fetchRemoteTemplate(name string, cfg *config.Config, refresh bool, outputMgr *utils.OutputManager) (string, error) {
+	url := templates.RemoteURL(name)
+	writers := []io.Writer{}
+	if verbose {
+		writers = append(writers, os.Stderr)
+	}
+	if emitter := events(); emitter != nil {
+		writers = append(writers, emitter.Writer(progressFetch, url))
+	}
+	var progress io.Writer
+	if len(writers) > 0 {
+		progress = io.MultiWriter(writers...)
+	}
+
+	pin := templatePin(cfg, name)
+	outputMgr.Verbose(fmt.Sprintf("Fetching template %s", url))
+	events().Start(progressFetch, url, name)
+	var dir string
+	var err error
+	if templates.IsArchive(name) {
+		dir, err = templates.FetchArchive(name, int64(cfg.Templates.MaxArchiveMB)<<20, cfg.Templates.AutoUpdate, refresh)
+	} else {
+		dir, err = templates.FetchRemote(name, pin.Ref, cfg.Templates.AutoUpdate, refresh, progress)
+	}
+	events().Finish(progressFetch, url, err)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch template %s: %w", name, err)
+	}
+
+	if pin.SHA256 != "" {
+		checksum, err := templates.Checksum(dir)
+		if err != nil {
+			return "", err
+		}
+		if checksum != pin.SHA256 {
+			return "", fmt.Errorf("template %s does not match its pinned checksum (got %s, expected %s); review it and run 'mkcd template pin' to accept the change", name, checksum, pin.SHA256)
+		}
+		outputMgr.Debug(fmt.Sprintf("Template %s matches its pinned checksum", name))
+	}
+	outputMgr.Debug(fmt.Sprintf("Using template %s from %s", name, dir))
+	return dir, nil
+}
+
+// templatePin returns the pin of a remote template source, listed under
+// its URL or under a registry name of it
+func templatePin(cfg *config.Config, source string) config.TemplatePin {
+	if pin, found := cfg.Templates.Pins[source]; found {
+		return pin
+	}
+	for _, name := range utils.SortedKeys(cfg.Templates.Registry) {
+		if cfg.Templates.Registry[name] != source {
+			continue
+		}
+		if pin, found := cfg.Templates.Pins[name]; found {
+			return pin
+		}
+	}
+	return config.TemplatePin{}
+}
+
+// runHooks runs the configured hook commands inside the target directory
+// under the [hooks] watchdog. Their output is shown when they fail, or
+// with --verbose.
+func runHooks(targetPath string, mkcdConfig MkcdConfig, cfg *config.Config, outputMgr *utils.OutputManager) error {
+	runner := newHookRunner(cfg)
+	for _, hook := range mkcdConfig.Hooks {
+		if dryRun {
+			outputMgr.Info(fmt.Sprintf("[DRY RUN] Would run hook: %s", hook))
+			continue
+		}
+
+		if err := runHook(runner, targetPath, hook, outputMgr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runHook runs a single hook command in the target directory. Its output
+// is shown when it fails, or with --verbose.
+func runHook(runner *hooks.Runner, targetPath, command string, outputMgr *utils.OutputManager) error {
+	outputMgr.Verbose(fmt.Sprintf("Running hook: %s", command))
+	result, err := runner.Run(targetPath, command)
+	if result != nil && result.Output != "" && (err != nil || verbose) {
+		fmt.Fprint(outputMgr.Writer(), result.Output)
+	}
+	if err != nil {
+		return fmt.Errorf("hook %w", err)
+	}
+	outputMgr.Debug(fmt.Sprintf("Hook finished in %s: %s", result.Duration.Round(time.Millisecond), command))
+	return nil
+}
+
+// newHookRunner returns the hook runner configured by [hooks]
+func newHookRunner(cfg *config.Config) *hooks.Runner {
+	// The configuration is validated on load
+	timeout, _ := time.ParseDuration(cfg.Hooks.Timeout)
+	return &hooks.Runner{
+		Timeout:    timeout,
+		Env:        cfg.Hooks.Env,
+		Restricted: cfg.Hooks.Restricted,
+	}
+}
+
+// runThenCommand runs the --then command inside the new directory once
+// every step is done. Its output goes through the output manager.
+func runThenCommand(targetPath, command string, outputMgr *utils.OutputManager) error {
+	if command == "" {
+		return nil
+	}
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would run: %s", command))
+		return nil
+	}
 
+	outputMgr.Info(fmt.Sprintf("Running: %s", command))
+	thenCmd := shellCommand(command)
+	thenCmd.Dir = targetPath
+	thenCmd.Stdin = os.Stdin
+	thenCmd.Stdout = outputMgr.Writer()
+	thenCmd.Stderr = outputMgr.Writer()
+
+	if err := thenCmd.Run(); err != nil {
+		return &thenError{Command: command, Dir: targetPath, Err: err}
+	}
 	return nil
 }
 
+// thenError reports a failed --then command; the directory was created
+type thenError struct {
+	Command string
+	Dir     string
+	Err     error
+}
+
+func (e *thenError) Error() string {
+	return fmt.Sprintf("'%s' failed in %s: %v", e.Command, e.Dir, e.Err)
+}
+
+func (e *thenError) Unwrap() error {
+	return e.Err
+}
+
+// shellCommand returns a command running command with the system shell
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
 // openInEditor opens the project directory in an editor
 func openInEditor(targetPath string, mkcdConfig MkcdConfig, outputMgr *utils.OutputManager) error {
 	editorLauncher := editor.NewEditorLauncher(dryRun, verbose)
@@ -378,19 +1556,48 @@ func openInEditor(targetPath string, mkcdConfig MkcdConfig, outputMgr *utils.Out
 	return editorLauncher.Launch(options)
 }
 
-// generateShellScript generates the shell script for cd operation
-func generateShellScript(targetPath string, outputMgr *utils.OutputManager) error {
-	// This is where we output the shell script that the wrapper function will eval
-	// The actual shell integration will be implemented in the shell package
+// generateShellScript reports the created directory. The path alone is
+// also emitted to the machine-readable channels (--print-path, MKCD_PATH_FD)
+// for the wrapper from 'mkcd shell-init' and other scripts. When summary is
+// given and output.summary is enabled, the summary footer replaces the hint.
+func generateShellScript(targetPath string, cfg *config.Config, outputMgr *utils.OutputManager, summary *creationSummary) error {
+	// Nothing was created in a dry run, so there is nothing to change into
+	if !dryRun {
+		if err := emitPath(targetPath); err != nil {
+			return err
+		}
+		if cfg.Output.TerminalCwd {
+			outputMgr.ReportWorkingDirectory(targetPath)
+		}
+	}
+
+	// The footer goes to stderr with --print-path, so it shows there too
+	showSummary := summary != nil && cfg.Output.Summary && !dryRun
+	if printPath {
+		if showSummary {
+			printCreationSummary(*summary, outputMgr)
+		}
+		return nil
+	}
 
 	if !quiet {
 		outputMgr.Success(fmt.Sprintf("Directory created: %s", targetPath))
-		outputMgr.Info("To change to the directory, run: cd " + targetPath)
-	}
+		if showSummary {
+			printCreationSummary(*summary, outputMgr)
+		} else if !spawn {
+			shellName := shell.Detect()
+			if shellName == "" {
+				shellName = shell.Bash
+			}
+			outputMgr.Info("To change to the directory, run: " + shell.ChangeDirCommand(shellName, targetPath))
+			outputMgr.Verbose("To change into new directories automatically, add " + shell.InitCommand(shellName) + " to your shell startup file")
+		}
 
-	// For now, just output the cd command
-	// In the full implementation, this would generate proper shell scripts
-	fmt.Printf("cd %s\n", targetPath)
+		// Only mkcd itself can be missing its wrapper, not workspace
+		if summary != nil {
+			showIntegrationHint(cfg, outputMgr)
+		}
+	}
 
 	return nil
 }
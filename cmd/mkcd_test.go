@@ -0,0 +1,131 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/history"
+	"github.com/mochajutsu/mkcd/internal/testsupport"
+	"github.com/mochajutsu/mkcd/internal/utils"
+)
+
+// TestAsUserRejectsThen checks that --then, which would leave root-owned
+// files in the handed over directory, cannot be combined with --as-user
+func TestAsUserRejectsThen(t *testing.T) {
+	ws, conf := newTestEnv(t)
+
+	_, err := executeErr(t, "mkcd", "shared", "--as-user", "nobody", "--then", "touch out", "--config", conf)
+	if err == nil || !strings.Contains(err.Error(), "as-user") || !strings.Contains(err.Error(), "then") {
+		t.Fatalf("expected --as-user and --then to be rejected, got %v", err)
+	}
+	if utils.PathExists(ws.Path("shared")) {
+		t.Errorf("expected nothing to be created")
+	}
+}
+
+// TestCommandsRespectTargetLock checks that the commands changing a
+// workspace give up while another invocation holds its lock
+func TestCommandsRespectTargetLock(t *testing.T) {
+	ws, _ := newTestEnv(t)
+	conf := ws.WriteFile("config/mkcd.toml", "[core]\nlock_timeout = \"0s\"\n")
+	execute(t, "mkcd", "api", "--readme", "--config", conf)
+
+	stateDir, err := history.StateDir()
+	if err != nil {
+		t.Fatalf("failed to get state directory: %v", err)
+	}
+	lock, err := utils.LockPath(filepath.Join(stateDir, "locks"), ws.Path("api"), 0)
+	if err != nil {
+		t.Fatalf("failed to lock: %v", err)
+	}
+	defer lock.Unlock()
+
+	for _, args := range [][]string{
+		{"undo", "--force"},
+		{"rename-project", "api", "billing"},
+		{"template", "apply", "go", "api"},
+		{"freeze", "api"},
+	} {
+		_, err := executeErr(t, append(args, "--config", conf)...)
+		var locked *utils.LockedError
+		if !errors.As(err, &locked) {
+			t.Errorf("mkcd %s: expected the lock to be held, got %v", strings.Join(args, " "), err)
+		}
+	}
+
+	testsupport.AssertTree(t, ws.Path("api")).Entries(".mkcd.toml", "README.md")
+}
+
+// TestBatchGroupsOutput checks that the output of every directory of a
+// batch, including what Git integration prints directly, is in its section
+func TestBatchGroupsOutput(t *testing.T) {
+	ws, conf := newTestEnv(t)
+
+	output := execute(t, "mkcd", "api", "web", "--git", "--readme", "--config", conf)
+
+	api := strings.Index(output, "api\n")
+	web := strings.Index(output, "web\n")
+	if api < 0 || web < api {
+		t.Fatalf("expected a section for api followed by one for web:\n%s", output)
+	}
+	for _, dir := range []string{"api", "web"} {
+		for _, message := range []string{"Created file: " + ws.Path(dir+"/README.md"), "Initialized Git repository in: " + ws.Path(dir)} {
+			at := strings.Index(output, message)
+			inSection := at > api && at < web
+			if dir == "web" {
+				inSection = at > web
+			}
+			if !inSection {
+				t.Errorf("expected %q in the section of %s:\n%s", message, dir, output)
+			}
+		}
+	}
+}
+
+// TestBlueprintStepLockedLeavesNothingToRollBack checks that a blueprint
+// step finding its target locked by another invocation claims nothing for
+// the rollback, which would otherwise remove what that invocation creates
+func TestBlueprintStepLockedLeavesNothingToRollBack(t *testing.T) {
+	ws, _ := newTestEnv(t)
+	conf := ws.WriteFile("config/mkcd.toml", "[core]\nlock_timeout = \"0s\"\n")
+	cfg, err := config.Load(conf)
+	if err != nil {
+		t.Fatalf("failed to load configuration: %v", err)
+	}
+
+	stateDir, err := history.StateDir()
+	if err != nil {
+		t.Fatalf("failed to get state directory: %v", err)
+	}
+	lock, err := utils.LockPath(filepath.Join(stateDir, "locks"), ws.Path("api"), 0)
+	if err != nil {
+		t.Fatalf("failed to lock: %v", err)
+	}
+	defer lock.Unlock()
+
+	outputMgr := utils.NewOutputManager(false, false, false, false, false, false)
+	pathValidator := utils.NewPathValidator(cfg.Safety.ForbiddenPaths, cfg.Safety.MaxDepth)
+	for _, step := range []config.BlueprintStep{
+		{Create: "api"},
+		{Clone: "https://example.com/api.git", Dir: "api"},
+		{Apply: "go", Dir: "api"},
+	} {
+		undo, err := runBlueprintStep(step, cfg, outputMgr, pathValidator)
+		var locked *utils.LockedError
+		if !errors.As(err, &locked) {
+			t.Errorf("%s: expected the lock to be held, got %v", describeBlueprintStep(step), err)
+		}
+		if undo != nil {
+			t.Errorf("%s: expected nothing to roll back, got %+v", describeBlueprintStep(step), undo)
+		}
+	}
+}
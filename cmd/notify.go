@@ -0,0 +1,96 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/httpclient"
+	"github.com/mochajutsu/mkcd/internal/notify"
+	"github.com/mochajutsu/mkcd/internal/registry"
+	"github.com/mochajutsu/mkcd/internal/utils"
+)
+
+// Runs that can notify, as listed in integrations.notify.runs
+const (
+	notifyBlueprint = "blueprint"
+	notifyBatch     = "batch"
+	notifyCreate    = "create"
+)
+
+// notifyTimeout bounds posting a notification to the webhook
+const notifyTimeout = 30 * time.Second
+
+// notifyRun reports a finished run of kind as configured by
+// [integrations.notify]. Notifications are a convenience, so failing to
+// send one only warns, and dry runs send nothing.
+func notifyRun(cfg *config.Config, kind, run string, started time.Time, results *utils.BatchSummary, runErr error, outputMgr *utils.OutputManager) {
+	settings := cfg.Integrations.Notify
+	if dryRun || (settings.Webhook == "" && len(settings.Email) == 0) || !slices.Contains(settings.Runs, kind) {
+		return
+	}
+	if settings.OnlyFailures && runErr == nil {
+		return
+	}
+
+	duration := utils.Since(started)
+	if settings.MinDuration != "" {
+		// The configuration is validated on load
+		minDuration, _ := time.ParseDuration(settings.MinDuration)
+		if duration < minDuration {
+			return
+		}
+	}
+
+	summary := notify.Summary{
+		Run:        run,
+		Host:       registry.Hostname(),
+		Succeeded:  runErr == nil,
+		Items:      []notify.Item{},
+		Started:    started.UTC(),
+		DurationMs: duration.Milliseconds(),
+	}
+	if runErr != nil {
+		summary.Error = runErr.Error()
+	}
+	for _, item := range results.Items() {
+		summary.Items = append(summary.Items, notify.Item{Name: item.Name, Status: item.Status, Reason: item.Reason})
+	}
+
+	if settings.Webhook != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+		client := httpclient.NewClient()
+		client.MaxRetries = 1
+		err := notify.PostWebhook(ctx, client, settings.Webhook, settings.Format, summary)
+		cancel()
+		if err != nil {
+			outputMgr.Warning(fmt.Sprintf("Failed to send notification: %v", err))
+		} else {
+			outputMgr.Verbose("Notification posted to the webhook")
+		}
+	}
+
+	if len(settings.Email) > 0 {
+		mail := notify.Mail{
+			Host:     settings.SMTPHost,
+			Username: settings.SMTPUsername,
+			Password: os.Getenv(notify.PasswordEnv),
+			From:     settings.From,
+			To:       settings.Email,
+		}
+		if err := mail.Send(summary, utils.Now()); err != nil {
+			outputMgr.Warning(err.Error())
+		} else {
+			outputMgr.Verbose(fmt.Sprintf("Notification emailed to %d recipient(s)", len(settings.Email)))
+		}
+	}
+}
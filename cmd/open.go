@@ -0,0 +1,251 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/editor"
+	"github.com/mochajutsu/mkcd/internal/registry"
+	"github.com/mochajutsu/mkcd/internal/shell"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// Open command flags
+var (
+	openEditorName string
+	openNoEditor   bool
+)
+
+// openCmd represents the open command
+var openCmd = &cobra.Command{
+	Use:   "open [dir|name]",
+	Short: "Open an existing workspace in the editor and change into it",
+	Long: `Open an existing workspace in the editor and change into it, without
+creating anything.
+
+The argument is resolved in this order:
+
+• A path to an existing directory (default: the current directory)
+• The name of a bookmark ('mkcd bookmark add')
+• The name or ID prefix of a registered project
+• The closest match among the directories recorded in the history and the
+  registry of this machine: an exact name first, then a name starting with
+  the query, containing it, or containing its letters in order; ties go to
+  pinned directories, then to the most recently used one
+
+The editor is --editor, core.editor or the first editor detected. The path
+is emitted like a created one, to MKCD_PATH_FD and, with --print-path, as
+the only line on stdout:
+  cd "$(mkcd open api --print-path --no-editor)"
+
+Examples:
+  mkcd open                                # Open the current directory
+  mkcd open ~/src/api                      # Open a directory by path
+  mkcd open api                            # Open the registered project 'api'
+  mkcd open dash                           # Open e.g. ~/src/admin-dashboard
+  mkcd open api --editor vim               # Open with a specific editor`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeWorkspaces,
+	Annotations:       map[string]string{changesDirAnnotation: "true"},
+	RunE:              runOpen,
+}
+
+func init() {
+	rootCmd.AddCommand(openCmd)
+
+	openCmd.Flags().StringVarP(&openEditorName, "editor", "e", "", "editor to open the workspace with (default: core.editor or auto-detect)")
+	openCmd.Flags().BoolVar(&openNoEditor, "no-editor", false, "only change into the workspace")
+	openCmd.Flags().BoolVar(&printPath, "print-path", false, "print only the workspace path on stdout, all other output on stderr")
+	openCmd.MarkFlagsMutuallyExclusive("editor", "no-editor")
+}
+
+// runOpen resolves a workspace, opens it in the editor and emits its path
+func runOpen(cmd *cobra.Command, args []string) error {
+	// Keep stdout free for the path
+	if printPath {
+		redirectOutput(os.Stderr)
+	}
+
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	cmd.SilenceUsage = true
+	query := ""
+	if len(args) > 0 {
+		query = args[0]
+	}
+	dir, err := resolveWorkspace(query, cfg, outputMgr)
+	if err != nil {
+		return err
+	}
+
+	if !openNoEditor {
+		name := openEditorName
+		if name == "" {
+			name = cfg.Core.Editor
+		}
+		launcher := editor.NewEditorLauncher(dryRun, verbose)
+		err := launcher.Launch(editor.LaunchOptions{
+			EditorName: name,
+			Path:       dir,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to open %s in editor: %w", dir, err)
+		}
+	}
+
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would change into %s", dir))
+		return nil
+	}
+	recordVisit(dir, cfg, outputMgr)
+	if err := emitPath(dir); err != nil {
+		return err
+	}
+	if cfg.Output.TerminalCwd {
+		outputMgr.ReportWorkingDirectory(dir)
+	}
+
+	if !printPath && !quiet {
+		outputMgr.Success(fmt.Sprintf("Opened workspace: %s", dir))
+		if file, _ := pathFD(); file == nil {
+			shellName := shell.Detect()
+			if shellName == "" {
+				shellName = shell.Bash
+			}
+			outputMgr.Info("To change to the directory, run: " + shell.ChangeDirCommand(shellName, dir))
+		}
+	}
+	return nil
+}
+
+// resolveWorkspace returns the existing directory query refers to: a path,
+// a bookmark, a registered project or the best fuzzy match among known
+// directories
+func resolveWorkspace(query string, cfg *config.Config, outputMgr *utils.OutputManager) (string, error) {
+	if query == "" {
+		query = "."
+	}
+
+	if path, err := utils.ExpandPath(query); err == nil && utils.IsDirectory(path) {
+		return utils.GetAbsolutePath(path)
+	}
+
+	if bookmarks, _, err := loadBookmarks(); err == nil {
+		if path, exists := bookmarks.Get(query); exists {
+			if !utils.IsDirectory(path) {
+				return "", fmt.Errorf("bookmark '%s' points to %s, which no longer exists", query, path)
+			}
+			return path, nil
+		}
+	}
+
+	if reg, _, err := loadRegistry(); err == nil {
+		if entry, err := reg.Find(query); err == nil {
+			path, exists := entry.Paths[registry.Hostname()]
+			if !exists {
+				return "", fmt.Errorf("project '%s' is not on this machine", entry.Name)
+			}
+			if !utils.IsDirectory(path) {
+				return "", fmt.Errorf("project '%s' was at %s, which no longer exists (see 'mkcd registry gc')", entry.Name, path)
+			}
+			return path, nil
+		}
+	}
+
+	names := registeredNames()
+	used := usedDirectories(cfg)
+	paths := []string{}
+	for path := range used {
+		if utils.IsDirectory(path) {
+			paths = append(paths, path)
+		}
+	}
+	sortWorkspaces(paths, used)
+
+	best, bestScore := "", 0
+	for _, path := range paths {
+		score := max(matchScore(query, filepath.Base(path)), matchScore(query, workspaceName(path, names)))
+		if score > bestScore {
+			best, bestScore = path, score
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("no directory, registered project or recent workspace matches '%s'", query)
+	}
+
+	outputMgr.Verbose(fmt.Sprintf("'%s' matches %s", query, best))
+	return best, nil
+}
+
+// matchScore rates how well name matches query, ignoring case: 4 for the
+// same name, 3 for a prefix, 2 for a substring, 1 for its letters in order
+// and 0 for no match
+func matchScore(query, name string) int {
+	query, name = strings.ToLower(query), strings.ToLower(name)
+	switch {
+	case name == query:
+		return 4
+	case strings.HasPrefix(name, query):
+		return 3
+	case strings.Contains(name, query):
+		return 2
+	}
+
+	rest := name
+	for _, r := range query {
+		i := strings.IndexRune(rest, r)
+		if i < 0 {
+			return 0
+		}
+		rest = rest[i+len(string(r)):]
+	}
+	return 1
+}
+
+// completeWorkspaces completes registered project names of this machine,
+// falling back to directories
+func completeWorkspaces(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names := []string{}
+	if bookmarks, _, err := loadBookmarks(); err == nil {
+		for _, name := range bookmarks.Names() {
+			if strings.HasPrefix(name, toComplete) {
+				names = append(names, name)
+			}
+		}
+	}
+	if reg, _, err := loadRegistry(); err == nil {
+		host := registry.Hostname()
+		for _, entry := range reg.Entries() {
+			if _, exists := entry.Paths[host]; exists && strings.HasPrefix(entry.Name, toComplete) {
+				names = append(names, entry.Name)
+			}
+		}
+	}
+	return names, cobra.ShellCompDirectiveFilterDirs
+}
@@ -0,0 +1,234 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/registry"
+	"github.com/mochajutsu/mkcd/internal/shell"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/mochajutsu/mkcd/internal/workspaces"
+	"github.com/spf13/cobra"
+)
+
+// maxCompletedPaths caps the directories offered for one completion
+const maxCompletedPaths = 50
+
+// completePathsCmd represents the hidden __complete-paths command
+var completePathsCmd = &cobra.Command{
+	Use:    shell.CompletePathsCmd + " [prefix]",
+	Short:  "Print recently created and registered directories",
+	Hidden: true,
+	Long: `Print the directories recorded in the history and the registry of this
+machine, pinned ones first, then the most recent, one per line, for the
+completion installed by shell-init. Only directories that still exist and
+whose path or name starts with the prefix are printed.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCompletePaths,
+}
+
+func init() {
+	rootCmd.AddCommand(completePathsCmd)
+}
+
+// runCompletePaths prints the directories matching the prefix. It is called
+// on every <Tab>, so broken state yields fewer paths instead of errors.
+func runCompletePaths(cmd *cobra.Command, args []string) error {
+	prefix := ""
+	if len(args) > 0 {
+		prefix = args[0]
+	}
+
+	for _, path := range recentDirectories(prefix) {
+		fmt.Println(path)
+	}
+	return nil
+}
+
+// recentDirectories returns the existing directories from the history and
+// the registry that match prefix, pinned first, then most recently used first
+func recentDirectories(prefix string) []string {
+	cfg := loadCompletionConfig()
+	if cfg == nil {
+		return nil
+	}
+
+	// A prefix such as ~/src is compared with the absolute paths
+	expanded := prefix
+	if strings.HasPrefix(prefix, "~") || strings.Contains(prefix, "$") {
+		if path, err := utils.ExpandPath(prefix); err == nil {
+			expanded = path
+		}
+	}
+
+	used := usedDirectories(cfg)
+	paths := []string{}
+	for path := range used {
+		if prefix != "" && !strings.HasPrefix(path, expanded) && !strings.HasPrefix(filepath.Base(path), prefix) {
+			continue
+		}
+		if utils.IsDirectory(path) {
+			paths = append(paths, path)
+		}
+	}
+	sortWorkspaces(paths, used)
+
+	if len(paths) > maxCompletedPaths {
+		paths = paths[:maxCompletedPaths]
+	}
+	return paths
+}
+
+// usedDirectories returns the directories recorded in the history and the
+// registry of this machine and the pinned and bookmarked ones with the time
+// they were last used. Broken state yields fewer directories.
+func usedDirectories(cfg *config.Config) map[string]time.Time {
+	used := make(map[string]time.Time)
+	if store, err := newHistoryStore(cfg); err == nil {
+		if entries, err := store.Load(); err == nil {
+			for _, entry := range entries {
+				if entry.Timestamp.After(used[entry.Path]) {
+					used[entry.Path] = entry.Timestamp
+				}
+			}
+		}
+	}
+	if reg, _, err := loadRegistry(); err == nil {
+		host := registry.Hostname()
+		for _, entry := range reg.Entries() {
+			path, exists := entry.Paths[host]
+			if exists && entry.Updated.After(used[path]) {
+				used[path] = entry.Updated
+			}
+		}
+	}
+
+	if pins, _, err := loadPins(); err == nil {
+		for _, path := range pins.Paths {
+			if _, exists := used[path]; !exists {
+				used[path] = time.Time{}
+			}
+		}
+	}
+	if bookmarks, _, err := loadBookmarks(); err == nil {
+		for _, path := range bookmarks.Paths {
+			if _, exists := used[path]; !exists {
+				used[path] = time.Time{}
+			}
+		}
+	}
+
+	return used
+}
+
+// sortWorkspaces sorts paths pinned first, then most recently used first.
+// Unreadable pins only lose the pinned order.
+func sortWorkspaces(paths []string, used map[string]time.Time) {
+	pins, _, _ := loadPins()
+	workspaces.Order(paths, used, pins)
+}
+
+// loadPins loads the pinned workspaces and returns them with their path
+func loadPins() (*workspaces.Pins, string, error) {
+	path, err := workspaces.DefaultPinsPath()
+	if err != nil {
+		return nil, "", err
+	}
+
+	pins, err := workspaces.LoadPins(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return pins, path, nil
+}
+
+// moveWorkspaceRefs points the bookmarks and pins of the workspaces in
+// moves from their old paths (the keys) to the new ones, or removes them
+// for an empty new path, and returns what changed
+func moveWorkspaceRefs(moves map[string]string) ([]string, error) {
+	if len(moves) == 0 {
+		return nil, nil
+	}
+
+	bookmarks, bookmarksPath, err := loadBookmarks()
+	if err != nil {
+		return nil, err
+	}
+	pins, pinsPath, err := loadPins()
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []string{}
+	bookmarksChanged, pinsChanged := false, false
+	for _, oldPath := range utils.SortedKeys(moves) {
+		newPath := moves[oldPath]
+		for _, name := range bookmarks.Move(oldPath, newPath) {
+			bookmarksChanged = true
+			if newPath == "" {
+				changes = append(changes, fmt.Sprintf("Removed bookmark '%s' of %s", name, oldPath))
+			} else {
+				changes = append(changes, fmt.Sprintf("Bookmark '%s' now points to %s", name, bookmarks.Paths[name]))
+			}
+		}
+		if pins.Move(oldPath, newPath) {
+			pinsChanged = true
+			if newPath == "" {
+				changes = append(changes, fmt.Sprintf("Unpinned %s", oldPath))
+			} else {
+				changes = append(changes, fmt.Sprintf("Moved the pin of %s to %s", oldPath, newPath))
+			}
+		}
+	}
+
+	if bookmarksChanged {
+		if err := bookmarks.Save(bookmarksPath); err != nil {
+			return nil, err
+		}
+	}
+	if pinsChanged {
+		if err := pins.Save(pinsPath); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+// hostPaths returns the path of every project registered on this machine,
+// by project ID
+func hostPaths(reg *registry.Registry) map[string]string {
+	host := registry.Hostname()
+	paths := map[string]string{}
+	for id, entry := range reg.Projects {
+		if path, exists := entry.Paths[host]; exists {
+			paths[id] = path
+		}
+	}
+	return paths
+}
+
+// registryMoves returns the workspaces whose path on this machine changed
+// between two snapshots of hostPaths, mapped to their new path. Projects
+// no longer registered here map to "" once their directory is gone.
+func registryMoves(before, after map[string]string) map[string]string {
+	moves := map[string]string{}
+	for id, oldPath := range before {
+		newPath, exists := after[id]
+		switch {
+		case exists && newPath != oldPath:
+			moves[oldPath] = newPath
+		case !exists && !utils.IsDirectory(oldPath):
+			moves[oldPath] = ""
+		}
+	}
+	return moves
+}
@@ -0,0 +1,179 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/utils"
+)
+
+// Pipeline step names
+const (
+	stepDirs     = "dirs"
+	stepTouch    = "touch"
+	stepTemplate = "template"
+	stepFiles    = "files"
+	stepGit      = "git"
+	stepHooks    = "hooks"
+	stepEditor   = "editor"
+)
+
+// defaultPipelineSteps is the default execution order of the mkcd pipeline
+var defaultPipelineSteps = []string{
+	stepDirs,
+	stepTouch,
+	stepTemplate,
+	stepFiles,
+	stepGit,
+	stepHooks,
+	stepEditor,
+}
+
+// pipelineContext carries the state shared by all pipeline steps
+type pipelineContext struct {
+	targetPath string
+	cfg        *config.Config
+	mkcdConfig MkcdConfig
+	outputMgr  *utils.OutputManager
+	fsOps      *utils.FileSystemOperations
+
+	// timings records the duration of every step when set (--time)
+	timings *utils.Timings
+}
+
+// pipelineStep is a single named step of the mkcd pipeline
+type pipelineStep struct {
+	name string
+	run  func(pc *pipelineContext) error
+}
+
+// pipelineStepFuncs returns the implementation of every known pipeline step
+func pipelineStepFuncs() map[string]func(pc *pipelineContext) error {
+	return map[string]func(pc *pipelineContext) error{
+		stepDirs: func(pc *pipelineContext) error {
+			if err := createDirectoryStructure(pc.targetPath, pc.mkcdConfig, pc.fsOps, pc.outputMgr); err != nil {
+				return err
+			}
+			return writeProjectFile(pc.targetPath, pc.mkcdConfig, pc.cfg, pc.fsOps, pc.outputMgr)
+		},
+		stepTouch: func(pc *pipelineContext) error {
+			return createTouchFiles(pc.targetPath, pc.mkcdConfig, pc.fsOps, pc.outputMgr)
+		},
+		stepTemplate: func(pc *pipelineContext) error {
+			return applyTemplate(pc.targetPath, pc.mkcdConfig, pc.cfg, pc.fsOps, pc.outputMgr)
+		},
+		stepFiles: func(pc *pipelineContext) error {
+			return generateProjectFiles(pc.targetPath, pc.mkcdConfig, pc.cfg, pc.fsOps, pc.outputMgr)
+		},
+		stepGit: func(pc *pipelineContext) error {
+			return initializeGitRepository(pc.targetPath, pc.mkcdConfig, pc.cfg, pc.outputMgr)
+		},
+		stepHooks: func(pc *pipelineContext) error {
+			return runHooks(pc.targetPath, pc.mkcdConfig, pc.cfg, pc.outputMgr)
+		},
+		stepEditor: func(pc *pipelineContext) error {
+			if !pc.mkcdConfig.Editor {
+				return nil
+			}
+			if err := openInEditor(pc.targetPath, pc.mkcdConfig, pc.outputMgr); err != nil {
+				pc.outputMgr.Warning(fmt.Sprintf("Failed to open in editor: %v", err))
+			}
+			return nil
+		},
+	}
+}
+
+// resolvePipelineSteps returns the steps to run, in order, for the given step
+// order (empty for the default order) and the steps to skip
+func resolvePipelineSteps(order, skip []string) ([]pipelineStep, error) {
+	if len(order) == 0 {
+		order = defaultPipelineSteps
+	}
+
+	funcs := pipelineStepFuncs()
+
+	skipped := make(map[string]bool)
+	for _, name := range skip {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, exists := funcs[name]; !exists {
+			return nil, fmt.Errorf("unknown step to skip: %s (valid steps: %s)", name, strings.Join(defaultPipelineSteps, ", "))
+		}
+		skipped[name] = true
+	}
+
+	steps := []pipelineStep{}
+	seen := make(map[string]bool)
+	for i, name := range order {
+		name = strings.ToLower(strings.TrimSpace(name))
+		run, exists := funcs[name]
+		if !exists {
+			return nil, fmt.Errorf("unknown step: %s (valid steps: %s)", name, strings.Join(defaultPipelineSteps, ", "))
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("step %s appears more than once", name)
+		}
+		seen[name] = true
+
+		// Every other step needs the directory to exist
+		if name == stepDirs && i != 0 {
+			return nil, fmt.Errorf("step %s must be the first step", stepDirs)
+		}
+
+		if skipped[name] {
+			continue
+		}
+		steps = append(steps, pipelineStep{name: name, run: run})
+	}
+
+	return steps, nil
+}
+
+// runPipeline runs the given steps in order, stopping at the first failure
+func runPipeline(steps []pipelineStep, pc *pipelineContext) (err error) {
+	events().Start(progressPipeline, pc.targetPath, strings.Join(stepNames(steps), ","))
+	defer func() { events().Finish(progressPipeline, pc.targetPath, err) }()
+
+	for i, step := range steps {
+		pc.outputMgr.Debug(fmt.Sprintf("Running step: %s", step.name))
+		pc.fsOps.SetSource("step:" + step.name)
+
+		run := step.run
+		events().Start(step.name, pc.targetPath, "")
+		err := runTimed(step.name, pc.timings, pc.outputMgr, func() error { return run(pc) })
+		events().Finish(step.name, pc.targetPath, err)
+		if err != nil {
+			return fmt.Errorf("step %s failed: %w", step.name, err)
+		}
+		events().Progress(progressPipeline, pc.targetPath, (i+1)*100/len(steps), step.name)
+	}
+	return nil
+}
+
+// runTimed runs operation, recording its duration in timings when timings
+// is set. Only --time reports each step as it runs; usage metrics are
+// collected silently.
+func runTimed(name string, timings *utils.Timings, outputMgr *utils.OutputManager, operation func() error) error {
+	if timings == nil {
+		return operation()
+	}
+	if !timeSteps {
+		return timings.Wrap(name, operation)()
+	}
+	return outputMgr.TimedOperation(name, timings.Wrap(name, operation))
+}
+
+// stepNames returns the names of the given steps
+func stepNames(steps []pipelineStep) []string {
+	names := make([]string, len(steps))
+	for i, step := range steps {
+		names[i] = step.name
+	}
+	return names
+}
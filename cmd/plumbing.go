@@ -0,0 +1,298 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/registry"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// plumbingFormatVersion is the "version" of every plumbing document. It
+// only changes when a field is removed or changes meaning; fields may be
+// added at any time.
+const plumbingFormatVersion = 1
+
+// internalCmd represents the hidden internal command
+var internalCmd = &cobra.Command{
+	Use:    "internal",
+	Short:  "Stable plumbing commands for scripts and integrations",
+	Hidden: true,
+	Long: `Plumbing commands for scripts, editor plugins and other integrations.
+
+Unlike the regular commands, whose output is meant for people and may
+change between releases, every plumbing command prints a single JSON
+document on stdout and nothing else; messages and errors go to stderr and
+failures exit with a non-zero status. Each document has a "version" field
+that only changes when a field is removed or changes meaning.
+
+Examples:
+  mkcd internal resolve-path api           # Where 'mkcd api' would create api
+  mkcd internal plan api --template go     # What 'mkcd api --template go' would write
+  mkcd internal registry                   # All registered projects
+  mkcd internal registry api               # A single project by name or ID`,
+}
+
+// resolvePathCmd represents the internal resolve-path command
+var resolvePathCmd = &cobra.Command{
+	Use:   "resolve-path <directory>...",
+	Short: "Print the paths mkcd would create",
+	Long: `Print the absolute path mkcd would create for each directory, without
+creating anything:
+
+  {"version": 1, "paths": [{"input": "api", "path": "/home/me/src/api", "exists": false}]}`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runResolvePath,
+}
+
+// planCmd represents the internal plan command
+var planCmd = &cobra.Command{
+	Use:   "plan <directory>",
+	Short: "Print the steps and files of a creation",
+	Long: `Print what mkcd would do for a directory with the same flags, without
+writing anything: the pipeline steps that would run and every directory,
+file and symlink the filesystem steps would write, with the template or
+generator each file comes from. Git, hooks and the editor are listed as
+steps but not run.
+
+  {"version": 1, "path": "...", "profile": "default", "steps": ["dirs", ...],
+   "entries": [{"path": "README.md", "type": "file", "mode": "0644", "size": 120, "source": "generator:readme", "exists": false}]}`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPlan,
+}
+
+// plumbingRegistryCmd represents the internal registry command
+var plumbingRegistryCmd = &cobra.Command{
+	Use:   "registry [name-or-id]",
+	Short: "Print registered projects",
+	Long: `Print the registered projects with their path on this machine, or the
+single project whose name equals or ID starts with the argument:
+
+  {"version": 1, "host": "laptop", "projects": [{"id": "...", "name": "api", "path": "/home/me/src/api", ...}]}`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPlumbingRegistry,
+}
+
+func init() {
+	rootCmd.AddCommand(internalCmd)
+
+	internalCmd.AddCommand(resolvePathCmd)
+	internalCmd.AddCommand(planCmd)
+	internalCmd.AddCommand(plumbingRegistryCmd)
+
+	// The mkcd flags are shared so a plan matches the real invocation;
+	// mkcd.go registers them first as its init runs earlier
+	resolvePathCmd.Flags().AddFlag(mkcdCmd.Flags().Lookup("temp"))
+	resolvePathCmd.Flags().AddFlag(mkcdCmd.Flags().Lookup("physical"))
+	planCmd.Flags().AddFlagSet(mkcdCmd.Flags())
+}
+
+// resolvedPath is an entry of the resolve-path document
+type resolvedPath struct {
+	Input  string `json:"input"`
+	Path   string `json:"path"`
+	Exists bool   `json:"exists"`
+}
+
+// planEntry is a directory, file or symlink in the plan document
+type planEntry struct {
+	Path   string `json:"path"`
+	Type   string `json:"type"`
+	Mode   string `json:"mode"`
+	Size   int64  `json:"size,omitempty"`
+	Source string `json:"source,omitempty"`
+	Exists bool   `json:"exists"`
+}
+
+// registryProject is a project in the registry document
+type registryProject struct {
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	Path    string            `json:"path,omitempty"`
+	Paths   map[string]string `json:"paths"`
+	Tags    []string          `json:"tags"`
+	Created time.Time         `json:"created"`
+	Updated time.Time         `json:"updated"`
+}
+
+// runResolvePath prints the target path of every directory argument. Like
+// every plumbing command, it reports failures without usage help.
+func runResolvePath(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	cfg, mkcdConfig, err := loadPlumbingConfig()
+	if err != nil {
+		return err
+	}
+
+	paths := []resolvedPath{}
+	for _, dirName := range args {
+		targetPath, err := determineTargetPath(dirName, mkcdConfig, cfg)
+		if err != nil {
+			return fmt.Errorf("failed to determine target path for %s: %w", dirName, err)
+		}
+		_, statErr := os.Lstat(targetPath)
+		paths = append(paths, resolvedPath{Input: dirName, Path: targetPath, Exists: statErr == nil})
+	}
+
+	return printPlumbing(map[string]any{"paths": paths})
+}
+
+// runPlan prints the steps and the entries a creation would write
+func runPlan(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	cfg, mkcdConfig, err := loadPlumbingConfig()
+	if err != nil {
+		return err
+	}
+
+	targetPath, err := determineTargetPath(args[0], mkcdConfig, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to determine target path: %w", err)
+	}
+
+	steps, err := resolvePipelineSteps(mkcdConfig.Steps, mkcdConfig.SkipSteps)
+	if err != nil {
+		return fmt.Errorf("invalid pipeline configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(false, false, false, true, false, debug)
+	fsOps, err := renderPipeline(targetPath, cfg, mkcdConfig, outputMgr)
+	if err != nil {
+		return err
+	}
+
+	sources := map[string]string{}
+	for _, record := range fsOps.Records() {
+		sources[record.Path] = record.Source
+	}
+
+	entries := []planEntry{}
+	for _, change := range fsOps.DryRunChanges() {
+		rel, err := filepath.Rel(targetPath, change.Path)
+		if err != nil {
+			rel = change.Path
+		}
+
+		entry := planEntry{
+			Path:   filepath.ToSlash(rel),
+			Type:   "file",
+			Mode:   fmt.Sprintf("%04o", change.Mode.Perm()),
+			Source: sources[change.Path],
+		}
+		switch {
+		case change.IsDir:
+			entry.Type = "dir"
+		case change.Mode&os.ModeSymlink != 0:
+			entry.Type = "symlink"
+		default:
+			entry.Size = change.Size
+		}
+		if _, err := os.Lstat(change.Path); err == nil {
+			entry.Exists = true
+		}
+		entries = append(entries, entry)
+	}
+
+	return printPlumbing(map[string]any{
+		"path":     targetPath,
+		"profile":  mkcdConfig.Profile,
+		"template": mkcdConfig.Template,
+		"steps":    stepNames(steps),
+		"entries":  entries,
+	})
+}
+
+// runPlumbingRegistry prints the registered projects
+func runPlumbingRegistry(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	redirectOutput(os.Stderr)
+
+	reg, _, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+
+	entries := reg.Entries()
+	if len(args) > 0 {
+		entry, err := reg.Find(args[0])
+		if err != nil {
+			return err
+		}
+		entries = []registry.Entry{*entry}
+	}
+
+	host := registry.Hostname()
+	projects := []registryProject{}
+	for _, entry := range entries {
+		project := registryProject{
+			ID:      entry.ID,
+			Name:    entry.Name,
+			Path:    entry.Paths[host],
+			Paths:   entry.Paths,
+			Tags:    entry.Tags,
+			Created: entry.Created,
+			Updated: entry.Updated,
+		}
+		if project.Paths == nil {
+			project.Paths = map[string]string{}
+		}
+		if project.Tags == nil {
+			project.Tags = []string{}
+		}
+		projects = append(projects, project)
+	}
+
+	return printPlumbing(map[string]any{"host": host, "projects": projects})
+}
+
+// loadPlumbingConfig keeps stdout for the JSON document and returns the
+// configuration merged with the profile and flags, as mkcd would use them
+func loadPlumbingConfig() (*config.Config, MkcdConfig, error) {
+	redirectOutput(os.Stderr)
+
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return nil, MkcdConfig{}, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	profileName := profile
+	if profileName == "" {
+		profileName = cfg.Core.DefaultProfile
+	}
+	profileConfig, err := cfg.GetProfile(profileName)
+	if err != nil {
+		if profile != "" {
+			return nil, MkcdConfig{}, fmt.Errorf("failed to get profile: %w", err)
+		}
+		profileConfig = config.ProfileConfig{}
+	}
+
+	mkcdConfig := mergeConfigWithFlags(profileConfig)
+	mkcdConfig.Profile = profileName
+	if mkcdConfig.TemplateVars, err = templateVariables(); err != nil {
+		return nil, MkcdConfig{}, err
+	}
+	return cfg, mkcdConfig, nil
+}
+
+// printPlumbing prints a plumbing document with its format version
+func printPlumbing(document map[string]any) error {
+	document["version"] = plumbingFormatVersion
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -0,0 +1,262 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// Preview command flags
+var (
+	previewTemplate string
+	previewStat     bool
+)
+
+// previewSteps are the pipeline steps that produce files; git, hooks and
+// the editor act outside the filesystem overlay and never run in a preview
+var previewSteps = map[string]bool{
+	stepDirs:     true,
+	stepTouch:    true,
+	stepTemplate: true,
+	stepFiles:    true,
+}
+
+// previewCmd represents the preview command
+var previewCmd = &cobra.Command{
+	Use:   "preview <directory>",
+	Short: "Preview applying a profile or template to an existing directory",
+	Long: `Render everything a profile and template would produce into an in-memory
+copy of the target and show what would change, without writing anything.
+
+The directory-level summary lists files that would be added or modified;
+the file-level diff shows the changes to every modified file. Use it to
+evaluate applying templates to mature projects before running mkcd.
+
+Examples:
+  mkcd preview . --template go                # Preview the go template here
+  mkcd preview ~/src/api --profile dev        # Preview the dev profile
+  mkcd preview api --template go,docker --stat  # Summary only, no diff`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPreview,
+}
+
+func init() {
+	rootCmd.AddCommand(previewCmd)
+
+	previewCmd.Flags().StringVarP(&previewTemplate, "template", "t", "", "template(s) to preview, comma-separated to layer in order")
+	previewCmd.Flags().BoolVar(&previewStat, "stat", false, "only show the directory-level summary")
+}
+
+// previewFile is the outcome of the preview for a single path
+type previewFile struct {
+	path   string
+	status string // added, modified or unchanged
+	isDir  bool
+	diff   string
+}
+
+// runPreview renders the pipeline into memory and diffs it against the target
+func runPreview(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	profileName := profile
+	if profileName == "" {
+		profileName = cfg.Core.DefaultProfile
+	}
+	profileConfig, err := cfg.GetProfile(profileName)
+	if err != nil {
+		return fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	mkcdConfig := mergeConfigWithFlags(profileConfig)
+	mkcdConfig.Profile = profileName
+	if previewTemplate != "" {
+		mkcdConfig.Template = previewTemplate
+	}
+
+	targetPath, err := utils.GetAbsolutePath(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	fsOps, err := renderPipeline(targetPath, cfg, mkcdConfig, outputMgr)
+	if err != nil {
+		return err
+	}
+
+	files, err := comparePreview(targetPath, fsOps)
+	if err != nil {
+		return err
+	}
+
+	outputMgr.Header(fmt.Sprintf("Preview: %s", targetPath))
+
+	counts := map[string]int{}
+	rows := [][]string{}
+	for _, file := range files {
+		counts[file.status]++
+		if file.status == "unchanged" && !verbose {
+			continue
+		}
+		path := file.path
+		if file.isDir {
+			path += "/"
+		}
+		rows = append(rows, []string{file.status, path})
+	}
+
+	if len(rows) > 0 {
+		outputMgr.Table([]string{"Status", "Path"}, rows)
+	}
+	outputMgr.Info(fmt.Sprintf("%d added, %d modified, %d unchanged", counts["added"], counts["modified"], counts["unchanged"]))
+
+	if previewStat {
+		return nil
+	}
+
+	for _, file := range files {
+		if file.diff != "" {
+			printDiff(file.diff, cfg.Output.Colors)
+		}
+	}
+	return nil
+}
+
+// renderPipeline runs the pipeline steps that produce files into an
+// in-memory overlay of the real disk and returns the operations holding it
+func renderPipeline(targetPath string, cfg *config.Config, mkcdConfig MkcdConfig, outputMgr *utils.OutputManager) (*utils.FileSystemOperations, error) {
+	steps, err := resolvePipelineSteps(mkcdConfig.Steps, mkcdConfig.SkipSteps)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pipeline configuration: %w", err)
+	}
+	fileSteps := []pipelineStep{}
+	for _, step := range steps {
+		if previewSteps[step.name] {
+			fileSteps = append(fileSteps, step)
+		}
+	}
+
+	fsOps := utils.NewFileSystemOperations(true, false)
+	fsOps.Silent = true
+	fsOps.Encoding = fileEncoding(cfg)
+
+	pc := &pipelineContext{
+		targetPath: targetPath,
+		cfg:        cfg,
+		mkcdConfig: mkcdConfig,
+		outputMgr:  outputMgr,
+		fsOps:      fsOps,
+	}
+	if err := runPipeline(fileSteps, pc); err != nil {
+		return nil, err
+	}
+	return fsOps, nil
+}
+
+// comparePreview compares the entries written to the overlay with the disk
+func comparePreview(targetPath string, fsOps *utils.FileSystemOperations) ([]previewFile, error) {
+	files := []previewFile{}
+
+	for _, change := range fsOps.DryRunChanges() {
+		rel, err := filepath.Rel(targetPath, change.Path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			rel = change.Path
+		}
+		rel = filepath.ToSlash(rel)
+
+		info, statErr := os.Lstat(change.Path)
+		if change.IsDir || change.Mode&os.ModeSymlink != 0 {
+			if statErr != nil {
+				files = append(files, previewFile{path: rel, status: "added", isDir: change.IsDir})
+			}
+			continue
+		}
+
+		rendered, err := fsOps.FS.ReadFile(change.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rendered %s: %w", rel, err)
+		}
+
+		if statErr != nil {
+			files = append(files, previewFile{path: rel, status: "added", diff: fileDiff(rel, nil, rendered)})
+			continue
+		}
+		if info.IsDir() {
+			return nil, fmt.Errorf("%s is a directory in the target but a file in the preview", rel)
+		}
+
+		existing, err := os.ReadFile(change.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", change.Path, err)
+		}
+
+		if string(existing) == string(rendered) {
+			files = append(files, previewFile{path: rel, status: "unchanged"})
+			continue
+		}
+		files = append(files, previewFile{path: rel, status: "modified", diff: fileDiff(rel, existing, rendered)})
+	}
+
+	return files, nil
+}
+
+// fileDiff returns the unified diff of a file; a nil old content means the
+// file is new
+func fileDiff(rel string, old, rendered []byte) string {
+	oldName := "a/" + rel
+	if old == nil {
+		oldName = "/dev/null"
+	}
+
+	if utils.IsBinary(old) || utils.IsBinary(rendered) {
+		return fmt.Sprintf("Binary files %s and b/%s differ\n", oldName, rel)
+	}
+	return utils.UnifiedDiff(oldName, "b/"+rel, string(old), string(rendered), 3)
+}
+
+// printDiff prints a unified diff, coloured when colours are enabled
+func printDiff(diff string, colors bool) {
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if line == "" {
+			continue
+		}
+		switch {
+		case !colors:
+			fmt.Print(line)
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			fmt.Print(pterm.Bold.Sprint(line))
+		case strings.HasPrefix(line, "@@"):
+			fmt.Print(pterm.FgCyan.Sprint(line))
+		case strings.HasPrefix(line, "+"):
+			fmt.Print(pterm.FgGreen.Sprint(line))
+		case strings.HasPrefix(line, "-"):
+			fmt.Print(pterm.FgRed.Sprint(line))
+		default:
+			fmt.Print(line)
+		}
+	}
+}
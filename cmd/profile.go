@@ -10,13 +10,25 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/history"
 	"github.com/mochajutsu/mkcd/internal/utils"
 	"github.com/spf13/cobra"
 )
 
+// Profile list flags
+var (
+	profileListSort   string
+	profileListFilter string
+	profileListWide   bool
+	profileListStale  bool
+	profileListMonths int
+)
+
 // profileCmd represents the profile command
 var profileCmd = &cobra.Command{
 	Use:   "profile",
@@ -27,20 +39,35 @@ Profiles allow you to save and reuse common configurations for different
 types of projects. Each profile can specify default settings for git
 initialization, editor preferences, file generation, and more.
 
+Teams can publish canonical profiles as a TOML file of [profiles.*] tables
+and point core.profiles_url at it. Those profiles are fetched, cached for an
+hour and merged read-only with your own; a local profile of the same name
+takes precedence.
+
 Examples:
   mkcd profile list                    # List all profiles
   mkcd profile show dev                # Show 'dev' profile details
   mkcd profile create myprofile        # Create new profile interactively
   mkcd profile edit dev                # Edit 'dev' profile in $EDITOR
   mkcd profile delete myprofile        # Delete 'myprofile'
-  mkcd profile copy dev mydev          # Copy 'dev' profile to 'mydev'`,
+  mkcd profile copy dev mydev          # Copy 'dev' profile to 'mydev'
+  mkcd profile refresh                 # Fetch profiles from profiles_url now`,
 }
 
 // profileListCmd represents the profile list command
 var profileListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all available profiles",
-	Long:  `List all available configuration profiles with their descriptions.`,
+	Long: `List all available configuration profiles with their descriptions.
+
+Usage counts are taken from the mkcd history.
+
+Examples:
+  mkcd profile list --sort usage       # Most used profiles first
+  mkcd profile list --filter web       # Profiles whose name contains 'web'
+  mkcd profile list --wide             # Include license and touch files
+  mkcd profile list --stale            # Profiles unused for 6 months
+  mkcd profile list --stale --months 3 # Profiles unused for 3 months`,
 	RunE:  runProfileList,
 }
 
@@ -89,6 +116,15 @@ var profileCopyCmd = &cobra.Command{
 	RunE:  runProfileCopy,
 }
 
+// profileRefreshCmd represents the profile refresh command
+var profileRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Fetch remote profiles now",
+	Long:  `Fetch the profiles published at core.profiles_url, bypassing the cache.`,
+	Args:  cobra.NoArgs,
+	RunE:  runProfileRefresh,
+}
+
 func init() {
 	rootCmd.AddCommand(profileCmd)
 	
@@ -99,6 +135,19 @@ func init() {
 	profileCmd.AddCommand(profileEditCmd)
 	profileCmd.AddCommand(profileDeleteCmd)
 	profileCmd.AddCommand(profileCopyCmd)
+	profileCmd.AddCommand(profileRefreshCmd)
+
+	// Complete profile name arguments
+	for _, cmd := range []*cobra.Command{profileShowCmd, profileEditCmd, profileDeleteCmd, profileCopyCmd} {
+		cmd.ValidArgsFunction = completeProfileArg
+	}
+
+	// List flags
+	profileListCmd.Flags().StringVar(&profileListSort, "sort", "name", "sort order: name or usage")
+	profileListCmd.Flags().StringVar(&profileListFilter, "filter", "", "only show profiles whose name contains this text")
+	profileListCmd.Flags().BoolVar(&profileListWide, "wide", false, "include license and touch file columns")
+	profileListCmd.Flags().BoolVar(&profileListStale, "stale", false, "only show profiles unused for --months months")
+	profileListCmd.Flags().IntVar(&profileListMonths, "months", 6, "months without use after which a profile is stale")
 }
 
 // runProfileList lists all available profiles
@@ -122,13 +171,66 @@ func runProfileList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	outputMgr.Header("Available Profiles")
+	if profileListSort != "name" && profileListSort != "usage" {
+		return fmt.Errorf("invalid sort order '%s' (expected name or usage)", profileListSort)
+	}
+	if profileListMonths < 1 {
+		return fmt.Errorf("--months must be at least 1")
+	}
+
+	// Usage counts come from history; a missing history simply means no usage
+	usage := map[string]int{}
+	lastUsed := map[string]time.Time{}
+	if store, err := newHistoryStore(cfg); err == nil {
+		if entries, err := store.Load(); err == nil {
+			usage = history.ProfileUsage(entries)
+			lastUsed = history.ProfileLastUsed(entries)
+		} else {
+			outputMgr.Warning(fmt.Sprintf("Failed to read history: %v", err))
+		}
+	}
+
+	// Select and order profiles
+	names := []string{}
+	for _, name := range utils.SortedKeys(cfg.Profiles) {
+		if profileListFilter != "" && !strings.Contains(strings.ToLower(name), strings.ToLower(profileListFilter)) {
+			continue
+		}
+		if profileListStale && !history.IsStale(lastUsed[name], utils.Now(), profileListMonths) {
+			continue
+		}
+		names = append(names, name)
+	}
+	if profileListSort == "usage" {
+		sort.SliceStable(names, func(i, j int) bool {
+			return usage[names[i]] > usage[names[j]]
+		})
+	}
+
+	if len(names) == 0 {
+		if profileListStale {
+			outputMgr.Success(fmt.Sprintf("No profiles unused for %d months", profileListMonths))
+		} else {
+			outputMgr.Info(fmt.Sprintf("No profiles match '%s'", profileListFilter))
+		}
+		return nil
+	}
+
+	if profileListStale {
+		outputMgr.Header(fmt.Sprintf("Profiles Unused for %d Months", profileListMonths))
+	} else {
+		outputMgr.Header("Available Profiles")
+	}
 
 	// Prepare table data
-	headers := []string{"Name", "Git", "Editor", "Template", "Description"}
+	headers := []string{"Name", "Git", "Editor", "Template", "Uses", "Last Used", "Description"}
+	if profileListWide {
+		headers = []string{"Name", "Git", "Editor", "Template", "Uses", "Last Used", "License", "Touch Files", "Description"}
+	}
 	rows := [][]string{}
 
-	for name, profile := range cfg.Profiles {
+	for _, name := range names {
+		profile := cfg.Profiles[name]
 		gitStatus := "No"
 		if profile.Git {
 			gitStatus = "Yes"
@@ -144,18 +246,44 @@ func runProfileList(cmd *cobra.Command, args []string) error {
 			template = "-"
 		}
 
+		uses := fmt.Sprintf("%d", usage[name])
+		lastUse := "never"
+		if !lastUsed[name].IsZero() {
+			lastUse = lastUsed[name].Format("2006-01-02")
+		}
 		description := generateProfileDescription(profile)
 
-		// Mark default profile
+		// Mark default and remote profiles
 		displayName := name
 		if name == cfg.Core.DefaultProfile {
 			displayName = name + " (default)"
 		}
+		if cfg.IsRemoteProfile(name) {
+			displayName += " (remote)"
+		}
 
-		rows = append(rows, []string{displayName, gitStatus, editorStatus, template, description})
+		if profileListWide {
+			license := profile.License
+			if license == "" {
+				license = "-"
+			}
+
+			touchFiles := strings.Join(profile.Touch, ", ")
+			if touchFiles == "" {
+				touchFiles = "-"
+			}
+
+			rows = append(rows, []string{displayName, gitStatus, editorStatus, template, uses, lastUse, license, touchFiles, description})
+			continue
+		}
+
+		rows = append(rows, []string{displayName, gitStatus, editorStatus, template, uses, lastUse, description})
 	}
 
 	outputMgr.Table(headers, rows)
+	if profileListStale {
+		outputMgr.Info("Remove profiles you no longer need with: mkcd profile delete <profile-name>")
+	}
 	return nil
 }
 
@@ -203,16 +331,48 @@ func runProfileShow(cmd *cobra.Command, args []string) error {
 		details = append(details, fmt.Sprintf("License: %s", profile.License))
 	}
 
+	if profile.Gitattributes != "" {
+		details = append(details, fmt.Sprintf("Gitattributes policy: %s", profile.Gitattributes))
+	}
+
+	if len(profile.EOL) > 0 {
+		eolRules := []string{}
+		for ext, eol := range profile.EOL {
+			eolRules = append(eolRules, fmt.Sprintf("%s=%s", ext, eol))
+		}
+		sort.Strings(eolRules)
+		details = append(details, fmt.Sprintf("Line endings: %s", strings.Join(eolRules, ", ")))
+	}
+
+	if len(profile.Generate) > 0 {
+		details = append(details, fmt.Sprintf("Generators: %s", strings.Join(profile.Generate, ", ")))
+	}
+
 	if len(profile.Touch) > 0 {
 		details = append(details, fmt.Sprintf("Touch files: %s", strings.Join(profile.Touch, ", ")))
 	}
 
+	if len(profile.Steps) > 0 {
+		details = append(details, fmt.Sprintf("Step order: %s", strings.Join(profile.Steps, " → ")))
+	}
+
+	if len(profile.SkipSteps) > 0 {
+		details = append(details, fmt.Sprintf("Skipped steps: %s", strings.Join(profile.SkipSteps, ", ")))
+	}
+
+	if len(profile.Hooks) > 0 {
+		details = append(details, fmt.Sprintf("Hooks: %s", strings.Join(profile.Hooks, "; ")))
+	}
+
 	outputMgr.List(details)
 
 	// Show if this is the default profile
 	if profileName == cfg.Core.DefaultProfile {
 		outputMgr.Info("This is the default profile")
 	}
+	if cfg.IsRemoteProfile(profileName) {
+		outputMgr.Info(fmt.Sprintf("This profile comes from %s and is read-only", cfg.Core.ProfilesURL))
+	}
 
 	return nil
 }
@@ -236,6 +396,9 @@ func runProfileCreate(cmd *cobra.Command, args []string) error {
 	)
 
 	// Check if profile already exists
+	if cfg.IsRemoteProfile(profileName) {
+		return fmt.Errorf("profile '%s' comes from profiles_url and is read-only", profileName)
+	}
 	if _, exists := cfg.Profiles[profileName]; exists {
 		if !force {
 			return fmt.Errorf("profile '%s' already exists (use --force to overwrite)", profileName)
@@ -293,6 +456,14 @@ func runProfileCreate(cmd *cobra.Command, args []string) error {
 	}
 	profile.License = licenseType
 
+	// Line-ending policy
+	gitattributesOptions := []string{"", "auto", "lf", "crlf"}
+	gitattributesPolicy, err := outputMgr.Select("Select default .gitattributes line-ending policy (or empty for none):", gitattributesOptions)
+	if err != nil {
+		return fmt.Errorf("failed to get gitattributes preference: %w", err)
+	}
+	profile.Gitattributes = gitattributesPolicy
+
 	// Touch files
 	touchFiles, err := outputMgr.Input("Enter files to create by default (comma-separated, or empty):", "")
 	if err != nil {
@@ -347,6 +518,9 @@ func runProfileEdit(cmd *cobra.Command, args []string) error {
 	if _, exists := cfg.Profiles[profileName]; !exists {
 		return fmt.Errorf("profile '%s' not found", profileName)
 	}
+	if cfg.IsRemoteProfile(profileName) {
+		return fmt.Errorf("profile '%s' comes from profiles_url and is read-only (copy it to customise it)", profileName)
+	}
 
 	// Get config file path
 	configPath := cfgFile
@@ -456,6 +630,9 @@ func runProfileCopy(cmd *cobra.Command, args []string) error {
 	}
 
 	// Check if destination profile already exists
+	if cfg.IsRemoteProfile(destProfile) {
+		return fmt.Errorf("profile '%s' comes from profiles_url and is read-only", destProfile)
+	}
 	if _, exists := cfg.Profiles[destProfile]; exists {
 		if !force {
 			return fmt.Errorf("destination profile '%s' already exists (use --force to overwrite)", destProfile)
@@ -475,6 +652,38 @@ func runProfileCopy(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runProfileRefresh fetches the remote profiles, bypassing the cache
+func runProfileRefresh(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	if cfg.Core.ProfilesURL == "" {
+		return fmt.Errorf("no profiles_url configured")
+	}
+
+	profiles, err := config.FetchRemoteProfiles(cfg.Core.ProfilesURL, true)
+	if err != nil {
+		return err
+	}
+
+	outputMgr.Success(fmt.Sprintf("Fetched %d profiles from %s", len(profiles), cfg.Core.ProfilesURL))
+	if len(profiles) > 0 {
+		outputMgr.List(utils.SortedKeys(profiles))
+	}
+	return nil
+}
+
 // generateProfileDescription generates a brief description of a profile
 func generateProfileDescription(profile config.ProfileConfig) string {
 	features := []string{}
@@ -0,0 +1,65 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/mochajutsu/mkcd/internal/progress"
+)
+
+// progressFDEnv names the environment variable holding a file descriptor
+// that receives progress events as newline-delimited JSON
+const progressFDEnv = "MKCD_PROGRESS_FD"
+
+// Progress step names besides the pipeline steps
+const (
+	progressPipeline = "pipeline"
+	progressFetch    = "fetch"
+)
+
+var (
+	progressOnce    sync.Once
+	progressEvents  *progress.Emitter
+	progressFailure error
+)
+
+// progressEmitter returns where progress events go: stdout with
+// --progress-json, the descriptor from MKCD_PROGRESS_FD, or nil when they
+// are not requested
+func progressEmitter() (*progress.Emitter, error) {
+	progressOnce.Do(func() {
+		if progressJSON {
+			if printPath || jsonOutput {
+				progressFailure = fmt.Errorf("--progress-json writes to stdout, which --print-path and --json need; set %s instead", progressFDEnv)
+				return
+			}
+			progressEvents = progress.NewEmitter(os.Stdout)
+			return
+		}
+
+		file, err := openFD(progressFDEnv)
+		if err != nil {
+			progressFailure = err
+			return
+		}
+		if file != nil {
+			progressEvents = progress.NewEmitter(file)
+		}
+	})
+	return progressEvents, progressFailure
+}
+
+// events returns the emitter of progress events, which is nil when they
+// are not requested or their channel is unusable. Commands check the
+// channel with progressEmitter before starting.
+func events() *progress.Emitter {
+	emitter, _ := progressEmitter()
+	return emitter
+}
@@ -0,0 +1,145 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/templates"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// quotaJSON prints the space information as JSON
+var quotaJSON bool
+
+// quotaCmd represents the quota command
+var quotaCmd = &cobra.Command{
+	Use:   "quota [path]",
+	Short: "Show the space and disk quota available for new directories",
+	Long: `Show the free space and, on Linux, the disk quota of the current user for
+the filesystem holding a path (the current directory by default). The path
+does not need to exist yet.
+
+Before applying templates, mkcd compares their size with the available
+space and warns when the scaffold would exceed the quota.
+
+Examples:
+  mkcd quota                           # Space available in the current directory
+  mkcd quota /shared/projects/new      # Space available for a new directory
+  mkcd quota --json                    # Machine-readable output for tooling`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runQuota,
+}
+
+func init() {
+	rootCmd.AddCommand(quotaCmd)
+
+	quotaCmd.Flags().BoolVar(&quotaJSON, "json", false, "print the space information as JSON")
+}
+
+// runQuota prints the space available at a path
+func runQuota(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+
+	info, err := utils.GetSpaceInfo(absPath)
+	if err != nil {
+		return err
+	}
+
+	// JSON is machine-readable output, printed as-is to stdout
+	if quotaJSON {
+		data, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode space information: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	outputMgr.Header("Available Space")
+	items := []string{
+		fmt.Sprintf("Path: %s", info.Path),
+		fmt.Sprintf("Filesystem Free: %s", utils.FormatBytes(int64(info.FilesystemAvailable))),
+	}
+	if info.HasQuota {
+		items = append(items,
+			fmt.Sprintf("Quota: %s of %s used, %s remaining",
+				utils.FormatBytes(int64(info.QuotaUsed)), utils.FormatBytes(int64(info.QuotaLimit)), utils.FormatBytes(int64(info.QuotaRemaining))),
+		)
+	} else {
+		items = append(items, "Quota: none")
+	}
+	items = append(items, fmt.Sprintf("Available: %s", utils.FormatBytes(int64(info.Available))))
+	outputMgr.List(items)
+	return nil
+}
+
+// checkScaffoldSpace warns when the templates to apply are larger than the
+// space or quota left at the target. Space that cannot be determined is
+// not an error.
+func checkScaffoldSpace(targetPath string, mkcdConfig MkcdConfig, cfg *config.Config, outputMgr *utils.OutputManager) {
+	var size uint64
+	for _, name := range templates.ParseNames(mkcdConfig.Template) {
+		dir, err := templates.LocalDir(cfg.Templates.Directory, templateSource(cfg, name))
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+		if layerSize, err := utils.DirSize(dir); err == nil {
+			size += layerSize
+		}
+	}
+	if size == 0 {
+		return
+	}
+
+	info, err := utils.GetSpaceInfo(targetPath)
+	if err != nil {
+		outputMgr.Debug(fmt.Sprintf("Could not determine available space: %v", err))
+		return
+	}
+
+	outputMgr.Verbose(fmt.Sprintf("Scaffold size %s, %s available", utils.FormatBytes(int64(size)), utils.FormatBytes(int64(info.Available))))
+	if info.Fits(size) {
+		return
+	}
+
+	limit := "free space"
+	if info.HasQuota && info.QuotaRemaining <= info.FilesystemAvailable {
+		limit = "remaining disk quota"
+	}
+	outputMgr.Warning(fmt.Sprintf("The templates need about %s but only %s of %s is left at %s",
+		utils.FormatBytes(int64(size)), utils.FormatBytes(int64(info.Available)), limit, info.Path))
+}
@@ -0,0 +1,192 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/history"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// redoFrom selects the creation to repeat by history ID or directory
+var redoFrom string
+
+// redoSkippedFlags only change how a creation reports or continues, not
+// what it creates, so they are not repeated
+var redoSkippedFlags = []string{"print-path", "json", "spawn"}
+
+// redoCmd represents the redo command
+var redoCmd = &cobra.Command{
+	Use:   "redo <dir>...",
+	Short: "Create directories like the last creation",
+	Long: `Create new directories with the profile and the command-line flags of
+the last creation recorded in the history, e.g. to spin up many similar
+experiment directories. Template, Git, generator and file flags are
+repeated; --print-path, --json and --spawn are not.
+
+--from picks another creation by history ID (or ID prefix), or by the
+directory it created when that holds a workspace manifest
+(core.workspace_manifest), which works without the history. The global
+--profile flag replaces the recorded profile.
+
+Like a creation, the path is emitted so the shell integration changes into
+the new directory.
+
+Examples:
+  mkcd mkcd exp1 --template python --git
+  mkcd redo exp2                           # Same template and Git setup
+  mkcd redo exp3 exp4                      # Several at once
+  mkcd redo exp5 --from 3f2a9c             # Repeat an older creation
+  mkcd redo exp6 --from ./exp1             # Repeat from a workspace manifest`,
+	Args:        cobra.MinimumNArgs(1),
+	Annotations: map[string]string{changesDirAnnotation: "true"},
+	RunE:        runRedo,
+}
+
+func init() {
+	rootCmd.AddCommand(redoCmd)
+
+	redoCmd.Flags().StringVar(&redoFrom, "from", "", "repeat the creation with this history ID, or of this directory")
+	redoCmd.Flags().BoolVar(&printPath, "print-path", false, "print only the created path on stdout, all other output on stderr")
+}
+
+// redoSource is a recorded creation to repeat
+type redoSource struct {
+	id      string
+	path    string
+	profile string
+	flags   []string
+}
+
+// runRedo repeats a recorded creation for new directories
+func runRedo(cmd *cobra.Command, args []string) error {
+	// Keep stdout free for the path
+	if printPath {
+		redirectOutput(os.Stderr)
+	}
+
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	cmd.SilenceUsage = true
+	source, err := findRedoSource(cfg)
+	if err != nil {
+		return err
+	}
+	if err := replayFlags(source.flags, outputMgr); err != nil {
+		return err
+	}
+	if profile == "" {
+		profile = source.profile
+	}
+
+	outputMgr.Verbose(fmt.Sprintf("Repeating %s (%s): %s", source.id, source.path, valueOrDash(strings.Join(mkcdFlagsFor(source.flags), " "))))
+
+	return runMkcd(mkcdCmd, args)
+}
+
+// findRedoSource returns the creation selected by --from, or the last one
+// in the history
+func findRedoSource(cfg *config.Config) (*redoSource, error) {
+	if redoFrom != "" && utils.IsDirectory(redoFrom) {
+		dir, err := filepath.Abs(redoFrom)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", redoFrom, err)
+		}
+		manifest, err := history.LoadWorkspaceManifest(dir)
+		if err != nil {
+			return nil, err
+		}
+		return &redoSource{id: manifest.ID, path: manifest.Path, profile: manifest.Profile, flags: manifest.Flags}, nil
+	}
+
+	store, err := newHistoryStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var entry *history.Entry
+	if redoFrom != "" {
+		if entry, err = store.Find(redoFrom); err != nil {
+			return nil, err
+		}
+	} else {
+		entries, err := store.Load()
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			return nil, fmt.Errorf("no creation recorded in the history to repeat")
+		}
+		entry = &entries[len(entries)-1]
+	}
+
+	flags := entry.Flags
+	// Entries recorded before flags were kept only know the template and Git
+	if len(flags) == 0 {
+		if entry.Template != "" {
+			flags = append(flags, "--template="+entry.Template)
+		}
+		if entry.Git {
+			flags = append(flags, "--git")
+		}
+	}
+	return &redoSource{id: valueOrDash(entry.ID), path: entry.Path, profile: entry.Profile, flags: flags}, nil
+}
+
+// mkcdFlagsFor returns the recorded flags that are repeated: those of the
+// mkcd command except the skipped ones. Global flags such as --config
+// belong to the current invocation.
+func mkcdFlagsFor(flags []string) []string {
+	repeated := []string{}
+	for _, flag := range flags {
+		name, _, _ := strings.Cut(strings.TrimPrefix(flag, "--"), "=")
+		if mkcdCmd.LocalFlags().Lookup(name) != nil && !slices.Contains(redoSkippedFlags, name) {
+			repeated = append(repeated, flag)
+		}
+	}
+	return repeated
+}
+
+// replayFlags sets the recorded flags on the mkcd command as if they were
+// given on the command line
+func replayFlags(flags []string, outputMgr *utils.OutputManager) error {
+	for _, flag := range flags {
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(flag, "--"), "=")
+		if slices.Contains(redoSkippedFlags, name) || rootCmd.PersistentFlags().Lookup(name) != nil {
+			continue
+		}
+		if mkcdCmd.LocalFlags().Lookup(name) == nil {
+			outputMgr.Warning(fmt.Sprintf("Not repeating %s: mkcd no longer has this flag", flag))
+			continue
+		}
+		if !hasValue {
+			value = "true"
+		}
+		if err := mkcdCmd.Flags().Set(name, value); err != nil {
+			return fmt.Errorf("failed to repeat %s: %w", flag, err)
+		}
+	}
+	return nil
+}
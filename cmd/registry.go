@@ -0,0 +1,574 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/history"
+	"github.com/mochajutsu/mkcd/internal/project"
+	"github.com/mochajutsu/mkcd/internal/registry"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// Registry command flags
+var (
+	registryGCAuto    bool
+	registryScanYes   bool
+	registryScanDepth int
+)
+
+// registryCmd represents the registry command
+var registryCmd = &cobra.Command{
+	Use:   "registry",
+	Short: "Manage the registry of projects and sync it between machines",
+	Long: `Manage the registry of mkcd projects.
+
+Every directory created by mkcd gets a .mkcd.toml file holding a unique
+project ID (disable with core.project_file = false). The registry maps these
+IDs to where each project lives on every machine, and can be synced through
+a git repository or a WebDAV endpoint you provide:
+
+  [sync]
+  backend = "git"                                    # or "webdav"
+  url = "git@github.com:me/mkcd-registry.git"        # for webdav: URL of the registry file
+  username = "me"                                    # webdav only; password from MKCD_SYNC_PASSWORD
+
+Examples:
+  mkcd registry list              # List registered projects
+  mkcd registry add               # Register the current directory
+  mkcd registry add ~/src/api     # Register an existing directory
+  mkcd registry sync              # Merge with the remote registry
+  mkcd registry gc                # Clean up projects that no longer exist
+  mkcd registry scan ~/src        # Import existing projects`,
+}
+
+// registryListCmd represents the registry list command
+var registryListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered projects",
+	Long:  `List registered projects and their path on this machine.`,
+	Args:  cobra.NoArgs,
+	RunE:  runRegistryList,
+}
+
+// registryAddCmd represents the registry add command
+var registryAddCmd = &cobra.Command{
+	Use:   "add [dir]",
+	Short: "Register an existing directory",
+	Long: `Register an existing directory, creating its .mkcd.toml project file if it
+does not have one yet.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRegistryAdd,
+}
+
+// registrySyncCmd represents the registry sync command
+var registrySyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync the registry with the configured remote",
+	Long:  `Merge the remote registry into the local one and push the result back.`,
+	Args:  cobra.NoArgs,
+	RunE:  runRegistrySync,
+}
+
+// registryGCCmd represents the registry gc command
+var registryGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Remove registry entries whose projects are gone",
+	Long: `Find registry entries whose path on this machine is orphaned and remove
+that path from the registry:
+
+• missing            The directory no longer exists
+• moved to trash     The directory is gone and the project is in the trash
+• inside trash       The registered path lies inside the trash
+• different project  The directory now holds another project or none
+
+Each orphan is confirmed interactively unless --auto is given. Removals are
+kept when syncing, so other machines do not bring the paths back.
+
+Examples:
+  mkcd registry gc              # Review and remove orphans one by one
+  mkcd registry gc --auto       # Remove all orphans
+  mkcd registry gc --dry-run    # Only list the orphans`,
+	Args: cobra.NoArgs,
+	RunE: runRegistryGC,
+}
+
+// registryScanCmd represents the registry scan command
+var registryScanCmd = &cobra.Command{
+	Use:   "scan <root>",
+	Short: "Import existing projects below a directory",
+	Long: `Walk a directory tree, detect project roots and offer to import them into
+the registry.
+
+Git repositories, directories with a language manifest (go.mod,
+package.json, pyproject.toml, ...) and existing mkcd projects are detected;
+the walk does not descend into them. Imported directories get a .mkcd.toml
+project file with tags inferred from their contents (languages, git) and
+the profile whose name or gitignore matches their language.
+
+Examples:
+  mkcd registry scan ~/src              # Confirm each project
+  mkcd registry scan ~/src --yes        # Import everything found
+  mkcd registry scan ~/src --depth 2    # Only look two levels deep
+  mkcd registry scan ~/src --dry-run    # Only list what would be imported`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRegistryScan,
+}
+
+func init() {
+	rootCmd.AddCommand(registryCmd)
+
+	// Add subcommands
+	registryCmd.AddCommand(registryListCmd)
+	registryCmd.AddCommand(registryAddCmd)
+	registryCmd.AddCommand(registrySyncCmd)
+	registryCmd.AddCommand(registryGCCmd)
+	registryCmd.AddCommand(registryScanCmd)
+
+	registryGCCmd.Flags().BoolVar(&registryGCAuto, "auto", false, "remove all orphans without asking")
+	registryScanCmd.Flags().BoolVarP(&registryScanYes, "yes", "y", false, "import all projects found without asking")
+	registryScanCmd.Flags().IntVar(&registryScanDepth, "depth", 4, "how many directory levels below the root to search")
+}
+
+// runRegistryList lists registered projects
+func runRegistryList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	reg, _, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+
+	if len(reg.Projects) == 0 {
+		outputMgr.Info("No projects registered yet")
+		return nil
+	}
+
+	outputMgr.Header("Registered Projects")
+
+	host := registry.Hostname()
+	rows := [][]string{}
+	for _, entry := range reg.Entries() {
+		// Entries removed everywhere are only kept for syncing
+		if len(entry.Paths) == 0 {
+			continue
+		}
+		others := []string{}
+		for _, other := range utils.SortedKeys(entry.Paths) {
+			if other != host {
+				others = append(others, other)
+			}
+		}
+		name := entry.Name
+		if _, frozen := entry.Frozen[host]; frozen {
+			name += " (frozen)"
+		}
+		rows = append(rows, []string{
+			entry.ID[:8],
+			name,
+			valueOrDash(entry.Paths[host]),
+			valueOrDash(strings.Join(entry.Tags, ", ")),
+			valueOrDash(strings.Join(others, ", ")),
+		})
+	}
+	outputMgr.Table([]string{"ID", "Name", "Path", "Tags", "Other Machines"}, rows)
+	return nil
+}
+
+// runRegistryAdd registers an existing directory
+func runRegistryAdd(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	dir, err = utils.GetAbsolutePath(dir)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	if !utils.IsDirectory(dir) {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	if !utils.PathExists(project.Path(dir)) {
+		if dryRun {
+			outputMgr.Info(fmt.Sprintf("[DRY RUN] Would create %s and register %s", project.Path(dir), dir))
+			return nil
+		}
+		fsOps := utils.NewFileSystemOperations(false, backup)
+		fsOps.Output = outputMgr
+		fsOps.Encoding = fileEncoding(cfg)
+		if err := project.New(dir, "").Write(fsOps, dir); err != nil {
+			return err
+		}
+	}
+
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would register %s", dir))
+		return nil
+	}
+
+	if err := registerProject(dir); err != nil {
+		return err
+	}
+	outputMgr.Success(fmt.Sprintf("Registered project: %s", dir))
+	return nil
+}
+
+// runRegistrySync syncs the registry with the configured remote
+func runRegistrySync(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	stateDir, err := history.StateDir()
+	if err != nil {
+		return err
+	}
+	backend, err := registry.NewBackend(cfg.Sync.Backend, cfg.Sync.URL, cfg.Sync.Username, filepath.Join(stateDir, "sync"))
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would sync the registry with %s (%s)", cfg.Sync.URL, cfg.Sync.Backend))
+		return nil
+	}
+
+	reg, path, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+
+	before := hostPaths(reg)
+	result, err := registry.Sync(context.Background(), backend, reg)
+	if err != nil {
+		return err
+	}
+	if err := reg.Save(path); err != nil {
+		return err
+	}
+
+	// Bookmarks and pins follow projects the remote moved on this machine
+	changes, err := moveWorkspaceRefs(registryMoves(before, hostPaths(reg)))
+	if err != nil {
+		outputMgr.Warning(fmt.Sprintf("Bookmarks and pins not updated: %v", err))
+	}
+	for _, change := range changes {
+		outputMgr.Info(change)
+	}
+
+	outputMgr.Success(fmt.Sprintf("Registry synced: %d updated from remote, %d projects total", result.Pulled, result.Total))
+	return nil
+}
+
+// runRegistryGC removes orphaned registry entries
+func runRegistryGC(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	reg, path, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+
+	host := registry.Hostname()
+	orphans := reg.Orphans(host, registry.TrashDirs())
+	if len(orphans) == 0 {
+		outputMgr.Success("No orphaned registry entries")
+		return nil
+	}
+
+	outputMgr.Header("Orphaned Projects")
+	rows := [][]string{}
+	for _, orphan := range orphans {
+		rows = append(rows, []string{
+			orphan.Entry.ID[:8],
+			orphan.Entry.Name,
+			orphan.Path,
+			orphan.Reason,
+		})
+	}
+	outputMgr.Table([]string{"ID", "Name", "Path", "Reason"}, rows)
+
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would remove %d entries from the registry", len(orphans)))
+		return nil
+	}
+
+	removed := 0
+	moves := map[string]string{}
+	for _, orphan := range orphans {
+		if !registryGCAuto {
+			question := fmt.Sprintf("Remove %s (%s, %s)?", orphan.Entry.Name, orphan.Path, orphan.Reason)
+			confirmed, err := outputMgr.Confirm(question, false)
+			if err != nil {
+				return err
+			}
+			if !confirmed {
+				continue
+			}
+		}
+
+		if reg.Forget(orphan.Entry.ID, host) {
+			removed++
+			if orphan.TrashPath != "" && orphan.TrashPath != orphan.Path {
+				outputMgr.Verbose(fmt.Sprintf("%s is still in the trash at %s", orphan.Entry.Name, orphan.TrashPath))
+			}
+			// A directory now holding another project keeps its bookmarks
+			if orphan.Reason != registry.ReasonReplaced {
+				moves[orphan.Path] = ""
+			}
+		}
+	}
+
+	if removed == 0 {
+		outputMgr.Info("No entries removed")
+		return nil
+	}
+	if err := reg.Save(path); err != nil {
+		return err
+	}
+
+	changes, err := moveWorkspaceRefs(moves)
+	if err != nil {
+		outputMgr.Warning(fmt.Sprintf("Bookmarks and pins not updated: %v", err))
+	}
+	for _, change := range changes {
+		outputMgr.Info(change)
+	}
+	outputMgr.Success(fmt.Sprintf("Removed %d of %d orphaned entries", removed, len(orphans)))
+	return nil
+}
+
+// runRegistryScan imports the projects found below a directory
+func runRegistryScan(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	if registryScanDepth < 1 {
+		return fmt.Errorf("--depth must be at least 1")
+	}
+	root, err := utils.GetAbsolutePath(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	if !utils.IsDirectory(root) {
+		return fmt.Errorf("%s is not a directory", root)
+	}
+
+	candidates, err := registry.Scan(root, registryScanDepth)
+	if err != nil {
+		return fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+
+	reg, _, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+	host := registry.Hostname()
+
+	// Projects already registered at their current path are left alone
+	pending := []registry.Candidate{}
+	for _, candidate := range candidates {
+		if candidate.Project != nil && reg.Projects[candidate.Project.ID].Paths[host] == candidate.Path {
+			continue
+		}
+		pending = append(pending, candidate)
+	}
+
+	if len(pending) == 0 {
+		outputMgr.Info(fmt.Sprintf("No unregistered projects found below %s (%d already registered)", root, len(candidates)))
+		return nil
+	}
+
+	outputMgr.Header(fmt.Sprintf("Projects Found: %d", len(pending)))
+	rows := [][]string{}
+	for _, candidate := range pending {
+		status := "new"
+		if candidate.Project != nil {
+			status = "has project file"
+		}
+		rows = append(rows, []string{
+			candidate.Path,
+			valueOrDash(strings.Join(candidate.Tags, ", ")),
+			valueOrDash(inferProfile(cfg, candidate.Tags)),
+			status,
+		})
+	}
+	outputMgr.Table([]string{"Path", "Tags", "Profile", "Status"}, rows)
+
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would import %d projects", len(pending)))
+		return nil
+	}
+
+	fsOps := utils.NewFileSystemOperations(false, backup)
+	fsOps.Encoding = fileEncoding(cfg)
+	fsOps.Silent = true
+
+	imported := 0
+	for _, candidate := range pending {
+		if !registryScanYes {
+			confirmed, err := outputMgr.Confirm(fmt.Sprintf("Import %s?", candidate.Path), true)
+			if err != nil {
+				return err
+			}
+			if !confirmed {
+				continue
+			}
+		}
+
+		// Existing project files keep their ID, new ones get the inferred details
+		if candidate.Project == nil {
+			p := project.New(candidate.Path, inferProfile(cfg, candidate.Tags))
+			p.Tags = candidate.Tags
+			if err := p.Write(fsOps, candidate.Path); err != nil {
+				outputMgr.Warning(fmt.Sprintf("Skipping %s: %v", candidate.Path, err))
+				continue
+			}
+		}
+		if err := registerProject(candidate.Path); err != nil {
+			outputMgr.Warning(fmt.Sprintf("Skipping %s: %v", candidate.Path, err))
+			continue
+		}
+		imported++
+	}
+
+	outputMgr.Success(fmt.Sprintf("Imported %d of %d projects", imported, len(pending)))
+	return nil
+}
+
+// inferProfile returns the first profile, by name, whose name or gitignore
+// matches one of the tags
+func inferProfile(cfg *config.Config, tags []string) string {
+	for _, name := range utils.SortedKeys(cfg.Profiles) {
+		for _, tag := range tags {
+			if name == tag || cfg.Profiles[name].Gitignore == tag {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// loadRegistry loads the local registry and returns it with its path
+func loadRegistry() (*registry.Registry, string, error) {
+	path, err := registry.DefaultPath()
+	if err != nil {
+		return nil, "", err
+	}
+
+	reg, err := registry.Load(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return reg, path, nil
+}
+
+// writeProjectFile gives a new directory its project file. Existing project
+// files are kept so a project never changes its ID.
+func writeProjectFile(targetPath string, mkcdConfig MkcdConfig, cfg *config.Config, fsOps *utils.FileSystemOperations, outputMgr *utils.OutputManager) error {
+	if !cfg.Core.ProjectFile || mkcdConfig.Symlink != "" {
+		return nil
+	}
+
+	if _, err := fsOps.FS.Stat(project.Path(targetPath)); err == nil {
+		outputMgr.Verbose(fmt.Sprintf("Keeping existing project file: %s", project.Path(targetPath)))
+		return nil
+	}
+
+	return project.New(targetPath, mkcdConfig.Profile).Write(fsOps, targetPath)
+}
+
+// registerProject records the project in dir under this machine's host name
+func registerProject(dir string) error {
+	p, err := project.Load(dir)
+	if err != nil {
+		return err
+	}
+
+	reg, path, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+
+	before := hostPaths(reg)
+	if !reg.Register(p, dir, registry.Hostname()) {
+		return nil
+	}
+	if err := reg.Save(path); err != nil {
+		return fmt.Errorf("failed to record project in registry: %w", err)
+	}
+
+	// Registering a moved project moves its bookmarks and pins along
+	_, err = moveWorkspaceRefs(registryMoves(before, hostPaths(reg)))
+	return err
+}
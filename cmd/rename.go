@@ -0,0 +1,215 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/history"
+	"github.com/mochajutsu/mkcd/internal/project"
+	"github.com/mochajutsu/mkcd/internal/registry"
+	"github.com/mochajutsu/mkcd/internal/shell"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// renameProjectCmd represents the rename-project command
+var renameProjectCmd = &cobra.Command{
+	Use:   "rename-project <directory> <new-name>",
+	Short: "Rename a project and the name inside its files",
+	Long: `Rename a project directory and update its name where mkcd put it.
+
+Files mkcd generated that were not changed since, according to the
+creation manifest in the history, have every occurrence of the old name as
+a whole word replaced. In other files only the fields holding the name
+change: the README.md title, the last element of the go.mod module path
+and the name in package.json, pyproject.toml and Cargo.toml. The project
+file, the registry entry, bookmarks and pins follow the new name and path.
+
+The directory stays in the same parent; use mv to move it elsewhere and
+'mkcd registry add' to record the new place.
+
+Examples:
+  mkcd rename-project api billing-api      # Rename ./api to ./billing-api
+  mkcd rename-project ~/src/app web -n     # Show what would change`,
+	Args: cobra.ExactArgs(2),
+	RunE: runRenameProject,
+}
+
+func init() {
+	rootCmd.AddCommand(renameProjectCmd)
+
+	renameProjectCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return nil, cobra.ShellCompDirectiveFilterDirs
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// runRenameProject renames a project directory, the name in its files and
+// its registry entry
+func runRenameProject(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	dir, err := utils.GetAbsolutePath(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	if !utils.IsDirectory(dir) {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	if err := checkNotFrozen(dir); err != nil {
+		return err
+	}
+
+	newName := args[1]
+	if err := utils.ValidateDirectoryName(newName); err != nil {
+		return fmt.Errorf("invalid project name: %w", err)
+	}
+	newPath := filepath.Join(filepath.Dir(dir), newName)
+	cmd.SilenceUsage = true
+
+	// Neither the project nor its new path may be worked on meanwhile
+	if !dryRun {
+		for _, path := range []string{dir, newPath} {
+			lock, err := lockTarget(path, cfg, outputMgr)
+			if err != nil {
+				return err
+			}
+			defer lock.Unlock()
+		}
+	}
+
+	if _, err := os.Lstat(newPath); err == nil {
+		return fmt.Errorf("%s already exists", newPath)
+	}
+
+	oldName := filepath.Base(dir)
+	p, projectErr := project.Load(dir)
+	if projectErr == nil && p.Name != "" {
+		oldName = p.Name
+	}
+
+	edits, err := project.RenameEdits(dir, oldName, newName, unchangedGeneratedFiles(dir, cfg))
+	if err != nil {
+		return err
+	}
+
+	fsOps := utils.NewFileSystemOperations(dryRun, backup || cfg.Core.BackupEnabled)
+	fsOps.Silent = true
+
+	changes := []string{}
+	for _, edit := range edits {
+		if err := fsOps.CreateFile(filepath.Join(dir, filepath.FromSlash(edit.Path)), string(edit.Content), edit.Mode); err != nil {
+			return err
+		}
+		changes = append(changes, fmt.Sprintf("%s (%s)", edit.Path, edit.Reason))
+	}
+	if projectErr == nil {
+		p.Name = newName
+		if err := p.Write(fsOps, dir); err != nil {
+			return err
+		}
+		changes = append(changes, fmt.Sprintf("%s (project name)", project.FileName))
+	}
+
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would rename %s to %s", dir, newPath))
+		if len(changes) > 0 {
+			outputMgr.Info("[DRY RUN] Would update:")
+			outputMgr.List(changes)
+		}
+		return nil
+	}
+
+	if err := os.Rename(dir, newPath); err != nil {
+		return fmt.Errorf("failed to rename %s: %w", dir, err)
+	}
+	if len(changes) > 0 {
+		outputMgr.Section("Updated Files")
+		outputMgr.List(changes)
+	}
+
+	if projectErr == nil {
+		reg, path, err := loadRegistry()
+		if err != nil {
+			return err
+		}
+		if reg.Register(p, newPath, registry.Hostname()) {
+			if err := reg.Save(path); err != nil {
+				return fmt.Errorf("failed to update registry: %w", err)
+			}
+			outputMgr.Verbose(fmt.Sprintf("Registry entry %s now points to %s", p.ID, newPath))
+		}
+	}
+
+	refs, err := moveWorkspaceRefs(map[string]string{dir: newPath})
+	if err != nil {
+		outputMgr.Warning(fmt.Sprintf("Bookmarks and pins not updated: %v", err))
+	}
+	for _, ref := range refs {
+		outputMgr.Verbose(ref)
+	}
+
+	outputMgr.Success(fmt.Sprintf("Renamed %s to %s", oldName, newPath))
+
+	// A shell inside the project keeps the old, now missing, directory
+	cwd, err := os.Getwd()
+	if rel, relErr := filepath.Rel(dir, cwd); err != nil || (relErr == nil && !strings.HasPrefix(rel, "..")) {
+		outputMgr.Info(fmt.Sprintf("Change to the new directory with: cd -- %s", shell.Quote(shell.Bash, newPath)))
+	}
+	return nil
+}
+
+// unchangedGeneratedFiles returns the files mkcd generated in dir, relative
+// to it, whose content is still what the latest creation manifest recorded
+func unchangedGeneratedFiles(dir string, cfg *config.Config) []string {
+	store, err := newHistoryStore(cfg)
+	if err != nil {
+		return nil
+	}
+	entries, err := store.Load()
+	if err != nil {
+		return nil
+	}
+
+	var manifest *history.Manifest
+	for i := len(entries) - 1; i >= 0 && manifest == nil; i-- {
+		if entries[i].Path == dir && entries[i].ID != "" {
+			manifest, _ = store.LoadManifest(entries[i].ID)
+		}
+	}
+	if manifest == nil {
+		return nil
+	}
+
+	files := []string{}
+	for _, file := range manifest.Files {
+		checksum, _, err := history.FileChecksum(filepath.Join(dir, filepath.FromSlash(file.Path)))
+		if err == nil && checksum == file.SHA256 {
+			files = append(files, file.Path)
+		}
+	}
+	return files
+}
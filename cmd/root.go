@@ -6,23 +6,34 @@ Licensed under the MIT License. See LICENSE file for details.
 package cmd
 
 import (
+	"errors"
 	"os"
+	"time"
 
+	"github.com/mochajutsu/mkcd/internal/utils"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 )
 
+// deterministicEnv enables deterministic output without passing the flag, e.g. in CI
+const deterministicEnv = "MKCD_DETERMINISTIC"
+
+// exitPartialFailure is the exit code when only some items of a batch failed
+const exitPartialFailure = 2
+
 // Global configuration variables
 var (
-	cfgFile     string
-	profile     string
-	dryRun      bool
-	verbose     bool
-	quiet       bool
-	debug       bool
-	force       bool
-	interactive bool
-	backup      bool
+	cfgFile       string
+	profile       string
+	dryRun        bool
+	verbose       bool
+	quiet         bool
+	debug         bool
+	force         bool
+	interactive   bool
+	backup        bool
+	deterministic bool
+	progressJSON  bool
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -41,12 +52,16 @@ workspace initialization tool with features like:
 • Batch operations and safety checks
 
 Examples:
-  mkcd myproject                    # Create directory and prepare for cd
+  eval "$(mkcd shell-init bash)"    # Enable the shell wrapper (add to ~/.bashrc)
+  mkcd myproject                    # Create directory and change into it
   mkcd myproject --git --editor     # Create with git repo and open in editor
   mkcd myproject --template nodejs  # Create using Node.js template
   mkcd myproject --profile dev      # Create using 'dev' profile`,
 	Version: "1.0.0",
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// Fix timestamps and durations for reproducible output
+		utils.SetDeterministic(deterministic || os.Getenv(deterministicEnv) != "")
+
 		// Configure pterm based on flags
 		if quiet {
 			pterm.DisableOutput()
@@ -57,17 +72,29 @@ Examples:
 		if !verbose && !debug {
 			pterm.DisableStyling()
 		}
+
+		// Keep stdout free for the progress events
+		if progressJSON {
+			redirectOutput(os.Stderr)
+		}
 	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	err := rootCmd.Execute()
+	start := time.Now()
+	cmd, err := rootCmd.ExecuteC()
+	recordUsage(cmd, start, err)
 	if err != nil {
 		if !quiet {
 			pterm.Error.Printf("Command failed: %v\n", err)
 		}
+
+		var partialErr *utils.PartialFailureError
+		if errors.As(err, &partialErr) {
+			os.Exit(exitPartialFailure)
+		}
 		os.Exit(1)
 	}
 }
@@ -83,9 +110,12 @@ func init() {
 	rootCmd.PersistentFlags().BoolVarP(&force, "force", "f", false, "override safety checks")
 	rootCmd.PersistentFlags().BoolVarP(&interactive, "interactive", "i", false, "interactive mode for confirmations")
 	rootCmd.PersistentFlags().BoolVar(&backup, "backup", false, "backup existing directories before operations")
+	rootCmd.PersistentFlags().BoolVar(&deterministic, "deterministic", false, "stable output: fixed timestamps (SOURCE_DATE_EPOCH), no durations, sorted listings")
+	rootCmd.PersistentFlags().BoolVar(&progressJSON, "progress-json", false, "print progress events as JSON lines on stdout, all other output on stderr (or set MKCD_PROGRESS_FD)")
 
 	// Mark some flags as mutually exclusive
 	rootCmd.MarkFlagsMutuallyExclusive("verbose", "quiet")
-}
-
 
+	// Complete flag values from the configuration
+	registerFlagCompletion(rootCmd, "profile", completeProfiles)
+}
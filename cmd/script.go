@@ -0,0 +1,297 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/shell"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// scriptOutput is the file the script is written to; stdout when empty
+var scriptOutput string
+
+// scriptCmd represents the script command
+var scriptCmd = &cobra.Command{
+	Use:   "script <directory>",
+	Short: "Print a shell script reproducing a creation",
+	Long: `Print a standalone bash script that does what mkcd would do for a
+directory with the same flags, without creating anything: the directories,
+every file with its content, symlinks, the Git repository with its remote
+and initial commit, the hooks and the --then command.
+
+The script only needs bash, coreutils and git, so a creation can be
+reviewed before it runs or replayed on machines without mkcd. The history
+and the registry of mkcd are not updated, and no editor is opened.
+
+Examples:
+  mkcd script api --template go --git      # Print the script
+  mkcd script api -t go -o create-api.sh   # Write it to an executable file
+  mkcd script api --readme | ssh host bash # Replay on another machine`,
+	Args: cobra.ExactArgs(1),
+	RunE: runScript,
+}
+
+func init() {
+	rootCmd.AddCommand(scriptCmd)
+
+	// The mkcd flags are shared so the script matches the real invocation
+	scriptCmd.Flags().AddFlagSet(mkcdCmd.Flags())
+	scriptCmd.Flags().StringVarP(&scriptOutput, "output", "o", "", "write the script to this file instead of stdout")
+}
+
+// runScript prints the script reproducing the creation of a directory
+func runScript(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	cfg, mkcdConfig, err := loadPlumbingConfig()
+	if err != nil {
+		return err
+	}
+
+	targetPath, err := determineTargetPath(args[0], mkcdConfig, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to determine target path: %w", err)
+	}
+
+	steps, err := resolvePipelineSteps(mkcdConfig.Steps, mkcdConfig.SkipSteps)
+	if err != nil {
+		return fmt.Errorf("invalid pipeline configuration: %w", err)
+	}
+	enabled := map[string]bool{}
+	for _, name := range stepNames(steps) {
+		enabled[name] = true
+	}
+
+	outputMgr := utils.NewOutputManager(false, false, false, true, false, debug)
+	fsOps, err := renderPipeline(targetPath, cfg, mkcdConfig, outputMgr)
+	if err != nil {
+		return err
+	}
+
+	var sb scriptBuilder
+	sb.line("#!/usr/bin/env bash")
+	sb.line("# Generated by 'mkcd script' on %s", utils.Now().Format("2006-01-02 15:04"))
+	invocation := []string{"mkcd"}
+	for _, arg := range os.Args[1:] {
+		invocation = append(invocation, quoteScript(arg))
+	}
+	sb.line("# Generated by: %s", strings.Join(invocation, " "))
+	sb.line("set -euo pipefail")
+	sb.line("")
+	if err := sb.entries(targetPath, fsOps); err != nil {
+		return err
+	}
+
+	if enabled[stepGit] && mkcdConfig.Git {
+		sb.line("")
+		if err := sb.git(targetPath, mkcdConfig, cfg); err != nil {
+			return err
+		}
+	}
+	if enabled[stepHooks] && len(mkcdConfig.Hooks) > 0 {
+		sb.line("")
+		sb.line("# Hooks")
+		for _, hook := range mkcdConfig.Hooks {
+			sb.line("%s", hook)
+		}
+	}
+	if mkcdConfig.AsUser != "" {
+		sb.line("")
+		sb.line("chown -R %s: %s", quoteScript(mkcdConfig.AsUser), quoteScript(targetPath))
+	}
+	if mkcdConfig.Then != "" {
+		sb.line("")
+		sb.line("%s", mkcdConfig.Then)
+	}
+
+	if scriptOutput == "" {
+		fmt.Print(sb.String())
+		return nil
+	}
+	if err := os.WriteFile(scriptOutput, []byte(sb.String()), 0755); err != nil {
+		return fmt.Errorf("failed to write script: %w", err)
+	}
+	outputMgr.Success(fmt.Sprintf("Script written to %s", scriptOutput))
+	return nil
+}
+
+// scriptBuilder accumulates the lines of a generated script
+type scriptBuilder struct {
+	strings.Builder
+}
+
+// line appends a formatted line
+func (sb *scriptBuilder) line(format string, args ...any) {
+	fmt.Fprintf(sb, format, args...)
+	sb.WriteByte('\n')
+}
+
+// entries appends the commands creating the directories, files and
+// symlinks written to the overlay of fsOps. The script changes into the
+// target first and uses paths relative to it.
+func (sb *scriptBuilder) entries(targetPath string, fsOps *utils.FileSystemOperations) error {
+	changes := fsOps.DryRunChanges()
+	sources := map[string]string{}
+	for _, record := range fsOps.Records() {
+		sources[record.Path] = record.Source
+	}
+
+	// A symlinked workspace is a link, not a directory to create
+	for _, change := range changes {
+		if change.Path == targetPath && change.Mode&os.ModeSymlink != 0 {
+			sb.line("mkdir -p %s", quoteScript(filepath.Dir(targetPath)))
+			sb.line("ln -s %s %s", quoteScript(change.Target), quoteScript(targetPath))
+			sb.line("cd %s", quoteScript(targetPath))
+			return nil
+		}
+	}
+
+	sb.line("mkdir -p %s", quoteScript(targetPath))
+	sb.line("cd %s", quoteScript(targetPath))
+
+	for _, change := range changes {
+		path := change.Path
+		if rel, err := filepath.Rel(targetPath, change.Path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+		quoted := quoteScript(path)
+
+		switch {
+		case change.IsDir:
+			// The target and its parents exist after the first mkdir
+			if path != "." && path != change.Path {
+				sb.line("")
+				sb.line("mkdir -p %s", quoted)
+			}
+			if change.Mode.Perm() != 0755 {
+				sb.line("chmod %04o %s", change.Mode.Perm(), quoted)
+			}
+		case change.Mode&os.ModeSymlink != 0:
+			sb.line("ln -sfn %s %s", quoteScript(change.Target), quoted)
+		default:
+			content, err := fsOps.FS.ReadFile(change.Path)
+			if err != nil {
+				return fmt.Errorf("failed to read rendered %s: %w", path, err)
+			}
+			sb.line("")
+			if source := sources[change.Path]; source != "" {
+				sb.line("# %s (%s)", path, source)
+			}
+			sb.file(quoted, content)
+			if change.Mode.Perm() != 0644 {
+				sb.line("chmod %04o %s", change.Mode.Perm(), quoted)
+			}
+		}
+	}
+	return nil
+}
+
+// file appends the command writing content to the quoted path: a quoted
+// here-document for text ending in a newline, printf for anything else
+func (sb *scriptBuilder) file(quoted string, content []byte) {
+	if len(content) == 0 {
+		sb.line(": > %s", quoted)
+		return
+	}
+
+	text := utf8.Valid(content) && !utils.IsBinary(content) && bytes.HasSuffix(content, []byte("\n")) && !bytes.Contains(content, []byte("\r"))
+	if !text {
+		sb.line("printf '%s' > %s", printfEscape(content), quoted)
+		return
+	}
+
+	delimiter := "MKCD_EOF"
+	for i := 1; bytes.Contains(append([]byte("\n"), content...), []byte("\n"+delimiter+"\n")); i++ {
+		delimiter = fmt.Sprintf("MKCD_EOF_%d", i)
+	}
+	sb.line("cat > %s <<'%s'", quoted, delimiter)
+	sb.Write(content)
+	sb.line("%s", delimiter)
+}
+
+// git appends the commands initializing the repository, adding the remote
+// and creating the initial commit, as the git step would
+func (sb *scriptBuilder) git(targetPath string, mkcdConfig MkcdConfig, cfg *config.Config) error {
+	if cfg.Git.DefaultBranch != "" {
+		sb.line("git init -b %s", quoteScript(cfg.Git.DefaultBranch))
+	} else {
+		sb.line("git init")
+	}
+	if mkcdConfig.GitRemote != "" {
+		sb.line("git remote add %s %s", quoteScript(cfg.Git.DefaultRemoteName), quoteScript(mkcdConfig.GitRemote))
+	}
+
+	// With --as-user the first commit is left to the user
+	if mkcdConfig.AsUser != "" || mkcdConfig.NoInitialCommit || !cfg.Git.InitialCommit {
+		return nil
+	}
+
+	message, commitFiles, err := initialCommitSettings(targetPath, mkcdConfig, cfg)
+	if err != nil {
+		return err
+	}
+	if commitFiles == nil {
+		sb.line("git add -A")
+	} else {
+		quoted := make([]string, len(commitFiles))
+		for i, file := range commitFiles {
+			quoted[i] = quoteScript(file)
+		}
+		sb.line("git add -- %s", strings.Join(quoted, " "))
+	}
+
+	// Like mkcd, the commit falls back to the Git configuration and then to
+	// a placeholder identity
+	sb.line("git -c user.name=%s -c user.email=%s commit -m %s",
+		gitIdentity("user.name", cfg.Git.UserName, "mkcd user"),
+		gitIdentity("user.email", cfg.Git.UserEmail, "user@example.com"),
+		quoteScript(message))
+	return nil
+}
+
+// gitIdentity returns the script expression of a commit identity setting:
+// the configured value, or the Git configuration with a fallback
+func gitIdentity(key, value, fallback string) string {
+	if value != "" {
+		return quoteScript(value)
+	}
+	return fmt.Sprintf(`"$(git config %s || echo %s)"`, key, quoteScript(fallback))
+}
+
+// quoteScript quotes a value for the generated bash script
+func quoteScript(value string) string {
+	return shell.Quote(shell.Bash, value)
+}
+
+// printfEscape escapes content for a single-quoted printf format: bytes
+// other than printable ASCII are spelled as octal escapes
+func printfEscape(content []byte) string {
+	var b strings.Builder
+	for _, c := range content {
+		switch {
+		case c == '\'':
+			b.WriteString(`'\''`)
+		case c == '\\':
+			b.WriteString(`\\`)
+		case c == '%':
+			b.WriteString("%%")
+		case c >= 0x20 && c < 0x7f:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, `\%03o`, c)
+		}
+	}
+	return b.String()
+}
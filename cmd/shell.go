@@ -0,0 +1,397 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/history"
+	"github.com/mochajutsu/mkcd/internal/shell"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// Shell-init command flags
+var (
+	shellInstall   bool
+	shellUninstall bool
+)
+
+// shellInitCmd represents the shell-init command
+var shellInitCmd = &cobra.Command{
+	Use:   "shell-init [shell]",
+	Short: "Print the shell function that creates a directory and changes into it",
+	Long: `Print a shell function named mkcd that wraps the binary so that
+'mkcd <directory>' creates the directory and changes into it.
+
+A program cannot change the working directory of the shell that started it,
+so the wrapper runs 'mkcd mkcd --print-path', which prints only the created
+path on stdout (all other output goes to stderr), and changes into it.
+Subcommands such as 'mkcd profile list' are passed through unchanged,
+except those that lead to a directory, such as 'mkcd open', which are run
+with --print-path and changed into as well.
+For fish, the output also registers the mkcd completions. On Windows,
+the PowerShell wrapper uses Set-Location and also accepts the name pwsh.
+Nushell cannot eval generated code, so its wrapper is saved to a file that
+config.nu sources. For the classic Windows command prompt, the output is a
+batch file that a doskey macro calls; it runs mkcd.exe, quotes the printed
+path and changes drive and directory with 'cd /d', so %CD% follows.
+
+The printed path keeps the symlinks of the current directory as the shell
+shows it in $PWD, so the prompt does not switch to the resolved path; pass
+--physical to mkcd to resolve them.
+
+In bash, zsh and fish, the wrapper completes its first argument with the
+subcommands, local directories and the directories recorded in the history
+and the registry, pinned ones first, then the most recent, so
+'mkcd api<Tab>' finds ~/src/api from anywhere. Later arguments use the
+'mkcd completion' script when it is loaded before the wrapper.
+
+Every wrapper sets MKCD_SHELL_INTEGRATION. When a directory is created
+without it, mkcd explains once that it cannot change into it; set
+core.integration_hint = false to turn the hint off.
+
+The shell defaults to the basename of $SHELL. Nothing but a comment is
+printed when core.shell_integration is disabled.
+
+With --install, the line loading the wrapper is added to ~/.bashrc, ~/.zshrc
+or ~/.config/fish/config.fish between managed markers. Running it again
+changes nothing, and --uninstall removes the block again.
+
+Examples:
+  eval "$(mkcd shell-init bash)"   # Add to ~/.bashrc
+  eval "$(mkcd shell-init zsh)"    # Add to ~/.zshrc
+  mkcd shell-init fish | source    # Add to ~/.config/fish/config.fish
+  Invoke-Expression (& mkcd shell-init powershell | Out-String)  # Add to $PROFILE
+  mkcd shell-init nu | save -f ~/.cache/mkcd/init.nu            # Then source it in config.nu
+  mkcd shell-init cmd > "%USERPROFILE%\.mkcd\mkcd.cmd"           # Then: doskey mkcd=call "%USERPROFILE%\.mkcd\mkcd.cmd" $*
+  mkcd shell-init --install        # Set up the detected shell permanently
+  mkcd shell-init zsh --uninstall  # Remove the integration from ~/.zshrc`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: shell.GetSupportedShells(),
+	RunE:      runShellInit,
+}
+
+func init() {
+	rootCmd.AddCommand(shellInitCmd)
+
+	shellInitCmd.Flags().BoolVar(&shellInstall, "install", false, "add the integration to the shell's startup file")
+	shellInitCmd.Flags().BoolVar(&shellUninstall, "uninstall", false, "remove the integration from the shell's startup file")
+	shellInitCmd.MarkFlagsMutuallyExclusive("install", "uninstall")
+}
+
+// runShellInit prints the wrapper function for the requested shell
+func runShellInit(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	shellName := shell.Detect()
+	if len(args) > 0 {
+		shellName = shell.Normalize(args[0])
+	}
+
+	if shellInstall || shellUninstall {
+		return runShellInstall(cfg, shellName)
+	}
+
+	opts := shell.Options{Passthrough: passthroughCommands(), CdCommands: cdCommands()}
+
+	// Fish loads completions by sourcing them, so they ship with the wrapper
+	if shellName == shell.Fish {
+		var completion bytes.Buffer
+		if err := rootCmd.GenFishCompletion(&completion, true); err != nil {
+			return fmt.Errorf("failed to generate fish completions: %w", err)
+		}
+		opts.Completion = completion.String()
+	}
+
+	wrapper, err := shell.Wrapper(shellName, opts)
+	if err != nil {
+		return err
+	}
+
+	// The output is meant for eval, so it goes straight to stdout
+	if !cfg.Core.ShellIntegration {
+		fmt.Println("# mkcd shell integration is disabled (core.shell_integration = false)")
+		return nil
+	}
+
+	fmt.Print(wrapper)
+	return nil
+}
+
+// runShellInstall adds or removes the managed block in the shell's startup file
+func runShellInstall(cfg *config.Config, shellName string) error {
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	homeDir, err := homedir.Dir()
+	if err != nil {
+		return fmt.Errorf("failed to get home directory: %w", err)
+	}
+	path, err := shell.StartupFile(shellName, homeDir)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	var updated string
+	var changed bool
+	if shellUninstall {
+		updated, changed = shell.RemoveBlock(string(content))
+		if !changed {
+			outputMgr.Info(fmt.Sprintf("mkcd is not installed in %s", path))
+			if shell.HasManualInit(string(content)) {
+				outputMgr.Warning(fmt.Sprintf("%s loads mkcd outside the managed block; remove that line by hand", path))
+			}
+			return nil
+		}
+	} else {
+		if shell.HasManualInit(string(content)) {
+			outputMgr.Info(fmt.Sprintf("%s already loads mkcd; leaving it unchanged", path))
+			return nil
+		}
+		updated, changed = shell.AddBlock(string(content), shellName)
+		if !changed {
+			outputMgr.Info(fmt.Sprintf("mkcd is already installed in %s", path))
+			return nil
+		}
+		if !cfg.Core.ShellIntegration {
+			outputMgr.Warning("core.shell_integration is disabled, so the wrapper will not be loaded until it is enabled")
+		}
+	}
+
+	action := "Installed mkcd shell integration in"
+	if shellUninstall {
+		action = "Removed mkcd shell integration from"
+	}
+
+	if dryRun && shellUninstall {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would remove the mkcd block from %s", path))
+		return nil
+	}
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would add to %s:", path))
+		outputMgr.Printf("%s", shell.Block(shellName))
+		return nil
+	}
+
+	fsOps := utils.NewFileSystemOperations(false, backup)
+	fsOps.Silent = true
+	if err := fsOps.CreateFile(path, updated, mode); err != nil {
+		return err
+	}
+
+	outputMgr.Success(fmt.Sprintf("%s %s", action, path))
+	if !shellUninstall {
+		outputMgr.Info("Restart the shell or open a new terminal to start using it")
+	}
+	return nil
+}
+
+// passthroughCommands returns the first arguments the wrapper hands to the
+// binary unchanged: subcommands, their aliases, informational flags and the
+// hidden commands used by shell completion
+func passthroughCommands() []string {
+	names := []string{"help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd}
+	for _, sub := range rootCmd.Commands() {
+		names = append(names, sub.Name())
+		names = append(names, sub.Aliases...)
+	}
+	sort.Strings(names)
+
+	unique := []string{}
+	for i, name := range names {
+		if i == 0 || name != names[i-1] {
+			unique = append(unique, name)
+		}
+	}
+	return append([]string{"-h", "--help", "--version"}, unique...)
+}
+
+// changesDirAnnotation marks subcommands that print a path to change into
+// with --print-path, so the wrapper changes into it like into a created one
+const changesDirAnnotation = "mkcd_changes_dir"
+
+// cdCommands returns the subcommands marked with changesDirAnnotation
+func cdCommands() []string {
+	names := []string{}
+	for _, sub := range rootCmd.Commands() {
+		if _, marked := sub.Annotations[changesDirAnnotation]; marked {
+			names = append(names, sub.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// pathFDEnv names the environment variable holding a file descriptor that
+// receives every created path, one per line, independent of other output
+const pathFDEnv = "MKCD_PATH_FD"
+
+var (
+	pathFDOnce sync.Once
+	pathFDFile *os.File
+	pathFDErr  error
+)
+
+// pathFD returns the file for the descriptor in MKCD_PATH_FD, or nil when
+// the variable is unset
+func pathFD() (*os.File, error) {
+	pathFDOnce.Do(func() {
+		pathFDFile, pathFDErr = openFD(pathFDEnv)
+	})
+	return pathFDFile, pathFDErr
+}
+
+// openFD returns the file for the descriptor number in the environment
+// variable env, or nil when the variable is unset
+func openFD(env string) (*os.File, error) {
+	value := os.Getenv(env)
+	if value == "" {
+		return nil, nil
+	}
+
+	fd, err := strconv.Atoi(value)
+	if err != nil || fd < 0 {
+		return nil, fmt.Errorf("%s must be a file descriptor number, got '%s'", env, value)
+	}
+
+	file := os.NewFile(uintptr(fd), env)
+	if file == nil {
+		return nil, fmt.Errorf("%s=%d is not a valid file descriptor", env, fd)
+	}
+	if _, err := file.Stat(); err != nil {
+		return nil, fmt.Errorf("%s=%d is not an open file descriptor: %w", env, fd, err)
+	}
+	return file, nil
+}
+
+// emitPath writes a created path to the machine-readable channels: stdout
+// with --print-path and the descriptor from MKCD_PATH_FD
+func emitPath(path string) error {
+	if printPath {
+		fmt.Println(path)
+	}
+
+	file, err := pathFD()
+	if err != nil {
+		return err
+	}
+	if file != nil {
+		if _, err := fmt.Fprintln(file, path); err != nil {
+			return fmt.Errorf("failed to write path to %s: %w", pathFDEnv, err)
+		}
+	}
+	return nil
+}
+
+// emitFailurePath emits the path the shell wrapper should change to after
+// creating targetPath failed midway, according to the cd-on-failure policy
+func emitFailurePath(targetPath, policy string, outputMgr *utils.OutputManager) {
+	if dryRun {
+		return
+	}
+
+	path := ""
+	switch policy {
+	case shell.CdPartial:
+		if utils.IsDirectory(targetPath) {
+			path = targetPath
+		}
+	case shell.CdParent:
+		path, _ = utils.ExistingAncestor(filepath.Dir(targetPath))
+	}
+	if path == "" {
+		return
+	}
+
+	// The creation error is what matters, so a broken channel is only logged
+	if err := emitPath(path); err != nil {
+		outputMgr.Debug(err.Error())
+		return
+	}
+	outputMgr.Verbose(fmt.Sprintf("Changing into %s after the failure (cd_on_failure = %s)", path, policy))
+}
+
+// integrationHintFile marks in the state directory that the missing shell
+// integration hint was shown
+const integrationHintFile = "integration-hint-shown"
+
+// showIntegrationHint explains, once, that mkcd cannot change the directory
+// of the shell without the wrapper from 'mkcd shell-init'. It stays silent
+// when a wrapper is loaded, when the path goes to a script (MKCD_PATH_FD,
+// --json) and when nobody is watching the terminal.
+func showIntegrationHint(cfg *config.Config, outputMgr *utils.OutputManager) {
+	if !cfg.Core.IntegrationHint || !cfg.Core.ShellIntegration || os.Getenv(shell.IntegrationEnv) != "" {
+		return
+	}
+	if file, _ := pathFD(); file != nil || jsonOutput || printPath || spawn || dryRun || quiet {
+		return
+	}
+	if !utils.IsTerminal(os.Stderr) && !utils.IsTerminal(os.Stdout) {
+		return
+	}
+
+	stateDir, err := history.StateDir()
+	if err != nil {
+		return
+	}
+	marker := filepath.Join(stateDir, integrationHintFile)
+	if _, err := os.Stat(marker); err == nil {
+		return
+	}
+
+	shellName := shell.Detect()
+	if shellName == "" {
+		shellName = shell.Bash
+	}
+	outputMgr.Warning("Shell integration is not loaded: a program cannot change the directory of your shell, so you are still where you were")
+	homeDir, _ := homedir.Dir()
+	if _, err := shell.StartupFile(shellName, homeDir); err == nil && homeDir != "" {
+		outputMgr.Info("Set it up with: mkcd shell-init --install")
+	} else {
+		outputMgr.Info("Set it up by adding " + shell.InitCommand(shellName) + " to your shell startup file")
+	}
+	outputMgr.Info("This hint is shown once; set core.integration_hint = false to silence it everywhere")
+
+	// Failing to remember only means the hint shows again
+	if err := os.MkdirAll(stateDir, 0755); err == nil {
+		os.WriteFile(marker, []byte(utils.Now().UTC().Format(time.RFC3339)+"\n"), 0644)
+	}
+}
+
+// redirectOutput sends all human-readable output to w
+func redirectOutput(w io.Writer) {
+	utils.SetOutput(w)
+}
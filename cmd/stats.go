@@ -0,0 +1,225 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/stats"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// usageTimings collects the step timings of directories created by this
+// invocation for the usage metrics
+var usageTimings []*utils.Timings
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show the local usage metrics",
+	Long: `Show the usage metrics recorded on this machine.
+
+Metrics are off by default. When enabled, mkcd counts how often each command
+and pipeline step ran and how long it took, in a local file. Paths, directory
+names and arguments are never recorded, and nothing is ever sent anywhere;
+use 'mkcd stats export' with your own collection tooling instead.
+
+  [telemetry]
+  enabled = true
+
+Examples:
+  mkcd stats                        # Show the recorded metrics
+  mkcd stats export > usage.json    # Export them for collection
+  mkcd stats reset                  # Delete the recorded metrics`,
+	Args: cobra.NoArgs,
+	RunE: runStats,
+}
+
+// statsExportCmd represents the stats export command
+var statsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Print the usage metrics as JSON",
+	Long: `Print the usage metrics as JSON, with the mkcd version and platform added
+so files collected from several machines can be compared.`,
+	Args: cobra.NoArgs,
+	RunE: runStatsExport,
+}
+
+// statsResetCmd represents the stats reset command
+var statsResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Delete the recorded usage metrics",
+	Args:  cobra.NoArgs,
+	RunE:  runStatsReset,
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	// Add subcommands
+	statsCmd.AddCommand(statsExportCmd)
+	statsCmd.AddCommand(statsResetCmd)
+}
+
+// runStats prints the recorded metrics
+func runStats(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	metrics, _, err := loadStats()
+	if err != nil {
+		return err
+	}
+
+	if !cfg.Telemetry.Enabled {
+		outputMgr.Info("Usage metrics are disabled; set [telemetry] enabled = true to record them")
+	}
+	if len(metrics.Commands) == 0 {
+		outputMgr.Info("No usage recorded yet")
+		return nil
+	}
+
+	outputMgr.Header(fmt.Sprintf("Usage Since %s", metrics.Since.Format("2006-01-02")))
+
+	outputMgr.Section("Commands")
+	outputMgr.Table([]string{"Command", "Runs", "Failures", "Mean", "Max"}, counterRows(metrics.Commands))
+
+	if len(metrics.Steps) > 0 {
+		outputMgr.Section("Steps")
+		outputMgr.Table([]string{"Step", "Runs", "Failures", "Mean", "Max"}, counterRows(metrics.Steps))
+	}
+	return nil
+}
+
+// runStatsExport prints the metrics as JSON
+func runStatsExport(cmd *cobra.Command, args []string) error {
+	metrics, _, err := loadStats()
+	if err != nil {
+		return err
+	}
+
+	// The export is machine-readable output, printed as-is to stdout
+	data, err := json.MarshalIndent(metrics.Export(rootCmd.Version), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode metrics: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// runStatsReset deletes the metrics file
+func runStatsReset(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	path, err := stats.DefaultPath()
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would delete %s", path))
+		return nil
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete metrics: %w", err)
+	}
+	outputMgr.Success("Usage metrics deleted")
+	return nil
+}
+
+// counterRows renders counters as table rows sorted by name
+func counterRows(counters map[string]*stats.Counter) [][]string {
+	rows := [][]string{}
+	for _, name := range utils.SortedKeys(counters) {
+		c := counters[name]
+		rows = append(rows, []string{
+			name,
+			fmt.Sprintf("%d", c.Count),
+			fmt.Sprintf("%d", c.Failures),
+			utils.FormatTiming(c.Mean()),
+			utils.FormatTiming(c.Max),
+		})
+	}
+	return rows
+}
+
+// loadStats loads the local metrics and returns them with their path
+func loadStats() (*stats.Metrics, string, error) {
+	path, err := stats.DefaultPath()
+	if err != nil {
+		return nil, "", err
+	}
+
+	metrics, err := stats.Load(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return metrics, path, nil
+}
+
+// recordUsage adds the finished command to the usage metrics when they are
+// enabled. Metrics are best effort and never fail or slow down a command
+// noticeably, so errors are only shown in debug mode.
+func recordUsage(cmd *cobra.Command, start time.Time, cmdErr error) {
+	if cmd == nil || cmd == rootCmd || cmd.Hidden || cmd.Name() == "help" {
+		return
+	}
+
+	cfg, err := config.Load(cfgFile)
+	if err != nil || !cfg.Telemetry.Enabled {
+		return
+	}
+
+	metrics, path, err := loadStats()
+	if err != nil {
+		pterm.Debug.Printf("Skipping usage metrics: %v\n", err)
+		return
+	}
+
+	// Record the command path without the binary name, e.g. "history undo"
+	name := strings.TrimSpace(strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()))
+
+	metrics.RecordCommand(name, time.Since(start), cmdErr != nil)
+	for _, timings := range usageTimings {
+		metrics.RecordSteps(timings)
+	}
+
+	if err := metrics.Save(path); err != nil {
+		pterm.Debug.Printf("Skipping usage metrics: %v\n", err)
+	}
+}
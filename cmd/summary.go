@@ -0,0 +1,143 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/editor"
+	"github.com/mochajutsu/mkcd/internal/git"
+	"github.com/mochajutsu/mkcd/internal/shell"
+	"github.com/mochajutsu/mkcd/internal/utils"
+)
+
+// createdSummaries collects the summaries printed by --json
+var createdSummaries = []creationSummary{}
+
+// creationSummary describes a created directory and what to do next
+type creationSummary struct {
+	Path     string        `json:"path"`
+	Profile  string        `json:"profile,omitempty"`
+	Template string        `json:"template,omitempty"`
+	Branch   string        `json:"branch,omitempty"`
+	Remote   string        `json:"remote,omitempty"`
+	DryRun   bool          `json:"dry_run"`
+	Next     []nextCommand `json:"next"`
+}
+
+// nextCommand is a copy-pasteable command suggested after creation
+type nextCommand struct {
+	Description string `json:"description"`
+	Command     string `json:"command"`
+}
+
+// buildCreationSummary collects the details of a created directory and the
+// commands that continue from it in the user's shell
+func buildCreationSummary(targetPath string, cfg *config.Config, mkcdConfig MkcdConfig) creationSummary {
+	summary := creationSummary{
+		Path:     targetPath,
+		Profile:  mkcdConfig.Profile,
+		Template: mkcdConfig.Template,
+		DryRun:   dryRun,
+		Next:     []nextCommand{},
+	}
+
+	shellName := shell.Detect()
+	if shellName == "" {
+		shellName = shell.Bash
+	}
+
+	// The wrapper and --spawn already changed into the directory
+	if !printPath && !spawn {
+		summary.Next = append(summary.Next, nextCommand{"Change into the directory", shell.ChangeDirCommand(shellName, targetPath)})
+	}
+
+	if mkcdConfig.Git {
+		summary.Branch = cfg.Git.DefaultBranch
+		summary.Remote = mkcdConfig.GitRemote
+		committed := cfg.Git.InitialCommit
+
+		// A dry run has no repository to ask
+		if !dryRun {
+			gitMgr := git.NewGitManager(false, false, "", "")
+			if info, err := gitMgr.GetRepositoryInfo(targetPath); err == nil {
+				if info.CurrentBranch != "" {
+					summary.Branch = info.CurrentBranch
+				}
+				if remote, exists := info.Remotes[cfg.Git.DefaultRemoteName]; exists {
+					summary.Remote = remote
+				}
+				committed = info.LastCommit != nil
+			}
+		}
+
+		if !committed {
+			summary.Next = append(summary.Next,
+				nextCommand{"Stage the files", "git add -A"},
+				nextCommand{"Create the first commit", "git commit -m " + shell.Quote(shellName, "Initial commit")},
+			)
+		}
+		if summary.Remote != "" && summary.Branch != "" {
+			summary.Next = append(summary.Next, nextCommand{"Publish the branch", fmt.Sprintf("git push -u %s %s", cfg.Git.DefaultRemoteName, summary.Branch)})
+		}
+	}
+
+	// Suggest the editor unless it was opened already
+	if !mkcdConfig.Editor {
+		detector := editor.NewEditorDetector(false, false)
+		if info, err := detector.DetectEditor(); err == nil {
+			summary.Next = append(summary.Next, nextCommand{"Open it in " + info.Name, info.Command + " ."})
+		}
+	}
+
+	return summary
+}
+
+// printCreationSummary prints the summary footer: the project details,
+// then the next commands one per line so they can be copied as they are
+func printCreationSummary(summary creationSummary, outputMgr *utils.OutputManager) {
+	details := []string{fmt.Sprintf("Path: %s", summary.Path)}
+	if summary.Profile != "" {
+		details = append(details, fmt.Sprintf("Profile: %s", summary.Profile))
+	}
+	if summary.Template != "" {
+		details = append(details, fmt.Sprintf("Template: %s", summary.Template))
+	}
+	if summary.Branch != "" {
+		details = append(details, fmt.Sprintf("Git branch: %s", summary.Branch))
+	}
+	if summary.Remote != "" {
+		details = append(details, fmt.Sprintf("Remote: %s", summary.Remote))
+	}
+
+	outputMgr.Section("Summary")
+	outputMgr.List(details)
+
+	if len(summary.Next) == 0 {
+		return
+	}
+
+	outputMgr.Section("Next steps")
+	var b strings.Builder
+	for _, next := range summary.Next {
+		b.WriteString("  " + next.Command + "\n")
+	}
+	outputMgr.Printf("%s", b.String())
+}
+
+// printSummariesJSON prints the summaries collected for --json
+func printSummariesJSON() error {
+	data, err := json.MarshalIndent(createdSummaries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode summary: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -0,0 +1,1571 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/git"
+	"github.com/mochajutsu/mkcd/internal/templates"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// Template command flags
+var (
+	templateCreateFrom     string
+	templateApplyConflict  string
+	templateCaptureProject string
+	templateDiffStat       bool
+	templatePinRef         string
+	templateSearchFilter   templates.Filter
+	templateTestKeep       bool
+	templateLintStrict     bool
+)
+
+// templateCmd represents the template command
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Manage project templates",
+	Long: `Manage the project templates in the templates directory
+(templates.directory in the configuration).
+
+A template is a directory whose files are copied into new projects by
+--template or a profile's template setting. Text files are rendered with
+Go's text/template and may use {{.ProjectName}}, {{.Author}}, {{.Email}},
+{{.Description}}, {{.License}}, {{.GitRemote}}, {{.Profile}}, {{.Year}} and
+{{.Date}}. Files ending in .tmpl must render (the suffix is removed) and may
+include shared partials; other files that do not render are copied
+unchanged. Set templates.render to "tmpl" to render only .tmpl files.
+
+A template.toml file in the template describes it and is not copied:
+
+  description = "HTTP service in Go"
+
+  extends = "basic-dev"       # apply basic-dev first, then override it
+
+  [variables.port]            # used as {{.Vars.port}}
+  default = "8080"
+  required = true
+  pattern = "[0-9]+"          # values must match as a whole
+
+  [variables.database]
+  choices = ["postgres", "sqlite"]
+
+  [[files]]
+  source = "main.go"          # a path or a pattern such as "docs/*.md"
+  target = "cmd/{{.ProjectName}}/main.go"
+  render = true               # false copies the file verbatim
+
+  [[files]]
+  source = "scripts/*.sh"
+  executable = true           # or mode = "0750"; default: the file's mode
+
+  [[files]]
+  source = ".github"          # a file, pattern or directory
+  when = "{{.Git}}"           # left out unless it gives true
+
+  [[hooks]]                   # run in the project once the files are written
+  run = "go mod tidy"
+  timeout = "2m"              # default: hooks.timeout
+  when = '{{eq .Vars.database "postgres"}}'
+
+Conditions are rendered like files and hold unless they give "", false, 0
+or no. Besides the variables they may use {{.Git}}, {{.Readme}},
+{{.Gitignore}} and {{.Profile}}, the merged flags and profile settings.
+
+On a terminal, variables without a default are asked for when the
+template is applied, and all of them with --interactive; --no-input never
+asks, so required variables without a value fail, as they do in CI.
+--var name=value and --var-file vars.toml (or vars.json, a flat table of
+names and values) set variables, which are then never asked for:
+
+  mkcd mkcd api --template go --var port=9090 --no-input
+
+Template hooks are listed and only run once confirmed on a terminal, or
+always with --force or hooks.templates = "always"; without a terminal
+they are skipped. Dry runs only list them.
+
+mkcd ships the templates basic-dev, nodejs, python, go and web. A
+template of the same name in the templates directory overrides the
+built-in one.
+
+A template can also be a Git repository: --template takes any Git URL or
+the gh:org/repo shorthand for GitHub. Remote templates are cloned into
+~/.cache/mkcd/templates, and the cached clone is used when offline.
+
+--template also takes a tar.gz or zip archive, a local path or an HTTP(S)
+URL. Archives up to templates.max_archive_mb are extracted into
+~/.cache/mkcd/templates/archives, dropping a single top-level directory,
+and applied like a template directory. Entries leaving the archive fail
+the extraction; links and special files are skipped.
+
+The [templates.registry] table of the configuration names Git
+repositories, so their templates are used and installed by name.
+'mkcd template pin' fixes a remote template to a commit or tag and to the
+checksum of its files in [templates.pins].
+
+Cookiecutter templates, local or remote, are applied with the cookiecutter:
+prefix. The variables of cookiecutter.json take their defaults, or the
+project name, author and description of mkcd, and {{cookiecutter.name}}
+may use lower(), upper(), strip(), title(), capitalize() and replace().
+Other Jinja features are copied unchanged and hooks are not run.
+
+Examples:
+  mkcd template list                       # List all templates
+  mkcd template search --language go       # Find templates by language or tag
+  mkcd template show go                    # Show the files of 'go'
+  mkcd template create go --from ~/src/app # Create 'go' from an existing project
+  mkcd template capture ~/src/app go       # Capture a project as 'go', parameterized
+  mkcd template edit go                    # Open 'go' in $EDITOR
+  mkcd template apply go,docker            # Apply templates to the current directory
+  mkcd template apply gh:org/tpl           # Apply a template hosted on GitHub
+  mkcd template apply https://example.com/tpl.tar.gz # Apply an archive template
+  mkcd template apply cookiecutter:gh:audreyr/cookiecutter-pypackage
+  mkcd template diff go ~/src/app          # What applying 'go' would change
+  mkcd template test go                    # Apply 'go' and check its assertions
+  mkcd template lint                       # Check all templates for problems
+  mkcd template install go-service         # Install a template of the registry
+  mkcd template update                     # Fetch installed remote templates again
+  mkcd template pin go-service --ref v1.4.0 # Pin a remote template to a tag
+  mkcd template delete go                  # Delete 'go'`,
+}
+
+// templateListCmd represents the template list command
+var templateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all available templates",
+	Long:  `List the templates in the templates directory and the built-in templates, with the profiles using them.`,
+	Args:  cobra.NoArgs,
+	RunE:  runTemplateList,
+}
+
+// templateSearchCmd represents the template search command
+var templateSearchCmd = &cobra.Command{
+	Use:   "search [query]",
+	Short: "Search templates by name, language, framework or tag",
+	Long: `Search the templates in the templates directory, the built-in templates
+and the templates of the registry. The query is found in the name,
+description, language, framework or tags of a template, ignoring case;
+--language, --framework and --tag must match exactly.
+
+Templates describe themselves in their template.toml:
+
+  language = "go"
+  framework = "chi"
+  tags = ["http", "service"]
+
+Registry templates that are not installed are only found by name.
+
+Examples:
+  mkcd template search                     # List every template
+  mkcd template search http                # Templates mentioning http
+  mkcd template search --language go       # Go templates
+  mkcd template search --tag service       # Templates tagged service`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTemplateSearch,
+}
+
+// templateShowCmd represents the template show command
+var templateShowCmd = &cobra.Command{
+	Use:   "show <template-name>",
+	Short: "Show the files of a template",
+	Long:  `Show the location, version and file tree of a template.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTemplateShow,
+}
+
+// templateCreateCmd represents the template create command
+var templateCreateCmd = &cobra.Command{
+	Use:   "create <template-name>",
+	Short: "Create a new template",
+	Long: `Create an empty template, or copy an existing directory into a new
+template with --from. Version control data is not copied.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTemplateCreate,
+}
+
+// templateCaptureCmd represents the template capture command
+var templateCaptureCmd = &cobra.Command{
+	Use:   "capture <directory> [template-name]",
+	Short: "Create a reusable template from an existing project",
+	Long: `Create a template from an existing project, named after its directory
+unless a name is given.
+
+Unlike 'template create --from', which copies everything, capture leaves
+out what does not belong in a template:
+
+• files ignored by the project's .gitignore files
+• files matching .mkcdignore files, which use the same syntax
+• version control data and the .mkcd.toml project file
+
+The project name (the directory name, or --project-name) is replaced with
+{{.ProjectName}} in file contents and paths, so every new project gets its
+own name. Files that already contain {{ }} are marked in the generated
+template.toml to be copied verbatim.
+
+Examples:
+  mkcd template capture ~/src/api                     # Capture as template 'api'
+  mkcd template capture . service                     # Capture the current project as 'service'
+  mkcd template capture . go --project-name acme-api  # Replace 'acme-api' instead of the directory name
+  mkcd template capture . go --dry-run                # Show what would be captured`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runTemplateCapture,
+}
+
+// templateEditCmd represents the template edit command
+var templateEditCmd = &cobra.Command{
+	Use:   "edit <template-name>",
+	Short: "Edit a template",
+	Long:  `Open the directory of a template in your default editor.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTemplateEdit,
+}
+
+// templateDeleteCmd represents the template delete command
+var templateDeleteCmd = &cobra.Command{
+	Use:   "delete <template-name>",
+	Short: "Delete a template",
+	Long:  `Delete a template and all of its files.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTemplateDelete,
+}
+
+// templateInstallCmd represents the template install command
+var templateInstallCmd = &cobra.Command{
+	Use:   "install <name>...",
+	Short: "Install templates from the registry",
+	Long: `Install templates listed in the registry, cloning them into the template
+cache so they can be used by name, also offline. Git URLs and gh:org/repo
+are installed as well.
+
+The registry maps names to Git repositories in the configuration:
+
+  [templates.registry]
+  go-service = "gh:acme/go-service-template"
+  docs = "https://git.example.com/templates/docs.git"
+
+A template of the same name in the templates directory takes precedence
+over the registry; a registry entry takes precedence over a built-in
+template. Registry templates are also cloned on first use, so installing
+them is optional.
+
+Examples:
+  mkcd template install go-service         # Clone go-service into the cache
+  mkcd template install go-service --force # Clone it again
+  mkcd mkcd api --template go-service      # Use it by name`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runTemplateInstall,
+}
+
+// templateUpdateCmd represents the template update command
+var templateUpdateCmd = &cobra.Command{
+	Use:   "update [name-or-url...]",
+	Short: "Fetch remote templates again",
+	Long: `Fetch remote templates again, replacing their cached clones, and report
+the templates whose version changed. Without arguments, every installed
+template of the registry is updated.
+
+With templates.auto_update, cached remote templates are fetched again
+automatically once they are an hour old; otherwise they are only updated
+by this command.
+
+Examples:
+  mkcd template update                     # Update all installed registry templates
+  mkcd template update go-service          # Update a single template
+  mkcd template update gh:org/tpl          # Update a template used by URL`,
+	RunE: runTemplateUpdate,
+}
+
+// templatePinCmd represents the template pin command
+var templatePinCmd = &cobra.Command{
+	Use:   "pin <name-or-url>",
+	Short: "Pin a remote template to a version and checksum",
+	Long: `Pin a remote template, from the registry or by Git URL, to a commit or
+tag and record the SHA-256 checksum of its files, so every project gets
+the same scaffold. Without --ref the template is pinned to the latest
+commit of its default branch.
+
+Pins are stored in the configuration:
+
+  [templates.pins.go-service]
+  ref = "v1.4.0"
+  sha256 = "3f2a..."
+
+A pinned template is cloned at its ref, and mkcd refuses to apply it when
+its files no longer match the checksum, e.g. because a tag was moved.
+Review the change and run this command again to accept it. Remove the
+entry from the configuration to unpin the template. Archive templates have
+no refs and are pinned by their checksum alone.
+
+Examples:
+  mkcd template pin go-service               # Pin to the current commit
+  mkcd template pin go-service --ref v1.4.0  # Pin to a tag
+  mkcd template pin gh:org/tpl --ref 3f2a9c1 # Pin a template used by URL`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTemplatePin,
+}
+
+// templateApplyCmd represents the template apply command
+var templateApplyCmd = &cobra.Command{
+	Use:   "apply <template[,template...]> [directory]",
+	Short: "Apply templates to an existing directory",
+	Long: `Apply one or more comma-separated templates to an existing directory,
+the current directory by default. Templates are layered in order, exactly
+as with 'mkcd --template'.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runTemplateApply,
+}
+
+// templateDiffCmd represents the template diff command
+var templateDiffCmd = &cobra.Command{
+	Use:   "diff <template[,template...]> [directory]",
+	Short: "Show what applying templates would change",
+	Long: `Show what applying one or more comma-separated templates to an existing
+directory, the current directory by default, would change, without
+writing anything.
+
+Every file the templates write is listed as create, overwrite or skip,
+the last for files whose content would not change, followed by the diff
+of the rendered content against the files on disk.
+
+Examples:
+  mkcd template diff go                    # Changes 'go' would make here
+  mkcd template diff go,docker ~/src/app   # Changes of layered templates
+  mkcd template diff go --stat             # Only list the files`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runTemplateDiff,
+}
+
+// templateTestCmd represents the template test command
+var templateTestCmd = &cobra.Command{
+	Use:   "test <template>",
+	Short: "Apply a template to a temporary directory and check its assertions",
+	Long: `Apply a template to a new project in a temporary directory and check the
+assertions of the [tests] section of its manifest, so template authors can
+test their templates in CI:
+
+  [tests]
+  project = "demo"            # default: example
+  vars = { port = "9090" }    # default: defaults, first choice or "example"
+
+  [[tests.assert]]
+  exists = "cmd/demo/main.go" # or absent = "..."
+
+  [[tests.assert]]
+  file = "go.mod"
+  contains = "module demo"
+
+  [[tests.assert]]
+  run = "go build ./..."      # must succeed in the project
+  timeout = "5m"              # default: hooks.timeout
+
+Variables are never asked for. Template hooks follow hooks.templates, so
+pass --force to run them without a terminal. The command fails when the
+template cannot be applied or any assertion fails, and the directory is
+removed afterwards unless --keep is given.
+
+Examples:
+  mkcd template test go                    # Test the template 'go'
+  mkcd template test go --force            # Run its hooks too
+  mkcd template test go --keep             # Keep the project for inspection`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTemplateTest,
+}
+
+// templateLintCmd represents the template lint command
+var templateLintCmd = &cobra.Command{
+	Use:   "lint [template|dir...]",
+	Short: "Check templates for problems without applying them",
+	Long: `Check templates for problems without applying them, all templates of the
+templates directory by default. A directory that is not a template name
+is checked as a template, e.g. '.' in the repository of a shared template.
+
+These problems are reported as a table:
+
+• errors in the manifest, or in the manifests of extended templates
+• variables that are used but not declared, or declared but never used
+• files that do not render with sample values for the variables and the
+  project; plain files that do not render are copied unchanged
+• files that are never copied, such as symlinks leaving the template,
+  file rules that earlier rules shadow, and conditions that never hold
+• files written to the same path
+
+The command fails when there are errors, and with --strict when there are
+warnings too.
+
+Examples:
+  mkcd template lint                       # Check all templates
+  mkcd template lint go                    # Check the template 'go'
+  mkcd template lint . --strict            # Check the template in this directory`,
+	RunE: runTemplateLint,
+}
+
+func init() {
+	rootCmd.AddCommand(templateCmd)
+
+	// Add subcommands
+	templateCmd.AddCommand(templateListCmd)
+	templateCmd.AddCommand(templateSearchCmd)
+	templateCmd.AddCommand(templateShowCmd)
+	templateCmd.AddCommand(templateCreateCmd)
+	templateCmd.AddCommand(templateCaptureCmd)
+	templateCmd.AddCommand(templateEditCmd)
+	templateCmd.AddCommand(templateDeleteCmd)
+	templateCmd.AddCommand(templateApplyCmd)
+	templateCmd.AddCommand(templateDiffCmd)
+	templateCmd.AddCommand(templateTestCmd)
+	templateCmd.AddCommand(templateLintCmd)
+	templateCmd.AddCommand(templateInstallCmd)
+	templateCmd.AddCommand(templateUpdateCmd)
+	templateCmd.AddCommand(templatePinCmd)
+
+	// Complete template name arguments
+	for _, cmd := range []*cobra.Command{templateShowCmd, templateEditCmd, templateDeleteCmd, templateTestCmd} {
+		cmd.ValidArgsFunction = completeTemplateArg
+	}
+	// Templates, then the directory they are applied to
+	completeTemplateDir := func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return completeTemplates(cmd, args, toComplete)
+		}
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	}
+	templateApplyCmd.ValidArgsFunction = completeTemplateDir
+	templateDiffCmd.ValidArgsFunction = completeTemplateDir
+
+	templateCreateCmd.Flags().StringVar(&templateCreateFrom, "from", "", "copy the files of this directory into the template")
+	templateCaptureCmd.Flags().StringVar(&templateCaptureProject, "project-name", "", "name replaced with {{.ProjectName}} (default: the directory name)")
+	templateCaptureCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return nil, cobra.ShellCompDirectiveFilterDirs
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	templateApplyCmd.Flags().StringVar(&templateApplyConflict, "template-conflict", "", "how layered templates resolve shared files: override, keep or error")
+	templateApplyCmd.Flags().BoolVar(&noInput, "no-input", false, "never prompt for template variables; required variables without a value fail")
+	templateApplyCmd.Flags().StringArrayVar(&templateVars, "var", []string{}, "set a template variable, name=value (repeatable; wins over --var-file)")
+	templateApplyCmd.Flags().StringVar(&templateVarFile, "var-file", "", "read template variables from a TOML or JSON file")
+	registerFlagCompletion(templateApplyCmd, "template-conflict", completeValues(templates.GetAvailableConflictStrategies()))
+
+	templateDiffCmd.Flags().StringVar(&templateApplyConflict, "template-conflict", "", "how layered templates resolve shared files: override, keep or error")
+	templateDiffCmd.Flags().BoolVar(&noInput, "no-input", false, "never prompt for template variables; required variables without a value fail")
+	templateDiffCmd.Flags().StringArrayVar(&templateVars, "var", []string{}, "set a template variable, name=value (repeatable; wins over --var-file)")
+	templateDiffCmd.Flags().StringVar(&templateVarFile, "var-file", "", "read template variables from a TOML or JSON file")
+	templateDiffCmd.Flags().BoolVar(&templateDiffStat, "stat", false, "only list the files, without diffs")
+	registerFlagCompletion(templateDiffCmd, "template-conflict", completeValues(templates.GetAvailableConflictStrategies()))
+
+	templateLintCmd.Flags().BoolVar(&templateLintStrict, "strict", false, "fail on warnings too")
+	templateLintCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		names, _ := completeTemplates(cmd, args, toComplete)
+		return names, cobra.ShellCompDirectiveFilterDirs
+	}
+	templateTestCmd.Flags().BoolVar(&templateTestKeep, "keep", false, "keep the temporary project instead of removing it")
+
+	templatePinCmd.Flags().StringVar(&templatePinRef, "ref", "", "commit hash, tag or branch to pin (default: the latest commit)")
+
+	templateSearchCmd.Flags().StringVar(&templateSearchFilter.Language, "language", "", "only templates for this language")
+	templateSearchCmd.Flags().StringVar(&templateSearchFilter.Framework, "framework", "", "only templates for this framework")
+	templateSearchCmd.Flags().StringVar(&templateSearchFilter.Tag, "tag", "", "only templates with this tag")
+	templateSearchCmd.ValidArgsFunction = cobra.NoFileCompletions
+}
+
+// runTemplateList lists all available templates
+func runTemplateList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	names, err := templates.ListAll(cfg.Templates.Directory, cfg.GetPartialsDirectory())
+	if err != nil {
+		return err
+	}
+
+	outputMgr.Header("Available Templates")
+
+	headers := []string{"Name", "Files", "Size", "Version", "Profiles"}
+	rows := [][]string{}
+	for _, name := range names {
+		// Registry entries replace the built-in templates of their name
+		if templates.IsRemote(templateSource(cfg, name)) {
+			continue
+		}
+		info, err := templates.Inspect(cfg.Templates.Directory, name)
+		if err != nil {
+			outputMgr.Warning(fmt.Sprintf("Skipping template '%s': %v", name, err))
+			continue
+		}
+
+		profiles := strings.Join(templateProfiles(cfg, name), ", ")
+		if profiles == "" {
+			profiles = "-"
+		}
+		if info.Builtin {
+			name += " (built-in)"
+		}
+		rows = append(rows, []string{name, fmt.Sprintf("%d", len(info.Files)), utils.FormatBytes(info.Size), info.Version, profiles})
+	}
+
+	for _, name := range utils.SortedKeys(cfg.Templates.Registry) {
+		source := templateSource(cfg, name)
+		if !templates.IsRemote(source) {
+			continue
+		}
+		profiles := strings.Join(templateProfiles(cfg, name), ", ")
+		if profiles == "" {
+			profiles = "-"
+		}
+
+		files, size, version := "-", "-", "not installed"
+		if dir, err := templates.LocalDir(cfg.Templates.Directory, source); err == nil && templates.Installed(source) {
+			if info, err := templates.Inspect(filepath.Dir(dir), filepath.Base(dir)); err == nil {
+				files, size = fmt.Sprintf("%d", len(info.Files)), utils.FormatBytes(info.Size)
+			}
+			version = templateVersion(dir)
+		}
+		rows = append(rows, []string{name + " (registry)", files, size, version, profiles})
+	}
+
+	outputMgr.Table(headers, rows)
+	return nil
+}
+
+// runTemplateSearch lists the templates matching a query and filters
+func runTemplateSearch(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	filter := templateSearchFilter
+	if len(args) > 0 {
+		filter.Query = args[0]
+	}
+
+	names, err := templates.ListAll(cfg.Templates.Directory, cfg.GetPartialsDirectory())
+	if err != nil {
+		return err
+	}
+
+	rows := [][]string{}
+	row := func(name string, manifest *templates.Manifest) []string {
+		if manifest == nil {
+			manifest = &templates.Manifest{}
+		}
+		return []string{name, valueOrDash(manifest.Language), valueOrDash(manifest.Framework), valueOrDash(strings.Join(manifest.Tags, ", ")), valueOrDash(manifest.Description)}
+	}
+
+	for _, name := range names {
+		// Registry entries replace the built-in templates of their name
+		if templates.IsRemote(templateSource(cfg, name)) {
+			continue
+		}
+		info, err := templates.Inspect(cfg.Templates.Directory, name)
+		if err != nil {
+			outputMgr.Warning(fmt.Sprintf("Skipping template '%s': %v", name, err))
+			continue
+		}
+		if !filter.Matches(name, info.Manifest) {
+			continue
+		}
+		if info.Builtin {
+			name += " (built-in)"
+		}
+		rows = append(rows, row(name, info.Manifest))
+	}
+
+	for _, name := range utils.SortedKeys(cfg.Templates.Registry) {
+		source := templateSource(cfg, name)
+		if !templates.IsRemote(source) {
+			continue
+		}
+
+		var manifest *templates.Manifest
+		if dir, err := templates.LocalDir(cfg.Templates.Directory, source); err == nil && templates.Installed(source) {
+			if manifest, err = templates.LoadManifest(dir); err != nil {
+				outputMgr.Warning(fmt.Sprintf("Skipping template '%s': %v", name, err))
+				continue
+			}
+		} else {
+			manifest = &templates.Manifest{Description: "not installed"}
+		}
+		if !filter.Matches(name, manifest) {
+			continue
+		}
+		rows = append(rows, row(name+" (registry)", manifest))
+	}
+
+	if len(rows) == 0 {
+		outputMgr.Info("No templates match")
+		return nil
+	}
+	outputMgr.Table([]string{"Name", "Language", "Framework", "Tags", "Description"}, rows)
+	return nil
+}
+
+// runTemplateShow shows the details of a template
+func runTemplateShow(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	info, err := templates.Inspect(cfg.Templates.Directory, args[0])
+	if err != nil {
+		return err
+	}
+
+	outputMgr.Header(fmt.Sprintf("Template: %s", info.Name))
+
+	profiles := strings.Join(templateProfiles(cfg, info.Name), ", ")
+	if profiles == "" {
+		profiles = "-"
+	}
+	chain, err := templates.Resolve(templates.Layer{Name: info.Name, Dir: info.Dir}, templateLocator(cfg, outputMgr))
+	if err != nil {
+		return err
+	}
+	manifest := chain.Manifest
+
+	location := info.Dir
+	if info.Builtin {
+		location = "built in (extracted to " + info.Dir + ")"
+	}
+
+	details := []string{}
+	if manifest.Description != "" {
+		details = append(details, fmt.Sprintf("Description: %s", manifest.Description))
+	}
+	if len(chain.Layers) > 1 {
+		// Nearest template first
+		parents := []string{}
+		for i := len(chain.Layers) - 2; i >= 0; i-- {
+			parents = append(parents, chain.Layers[i].Name)
+		}
+		details = append(details, fmt.Sprintf("Extends: %s", strings.Join(parents, " → ")))
+	}
+	if manifest.Language != "" {
+		details = append(details, fmt.Sprintf("Language: %s", manifest.Language))
+	}
+	if manifest.Framework != "" {
+		details = append(details, fmt.Sprintf("Framework: %s", manifest.Framework))
+	}
+	if len(manifest.Tags) > 0 {
+		details = append(details, fmt.Sprintf("Tags: %s", strings.Join(manifest.Tags, ", ")))
+	}
+	details = append(details,
+		fmt.Sprintf("Location: %s", location),
+		fmt.Sprintf("Version: %s", info.Version),
+		fmt.Sprintf("Files: %d (%d rendered)", len(info.Files), info.Rendered),
+		fmt.Sprintf("Size: %s", utils.FormatBytes(info.Size)),
+		fmt.Sprintf("Used by profiles: %s", profiles),
+	)
+	outputMgr.List(details)
+
+	if len(manifest.Variables) > 0 {
+		outputMgr.Section("Variables")
+		rows := [][]string{}
+		for _, name := range utils.SortedKeys(manifest.Variables) {
+			variable := manifest.Variables[name]
+			required := "no"
+			if variable.Required {
+				required = "yes"
+			}
+			allowed := variable.Pattern
+			if len(variable.Choices) > 0 {
+				allowed = strings.Join(variable.Choices, ", ")
+			}
+			rows = append(rows, []string{name, valueOrDash(variable.Default), required, valueOrDash(allowed), valueOrDash(variable.Description)})
+		}
+		outputMgr.Table([]string{"Name", "Default", "Required", "Allowed", "Description"}, rows)
+	}
+
+	if len(manifest.Hooks) > 0 {
+		outputMgr.Section("Hooks")
+		rows := [][]string{}
+		for _, hook := range manifest.Hooks {
+			rows = append(rows, []string{hook.Run, valueOrDash(hook.Timeout), valueOrDash(hook.When)})
+		}
+		outputMgr.Table([]string{"Command", "Timeout", "When"}, rows)
+	}
+
+	if len(info.Files) > 0 {
+		outputMgr.Section("Files")
+		outputMgr.Tree(info.Name, utils.DirectoryEntries(info.Dir, math.MaxInt32))
+	}
+	return nil
+}
+
+// runTemplateCreate creates a new template
+func runTemplateCreate(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	if filepath.Join(cfg.Templates.Directory, name) == cfg.GetPartialsDirectory() {
+		return fmt.Errorf("'%s' is the shared partials directory and cannot be a template", name)
+	}
+
+	from := ""
+	if templateCreateFrom != "" {
+		if from, err = absolutePath(templateCreateFrom); err != nil {
+			return fmt.Errorf("failed to resolve source directory: %w", err)
+		}
+	}
+
+	fsOps := utils.NewFileSystemOperations(dryRun, false)
+	fsOps.Output = outputMgr
+	fsOps.Silent = !dryRun
+	dir, err := templates.Create(fsOps, cfg.Templates.Directory, name, from)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would create template '%s' in %s", name, dir))
+		return nil
+	}
+
+	outputMgr.Success(fmt.Sprintf("Template '%s' created in %s", name, dir))
+	if from == "" {
+		outputMgr.Info(fmt.Sprintf("Add files to it, or open it with: mkcd template edit %s", name))
+	}
+	return nil
+}
+
+// runTemplateCapture creates a template from an existing project
+func runTemplateCapture(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	source, err := absolutePath(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to resolve source directory: %w", err)
+	}
+
+	name := filepath.Base(source)
+	if len(args) > 1 {
+		name = args[1]
+	}
+	if filepath.Join(cfg.Templates.Directory, name) == cfg.GetPartialsDirectory() {
+		return fmt.Errorf("'%s' is the shared partials directory and cannot be a template", name)
+	}
+
+	projectName := templateCaptureProject
+	if projectName == "" {
+		projectName = filepath.Base(source)
+	}
+	if len(projectName) < templates.MinProjectNameLength {
+		outputMgr.Warning(fmt.Sprintf("Project name '%s' is too short to replace safely; files are captured unchanged", projectName))
+	}
+
+	fsOps := utils.NewFileSystemOperations(dryRun, false)
+	fsOps.Output = outputMgr
+	fsOps.Silent = !verbose && !dryRun
+	result, err := templates.Capture(fsOps, cfg.Templates.Directory, name, source, projectName)
+	if err != nil {
+		return err
+	}
+
+	for _, ignored := range result.Ignored {
+		outputMgr.Verbose(fmt.Sprintf("Ignored %s", ignored))
+	}
+	summary := fmt.Sprintf("%d files (%d parameterized, %d ignored)", len(result.Files), len(result.Parameterized), len(result.Ignored))
+
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would capture %s from %s into template '%s'", summary, source, name))
+		return nil
+	}
+
+	outputMgr.Success(fmt.Sprintf("Captured %s from %s into template '%s'", summary, source, name))
+	outputMgr.Info(fmt.Sprintf("Use it with: mkcd <directory> --template %s", name))
+	return nil
+}
+
+// runTemplateEdit opens a template in the default editor
+func runTemplateEdit(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	dir, err := templates.Dir(cfg.Templates.Directory, args[0])
+	if err != nil {
+		return err
+	}
+
+	// Get editor
+	editorCmd := os.Getenv("EDITOR")
+	if editorCmd == "" {
+		editorCmd = "vi" // fallback
+	}
+
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would open %s in %s", dir, editorCmd))
+		return nil
+	}
+
+	outputMgr.Info(fmt.Sprintf("Opening template '%s' in %s...", args[0], editorCmd))
+
+	execCmd := exec.Command(editorCmd, dir)
+	execCmd.Dir = dir
+	execCmd.Stdin = os.Stdin
+	execCmd.Stdout = os.Stdout
+	execCmd.Stderr = os.Stderr
+
+	if err := execCmd.Run(); err != nil {
+		return fmt.Errorf("editor exited with error: %w", err)
+	}
+	return nil
+}
+
+// runTemplateDelete deletes a template
+func runTemplateDelete(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	dir, err := templates.Dir(cfg.Templates.Directory, name)
+	if err != nil {
+		return err
+	}
+
+	if profiles := templateProfiles(cfg, name); len(profiles) > 0 {
+		outputMgr.Warning(fmt.Sprintf("Template '%s' is used by profiles: %s", name, strings.Join(profiles, ", ")))
+	}
+
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would delete template '%s' (%s)", name, dir))
+		return nil
+	}
+
+	// Confirm deletion unless force is used
+	if !force {
+		confirmed, err := outputMgr.Confirm(fmt.Sprintf("Delete template '%s' and all of its files?", name), false)
+		if err != nil {
+			return fmt.Errorf("failed to get confirmation: %w", err)
+		}
+		if !confirmed {
+			outputMgr.Info("Deletion cancelled")
+			return nil
+		}
+	}
+
+	if err := templates.Delete(cfg.Templates.Directory, name); err != nil {
+		return err
+	}
+
+	outputMgr.Success(fmt.Sprintf("Template '%s' deleted successfully", name))
+	return nil
+}
+
+// runTemplateApply applies templates to an existing directory
+func runTemplateApply(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	targetPath, names, err := templateApplyArgs(args, cfg)
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+	if !dryRun {
+		lock, err := lockTarget(targetPath, cfg, outputMgr)
+		if err != nil {
+			return err
+		}
+		defer lock.Unlock()
+	}
+	if err := checkNotFrozen(targetPath); err != nil {
+		return err
+	}
+
+	vars, err := templateVariables()
+	if err != nil {
+		return err
+	}
+	mkcdConfig := MkcdConfig{
+		Template:         strings.Join(names, ","),
+		TemplateConflict: templateApplyConflict,
+		TemplateVars:     vars,
+	}
+
+	// A dry run into a directory with files shows what would change in it
+	if dryRun && !utils.IsEmptyDir(targetPath) {
+		files, err := templatePlan(targetPath, mkcdConfig, cfg, outputMgr)
+		if err != nil {
+			return err
+		}
+		printTemplatePlan(files, outputMgr)
+		return nil
+	}
+
+	fsOps := utils.NewFileSystemOperations(dryRun, backup || cfg.Core.BackupEnabled)
+	fsOps.Output = outputMgr
+	if err := applyTemplate(targetPath, mkcdConfig, cfg, fsOps, outputMgr); err != nil {
+		return err
+	}
+
+	if !dryRun {
+		outputMgr.Success(fmt.Sprintf("Applied %s to %s", strings.Join(names, ", "), targetPath))
+	}
+	return nil
+}
+
+// runTemplateTest applies a template to a temporary project and checks the
+// assertions of its manifest
+func runTemplateTest(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	name := args[0]
+	locate := templateLocator(cfg, outputMgr)
+	templateDir, err := locate(name)
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	manifest, err := templates.LoadManifest(templateDir)
+	if err != nil {
+		return err
+	}
+	if manifest == nil {
+		manifest = &templates.Manifest{}
+	}
+	chain, err := templates.Resolve(templates.Layer{Name: name, Dir: templateDir}, locate)
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "mkcd-template-test-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	if templateTestKeep {
+		defer outputMgr.Info(fmt.Sprintf("Kept the test project in %s", tmpDir))
+	} else {
+		defer os.RemoveAll(tmpDir)
+	}
+
+	project := manifest.Tests.Project
+	if project == "" {
+		project = templates.DefaultTestProject
+	}
+	targetPath := filepath.Join(tmpDir, project)
+	if err := os.Mkdir(targetPath, 0755); err != nil {
+		return fmt.Errorf("failed to create test project: %w", err)
+	}
+
+	// Tests never wait for input
+	noInput = true
+	mkcdConfig := MkcdConfig{
+		Template:     name,
+		TemplateVars: manifest.Tests.SampleVars(chain.Manifest.Variables),
+	}
+	fsOps := utils.NewFileSystemOperations(false, false)
+	fsOps.Output = outputMgr
+	fsOps.Encoding = fileEncoding(cfg)
+	outputMgr.Header(fmt.Sprintf("Testing template %s", name))
+	if err := applyTemplate(targetPath, mkcdConfig, cfg, fsOps, outputMgr); err != nil {
+		return fmt.Errorf("failed to apply template %s: %w", name, err)
+	}
+
+	if len(manifest.Tests.Assertions) == 0 {
+		outputMgr.Warning(fmt.Sprintf("Template %s declares no assertions; only applying it was tested", name))
+		outputMgr.Success(fmt.Sprintf("Applied %s to %s", name, targetPath))
+		return nil
+	}
+
+	runner := newHookRunner(cfg)
+	run := func(dir, command string, timeout time.Duration) (string, error) {
+		assertionRunner := *runner
+		if timeout > 0 {
+			assertionRunner.Timeout = timeout
+		}
+		outputMgr.Verbose(fmt.Sprintf("Running: %s", command))
+		result, err := assertionRunner.Run(dir, command)
+		if result == nil {
+			return "", err
+		}
+		return result.Output, err
+	}
+
+	failed := 0
+	rows := [][]string{}
+	for _, assertion := range manifest.Tests.Assertions {
+		status, detail := "pass", "-"
+		if err := assertion.Check(targetPath, run); err != nil {
+			failed++
+			status, detail = "FAIL", err.Error()
+		}
+		rows = append(rows, []string{assertion.String(), status, detail})
+	}
+	outputMgr.Table([]string{"Assertion", "Result", "Detail"}, rows)
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d assertions of template %s failed", failed, len(rows), name)
+	}
+	outputMgr.Success(fmt.Sprintf("All %d assertions of template %s passed", len(rows), name))
+	return nil
+}
+
+// runTemplateLint checks templates and reports their problems
+func runTemplateLint(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	names := args
+	if len(names) == 0 {
+		if names, err = templates.List(cfg.Templates.Directory, cfg.GetPartialsDirectory()); err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			outputMgr.Info(fmt.Sprintf("No templates in %s", cfg.Templates.Directory))
+			return nil
+		}
+	}
+	cmd.SilenceUsage = true
+
+	locate := templateLocator(cfg, outputMgr)
+	fsOps := utils.NewFileSystemOperations(true, false)
+	fsOps.Silent = true
+	applier, err := templates.NewApplier(fsOps, "", verbose)
+	if err != nil {
+		return err
+	}
+	applier.Partials = cfg.GetPartialsDirectory()
+	applier.RenderAll = cfg.Templates.Render != "tmpl"
+	applier.Locate = locate
+	applier.Data = templates.NewData(newGenerationContext(filepath.Join(os.TempDir(), templates.DefaultTestProject), MkcdConfig{}, cfg))
+
+	errors, warnings := 0, 0
+	rows := [][]string{}
+	for _, name := range names {
+		source, cookiecutter := templates.CookiecutterName(name)
+		dir, err := locate(source)
+		if err != nil {
+			// Directories are linted as templates named after them
+			path, pathErr := utils.ExpandPath(source)
+			if pathErr != nil || !utils.IsDirectory(path) {
+				return err
+			}
+			if dir, err = utils.GetAbsolutePath(path); err != nil {
+				return err
+			}
+			name = filepath.Base(dir)
+		}
+
+		problems, err := applier.Lint(templates.Layer{Name: name, Dir: dir, Cookiecutter: cookiecutter})
+		if err != nil {
+			return fmt.Errorf("template %s: %w", name, err)
+		}
+		for _, problem := range problems {
+			if problem.Severity == templates.SeverityError {
+				errors++
+			} else {
+				warnings++
+			}
+			rows = append(rows, []string{problem.Template, problem.Path, problem.Severity, problem.Message})
+		}
+	}
+
+	linted := fmt.Sprintf("%d templates", len(names))
+	if len(names) == 1 {
+		linted = "template " + names[0]
+	}
+	if len(rows) == 0 {
+		outputMgr.Success(fmt.Sprintf("No problems found in %s", linted))
+		return nil
+	}
+	outputMgr.Table([]string{"Template", "File", "Level", "Problem"}, rows)
+
+	summary := fmt.Sprintf("%d errors and %d warnings in %s", errors, warnings, linted)
+	if errors > 0 || templateLintStrict {
+		return fmt.Errorf("%s", summary)
+	}
+	outputMgr.Warning(summary)
+	return nil
+}
+
+// runTemplateDiff shows the files applying templates would write and how
+// they differ from those on disk
+func runTemplateDiff(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	targetPath, names, err := templateApplyArgs(args, cfg)
+	if err != nil {
+		return err
+	}
+
+	vars, err := templateVariables()
+	if err != nil {
+		return err
+	}
+	mkcdConfig := MkcdConfig{
+		Template:         strings.Join(names, ","),
+		TemplateConflict: templateApplyConflict,
+		TemplateVars:     vars,
+	}
+	files, err := templatePlan(targetPath, mkcdConfig, cfg, outputMgr)
+	if err != nil {
+		return err
+	}
+
+	outputMgr.Header(fmt.Sprintf("Applying %s to %s", strings.Join(names, ", "), targetPath))
+	printTemplatePlan(files, outputMgr)
+
+	if templateDiffStat {
+		return nil
+	}
+	for _, file := range files {
+		if file.diff != "" {
+			printDiff(file.diff, cfg.Output.Colors)
+		}
+	}
+	return nil
+}
+
+// templateApplyArgs returns the directory and the templates of the apply
+// and diff arguments. Unlike mkcd, a missing template is an error: there
+// is nothing else to do.
+func templateApplyArgs(args []string, cfg *config.Config) (string, []string, error) {
+	target := "."
+	if len(args) > 1 {
+		target = args[1]
+	}
+	targetPath, err := absolutePath(target)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to resolve target directory: %w", err)
+	}
+	if !utils.IsDirectory(targetPath) {
+		return "", nil, fmt.Errorf("target directory does not exist: %s", targetPath)
+	}
+
+	names := templates.ParseNames(args[0])
+	if len(names) == 0 {
+		return "", nil, fmt.Errorf("no template given")
+	}
+	for _, name := range names {
+		name, _ = templates.CookiecutterName(templateSource(cfg, name))
+		if templates.IsRemote(name) || templates.IsArchive(name) {
+			continue
+		}
+		if _, err := templates.Find(cfg.Templates.Directory, name); err != nil {
+			return "", nil, err
+		}
+	}
+	return targetPath, names, nil
+}
+
+// templatePlan applies the templates of mkcdConfig to an in-memory overlay
+// of targetPath and compares the result with the disk
+func templatePlan(targetPath string, mkcdConfig MkcdConfig, cfg *config.Config, outputMgr *utils.OutputManager) ([]previewFile, error) {
+	fsOps := utils.NewFileSystemOperations(true, false)
+	fsOps.Silent = true
+	fsOps.Encoding = fileEncoding(cfg)
+
+	if err := applyTemplate(targetPath, mkcdConfig, cfg, fsOps, outputMgr); err != nil {
+		return nil, err
+	}
+	return comparePreview(targetPath, fsOps)
+}
+
+// templatePlanActions names what applying a template does to a file of
+// each preview status
+var templatePlanActions = map[string]string{
+	"added":     "create",
+	"modified":  "overwrite",
+	"unchanged": "skip",
+}
+
+// printTemplatePlan prints the action on every file of a template plan
+func printTemplatePlan(files []previewFile, outputMgr *utils.OutputManager) {
+	counts := map[string]int{}
+	rows := [][]string{}
+	for _, file := range files {
+		path := file.path
+		if file.isDir {
+			// Directories only matter for the files in them
+			if !verbose {
+				continue
+			}
+			path += "/"
+		} else {
+			counts[file.status]++
+		}
+		rows = append(rows, []string{templatePlanActions[file.status], path})
+	}
+
+	if len(rows) > 0 {
+		outputMgr.Table([]string{"Action", "Path"}, rows)
+	}
+	outputMgr.Info(fmt.Sprintf("%d files to create, %d to overwrite, %d unchanged", counts["added"], counts["modified"], counts["unchanged"]))
+}
+
+// runTemplateInstall clones templates of the registry into the cache
+func runTemplateInstall(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	sources, err := remoteTemplateSources(cfg, args)
+	if err != nil {
+		return err
+	}
+
+	for i, source := range sources {
+		name := args[i]
+		if templates.Installed(source) && !force {
+			outputMgr.Info(fmt.Sprintf("Template %s is already installed; use --force to clone it again or 'mkcd template update %s'", name, name))
+			continue
+		}
+		if dryRun {
+			outputMgr.Info(fmt.Sprintf("[DRY RUN] Would install template %s from %s", name, templates.RemoteURL(source)))
+			continue
+		}
+
+		dir, err := fetchRemoteTemplate(source, cfg, true, outputMgr)
+		if err != nil {
+			return err
+		}
+		outputMgr.Success(fmt.Sprintf("Installed template %s at %s", name, templateVersion(dir)))
+	}
+	return nil
+}
+
+// runTemplateUpdate fetches remote templates again and reports the
+// versions that changed
+func runTemplateUpdate(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	if len(args) == 0 {
+		for _, name := range utils.SortedKeys(cfg.Templates.Registry) {
+			if templates.Installed(templateSource(cfg, name)) {
+				args = append(args, name)
+			}
+		}
+		if len(args) == 0 {
+			outputMgr.Info("No installed registry templates to update")
+			return nil
+		}
+	}
+
+	sources, err := remoteTemplateSources(cfg, args)
+	if err != nil {
+		return err
+	}
+
+	updated := 0
+	for i, source := range sources {
+		name := args[i]
+		if dryRun {
+			outputMgr.Info(fmt.Sprintf("[DRY RUN] Would fetch template %s", templates.RemoteURL(source)))
+			continue
+		}
+
+		previous := "not installed"
+		if dir, err := templates.LocalDir(cfg.Templates.Directory, source); err == nil && templates.Installed(source) {
+			previous = templateVersion(dir)
+		}
+
+		dir, err := fetchRemoteTemplate(source, cfg, true, outputMgr)
+		if err != nil {
+			return err
+		}
+		version := templateVersion(dir)
+
+		if version == previous {
+			outputMgr.Info(fmt.Sprintf("Template %s is up to date at %s", name, version))
+			continue
+		}
+		updated++
+		outputMgr.Success(fmt.Sprintf("Template %s updated: %s → %s", name, previous, version))
+	}
+
+	if !dryRun && len(sources) > 1 {
+		outputMgr.Info(fmt.Sprintf("%d of %d templates updated", updated, len(sources)))
+	}
+	return nil
+}
+
+// runTemplatePin pins a remote template to a ref and the checksum of its
+// files in the configuration
+func runTemplatePin(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	sources, err := remoteTemplateSources(cfg, args)
+	if err != nil {
+		return err
+	}
+	source := sources[0]
+	name, _ := templates.CookiecutterName(args[0])
+	cmd.SilenceUsage = true
+
+	if dryRun {
+		outputMgr.Info(fmt.Sprintf("[DRY RUN] Would pin template %s to %s", name, valueOrDash(templatePinRef)))
+		return nil
+	}
+
+	// The checksum is computed for the pinned ref, so it is fetched without
+	// the previous pin
+	delete(cfg.Templates.Pins, name)
+	var dir string
+	if templates.IsArchive(source) {
+		// Archives have no refs, so they are pinned by checksum alone
+		if templatePinRef != "" {
+			return fmt.Errorf("archive template %s cannot be pinned to a ref", name)
+		}
+		dir, err = templates.FetchArchive(source, int64(cfg.Templates.MaxArchiveMB)<<20, false, true)
+	} else {
+		var progress io.Writer
+		if verbose {
+			progress = os.Stderr
+		}
+		dir, err = templates.FetchRemote(source, templatePinRef, false, true, progress)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch template %s: %w", name, err)
+	}
+
+	ref := templatePinRef
+	if ref == "" && !templates.IsArchive(source) {
+		info, err := git.NewGitManager(false, false, "", "").GetRepositoryInfo(dir)
+		if err != nil || info.LastCommit == nil {
+			return fmt.Errorf("failed to read the commit of template %s", name)
+		}
+		ref = info.LastCommit.Hash
+	}
+	checksum, err := templates.Checksum(dir)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Templates.Pins == nil {
+		cfg.Templates.Pins = map[string]config.TemplatePin{}
+	}
+	cfg.Templates.Pins[name] = config.TemplatePin{Ref: ref, SHA256: checksum}
+	if err := cfg.Save(cfgFile); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+
+	outputMgr.Success(fmt.Sprintf("Pinned template %s to %s", name, valueOrDash(ref)))
+	outputMgr.Verbose(fmt.Sprintf("Checksum: %s", checksum))
+	return nil
+}
+
+// remoteTemplateSources returns the Git or archive source of every name,
+// which must be in templates.registry, a Git URL or an archive.
+// Cookiecutter templates are cached under their repository, so the prefix
+// is dropped.
+func remoteTemplateSources(cfg *config.Config, names []string) ([]string, error) {
+	sources := make([]string, len(names))
+	for i, name := range names {
+		source, _ := templates.CookiecutterName(name)
+		if url, listed := cfg.Templates.Registry[source]; listed {
+			source = url
+		}
+		if !templates.IsRemote(source) && !templates.IsArchive(source) {
+			return nil, fmt.Errorf("'%s' is neither in templates.registry nor a remote template (expected a Git URL, %sorg/repo or a tar.gz or zip archive)", name, templates.GitHubPrefix)
+		}
+		sources[i] = source
+	}
+	return sources, nil
+}
+
+// templateVersion returns the version of the template in dir, or
+// "unknown"
+func templateVersion(dir string) string {
+	version, err := templates.Version(dir)
+	if err != nil {
+		return "unknown"
+	}
+	return version
+}
+
+// templateProfiles returns the profiles whose template list includes name
+func templateProfiles(cfg *config.Config, name string) []string {
+	profiles := []string{}
+	for _, profileName := range utils.SortedKeys(cfg.Profiles) {
+		for _, template := range templates.ParseNames(cfg.Profiles[profileName].Template) {
+			if template == name {
+				profiles = append(profiles, profileName)
+				break
+			}
+		}
+	}
+	return profiles
+}
+
+// absolutePath expands ~ and environment variables in path and makes it
+// absolute
+func absolutePath(path string) (string, error) {
+	expanded, err := utils.ExpandPath(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(expanded)
+}
@@ -0,0 +1,373 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/git"
+	"github.com/mochajutsu/mkcd/internal/history"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// undoCmd represents the undo command
+var undoCmd = &cobra.Command{
+	Use:   "undo [id|dir]",
+	Short: "Reverse the last creation",
+	Long: `Reverse the last creation recorded in the history, or the one with the
+given ID, using its manifest. Given a directory created with
+core.workspace_manifest set, the manifest in its .mkcd directory is used
+instead, so the creation can be undone without the history, also after
+the directory was moved.
+
+Only what mkcd created is removed: the generated files, symlinks, the Git
+repository it initialized and the directories it made, once they are empty.
+Files changed since the creation are never touched, and neither is a
+repository with commits made afterwards; they are listed and kept together
+with the directories holding them. Anything added to the directory later
+keeps it as well. Undoing from the manifest in the directory never removes
+its repository, as the manifest may have been copied along with it.
+
+Removals are confirmed first when [safety] confirm_deletes is set, unless
+--force is given. An operation that was undone completely is dropped from
+the history, so the next undo reverses the one before it.
+
+Examples:
+  mkcd undo                # Reverse the last creation
+  mkcd undo --dry-run      # Show what would be removed
+  mkcd undo 3f2a9c         # Reverse a creation by ID (or ID prefix)
+  mkcd undo ./api          # Reverse the creation of ./api from its manifest`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runUndo,
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+}
+
+// undoPlan lists what undoing a creation removes and what it keeps
+type undoPlan struct {
+	// remove are the files and symlinks to remove, git the repository
+	remove []string
+	git    string
+
+	// workspace is the workspace manifest, removed unless something is kept
+	workspace string
+
+	// directories are removed deepest first when they end up empty
+	directories []string
+
+	// kept are the artifacts that changed since the creation, with the reason
+	kept []string
+}
+
+// runUndo reverses a recorded creation
+func runUndo(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	cmd.SilenceUsage = true
+	store, err := newHistoryStore(cfg)
+	if err != nil {
+		return err
+	}
+	manifest, fromWorkspace, err := undoManifest(store, args)
+	if err != nil {
+		return err
+	}
+
+	// The creation being undone may not be changed meanwhile
+	if !dryRun {
+		lock, err := lockTarget(manifest.Path, cfg, outputMgr)
+		if err != nil {
+			return err
+		}
+		defer lock.Unlock()
+	}
+	if err := checkNotFrozen(manifest.Path); err != nil {
+		return err
+	}
+
+	plan, err := planUndo(manifest, fromWorkspace)
+	if err != nil {
+		return err
+	}
+
+	outputMgr.Header(fmt.Sprintf("Undo %s: %s", manifest.ID, manifest.Path))
+	for _, kept := range plan.kept {
+		outputMgr.Warning(fmt.Sprintf("Keeping %s", kept))
+	}
+	if len(plan.remove) == 0 && plan.git == "" && len(plan.directories) == 0 {
+		outputMgr.Info("Nothing left to undo")
+		if len(plan.kept) == 0 && !dryRun {
+			return store.Remove(manifest.ID)
+		}
+		return nil
+	}
+
+	items := append([]string{}, plan.remove...)
+	if plan.git != "" {
+		items = append(items, plan.git)
+	}
+	if plan.workspace != "" && len(plan.kept) == 0 {
+		items = append(items, plan.workspace)
+	}
+	if dryRun {
+		for _, item := range items {
+			outputMgr.Info(fmt.Sprintf("[DRY RUN] Would remove %s", item))
+		}
+		for _, dir := range plan.directories {
+			outputMgr.Info(fmt.Sprintf("[DRY RUN] Would remove directory %s if empty", dir))
+		}
+		return nil
+	}
+
+	if cfg.Safety.ConfirmDeletes && !force {
+		outputMgr.List(append(items, plan.directories...))
+		confirmed, err := outputMgr.Confirm(fmt.Sprintf("Remove what mkcd created in %s?", manifest.Path), false)
+		if err != nil {
+			return fmt.Errorf("failed to get confirmation: %w", err)
+		}
+		if !confirmed {
+			outputMgr.Info("Operation cancelled by user")
+			return nil
+		}
+	}
+
+	removed := 0
+	for _, path := range plan.remove {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		outputMgr.Verbose(fmt.Sprintf("Removed %s", path))
+		removed++
+	}
+	if plan.git != "" {
+		if err := os.RemoveAll(plan.git); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", plan.git, err)
+		}
+		outputMgr.Verbose(fmt.Sprintf("Removed %s", plan.git))
+		removed++
+	}
+
+	// The workspace manifest stays for another undo while files are kept
+	complete := len(plan.kept) == 0
+	if plan.workspace != "" && complete {
+		if err := os.Remove(plan.workspace); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", plan.workspace, err)
+		}
+		outputMgr.Verbose(fmt.Sprintf("Removed %s", plan.workspace))
+		removed++
+	}
+
+	// Directories holding kept or later files stay
+	for _, dir := range plan.directories {
+		if !utils.IsEmptyDir(dir) {
+			outputMgr.Warning(fmt.Sprintf("Keeping %s (not empty)", dir))
+			complete = false
+			continue
+		}
+		if err := os.Remove(dir); err != nil {
+			return fmt.Errorf("failed to remove directory %s: %w", dir, err)
+		}
+		outputMgr.Verbose(fmt.Sprintf("Removed directory %s", dir))
+		removed++
+	}
+
+	if complete {
+		if err := store.Remove(manifest.ID); err != nil {
+			outputMgr.Warning(err.Error())
+		}
+		outputMgr.Success(fmt.Sprintf("Undid the creation of %s (%d item(s) removed)", manifest.Path, removed))
+		return nil
+	}
+	outputMgr.Success(fmt.Sprintf("Partly undid the creation of %s (%d item(s) removed)", manifest.Path, removed))
+	return nil
+}
+
+// undoManifest returns the manifest of the creation to undo: the one in
+// the given directory, the one of the history entry with the given ID, or
+// that of the last entry with a manifest. It reports whether the manifest
+// came from the workspace, which anyone handing out the directory controls.
+func undoManifest(store *history.Store, args []string) (*history.Manifest, bool, error) {
+	if len(args) > 0 && utils.IsDirectory(args[0]) {
+		dir, err := filepath.Abs(args[0])
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to resolve %s: %w", args[0], err)
+		}
+		manifest, err := history.LoadWorkspaceManifest(dir)
+		return manifest, true, err
+	}
+
+	entry, err := undoEntry(store, args)
+	if err != nil {
+		return nil, false, err
+	}
+	manifest, err := store.LoadManifest(entry.ID)
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot undo %s: %w", entry.ID, err)
+	}
+	return manifest, false, nil
+}
+
+// undoEntry returns the history entry given by ID, or the last one with a
+// manifest when no ID is given
+func undoEntry(store *history.Store, args []string) (*history.Entry, error) {
+	if len(args) > 0 {
+		return store.Find(args[0])
+	}
+
+	entries, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].ID != "" {
+			return &entries[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no creation recorded in the history to undo")
+}
+
+// planUndo works out which artifacts of a creation are unchanged and can
+// be removed. Every artifact must lie inside the created directory, except
+// the absolute paths the history records for parent directories created
+// with it; a workspace manifest may not name those, as it may come from
+// anyone. For the same reason the Git repository is only removed by the
+// history's manifest: one in the workspace cannot tell a repository mkcd
+// initialized from one copied along with it.
+func planUndo(manifest *history.Manifest, fromWorkspace bool) (*undoPlan, error) {
+	plan := &undoPlan{}
+	resolve := func(recorded string) (string, error) {
+		if filepath.IsAbs(recorded) && !fromWorkspace {
+			return filepath.Clean(recorded), nil
+		}
+		path := filepath.Join(manifest.Path, filepath.FromSlash(recorded))
+		if filepath.IsAbs(recorded) || !insideDir(manifest.Path, path) {
+			return "", fmt.Errorf("refusing to undo: the manifest of %s lists %s outside of it", manifest.Path, recorded)
+		}
+		return path, nil
+	}
+
+	for _, file := range manifest.Files {
+		path, err := resolve(file.Path)
+		if err != nil {
+			return nil, err
+		}
+		info, err := os.Lstat(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect %s: %w", path, err)
+		}
+		if !info.Mode().IsRegular() {
+			plan.kept = append(plan.kept, fmt.Sprintf("%s (replaced since creation)", path))
+			continue
+		}
+		checksum, _, err := history.FileChecksum(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to checksum %s: %w", path, err)
+		}
+		if checksum != file.SHA256 {
+			plan.kept = append(plan.kept, fmt.Sprintf("%s (modified since creation)", path))
+			continue
+		}
+		plan.remove = append(plan.remove, path)
+	}
+
+	for _, link := range manifest.Symlinks {
+		path, err := resolve(link)
+		if err != nil {
+			return nil, err
+		}
+		info, err := os.Lstat(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil || info.Mode()&os.ModeSymlink == 0 {
+			plan.kept = append(plan.kept, fmt.Sprintf("%s (replaced since creation)", path))
+			continue
+		}
+		plan.remove = append(plan.remove, path)
+	}
+
+	if manifest.Git {
+		path := filepath.Join(manifest.Path, ".git")
+		if utils.IsDirectory(path) {
+			head := ""
+			if info, err := git.NewGitManager(false, false, "", "").GetRepositoryInfo(manifest.Path); err == nil && info.LastCommit != nil {
+				head = info.LastCommit.Hash
+			}
+			switch {
+			case fromWorkspace:
+				plan.kept = append(plan.kept, fmt.Sprintf("%s (never removed through a workspace manifest)", path))
+			case head == manifest.GitHead:
+				plan.git = path
+			default:
+				plan.kept = append(plan.kept, fmt.Sprintf("%s (commits made since creation)", path))
+			}
+		}
+	}
+
+	// The workspace manifest is only removed with the creation it describes
+	if workspace, err := history.LoadWorkspaceManifest(manifest.Path); err == nil && workspace.ID == manifest.ID {
+		plan.workspace = history.WorkspaceManifestPath(manifest.Path)
+		plan.directories = append(plan.directories, filepath.Dir(plan.workspace))
+	}
+
+	// Children were created after their parents
+	for i := len(manifest.Directories) - 1; i >= 0; i-- {
+		path, err := resolve(manifest.Directories[i])
+		if err != nil {
+			return nil, err
+		}
+		if utils.IsDirectory(path) {
+			plan.directories = append(plan.directories, path)
+		}
+	}
+	return plan, nil
+}
+
+// insideDir reports whether path is dir or lies inside it, also once the
+// symlinks leading to it are followed; path itself may be a symlink
+func insideDir(dir, path string) bool {
+	inside := func(dir, path string) bool {
+		rel, err := filepath.Rel(dir, path)
+		return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+	}
+	if !inside(dir, path) {
+		return false
+	}
+
+	physicalDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return true
+	}
+	parent, err := filepath.EvalSymlinks(filepath.Dir(path))
+	if err != nil {
+		// Nothing is removed under a missing parent
+		return true
+	}
+	return inside(physicalDir, filepath.Join(parent, filepath.Base(path)))
+}
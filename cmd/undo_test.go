@@ -0,0 +1,202 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mochajutsu/mkcd/internal/history"
+	"github.com/mochajutsu/mkcd/internal/testsupport"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// newTestEnv points the state, cache and config directories into a
+// scratch workspace, changes into it and returns it with an empty config
+// file
+func newTestEnv(t *testing.T) (*testsupport.Workspace, string) {
+	t.Helper()
+
+	ws := testsupport.NewWorkspace(t)
+	t.Setenv("HOME", ws.Path("home"))
+	t.Setenv("XDG_DATA_HOME", ws.Path("data"))
+	t.Setenv("XDG_CACHE_HOME", ws.Path("cache"))
+	t.Setenv("XDG_CONFIG_HOME", ws.Path("config"))
+	t.Setenv(pathFDEnv, "")
+	ws.Chdir()
+
+	t.Cleanup(func() {
+		resetFlags(rootCmd)
+		redirectOutput(os.Stdout)
+	})
+	return ws, ws.WriteFile("config/mkcd.toml", "")
+}
+
+// resetFlags restores the defaults of the flags of cmd and its subcommands,
+// which keep their values between executions
+func resetFlags(cmd *cobra.Command) {
+	reset := func(flag *pflag.Flag) {
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			_ = slice.Replace(nil)
+		} else {
+			_ = flag.Value.Set(flag.DefValue)
+		}
+		flag.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
+// execute runs mkcd with args and returns what it printed on stdout
+func execute(t *testing.T, args ...string) string {
+	t.Helper()
+
+	output, err := executeErr(t, args...)
+	if err != nil {
+		t.Fatalf("mkcd %v failed: %v\n%s", args, err, output)
+	}
+	return output
+}
+
+// executeErr runs mkcd with the arguments and returns its output and error
+func executeErr(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	var err error
+	output := testsupport.CaptureStdout(t, func() {
+		redirectOutput(os.Stdout)
+		rootCmd.SetArgs(args)
+		_, err = rootCmd.ExecuteC()
+	})
+	resetFlags(rootCmd)
+	return output, err
+}
+
+// TestUndoReversesCreation creates a Git workspace and undoes it
+func TestUndoReversesCreation(t *testing.T) {
+	ws, conf := newTestEnv(t)
+
+	output := execute(t, "mkcd", "api", "--git", "--readme", "--config", conf)
+	testsupport.AssertScript(t, output).ChangesDirTo(ws.Path("api"))
+	ws.Tree("api").HasFile("README.md")
+	ws.Git("api").IsRepository().CommitCount(1)
+
+	path, err := history.DefaultPath()
+	if err != nil {
+		t.Fatalf("failed to locate history: %v", err)
+	}
+	store := history.NewStore(path, 0)
+	entries, err := store.Load()
+	if err != nil || len(entries) != 1 || entries[0].Path != ws.Path("api") {
+		t.Fatalf("expected the creation of %s in the history, got %+v (%v)", ws.Path("api"), entries, err)
+	}
+
+	execute(t, "undo", "--dry-run", "--config", conf)
+	ws.Tree("api").HasFile("README.md")
+
+	output = execute(t, "undo", "--force", "--config", conf)
+	testsupport.AssertScript(t, output).NoDirChange()
+	ws.Tree(".").NotExists("api")
+
+	if entries, err = store.Load(); err != nil || len(entries) != 0 {
+		t.Errorf("expected the undone creation to leave the history, got %+v (%v)", entries, err)
+	}
+}
+
+// TestUndoKeepsChangedFiles keeps files edited after the creation
+func TestUndoKeepsChangedFiles(t *testing.T) {
+	ws, conf := newTestEnv(t)
+
+	execute(t, "mkcd", "notes", "--readme", "--config", conf)
+	ws.WriteFile("notes/README.md", "# My notes\n")
+	ws.WriteFile("notes/todo.txt", "write tests\n")
+
+	execute(t, "undo", "--force", "--config", conf)
+	ws.Tree("notes").
+		Entries("README.md", "todo.txt").
+		FileEquals("README.md", "# My notes\n")
+}
+
+// TestUndoRejectsEscapingWorkspaceManifest refuses workspace manifests
+// naming files outside the workspace
+func TestUndoRejectsEscapingWorkspaceManifest(t *testing.T) {
+	ws, conf := newTestEnv(t)
+	ws.WriteFile(filepath.Base(conf), "[core]\nworkspace_manifest = true\n")
+	conf = ws.Path(filepath.Base(conf))
+
+	victim := ws.WriteFile("victim.txt", "keep me\n")
+	checksum, _, err := history.FileChecksum(victim)
+	if err != nil {
+		t.Fatalf("failed to checksum %s: %v", victim, err)
+	}
+	for _, recorded := range []string{"../victim.txt", victim, "link/victim.txt"} {
+		t.Run(recorded, func(t *testing.T) {
+			execute(t, "mkcd", "shared", "--config", conf)
+			if err := os.Symlink(ws.Root, ws.Path("shared/link")); err != nil {
+				t.Fatalf("failed to create symlink: %v", err)
+			}
+
+			manifest, err := history.LoadWorkspaceManifest(ws.Path("shared"))
+			if err != nil {
+				t.Fatalf("failed to load workspace manifest: %v", err)
+			}
+			manifest.Files = append(manifest.Files, history.FileRef{Path: recorded, SHA256: checksum})
+			if err := history.WriteWorkspaceManifest(manifest); err != nil {
+				t.Fatalf("failed to write workspace manifest: %v", err)
+			}
+
+			rootCmd.SetArgs([]string{"undo", ws.Path("shared"), "--force", "--config", conf})
+			_, err = rootCmd.ExecuteC()
+			resetFlags(rootCmd)
+			if err == nil || !strings.Contains(err.Error(), "outside") {
+				t.Fatalf("expected undo to refuse the manifest, got %v", err)
+			}
+			ws.Tree(".").FileEquals("victim.txt", "keep me\n")
+
+			if err := os.RemoveAll(ws.Path("shared")); err != nil {
+				t.Fatalf("failed to clean up: %v", err)
+			}
+		})
+	}
+}
+
+// TestUndoFromWorkspaceManifest undoes a moved workspace from its manifest
+func TestUndoFromWorkspaceManifest(t *testing.T) {
+	ws, _ := newTestEnv(t)
+	conf := ws.WriteFile("manifest.toml", "[core]\nworkspace_manifest = true\nhistory_limit = 0\n")
+
+	execute(t, "mkcd", "api", "--readme", "--config", conf)
+	if err := os.Rename(ws.Path("api"), ws.Path("moved")); err != nil {
+		t.Fatalf("failed to move workspace: %v", err)
+	}
+
+	execute(t, "undo", ws.Path("moved"), "--force", "--config", conf)
+	ws.Tree(".").NotExists("moved")
+}
+
+// TestUndoFromWorkspaceManifestKeepsRepository checks that a workspace
+// manifest, which may have been copied along with the directory, never gets
+// its Git repository removed
+func TestUndoFromWorkspaceManifestKeepsRepository(t *testing.T) {
+	ws, _ := newTestEnv(t)
+	conf := ws.WriteFile("manifest.toml", "[core]\nworkspace_manifest = true\nhistory_limit = 0\n")
+
+	execute(t, "mkcd", "api", "--git", "--readme", "--config", conf)
+	output := execute(t, "undo", ws.Path("api"), "--force", "--config", conf)
+
+	if !strings.Contains(output, "never removed through a workspace manifest") {
+		t.Errorf("expected the repository to be reported as kept:\n%s", output)
+	}
+	ws.Git("api").IsRepository().CommitCount(1)
+	ws.Tree("api").NotExists("README.md")
+}
@@ -0,0 +1,173 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/editor"
+	"github.com/mochajutsu/mkcd/internal/files"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// Workspace command flags
+var (
+	workspaceMembers []string
+	workspaceFormat  string
+	workspaceEditor  string
+	workspaceNoOpen  bool
+)
+
+// workspaceCmd represents the workspace command
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace <name>",
+	Short: "Create a multi-root editor workspace",
+	Long: `Create a workspace directory containing several member directories and an
+editor workspace file referencing them, then open it in the editor.
+
+Supported formats:
+• vscode     <name>.code-workspace listing each member as a folder
+• jetbrains  .idea/modules.xml with one module per member
+
+Examples:
+  mkcd workspace shop --member api --member web         # VS Code workspace
+  mkcd workspace shop --member api,web --format jetbrains
+  mkcd workspace shop --member api --member web --no-open`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWorkspace,
+}
+
+func init() {
+	rootCmd.AddCommand(workspaceCmd)
+
+	workspaceCmd.Flags().StringSliceVarP(&workspaceMembers, "member", "m", []string{}, "member directory to create (repeatable)")
+	workspaceCmd.Flags().StringVar(&workspaceFormat, "format", files.WorkspaceFormatVSCode, "workspace format: "+strings.Join(files.GetAvailableWorkspaceFormats(), ", "))
+	workspaceCmd.Flags().StringVarP(&workspaceEditor, "editor", "e", "", "editor to open the workspace with (auto-detect if empty)")
+	workspaceCmd.Flags().BoolVar(&workspaceNoOpen, "no-open", false, "do not open the workspace in an editor")
+}
+
+// runWorkspace creates the workspace directory, its members and the workspace file
+func runWorkspace(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	outputMgr := utils.NewOutputManager(
+		cfg.Output.Colors,
+		cfg.Output.Icons,
+		cfg.Output.ProgressBars,
+		quiet,
+		verbose,
+		debug,
+	)
+
+	members, err := validateWorkspaceMembers(workspaceMembers)
+	if err != nil {
+		return err
+	}
+
+	if workspaceFormat != files.WorkspaceFormatVSCode && workspaceFormat != files.WorkspaceFormatJetBrains {
+		return fmt.Errorf("invalid workspace format '%s' (expected %s)", workspaceFormat, strings.Join(files.GetAvailableWorkspaceFormats(), " or "))
+	}
+
+	rootPath, err := determineTargetPath(name, MkcdConfig{}, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to determine target path: %w", err)
+	}
+
+	pathValidator := utils.NewPathValidator(cfg.Safety.ForbiddenPaths, cfg.Safety.MaxDepth)
+	if err := pathValidator.ValidatePath(rootPath); err != nil {
+		if !force {
+			return fmt.Errorf("path validation failed: %w", err)
+		}
+		outputMgr.Warning(fmt.Sprintf("Path validation failed but continuing due to --force: %v", err))
+	}
+
+	fsOps := utils.NewFileSystemOperations(dryRun, backup || cfg.Core.BackupEnabled)
+	fsOps.Output = outputMgr
+	fsOps.Encoding = fileEncoding(cfg)
+	fsOps.EnableRetry(retryPolicy(cfg, outputMgr))
+	fsOps.EnforceModes = cfg.Safety.EnforceModes
+
+	// Create the workspace root and its members as sibling directories
+	if err := fsOps.CreateDirectory(rootPath, 0755); err != nil {
+		return err
+	}
+	for _, member := range members {
+		if err := fsOps.CreateDirectory(filepath.Join(rootPath, member), 0755); err != nil {
+			return err
+		}
+	}
+
+	// Generate the editor workspace file
+	fileGen := files.NewFileGenerator(fsOps, dryRun, verbose)
+	openFiles := []string{}
+
+	switch workspaceFormat {
+	case files.WorkspaceFormatVSCode:
+		workspaceFile, err := fileGen.GenerateCodeWorkspace(rootPath, filepath.Base(rootPath), members)
+		if err != nil {
+			return fmt.Errorf("failed to generate workspace file: %w", err)
+		}
+		openFiles = append(openFiles, filepath.Base(workspaceFile))
+	case files.WorkspaceFormatJetBrains:
+		if _, err := fileGen.GenerateJetBrainsProject(rootPath, members); err != nil {
+			return fmt.Errorf("failed to generate workspace file: %w", err)
+		}
+	}
+
+	if dryRun {
+		reportDryRunChanges(rootPath, fsOps, outputMgr)
+	}
+
+	// Open the workspace file (or project directory) in the editor
+	if !workspaceNoOpen {
+		editorLauncher := editor.NewEditorLauncher(dryRun, verbose)
+		options := editor.LaunchOptions{
+			EditorName:    workspaceEditor,
+			Path:          rootPath,
+			CreateMissing: dryRun,
+			OpenFiles:     openFiles,
+		}
+		if err := editorLauncher.Launch(options); err != nil {
+			// The workspace itself was created, so only warn
+			outputMgr.Warning(fmt.Sprintf("Failed to open editor: %v", err))
+		}
+	}
+
+	return generateShellScript(rootPath, cfg, outputMgr, nil)
+}
+
+// validateWorkspaceMembers checks that members are unique plain directory names
+func validateWorkspaceMembers(members []string) ([]string, error) {
+	if len(members) == 0 {
+		return nil, fmt.Errorf("at least one --member is required")
+	}
+
+	seen := make(map[string]bool)
+	valid := []string{}
+	for _, member := range members {
+		member = strings.TrimSpace(member)
+		if member == "" || member == "." || member == ".." || strings.ContainsAny(member, `/\`) {
+			return nil, fmt.Errorf("invalid member name '%s' (must be a plain directory name)", member)
+		}
+		if seen[member] {
+			return nil, fmt.Errorf("duplicate member '%s'", member)
+		}
+		seen[member] = true
+		valid = append(valid, member)
+	}
+
+	return valid, nil
+}
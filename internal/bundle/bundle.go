@@ -0,0 +1,379 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+// Package bundle packs templates, partials, custom generators and profiles
+// into a single archive that can be installed on an offline machine.
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mochajutsu/mkcd/internal/config"
+	"github.com/mochajutsu/mkcd/internal/templates"
+	"github.com/mochajutsu/mkcd/internal/utils"
+)
+
+// FormatVersion is the bundle format written by Create
+const FormatVersion = 1
+
+// manifestName is the archive entry holding the manifest
+const manifestName = "mkcd-bundle.json"
+
+// templatesPrefix is the archive directory holding templates directory content
+const templatesPrefix = "templates/"
+
+// Manifest describes the content of a bundle
+type Manifest struct {
+	Format     int                               `json:"format"`
+	Created    time.Time                         `json:"created"`
+	Templates  []string                          `json:"templates"`
+	Partials   bool                              `json:"partials"`
+	Generators map[string]config.GeneratorConfig `json:"generators"`
+	Profiles   map[string]config.ProfileConfig   `json:"profiles"`
+	Files      []string                          `json:"files"`
+}
+
+// CreateOptions selects what goes into a bundle; nil slices select everything
+type CreateOptions struct {
+	Templates  []string
+	Generators []string
+	Profiles   []string
+}
+
+// InstallOptions controls how a bundle is installed
+type InstallOptions struct {
+	Force  bool // overwrite existing files
+	DryRun bool // only report what would be installed
+}
+
+// InstallResult reports the outcome of an installation
+type InstallResult struct {
+	Manifest *Manifest
+	Written  []string
+	Skipped  []string
+}
+
+// Create writes a bundle of the selected content of cfg to archivePath
+func Create(archivePath string, cfg *config.Config, opts CreateOptions) (*Manifest, error) {
+	manifest := &Manifest{
+		Format:     FormatVersion,
+		Created:    utils.Now().UTC(),
+		Templates:  []string{},
+		Generators: map[string]config.GeneratorConfig{},
+		Profiles:   map[string]config.ProfileConfig{},
+		Files:      []string{},
+	}
+
+	templatesDir := cfg.Templates.Directory
+
+	// files maps archive names to source paths
+	files := map[string]string{}
+
+	templateNames, err := selectTemplates(templatesDir, opts.Templates, cfg.GetPartialsDirectory())
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range templateNames {
+		if err := collectDir(filepath.Join(templatesDir, name), templatesPrefix+name, files); err != nil {
+			return nil, err
+		}
+		manifest.Templates = append(manifest.Templates, name)
+	}
+
+	// Shared partials are needed by any rendered template
+	if partialsDir := cfg.GetPartialsDirectory(); partialsDir != "" && utils.IsDirectory(partialsDir) {
+		if err := collectDir(partialsDir, templatesPrefix+filepath.ToSlash(cfg.Templates.Partials), files); err != nil {
+			return nil, err
+		}
+		manifest.Partials = true
+	}
+
+	generatorNames, err := selectNames("generator", utils.SortedKeys(cfg.Generators), opts.Generators)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range generatorNames {
+		generator := cfg.Generators[name]
+		if filepath.IsAbs(generator.Template) {
+			return nil, fmt.Errorf("generator '%s' uses an absolute template path and cannot be bundled", name)
+		}
+		files[templatesPrefix+filepath.ToSlash(generator.Template)] = cfg.GetGeneratorTemplatePath(generator)
+		manifest.Generators[name] = generator
+	}
+
+	profileNames, err := selectNames("profile", utils.SortedKeys(cfg.Profiles), opts.Profiles)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range profileNames {
+		manifest.Profiles[name] = cfg.Profiles[name]
+	}
+
+	manifest.Files = utils.SortedKeys(files)
+
+	if err := writeArchive(archivePath, manifest, files); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// ReadManifest reads the manifest of a bundle without installing it
+func ReadManifest(archivePath string) (*Manifest, error) {
+	var manifest *Manifest
+
+	err := walkArchive(archivePath, func(header *tar.Header, reader io.Reader) error {
+		if header.Name != manifestName {
+			return nil
+		}
+		manifest = &Manifest{}
+		if err := json.NewDecoder(reader).Decode(manifest); err != nil {
+			return fmt.Errorf("failed to parse bundle manifest: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("%s is not an mkcd bundle (no manifest)", archivePath)
+	}
+	if manifest.Format > FormatVersion {
+		return nil, fmt.Errorf("bundle format %d is newer than supported format %d", manifest.Format, FormatVersion)
+	}
+	return manifest, nil
+}
+
+// Install extracts the bundle's files into templatesDir. Profiles and
+// generators from the manifest are returned for the caller to merge into
+// its configuration.
+func Install(archivePath, templatesDir string, opts InstallOptions) (*InstallResult, error) {
+	manifest, err := ReadManifest(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &InstallResult{
+		Manifest: manifest,
+		Written:  []string{},
+		Skipped:  []string{},
+	}
+
+	err = walkArchive(archivePath, func(header *tar.Header, reader io.Reader) error {
+		if header.Name == manifestName || header.Typeflag != tar.TypeReg {
+			return nil
+		}
+
+		rel, err := safeRelPath(header.Name)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(templatesDir, rel)
+
+		if _, err := os.Stat(target); err == nil && !opts.Force {
+			result.Skipped = append(result.Skipped, target)
+			return nil
+		}
+		result.Written = append(result.Written, target)
+
+		if opts.DryRun {
+			return nil
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", target, err)
+		}
+
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode).Perm())
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", target, err)
+		}
+		defer file.Close()
+
+		if _, err := io.Copy(file, reader); err != nil {
+			return fmt.Errorf("failed to write %s: %w", target, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// selectTemplates returns the requested templates, or every template
+// directory except the partials directory when none are requested
+func selectTemplates(templatesDir string, requested []string, partialsDir string) ([]string, error) {
+	available, err := templates.List(templatesDir, partialsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return selectNames("template", available, requested)
+}
+
+// selectNames returns requested names after checking they are available,
+// or all available names when none are requested
+func selectNames(kind string, available, requested []string) ([]string, error) {
+	if requested == nil {
+		return available, nil
+	}
+
+	known := make(map[string]bool)
+	for _, name := range available {
+		known[name] = true
+	}
+
+	selected := []string{}
+	for _, name := range requested {
+		if !known[name] {
+			return nil, fmt.Errorf("%s '%s' not found", kind, name)
+		}
+		selected = append(selected, name)
+	}
+	sort.Strings(selected)
+	return selected, nil
+}
+
+// collectDir adds every regular file below dir to files under prefix
+func collectDir(dir, prefix string, files map[string]string) error {
+	return filepath.Walk(dir, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+		files[path.Join(prefix, filepath.ToSlash(rel))] = filePath
+		return nil
+	})
+}
+
+// writeArchive writes the manifest and files into a gzip-compressed tarball
+func writeArchive(archivePath string, manifest *Manifest, files map[string]string) error {
+	out, err := os.Create(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle %s: %w", archivePath, err)
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode bundle manifest: %w", err)
+	}
+	if err := writeEntry(tw, manifestName, data, 0644, manifest.Created); err != nil {
+		return err
+	}
+
+	for _, name := range manifest.Files {
+		content, err := os.ReadFile(files[name])
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", files[name], err)
+		}
+		info, err := os.Stat(files[name])
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", files[name], err)
+		}
+		if err := writeEntry(tw, name, content, info.Mode().Perm(), manifest.Created); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	return nil
+}
+
+// writeEntry writes a single regular file entry
+func writeEntry(tw *tar.Writer, name string, content []byte, mode os.FileMode, modTime time.Time) error {
+	header := &tar.Header{
+		Name:     name,
+		Mode:     int64(mode),
+		Size:     int64(len(content)),
+		ModTime:  modTime,
+		Typeflag: tar.TypeReg,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+	}
+	return nil
+}
+
+// walkArchive calls fn for every entry of a gzip-compressed tarball
+func walkArchive(archivePath string, fn func(header *tar.Header, reader io.Reader) error) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open bundle %s: %w", archivePath, err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to read bundle %s: %w", archivePath, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read bundle %s: %w", archivePath, err)
+		}
+		if err := fn(header, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// safeRelPath converts an archive entry name below templates/ into a relative
+// path, rejecting entries that would escape the templates directory
+func safeRelPath(name string) (string, error) {
+	if !strings.HasPrefix(name, templatesPrefix) {
+		return "", fmt.Errorf("unexpected bundle entry %s", name)
+	}
+
+	rel := path.Clean(strings.TrimPrefix(name, templatesPrefix))
+	if rel == "." || path.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", fmt.Errorf("unsafe bundle entry %s", name)
+	}
+
+	return filepath.FromSlash(rel), nil
+}
@@ -0,0 +1,300 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+// Package check validates an existing workspace against the conventions mkcd
+// sets up when creating one: license, gitignore, Git remote and CI.
+package check
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mochajutsu/mkcd/internal/git"
+)
+
+// Check names
+const (
+	License   = "license"
+	Gitignore = "gitignore"
+	Remote    = "remote"
+	CI        = "ci"
+)
+
+// Result is the outcome of a single health check
+type Result struct {
+	Name   string
+	Passed bool
+	Detail string
+
+	// Fix is the generator spec ("name:option") that repairs a failed check,
+	// empty when the check cannot be fixed automatically
+	Fix string
+
+	// Missing lists .gitignore patterns required by detected languages
+	Missing []string
+}
+
+// Options supplies the policy a workspace is checked against
+type Options struct {
+	License   string // License type generated by --fix (default "mit")
+	Gitignore string // Gitignore type generated by --fix when no language is detected
+}
+
+// Language describes how a language is detected and ignored
+type Language struct {
+	Name       string
+	Markers    []string // files whose presence identifies the language
+	Extensions []string // source file extensions
+	Patterns   []string // .gitignore patterns the language needs
+}
+
+// Languages lists the languages mkcd can detect, matching the gitignore generator
+var Languages = []Language{
+	{
+		Name:       "go",
+		Markers:    []string{"go.mod"},
+		Extensions: []string{".go"},
+		Patterns:   []string{"*.exe", "*.test", "*.out"},
+	},
+	{
+		Name:       "node",
+		Markers:    []string{"package.json"},
+		Extensions: []string{".js", ".ts", ".jsx", ".tsx"},
+		Patterns:   []string{"node_modules/"},
+	},
+	{
+		Name:       "python",
+		Markers:    []string{"pyproject.toml", "requirements.txt", "setup.py"},
+		Extensions: []string{".py"},
+		Patterns:   []string{"__pycache__/", "*.py[cod]"},
+	},
+}
+
+// licenseFiles are the file names accepted as a license
+var licenseFiles = []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "LICENCE", "COPYING"}
+
+// ciFiles are the paths that indicate a configured CI system
+var ciFiles = []string{".gitlab-ci.yml", ".circleci/config.yml", "Jenkinsfile", "azure-pipelines.yml", ".travis.yml"}
+
+// detectDepth limits how deep source files are searched for language detection
+const detectDepth = 3
+
+// Run performs all health checks on the workspace at dir
+func Run(dir string, opts Options) []Result {
+	languages := DetectLanguages(dir)
+
+	return []Result{
+		checkLicense(dir, opts),
+		checkGitignore(dir, languages, opts),
+		checkRemote(dir),
+		checkCI(dir, languages),
+	}
+}
+
+// Score returns the number of passed checks
+func Score(results []Result) int {
+	passed := 0
+	for _, result := range results {
+		if result.Passed {
+			passed++
+		}
+	}
+	return passed
+}
+
+// DetectLanguages returns the sorted names of languages used in dir
+func DetectLanguages(dir string) []string {
+	found := map[string]bool{}
+
+	for _, language := range Languages {
+		for _, marker := range language.Markers {
+			if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+				found[language.Name] = true
+			}
+		}
+	}
+
+	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(dir, path)
+		if info.IsDir() {
+			name := info.Name()
+			if path != dir && (strings.HasPrefix(name, ".") || name == "node_modules" || name == "vendor" ||
+				strings.Count(rel, string(filepath.Separator)) >= detectDepth) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		ext := filepath.Ext(path)
+		for _, language := range Languages {
+			for _, extension := range language.Extensions {
+				if ext == extension {
+					found[language.Name] = true
+				}
+			}
+		}
+		return nil
+	})
+
+	languages := []string{}
+	for name := range found {
+		languages = append(languages, name)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// checkLicense verifies that a license file is present
+func checkLicense(dir string, opts Options) Result {
+	result := Result{Name: License}
+
+	for _, name := range licenseFiles {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			result.Passed = true
+			result.Detail = name
+			return result
+		}
+	}
+
+	licenseType := opts.License
+	if licenseType == "" {
+		licenseType = "mit"
+	}
+
+	result.Detail = "no license file found"
+	result.Fix = "license:" + licenseType
+	return result
+}
+
+// checkGitignore verifies that .gitignore exists and covers detected languages
+func checkGitignore(dir string, languages []string, opts Options) Result {
+	result := Result{Name: Gitignore}
+
+	content, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		gitignoreType := opts.Gitignore
+		if len(languages) > 0 {
+			gitignoreType = languages[0]
+		}
+		if gitignoreType == "" {
+			gitignoreType = "general"
+		}
+
+		result.Detail = ".gitignore not found"
+		result.Fix = "gitignore:" + gitignoreType
+		return result
+	}
+
+	lines := map[string]bool{}
+	for _, line := range strings.Split(string(content), "\n") {
+		lines[strings.TrimSpace(line)] = true
+	}
+
+	uncovered := []string{}
+	for _, language := range Languages {
+		if !contains(languages, language.Name) {
+			continue
+		}
+		covered := true
+		for _, pattern := range language.Patterns {
+			// Directory patterns may be written with or without the trailing slash
+			if !lines[pattern] && !lines[strings.TrimSuffix(pattern, "/")] {
+				result.Missing = append(result.Missing, pattern)
+				covered = false
+			}
+		}
+		if !covered {
+			uncovered = append(uncovered, language.Name)
+		}
+	}
+
+	if len(uncovered) > 0 {
+		result.Detail = fmt.Sprintf("does not cover %s (missing %s)", strings.Join(uncovered, ", "), strings.Join(result.Missing, " "))
+		return result
+	}
+
+	result.Passed = true
+	if len(languages) > 0 {
+		result.Detail = fmt.Sprintf("covers %s", strings.Join(languages, ", "))
+	} else {
+		result.Detail = "present"
+	}
+	return result
+}
+
+// checkRemote verifies that the workspace is a Git repository with a remote
+func checkRemote(dir string) Result {
+	result := Result{Name: Remote}
+
+	gitMgr := git.NewGitManager(false, false, "", "")
+	info, err := gitMgr.GetRepositoryInfo(dir)
+	if err != nil {
+		result.Detail = "not a Git repository (run 'git init')"
+		return result
+	}
+
+	if len(info.Remotes) == 0 {
+		result.Detail = "no remote configured (run 'git remote add origin <url>')"
+		return result
+	}
+
+	names := []string{}
+	for name := range info.Remotes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result.Passed = true
+	result.Detail = fmt.Sprintf("%s -> %s", names[0], info.Remotes[names[0]])
+	return result
+}
+
+// checkCI verifies that a CI configuration exists
+func checkCI(dir string, languages []string) Result {
+	result := Result{Name: CI}
+
+	workflows, _ := filepath.Glob(filepath.Join(dir, ".github", "workflows", "*.y*ml"))
+	if len(workflows) > 0 {
+		rel, _ := filepath.Rel(dir, workflows[0])
+		result.Passed = true
+		result.Detail = filepath.ToSlash(rel)
+		return result
+	}
+
+	for _, name := range ciFiles {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name))); err == nil {
+			result.Passed = true
+			result.Detail = name
+			return result
+		}
+	}
+
+	ciType := "general"
+	if len(languages) > 0 {
+		ciType = languages[0]
+	}
+
+	result.Detail = "no CI configuration found"
+	result.Fix = "ci:" + ciType
+	return result
+}
+
+// contains reports whether a slice contains a value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
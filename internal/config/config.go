@@ -13,6 +13,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/mitchellh/go-homedir"
@@ -21,12 +24,23 @@ import (
 
 // Config represents the main configuration structure for mkcd
 type Config struct {
-	Core      CoreConfig              `toml:"core"`
-	Git       GitConfig               `toml:"git"`
-	Templates TemplatesConfig         `toml:"templates"`
-	Safety    SafetyConfig            `toml:"safety"`
-	Output    OutputConfig            `toml:"output"`
-	Profiles  map[string]ProfileConfig `toml:"profiles"`
+	Core         CoreConfig                 `toml:"core"`
+	Git          GitConfig                  `toml:"git"`
+	Templates    TemplatesConfig            `toml:"templates"`
+	Safety       SafetyConfig               `toml:"safety"`
+	Output       OutputConfig               `toml:"output"`
+	Sync         SyncConfig                 `toml:"sync"`
+	Telemetry    TelemetryConfig            `toml:"telemetry"`
+	Files        FilesConfig                `toml:"files"`
+	Hooks        HooksConfig                `toml:"hooks"`
+	Integrations IntegrationsConfig         `toml:"integrations"`
+	Journal      JournalConfig              `toml:"journal"`
+	Profiles     map[string]ProfileConfig   `toml:"profiles"`
+	Generators   map[string]GeneratorConfig `toml:"generators"`
+	Blueprints   map[string]BlueprintConfig `toml:"blueprints"`
+
+	// remoteProfiles marks profiles merged in from Core.ProfilesURL
+	remoteProfiles map[string]bool
 }
 
 // CoreConfig contains core application settings
@@ -37,21 +51,92 @@ type CoreConfig struct {
 	HistoryLimit      int    `toml:"history_limit"`
 	BackupEnabled     bool   `toml:"backup_enabled"`
 	TempDir           string `toml:"temp_dir"`
+
+	// ProjectFile writes a .mkcd.toml with a unique project ID into every
+	// created directory and records it in the project registry
+	ProjectFile bool `toml:"project_file"`
+
+	// WorkspaceManifest writes the manifest of every creation into
+	// .mkcd/manifest.toml of the created directory as well, so it outlives
+	// the history
+	WorkspaceManifest bool `toml:"workspace_manifest"`
+
+	// CdOnFailure decides where the shell wrapper changes to when creation
+	// fails midway: "stay" (default), "partial" (into the partially created
+	// directory) or "parent" (into the nearest existing parent)
+	CdOnFailure string `toml:"cd_on_failure"`
+
+	// IntegrationHint warns once when a directory is created without the
+	// shell wrapper, which is needed to change into it
+	IntegrationHint bool `toml:"integration_hint"`
+
+	// SpawnShell is the shell --spawn starts in the new directory; empty
+	// uses $SHELL
+	SpawnShell string `toml:"spawn_shell"`
+
+	// LockTimeout is how long an invocation waits (e.g. "10s") for another
+	// one working on the same target directory before aborting; "0s" aborts
+	// immediately
+	LockTimeout string `toml:"lock_timeout"`
+
+	// ProfilesURL points to a TOML file of [profiles.*] tables published by
+	// a team; they are cached and merged read-only with local profiles
+	ProfilesURL string `toml:"profiles_url"`
 }
 
 // GitConfig contains git-related configuration
 type GitConfig struct {
-	AutoInit           bool   `toml:"auto_init"`
-	DefaultBranch      string `toml:"default_branch"`
-	UserName           string `toml:"user_name"`
-	UserEmail          string `toml:"user_email"`
-	DefaultRemoteName  string `toml:"default_remote_name"`
+	AutoInit             bool   `toml:"auto_init"`
+	DefaultBranch        string `toml:"default_branch"`
+	UserName             string `toml:"user_name"`
+	UserEmail            string `toml:"user_email"`
+	DefaultRemoteName    string `toml:"default_remote_name"`
+	InitialCommit        bool   `toml:"initial_commit"`
+	InitialCommitMessage string `toml:"initial_commit_message"`
+	InitialCommitContent string `toml:"initial_commit_content"`
+	ConventionalCommits  bool   `toml:"conventional_commits"`
 }
 
 // TemplatesConfig contains template system configuration
 type TemplatesConfig struct {
 	Directory  string `toml:"directory"`
 	AutoUpdate bool   `toml:"auto_update"`
+
+	// Conflict decides which file wins when layered templates provide the
+	// same path: "override" (last wins), "keep" (first wins) or "error";
+	// "overwrite" and "skip" are accepted as aliases
+	Conflict string `toml:"conflict"`
+
+	// Partials is the directory of partials shared by all templates,
+	// relative to Directory unless absolute
+	Partials string `toml:"partials"`
+
+	// Render is "all" to render every text file copied from a template, or
+	// "tmpl" to render only files ending in .tmpl
+	Render string `toml:"render"`
+
+	// Registry maps template names to the Git repositories they are
+	// installed from, as URLs or gh:org/repo
+	Registry map[string]string `toml:"registry"`
+
+	// Pins fix remote templates, by registry name or Git URL, to a
+	// commit or tag and to the checksum of their files
+	Pins map[string]TemplatePin `toml:"pins"`
+
+	// MaxArchiveMB limits the size of tar.gz and zip archive templates,
+	// both downloaded and extracted
+	MaxArchiveMB int `toml:"max_archive_mb"`
+}
+
+// TemplatePin fixes a remote template to a reviewed version
+type TemplatePin struct {
+	// Ref is the commit hash, tag or branch checked out instead of the
+	// default branch
+	Ref string `toml:"ref"`
+
+	// SHA256 is the checksum of the template files printed by 'mkcd
+	// template pin'; a template that does not match is not applied
+	SHA256 string `toml:"sha256"`
 }
 
 // SafetyConfig contains safety and validation settings
@@ -60,6 +145,14 @@ type SafetyConfig struct {
 	ConfirmDeletes    bool     `toml:"confirm_deletes"`
 	MaxDepth          int      `toml:"max_depth"`
 	ForbiddenPaths    []string `toml:"forbidden_paths"`
+
+	// EnforceModes chmods created directories and files to the exact
+	// requested mode when the umask strips permission bits
+	EnforceModes bool `toml:"enforce_modes"`
+
+	// AllowRoot lets root create directories outside system directories,
+	// where a stray sudo would leave files the user cannot change
+	AllowRoot bool `toml:"allow_root"`
 }
 
 // OutputConfig contains output formatting settings
@@ -67,6 +160,145 @@ type OutputConfig struct {
 	Colors       bool `toml:"colors"`
 	Icons        bool `toml:"icons"`
 	ProgressBars bool `toml:"progress_bars"`
+
+	// TerminalCwd reports created directories to the terminal (OSC 7), so
+	// new tabs open in the last directory mkcd created
+	TerminalCwd bool `toml:"terminal_cwd"`
+
+	// TreeDepth limits the tree of created entries printed after creation;
+	// 0 disables the tree
+	TreeDepth int `toml:"tree_depth"`
+
+	// Summary ends each creation with the project's path, profile, template
+	// and git details and the commands to run next
+	Summary bool `toml:"summary"`
+}
+
+// FilesConfig controls the encoding of generated files
+type FilesConfig struct {
+	// LineEndings is "lf", "crlf" or "native"; empty keeps files as generated
+	LineEndings string `toml:"line_endings"`
+
+	// BOM lists extensions (e.g. ".cs", ".ps1") or file names written with a
+	// UTF-8 byte order mark, for Windows toolchains that expect one; "*" means all
+	BOM []string `toml:"bom"`
+
+	// RetryAttempts is how often directory, file and git operations are
+	// retried after transient errors (EBUSY, ESTALE) on network mounts
+	RetryAttempts int `toml:"retry_attempts"`
+
+	// RetryDelay is the first backoff delay (e.g. "200ms"), doubled per retry
+	RetryDelay string `toml:"retry_delay"`
+
+	// DetectConventions makes files generated inside an existing project
+	// follow its language, license and indentation unless options say
+	// otherwise
+	DetectConventions bool `toml:"detect_conventions"`
+}
+
+// HooksConfig controls how profile hooks are run
+type HooksConfig struct {
+	// Timeout stops a hook running longer (e.g. "5m"); "0" disables it
+	Timeout string `toml:"timeout"`
+
+	// Env allowlists the environment variables hooks receive, with a
+	// trailing * matching a prefix; empty passes the whole environment
+	Env []string `toml:"env"`
+
+	// Restricted runs hooks with a restricted shell (bash -r)
+	Restricted bool `toml:"restricted"`
+
+	// Templates decides whether the hooks declared by templates run:
+	// "ask" on a terminal, "always" or "never"
+	Templates string `toml:"templates"`
+}
+
+// JournalConfig configures the dated directories of mkcd journal
+type JournalConfig struct {
+	// Root is the directory holding the dated directories
+	Root string `toml:"root"`
+
+	// Layout is the Go time layout of the dated directory below Root, such
+	// as "2006/01/02"
+	Layout string `toml:"layout"`
+
+	// Note is the daily note created in a new dated directory; empty
+	// creates none
+	Note string `toml:"note"`
+
+	// NoteTemplate is a text/template file rendered as the daily note;
+	// empty uses a heading with the date
+	NoteTemplate string `toml:"note_template"`
+
+	// Latest is the symlink in Root to the newest dated directory; empty
+	// disables it
+	Latest string `toml:"latest"`
+}
+
+// IntegrationsConfig configures integrations with other tools
+type IntegrationsConfig struct {
+	Tasks  TasksConfig  `toml:"tasks"`
+	Notify NotifyConfig `toml:"notify"`
+}
+
+// TasksConfig records setup tasks for new projects in a task manager
+type TasksConfig struct {
+	// Backend is "taskwarrior" or "todotxt"; empty disables tasks
+	Backend string `toml:"backend"`
+
+	// File is the todo.txt file of the todotxt backend
+	File string `toml:"file"`
+
+	// Profiles limits tasks to projects created with these profiles;
+	// empty means every profile
+	Profiles []string `toml:"profiles"`
+
+	// Tasks are the task descriptions, which may use {{.ProjectName}},
+	// {{.Profile}} and {{.Path}}
+	Tasks []string `toml:"tasks"`
+}
+
+// NotifyConfig sends a summary of finished provisioning runs to a webhook
+// or by email; without either, nothing is sent
+type NotifyConfig struct {
+	// Runs selects what notifies: "blueprint" runs, "batch" creations of
+	// several directories and single "create" runs
+	Runs []string `toml:"runs"`
+
+	// OnlyFailures limits notifications to failed runs, and MinDuration
+	// to runs that took at least this long (e.g. "5m")
+	OnlyFailures bool   `toml:"only_failures"`
+	MinDuration  string `toml:"min_duration"`
+
+	// Webhook is the URL the summary is posted to, as Format: "json",
+	// "slack" or "teams"
+	Webhook string `toml:"webhook"`
+	Format  string `toml:"format"`
+
+	// Email lists the recipients of notification emails, sent through
+	// SMTPHost (host:port) from From. The password is read from
+	// MKCD_SMTP_PASSWORD.
+	Email        []string `toml:"email"`
+	From         string   `toml:"from"`
+	SMTPHost     string   `toml:"smtp_host"`
+	SMTPUsername string   `toml:"smtp_username"`
+}
+
+// SyncConfig configures syncing the project registry between machines
+type SyncConfig struct {
+	// Backend is "git" (a repository holding the registry) or "webdav"
+	// (the URL of the registry file); empty disables sync
+	Backend  string `toml:"backend"`
+	URL      string `toml:"url"`
+	Username string `toml:"username"`
+}
+
+// TelemetryConfig controls the local usage metrics file. Nothing is ever
+// sent anywhere; 'mkcd stats export' prints the file for collection tooling.
+type TelemetryConfig struct {
+	// Enabled records command counts and step durations, never paths or
+	// names; off unless explicitly enabled
+	Enabled bool `toml:"enabled"`
 }
 
 // ProfileConfig represents a named configuration profile
@@ -78,6 +310,113 @@ type ProfileConfig struct {
 	Template  string   `toml:"template"`
 	Touch     []string `toml:"touch"`
 	License   string   `toml:"license"`
+
+	// Gitattributes selects the line-ending policy for a generated .gitattributes
+	// ("auto", "lf" or "crlf"); EOL maps file extensions to "lf" or "crlf".
+	Gitattributes string            `toml:"gitattributes"`
+	EOL           map[string]string `toml:"eol"`
+
+	// Generate lists additional generators to run, as "name" or "name:option"
+	Generate []string `toml:"generate"`
+
+	// Steps overrides the pipeline step order and SkipSteps disables steps
+	Steps     []string `toml:"steps"`
+	SkipSteps []string `toml:"skip_steps"`
+
+	// Hooks are shell commands run inside the new directory by the hooks step
+	Hooks []string `toml:"hooks"`
+}
+
+// conventionalCommitHeader loosely matches a Conventional Commits header
+var conventionalCommitHeader = regexp.MustCompile(`^[a-z]+(\([\w./-]+\))?!?: \S`)
+
+// sha256Pattern matches a hex SHA-256 checksum
+var sha256Pattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// GeneratorConfig represents a user-defined file generator that renders a
+// template file into the project
+type GeneratorConfig struct {
+	Description string            `toml:"description"`
+	Target      string            `toml:"target"`
+	Template    string            `toml:"template"`
+	Variables   map[string]string `toml:"variables"`
+}
+
+// BlueprintConfig represents a named, ordered list of mkcd operations run
+// by 'mkcd blueprint run'
+type BlueprintConfig struct {
+	Description string          `toml:"description"`
+	Steps       []BlueprintStep `toml:"steps"`
+}
+
+// Blueprint step kinds
+const (
+	BlueprintCreate = "create"
+	BlueprintClone  = "clone"
+	BlueprintApply  = "apply"
+	BlueprintRun    = "run"
+)
+
+// BlueprintStep is a single operation of a blueprint. Exactly one of
+// Create, Clone, Apply and Run is set; paths are relative to the directory
+// the blueprint runs in.
+type BlueprintStep struct {
+	// Create creates a directory like 'mkcd <dir>' with the profile,
+	// template and Git setting of the step
+	Create   string `toml:"create"`
+	Profile  string `toml:"profile"`
+	Template string `toml:"template"`
+	Git      bool   `toml:"git"`
+
+	// Clone clones a repository URL into Dir, with a depth of one when
+	// Shallow is set
+	Clone   string `toml:"clone"`
+	Shallow bool   `toml:"shallow"`
+
+	// Apply applies comma-separated templates to the existing Dir
+	Apply string `toml:"apply"`
+
+	// Run runs a shell command in Dir like a hook
+	Run string `toml:"run"`
+
+	// Dir is the directory of clone, apply and run steps; run steps
+	// default to the current directory
+	Dir string `toml:"dir"`
+
+	// Vars are template variables for create and apply steps
+	Vars map[string]string `toml:"vars"`
+}
+
+// Kind returns the operation of the step, or "" when none or several are set
+func (s BlueprintStep) Kind() string {
+	kind := ""
+	for _, candidate := range []struct{ kind, value string }{
+		{BlueprintCreate, s.Create},
+		{BlueprintClone, s.Clone},
+		{BlueprintApply, s.Apply},
+		{BlueprintRun, s.Run},
+	} {
+		if candidate.value == "" {
+			continue
+		}
+		if kind != "" {
+			return ""
+		}
+		kind = candidate.kind
+	}
+	return kind
+}
+
+// Path returns the directory the step works on, relative to the directory
+// the blueprint runs in
+func (s BlueprintStep) Path() string {
+	if s.Create != "" {
+		return s.Create
+	}
+	if s.Dir == "" {
+		return "."
+	}
+	return s.Dir
 }
 
 // DefaultConfig returns a configuration with sensible defaults
@@ -92,17 +431,31 @@ func DefaultConfig() *Config {
 			HistoryLimit:     100,
 			BackupEnabled:    false,
 			TempDir:          "/tmp/mkcd",
+			ProjectFile:      true,
+			CdOnFailure:      "stay",
+			LockTimeout:      "10s",
+			IntegrationHint:  true,
 		},
 		Git: GitConfig{
-			AutoInit:          false,
-			DefaultBranch:     "main",
-			UserName:          "",
-			UserEmail:         "",
-			DefaultRemoteName: "origin",
+			AutoInit:             false,
+			DefaultBranch:        "main",
+			UserName:             "",
+			UserEmail:            "",
+			DefaultRemoteName:    "origin",
+			InitialCommit:        true,
+			InitialCommitMessage: "Initial commit",
+			InitialCommitContent: "all",
 		},
 		Templates: TemplatesConfig{
 			Directory:  filepath.Join(homeDir, ".config", "mkcd", "templates"),
 			AutoUpdate: false,
+			Conflict:   "override",
+			Partials:   "partials",
+			Render:     "all",
+			Registry:   map[string]string{},
+			Pins:       map[string]TemplatePin{},
+
+			MaxArchiveMB: 100,
 		},
 		Safety: SafetyConfig{
 			ConfirmOverwrites: true,
@@ -114,6 +467,33 @@ func DefaultConfig() *Config {
 			Colors:       true,
 			Icons:        true,
 			ProgressBars: true,
+			TreeDepth:    3,
+			Summary:      true,
+		},
+		Files: FilesConfig{
+			RetryAttempts:     3,
+			RetryDelay:        "200ms",
+			DetectConventions: true,
+		},
+		Hooks: HooksConfig{
+			Timeout:   "5m",
+			Templates: "ask",
+		},
+		Journal: JournalConfig{
+			Root:   filepath.Join(homeDir, "notes"),
+			Layout: "2006/01/02",
+			Note:   "index.md",
+			Latest: "latest",
+		},
+		Integrations: IntegrationsConfig{
+			Tasks: TasksConfig{
+				File:  filepath.Join(homeDir, "todo.txt"),
+				Tasks: []string{"Set up CI for {{.ProjectName}}"},
+			},
+			Notify: NotifyConfig{
+				Runs:   []string{"blueprint", "batch"},
+				Format: "json",
+			},
 		},
 		Profiles: map[string]ProfileConfig{
 			"default": {
@@ -179,9 +559,20 @@ func Load(configPath string) (*Config, error) {
 	
 	// Load and parse config file
 	config := DefaultConfig()
-	if _, err := toml.DecodeFile(configPath, config); err != nil {
+	md, err := toml.DecodeFile(configPath, config)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse config file %s: %w", configPath, err)
 	}
+
+	// Merge team profiles; an unavailable source must not break local use
+	if config.Core.ProfilesURL != "" {
+		remote, err := FetchRemoteProfiles(config.Core.ProfilesURL, false)
+		if err != nil {
+			pterm.Warning.Printf("%v\n", err)
+		} else {
+			config.mergeRemoteProfiles(remote, md)
+		}
+	}
 	
 	// Validate the loaded configuration
 	if err := config.Validate(); err != nil {
@@ -209,6 +600,17 @@ func (c *Config) Save(configPath string) error {
 		return fmt.Errorf("failed to create config directory %s: %w", configDir, err)
 	}
 	
+	// Remote profiles are read-only and never written to the local file
+	local := *c
+	if len(c.remoteProfiles) > 0 {
+		local.Profiles = make(map[string]ProfileConfig)
+		for name, profile := range c.Profiles {
+			if !c.remoteProfiles[name] {
+				local.Profiles[name] = profile
+			}
+		}
+	}
+
 	// Create config file
 	file, err := os.Create(configPath)
 	if err != nil {
@@ -218,7 +620,7 @@ func (c *Config) Save(configPath string) error {
 	
 	// Encode configuration to TOML
 	encoder := toml.NewEncoder(file)
-	if err := encoder.Encode(c); err != nil {
+	if err := encoder.Encode(&local); err != nil {
 		return fmt.Errorf("failed to encode config to TOML: %w", err)
 	}
 	
@@ -233,6 +635,10 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("history_limit must be non-negative")
 	}
 	
+	if c.Output.TreeDepth < 0 {
+		return fmt.Errorf("tree_depth must be non-negative")
+	}
+
 	if c.Safety.MaxDepth < 1 {
 		return fmt.Errorf("max_depth must be at least 1")
 	}
@@ -244,6 +650,194 @@ func (c *Config) Validate() error {
 		}
 	}
 	
+	switch c.Core.CdOnFailure {
+	case "", "stay", "partial", "parent":
+	default:
+		return fmt.Errorf("cd_on_failure must be 'stay', 'partial' or 'parent', got '%s'", c.Core.CdOnFailure)
+	}
+
+	if c.Core.LockTimeout != "" {
+		if timeout, err := time.ParseDuration(c.Core.LockTimeout); err != nil {
+			return fmt.Errorf("invalid lock_timeout '%s': %w", c.Core.LockTimeout, err)
+		} else if timeout < 0 {
+			return fmt.Errorf("lock_timeout cannot be negative")
+		}
+	}
+
+	// Validate template composition settings
+	switch c.Templates.Conflict {
+	case "", "override", "keep", "error", "overwrite", "skip":
+	default:
+		return fmt.Errorf("templates conflict must be 'override' (or 'overwrite'), 'keep' (or 'skip') or 'error', got '%s'", c.Templates.Conflict)
+	}
+	switch c.Templates.Render {
+	case "", "all", "tmpl":
+	default:
+		return fmt.Errorf("templates render must be 'all' or 'tmpl', got '%s'", c.Templates.Render)
+	}
+	for name, source := range c.Templates.Registry {
+		if name == "" || strings.ContainsAny(name, ",/:") {
+			return fmt.Errorf("invalid templates registry name '%s'", name)
+		}
+		if !strings.Contains(source, ":") {
+			return fmt.Errorf("templates registry entry '%s' must be a Git URL or gh:org/repo, got '%s'", name, source)
+		}
+	}
+
+	if c.Templates.MaxArchiveMB < 1 {
+		return fmt.Errorf("templates max_archive_mb must be at least 1")
+	}
+
+	for name, pin := range c.Templates.Pins {
+		if pin.Ref == "" && pin.SHA256 == "" {
+			return fmt.Errorf("templates pin '%s' needs a ref, a sha256 or both", name)
+		}
+		if pin.SHA256 != "" && !sha256Pattern.MatchString(pin.SHA256) {
+			return fmt.Errorf("templates pin '%s' has an invalid sha256 '%s' (expected 64 lowercase hex digits)", name, pin.SHA256)
+		}
+	}
+
+	// Validate generated file encoding
+	switch c.Files.LineEndings {
+	case "", "lf", "crlf", "native":
+	default:
+		return fmt.Errorf("files line_endings must be 'lf', 'crlf' or 'native', got '%s'", c.Files.LineEndings)
+	}
+
+	if c.Files.RetryAttempts < 0 {
+		return fmt.Errorf("files retry_attempts cannot be negative")
+	}
+	if c.Hooks.Timeout != "" {
+		if timeout, err := time.ParseDuration(c.Hooks.Timeout); err != nil || timeout < 0 {
+			return fmt.Errorf("invalid hooks timeout '%s' (expected a duration such as 5m, or 0 for none)", c.Hooks.Timeout)
+		}
+	}
+	if c.Journal.Layout == "" {
+		return fmt.Errorf("journal layout cannot be empty")
+	}
+	if sample := filepath.Clean(time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC).Format(c.Journal.Layout)); filepath.IsAbs(sample) || sample == "." || strings.HasPrefix(sample, "..") {
+		return fmt.Errorf("journal layout must give a relative path, got '%s'", c.Journal.Layout)
+	}
+	for setting, name := range map[string]string{"note": c.Journal.Note, "latest": c.Journal.Latest} {
+		if name != "" && (name == "." || name == ".." || strings.ContainsAny(name, `/\`)) {
+			return fmt.Errorf("journal %s must be a plain file name, got '%s'", setting, name)
+		}
+	}
+
+	switch c.Integrations.Tasks.Backend {
+	case "", "taskwarrior":
+	case "todotxt":
+		if c.Integrations.Tasks.File == "" {
+			return fmt.Errorf("integrations tasks file is required for the todotxt backend")
+		}
+	default:
+		return fmt.Errorf("integrations tasks backend must be 'taskwarrior' or 'todotxt', got '%s'", c.Integrations.Tasks.Backend)
+	}
+
+	notify := c.Integrations.Notify
+	for _, run := range notify.Runs {
+		if run != "blueprint" && run != "batch" && run != "create" {
+			return fmt.Errorf("integrations notify runs must be 'blueprint', 'batch' or 'create', got '%s'", run)
+		}
+	}
+	switch notify.Format {
+	case "", "json", "slack", "teams":
+	default:
+		return fmt.Errorf("integrations notify format must be 'json', 'slack' or 'teams', got '%s'", notify.Format)
+	}
+	if notify.MinDuration != "" {
+		if _, err := time.ParseDuration(notify.MinDuration); err != nil {
+			return fmt.Errorf("invalid integrations notify min_duration '%s': %w", notify.MinDuration, err)
+		}
+	}
+	if len(notify.Email) > 0 && (notify.SMTPHost == "" || notify.From == "") {
+		return fmt.Errorf("integrations notify email needs smtp_host and from")
+	}
+
+	switch c.Hooks.Templates {
+	case "", "ask", "always", "never":
+	default:
+		return fmt.Errorf("hooks templates must be 'ask', 'always' or 'never', got '%s'", c.Hooks.Templates)
+	}
+	for _, name := range c.Hooks.Env {
+		if name == "" || strings.ContainsAny(name, "= ") {
+			return fmt.Errorf("invalid hooks env entry '%s' (expected a variable name, or a prefix followed by *)", name)
+		}
+	}
+
+	if c.Files.RetryDelay != "" {
+		if _, err := time.ParseDuration(c.Files.RetryDelay); err != nil {
+			return fmt.Errorf("invalid files retry_delay '%s': %w", c.Files.RetryDelay, err)
+		}
+	}
+
+	// Validate registry sync settings
+	switch c.Sync.Backend {
+	case "", "git", "webdav":
+	default:
+		return fmt.Errorf("sync backend must be 'git' or 'webdav', got '%s'", c.Sync.Backend)
+	}
+	if c.Sync.Backend != "" && c.Sync.URL == "" {
+		return fmt.Errorf("sync backend '%s' requires a url", c.Sync.Backend)
+	}
+
+	// Validate initial commit settings
+	switch c.Git.InitialCommitContent {
+	case "", "all", "gitignore":
+	default:
+		return fmt.Errorf("initial_commit_content must be 'all' or 'gitignore', got '%s'", c.Git.InitialCommitContent)
+	}
+
+	// Static commit messages can be checked against Conventional Commits up front
+	if c.Git.ConventionalCommits && c.Git.InitialCommit && !strings.Contains(c.Git.InitialCommitMessage, "{{") {
+		if !conventionalCommitHeader.MatchString(c.Git.InitialCommitMessage) {
+			return fmt.Errorf("initial_commit_message '%s' does not follow Conventional Commits (e.g. 'chore: initial commit')", c.Git.InitialCommitMessage)
+		}
+	}
+
+	// Validate profile line-ending settings
+	for name, profile := range c.Profiles {
+		switch profile.Gitattributes {
+		case "", "auto", "lf", "crlf":
+		default:
+			return fmt.Errorf("profile '%s' has invalid gitattributes policy '%s' (expected auto, lf or crlf)", name, profile.Gitattributes)
+		}
+		for ext, eol := range profile.EOL {
+			if eol != "lf" && eol != "crlf" {
+				return fmt.Errorf("profile '%s' has invalid eol '%s' for extension %s (expected lf or crlf)", name, eol, ext)
+			}
+		}
+	}
+
+	// Validate user-defined generators
+	for name, generator := range c.Generators {
+		if generator.Target == "" {
+			return fmt.Errorf("generator '%s' must specify a target", name)
+		}
+		if generator.Template == "" {
+			return fmt.Errorf("generator '%s' must specify a template", name)
+		}
+	}
+
+	// Validate blueprint steps
+	for name, blueprint := range c.Blueprints {
+		if len(blueprint.Steps) == 0 {
+			return fmt.Errorf("blueprint '%s' must have at least one step", name)
+		}
+		for i, step := range blueprint.Steps {
+			kind := step.Kind()
+			if kind == "" {
+				return fmt.Errorf("blueprint '%s' step %d must set exactly one of create, clone, apply and run", name, i+1)
+			}
+			if (kind == BlueprintClone || kind == BlueprintApply) && step.Dir == "" {
+				return fmt.Errorf("blueprint '%s' step %d must specify a dir to %s into", name, i+1, kind)
+			}
+			if !filepath.IsLocal(step.Path()) {
+				return fmt.Errorf("blueprint '%s' step %d path '%s' must be relative and stay inside the directory the blueprint runs in", name, i+1, step.Path())
+			}
+		}
+	}
+
 	// Validate forbidden paths are absolute
 	for _, path := range c.Safety.ForbiddenPaths {
 		if !filepath.IsAbs(path) {
@@ -268,6 +862,23 @@ func (c *Config) GetProfile(name string) (ProfileConfig, error) {
 	return profile, nil
 }
 
+// GetGeneratorTemplatePath returns the absolute path of a generator's template
+// file, resolving relative paths against the templates directory
+func (c *Config) GetGeneratorTemplatePath(generator GeneratorConfig) string {
+	if filepath.IsAbs(generator.Template) {
+		return generator.Template
+	}
+	return filepath.Join(c.Templates.Directory, generator.Template)
+}
+
+// GetPartialsDirectory returns the absolute path of the shared partials directory
+func (c *Config) GetPartialsDirectory() string {
+	if c.Templates.Partials == "" || filepath.IsAbs(c.Templates.Partials) {
+		return c.Templates.Partials
+	}
+	return filepath.Join(c.Templates.Directory, c.Templates.Partials)
+}
+
 // SetProfile sets or updates a profile in the configuration
 func (c *Config) SetProfile(name string, profile ProfileConfig) {
 	if c.Profiles == nil {
@@ -285,6 +896,10 @@ func (c *Config) DeleteProfile(name string) error {
 	if _, exists := c.Profiles[name]; !exists {
 		return fmt.Errorf("profile '%s' does not exist", name)
 	}
+
+	if c.IsRemoteProfile(name) {
+		return fmt.Errorf("profile '%s' comes from profiles_url and is read-only", name)
+	}
 	
 	delete(c.Profiles, name)
 	return nil
@@ -0,0 +1,168 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/mitchellh/go-homedir"
+	"github.com/mochajutsu/mkcd/internal/httpclient"
+	"github.com/pterm/pterm"
+)
+
+// RemoteProfilesTTL is how long fetched remote profiles are used before
+// they are fetched again
+const RemoteProfilesTTL = time.Hour
+
+// remoteProfilesTimeout bounds fetching remote profiles so an unreachable
+// server does not stall every command
+const remoteProfilesTimeout = 10 * time.Second
+
+// remoteProfilesFile is the format of a published profiles file
+type remoteProfilesFile struct {
+	Profiles map[string]ProfileConfig `toml:"profiles"`
+}
+
+// RemoteProfilesCachePath returns the cache location for a profiles URL,
+// inside $XDG_CACHE_HOME/mkcd or ~/.cache/mkcd
+func RemoteProfilesCachePath(url string) (string, error) {
+	cacheHome := os.Getenv("XDG_CACHE_HOME")
+	if cacheHome == "" {
+		homeDir, err := homedir.Dir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		cacheHome = filepath.Join(homeDir, ".cache")
+	}
+
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(cacheHome, "mkcd", "profiles-"+hex.EncodeToString(sum[:])[:12]+".toml"), nil
+}
+
+// FetchRemoteProfiles returns the profiles published at url. A cached copy
+// younger than RemoteProfilesTTL is used unless refresh is set, and a stale
+// cached copy is used when the server cannot be reached.
+func FetchRemoteProfiles(url string, refresh bool) (map[string]ProfileConfig, error) {
+	cachePath, err := RemoteProfilesCachePath(url)
+	if err != nil {
+		return nil, err
+	}
+
+	info, statErr := os.Stat(cachePath)
+	if statErr == nil && !refresh && time.Since(info.ModTime()) < RemoteProfilesTTL {
+		return readRemoteProfiles(cachePath)
+	}
+
+	data, fetchErr := downloadRemoteProfiles(url)
+	if fetchErr == nil {
+		// Parse before caching so a broken publication never replaces a good copy
+		profiles, err := parseRemoteProfiles(data, url)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err == nil {
+			if err := os.WriteFile(cachePath, data, 0644); err != nil {
+				pterm.Debug.Printf("Failed to cache remote profiles: %v", err)
+			}
+		}
+		return profiles, nil
+	}
+
+	if statErr == nil && !refresh {
+		pterm.Warning.Printf("Failed to fetch profiles from %s, using cached copy: %v\n", url, fetchErr)
+		// Keep using the copy for another TTL instead of stalling every command
+		now := time.Now()
+		_ = os.Chtimes(cachePath, now, now)
+		return readRemoteProfiles(cachePath)
+	}
+	return nil, fmt.Errorf("failed to fetch profiles from %s: %w", url, fetchErr)
+}
+
+// downloadRemoteProfiles fetches the profiles file from a URL; file:// URLs
+// and plain paths are read from disk
+func downloadRemoteProfiles(url string) ([]byte, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return os.ReadFile(strings.TrimPrefix(url, "file://"))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), remoteProfilesTimeout)
+	defer cancel()
+
+	// Retry once only: a cached copy is usually available as a fallback
+	client := httpclient.NewClient()
+	client.MaxRetries = 1
+
+	resp, err := client.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &httpclient.StatusError{URL: url, StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(data))}
+	}
+	return data, nil
+}
+
+// readRemoteProfiles reads a cached profiles file
+func readRemoteProfiles(path string) (map[string]ProfileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cached profiles: %w", err)
+	}
+	return parseRemoteProfiles(data, path)
+}
+
+// parseRemoteProfiles parses a profiles file
+func parseRemoteProfiles(data []byte, source string) (map[string]ProfileConfig, error) {
+	var file remoteProfilesFile
+	if _, err := toml.Decode(string(data), &file); err != nil {
+		return nil, fmt.Errorf("failed to parse profiles from %s: %w", source, err)
+	}
+	if file.Profiles == nil {
+		file.Profiles = map[string]ProfileConfig{}
+	}
+	return file.Profiles, nil
+}
+
+// mergeRemoteProfiles adds remote profiles to the configuration. Profiles
+// defined in the local configuration file take precedence over remote ones.
+func (c *Config) mergeRemoteProfiles(remote map[string]ProfileConfig, md toml.MetaData) {
+	if c.Profiles == nil {
+		c.Profiles = make(map[string]ProfileConfig)
+	}
+	c.remoteProfiles = make(map[string]bool)
+
+	for name, profile := range remote {
+		if md.IsDefined("profiles", name) {
+			pterm.Debug.Printf("Local profile '%s' overrides the remote profile", name)
+			continue
+		}
+		c.Profiles[name] = profile
+		c.remoteProfiles[name] = true
+	}
+}
+
+// IsRemoteProfile reports whether a profile comes from profiles_url and is
+// therefore read-only
+func (c *Config) IsRemoteProfile(name string) bool {
+	return c.remoteProfiles[name]
+}
@@ -0,0 +1,83 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package files
+
+func init() {
+	RegisterFactory("readme", func(fg *FileGenerator) Generator { return &readmeGenerator{fg: fg} })
+	RegisterFactory("gitignore", func(fg *FileGenerator) Generator { return &gitignoreGenerator{fg: fg} })
+	RegisterFactory("license", func(fg *FileGenerator) Generator { return &licenseGenerator{fg: fg} })
+	RegisterFactory("gitattributes", func(fg *FileGenerator) Generator { return &gitattributesGenerator{fg: fg} })
+}
+
+// readmeGenerator generates README.md
+type readmeGenerator struct {
+	fg *FileGenerator
+}
+
+func (g *readmeGenerator) Name() string        { return "readme" }
+func (g *readmeGenerator) Description() string { return "README.md with standard project sections" }
+func (g *readmeGenerator) Options() []string   { return nil }
+
+func (g *readmeGenerator) Generate(ctx *GenerationContext, option string) error {
+	return g.fg.GenerateReadme(ctx)
+}
+
+// gitignoreGenerator generates .gitignore for a language or framework
+type gitignoreGenerator struct {
+	fg *FileGenerator
+}
+
+func (g *gitignoreGenerator) Name() string        { return "gitignore" }
+func (g *gitignoreGenerator) Description() string { return ".gitignore for a language or framework" }
+func (g *gitignoreGenerator) Options() []string   { return g.fg.GetAvailableGitignoreTypes() }
+
+func (g *gitignoreGenerator) Generate(ctx *GenerationContext, option string) error {
+	if option == "" {
+		option = ctx.Language
+	}
+	if option == "" {
+		option = "general"
+	}
+	return g.fg.GenerateGitignore(ctx, option)
+}
+
+// licenseGenerator generates LICENSE
+type licenseGenerator struct {
+	fg *FileGenerator
+}
+
+func (g *licenseGenerator) Name() string        { return "license" }
+func (g *licenseGenerator) Description() string { return "LICENSE file for an open source license" }
+func (g *licenseGenerator) Options() []string   { return g.fg.GetAvailableLicenseTypes() }
+
+func (g *licenseGenerator) Generate(ctx *GenerationContext, option string) error {
+	if option == "" {
+		option = ctx.License
+	}
+	if option == "" {
+		option = "mit"
+	}
+	return g.fg.GenerateLicense(ctx, option)
+}
+
+// gitattributesGenerator generates .gitattributes with a line-ending policy
+type gitattributesGenerator struct {
+	fg *FileGenerator
+}
+
+func (g *gitattributesGenerator) Name() string { return "gitattributes" }
+func (g *gitattributesGenerator) Description() string {
+	return ".gitattributes enforcing a line-ending policy"
+}
+func (g *gitattributesGenerator) Options() []string { return g.fg.GetAvailableGitattributesPolicies() }
+
+func (g *gitattributesGenerator) Generate(ctx *GenerationContext, option string) error {
+	if option == "" {
+		option = "auto"
+	}
+	return g.fg.GenerateGitattributes(ctx, option, ctx.EOL)
+}
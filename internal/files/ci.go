@@ -0,0 +1,98 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package files
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pterm/pterm"
+)
+
+func init() {
+	RegisterFactory("ci", func(fg *FileGenerator) Generator { return &ciGenerator{fg: fg} })
+}
+
+// ciGenerator generates a GitHub Actions CI workflow
+type ciGenerator struct {
+	fg *FileGenerator
+}
+
+func (g *ciGenerator) Name() string        { return "ci" }
+func (g *ciGenerator) Description() string { return "GitHub Actions CI workflow for a language" }
+func (g *ciGenerator) Options() []string   { return []string{"go", "node", "python", "general"} }
+
+func (g *ciGenerator) Generate(ctx *GenerationContext, option string) error {
+	if option == "" {
+		option = ctx.Language
+	}
+	if option == "" {
+		option = "general"
+	}
+
+	content := g.content(strings.ToLower(option))
+	if content == "" {
+		return fmt.Errorf("unknown ci type: %s", option)
+	}
+
+	filePath := filepath.Join(ctx.ProjectPath, ".github", "workflows", "ci.yml")
+
+	if g.fg.Verbose {
+		pterm.Debug.Printf("Generating CI workflow for type: %s", option)
+	}
+
+	return g.fg.fsOps.CreateFile(filePath, content, 0644)
+}
+
+// content returns the workflow content for the given language
+func (g *ciGenerator) content(ciType string) string {
+	header := `name: CI
+
+on:
+  push:
+    branches: [main]
+  pull_request:
+
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+`
+
+	steps := map[string]string{
+		"go": `      - uses: actions/setup-go@v5
+        with:
+          go-version: stable
+      - run: go build ./...
+      - run: go vet ./...
+      - run: go test ./...
+`,
+		"node": `      - uses: actions/setup-node@v4
+        with:
+          node-version: lts/*
+      - run: npm ci
+      - run: npm test
+`,
+		"python": `      - uses: actions/setup-python@v5
+        with:
+          python-version: "3.x"
+      - run: pip install -r requirements.txt
+      - run: python -m pytest
+`,
+		"general": `      - run: echo "Add build and test steps here"
+`,
+	}
+
+	step, exists := steps[ciType]
+	if !exists {
+		return ""
+	}
+
+	return header + step
+}
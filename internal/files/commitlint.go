@@ -0,0 +1,58 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package files
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/pterm/pterm"
+)
+
+func init() {
+	RegisterFactory("commitlint", func(fg *FileGenerator) Generator { return &commitlintGenerator{fg: fg} })
+}
+
+// commitlintGenerator generates a commitlint configuration enforcing Conventional Commits
+type commitlintGenerator struct {
+	fg *FileGenerator
+}
+
+func (g *commitlintGenerator) Name() string { return "commitlint" }
+func (g *commitlintGenerator) Description() string {
+	return "commitlint config enforcing Conventional Commits"
+}
+func (g *commitlintGenerator) Options() []string { return []string{"js", "json"} }
+
+func (g *commitlintGenerator) Generate(ctx *GenerationContext, option string) error {
+	var fileName, content string
+
+	switch option {
+	case "", "js":
+		fileName = "commitlint.config.js"
+		content = `module.exports = {
+  extends: ['@commitlint/config-conventional'],
+};
+`
+	case "json":
+		fileName = ".commitlintrc.json"
+		content = `{
+  "extends": ["@commitlint/config-conventional"]
+}
+`
+	default:
+		return fmt.Errorf("unknown commitlint format: %s", option)
+	}
+
+	filePath := filepath.Join(ctx.ProjectPath, fileName)
+
+	if g.fg.Verbose {
+		pterm.Debug.Printf("Generating %s", fileName)
+	}
+
+	return g.fg.fsOps.CreateFile(filePath, content, 0644)
+}
@@ -0,0 +1,125 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package files
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/pterm/pterm"
+)
+
+// CustomGenerator is a user-defined generator that renders a template file
+// from the templates directory into a target path inside the project
+type CustomGenerator struct {
+	fg           *FileGenerator
+	name         string
+	description  string
+	target       string
+	templatePath string
+	variables    map[string]string
+}
+
+// NewCustomGenerator creates a new CustomGenerator instance
+func NewCustomGenerator(fg *FileGenerator, name, description, target, templatePath string, variables map[string]string) *CustomGenerator {
+	if description == "" {
+		description = fmt.Sprintf("Custom generator rendering %s", filepath.Base(templatePath))
+	}
+
+	return &CustomGenerator{
+		fg:           fg,
+		name:         name,
+		description:  description,
+		target:       target,
+		templatePath: templatePath,
+		variables:    variables,
+	}
+}
+
+// customTemplateData is the data passed to custom generator templates
+type customTemplateData struct {
+	ProjectName string
+	ProjectPath string
+	Author      string
+	Email       string
+	Description string
+	Language    string
+	Framework   string
+	License     string
+	GitRemote   string
+	Year        int
+	Date        string
+	Vars        map[string]string
+}
+
+func (g *CustomGenerator) Name() string        { return g.name }
+func (g *CustomGenerator) Description() string { return g.description }
+func (g *CustomGenerator) Options() []string   { return nil }
+
+// Generate renders the template file and writes it to the target path
+func (g *CustomGenerator) Generate(ctx *GenerationContext, option string) error {
+	source, err := os.ReadFile(g.templatePath)
+	if err != nil {
+		return fmt.Errorf("failed to read template %s: %w", g.templatePath, err)
+	}
+
+	data := customTemplateData{
+		ProjectName: ctx.ProjectName,
+		ProjectPath: ctx.ProjectPath,
+		Author:      ctx.Author,
+		Email:       ctx.Email,
+		Description: ctx.Description,
+		Language:    ctx.Language,
+		Framework:   ctx.Framework,
+		License:     ctx.License,
+		GitRemote:   ctx.GitRemote,
+		Year:        ctx.CurrentYear,
+		Date:        ctx.Date,
+		Vars:        g.variables,
+	}
+
+	target, err := g.render("target", g.target, data)
+	if err != nil {
+		return err
+	}
+
+	content, err := g.render(filepath.Base(g.templatePath), string(source), data)
+	if err != nil {
+		return err
+	}
+
+	// Keep rendered targets inside the project directory
+	filePath := filepath.Join(ctx.ProjectPath, target)
+	if rel, err := filepath.Rel(ctx.ProjectPath, filePath); err != nil || strings.HasPrefix(rel, "..") {
+		return fmt.Errorf("generator %s target %s resolves outside the project directory", g.name, target)
+	}
+
+	if g.fg.Verbose {
+		pterm.Debug.Printf("Generating %s from custom generator %s", target, g.name)
+	}
+
+	return g.fg.fsOps.CreateFile(filePath, content, 0644)
+}
+
+// render executes a text template with the given data
+func (g *CustomGenerator) render(name, text string, data customTemplateData) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template %s for generator %s: %w", name, g.name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render template %s for generator %s: %w", name, g.name, err)
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package files
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pterm/pterm"
+)
+
+func init() {
+	RegisterFactory("editorconfig", func(fg *FileGenerator) Generator { return &editorconfigGenerator{fg: fg} })
+}
+
+// editorconfigGenerator generates .editorconfig
+type editorconfigGenerator struct {
+	fg *FileGenerator
+}
+
+func (g *editorconfigGenerator) Name() string { return "editorconfig" }
+func (g *editorconfigGenerator) Description() string {
+	return ".editorconfig with consistent indentation and line endings"
+}
+func (g *editorconfigGenerator) Options() []string { return []string{"spaces", "tabs"} }
+
+func (g *editorconfigGenerator) Generate(ctx *GenerationContext, option string) error {
+	if option == "" {
+		option = ctx.Indent
+	}
+	if option == "" {
+		option = "spaces"
+	}
+	if option != "spaces" && option != "tabs" {
+		return fmt.Errorf("unknown editorconfig indent style: %s", option)
+	}
+
+	filePath := filepath.Join(ctx.ProjectPath, ".editorconfig")
+
+	if g.fg.Verbose {
+		pterm.Debug.Printf("Generating .editorconfig with %s indentation", option)
+	}
+
+	return g.fg.fsOps.CreateFile(filePath, g.content(option), 0644)
+}
+
+// content returns the .editorconfig content for the given indent style
+func (g *editorconfigGenerator) content(indentStyle string) string {
+	var content strings.Builder
+
+	content.WriteString("# EditorConfig is awesome: https://EditorConfig.org\n\n")
+	content.WriteString("root = true\n\n")
+
+	content.WriteString("[*]\n")
+	content.WriteString("charset = utf-8\n")
+	content.WriteString("end_of_line = lf\n")
+	content.WriteString("insert_final_newline = true\n")
+	content.WriteString("trim_trailing_whitespace = true\n")
+	if indentStyle == "tabs" {
+		content.WriteString("indent_style = tab\n")
+		content.WriteString("indent_size = 4\n\n")
+	} else {
+		content.WriteString("indent_style = space\n")
+		content.WriteString("indent_size = 2\n\n")
+	}
+
+	content.WriteString("[*.go]\n")
+	content.WriteString("indent_style = tab\n\n")
+
+	content.WriteString("[Makefile]\n")
+	content.WriteString("indent_style = tab\n\n")
+
+	content.WriteString("[*.py]\n")
+	content.WriteString("indent_style = space\n")
+	content.WriteString("indent_size = 4\n\n")
+
+	content.WriteString("[*.md]\n")
+	content.WriteString("trim_trailing_whitespace = false\n")
+
+	return content.String()
+}
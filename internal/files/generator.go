@@ -11,8 +11,8 @@ package files
 import (
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
-	"time"
 
 	"github.com/mochajutsu/mkcd/internal/utils"
 	"github.com/pterm/pterm"
@@ -45,7 +45,11 @@ type GenerationContext struct {
 	Framework     string
 	License       string
 	GitRemote     string
+	Profile       string
 	CurrentYear   int
+	Date          string            // Creation date as YYYY-MM-DD
+	EOL           map[string]string // Per-extension line endings for .gitattributes
+	Indent        string            // "tabs" or "spaces" for generators without an option
 }
 
 // NewGenerationContext creates a new GenerationContext with defaults
@@ -54,7 +58,8 @@ func NewGenerationContext(projectPath string) *GenerationContext {
 	return &GenerationContext{
 		ProjectName: projectName,
 		ProjectPath: projectPath,
-		CurrentYear: time.Now().Year(),
+		CurrentYear: utils.Now().Year(),
+		Date:        utils.Now().Format("2006-01-02"),
 	}
 }
 
@@ -717,6 +722,76 @@ limitations under the License.
 	return templates[strings.ToLower(licenseType)]
 }
 
+// GenerateGitattributes generates a .gitattributes file enforcing a line-ending policy.
+// The policy selects the base normalization rule and eolOverrides maps file
+// extensions (with or without the leading dot) to "lf" or "crlf".
+func (fg *FileGenerator) GenerateGitattributes(ctx *GenerationContext, policy string, eolOverrides map[string]string) error {
+	content, err := fg.getGitattributesContent(policy, eolOverrides)
+	if err != nil {
+		return err
+	}
+
+	filePath := filepath.Join(ctx.ProjectPath, ".gitattributes")
+
+	if fg.Verbose {
+		pterm.Debug.Printf("Generating .gitattributes for policy: %s", policy)
+	}
+
+	return fg.fsOps.CreateFile(filePath, content, 0644)
+}
+
+// getGitattributesContent returns .gitattributes content for the given policy and overrides
+func (fg *FileGenerator) getGitattributesContent(policy string, eolOverrides map[string]string) (string, error) {
+	var content strings.Builder
+
+	content.WriteString("# Line ending policy generated by mkcd\n")
+
+	switch strings.ToLower(policy) {
+	case "auto":
+		content.WriteString("# Auto detect text files and perform LF normalization\n")
+		content.WriteString("* text=auto\n\n")
+		content.WriteString("# Scripts that must keep a specific line ending\n")
+		content.WriteString("*.sh text eol=lf\n")
+		content.WriteString("*.bat text eol=crlf\n")
+		content.WriteString("*.cmd text eol=crlf\n")
+		content.WriteString("*.ps1 text eol=crlf\n")
+	case "lf":
+		content.WriteString("# Normalize all text files and check them out with LF\n")
+		content.WriteString("* text=auto eol=lf\n")
+	case "crlf":
+		content.WriteString("# Normalize all text files and check them out with CRLF\n")
+		content.WriteString("* text=auto eol=crlf\n")
+	default:
+		return "", fmt.Errorf("unknown gitattributes policy: %s", policy)
+	}
+
+	// Per-extension overrides, sorted for stable output
+	if len(eolOverrides) > 0 {
+		extensions := make([]string, 0, len(eolOverrides))
+		for ext := range eolOverrides {
+			extensions = append(extensions, ext)
+		}
+		sort.Strings(extensions)
+
+		content.WriteString("\n# Per-extension line endings\n")
+		for _, ext := range extensions {
+			eol := strings.ToLower(eolOverrides[ext])
+			if eol != "lf" && eol != "crlf" {
+				return "", fmt.Errorf("invalid eol '%s' for extension %s (expected lf or crlf)", eolOverrides[ext], ext)
+			}
+			content.WriteString(fmt.Sprintf("*.%s text eol=%s\n", strings.TrimPrefix(ext, "."), eol))
+		}
+	}
+
+	// Common binary files
+	content.WriteString("\n# Binary files\n")
+	for _, ext := range []string{"png", "jpg", "jpeg", "gif", "ico", "pdf", "zip", "gz", "tar", "woff", "woff2"} {
+		content.WriteString(fmt.Sprintf("*.%s binary\n", ext))
+	}
+
+	return content.String(), nil
+}
+
 // CreateCustomFile creates a custom file with specified content
 func (fg *FileGenerator) CreateCustomFile(projectPath, fileName, content string) error {
 	filePath := filepath.Join(projectPath, fileName)
@@ -737,3 +812,8 @@ func (fg *FileGenerator) GetAvailableGitignoreTypes() []string {
 func (fg *FileGenerator) GetAvailableLicenseTypes() []string {
 	return []string{"mit", "apache-2.0"}
 }
+
+// GetAvailableGitattributesPolicies returns a list of available line-ending policies
+func (fg *FileGenerator) GetAvailableGitattributesPolicies() []string {
+	return []string{"auto", "lf", "crlf"}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package files_test
+
+import (
+	"testing"
+
+	"github.com/mochajutsu/mkcd/internal/files"
+	"github.com/mochajutsu/mkcd/internal/testsupport"
+	"github.com/mochajutsu/mkcd/internal/utils"
+)
+
+// TestGeneratorsMatchGoldenTrees runs generators into empty directories and
+// compares the result with the golden trees
+func TestGeneratorsMatchGoldenTrees(t *testing.T) {
+	tests := []struct {
+		golden    string
+		generator string
+		option    string
+	}{
+		{golden: "editorconfig-tabs", generator: "editorconfig", option: "tabs"},
+		{golden: "gitattributes-lf", generator: "gitattributes", option: "lf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			ws := testsupport.NewWorkspace(t)
+			fg := files.NewFileGenerator(utils.NewFileSystemOperations(false, false), false, false)
+
+			if err := files.NewRegistry(fg).Run(files.NewGenerationContext(ws.Root), tt.generator, tt.option); err != nil {
+				t.Fatalf("generator %s:%s failed: %v", tt.generator, tt.option, err)
+			}
+			testsupport.AssertGoldenTree(t, ws.Root, tt.golden)
+		})
+	}
+}
+
+// TestDryRunWritesNothing checks that generators leave the disk untouched
+// in dry-run mode
+func TestDryRunWritesNothing(t *testing.T) {
+	ws := testsupport.NewWorkspace(t)
+	fg := files.NewFileGenerator(utils.NewFileSystemOperations(true, false), true, false)
+
+	if err := files.NewRegistry(fg).Run(files.NewGenerationContext(ws.Root), "readme", ""); err != nil {
+		t.Fatalf("readme generator failed: %v", err)
+	}
+	ws.Tree(".").Empty()
+}
@@ -0,0 +1,122 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package files
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Generator is a named file generator that can be registered with a Registry
+type Generator interface {
+	// Name returns the unique name used to reference the generator
+	Name() string
+	// Description returns a short human-readable description
+	Description() string
+	// Options returns the accepted option values, or nil if the generator takes none
+	Options() []string
+	// Generate writes the generator's files for the given context and option
+	Generate(ctx *GenerationContext, option string) error
+}
+
+// GeneratorFactory creates a Generator bound to a FileGenerator
+type GeneratorFactory func(fg *FileGenerator) Generator
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]GeneratorFactory)
+)
+
+// RegisterFactory makes a generator available to every registry created with
+// NewRegistry. It is intended to be called from init functions and panics if
+// a factory with the same name is registered twice.
+func RegisterFactory(name string, factory GeneratorFactory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if factory == nil {
+		panic("files: RegisterFactory factory is nil")
+	}
+	if _, exists := factories[name]; exists {
+		panic("files: RegisterFactory called twice for generator " + name)
+	}
+	factories[name] = factory
+}
+
+// Registry holds the set of generators available for a single mkcd run
+type Registry struct {
+	generators map[string]Generator
+}
+
+// NewRegistry creates a Registry populated with all registered generator factories
+func NewRegistry(fg *FileGenerator) *Registry {
+	registry := &Registry{
+		generators: make(map[string]Generator),
+	}
+
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	for name, factory := range factories {
+		registry.generators[name] = factory(fg)
+	}
+
+	return registry
+}
+
+// Register adds a generator to the registry
+func (r *Registry) Register(generator Generator) error {
+	name := strings.ToLower(generator.Name())
+	if name == "" {
+		return fmt.Errorf("generator name cannot be empty")
+	}
+	if _, exists := r.generators[name]; exists {
+		return fmt.Errorf("generator '%s' is already registered", name)
+	}
+
+	r.generators[name] = generator
+	return nil
+}
+
+// Get returns the generator with the specified name
+func (r *Registry) Get(name string) (Generator, bool) {
+	generator, exists := r.generators[strings.ToLower(name)]
+	return generator, exists
+}
+
+// List returns all registered generators sorted by name
+func (r *Registry) List() []Generator {
+	generators := make([]Generator, 0, len(r.generators))
+	for _, generator := range r.generators {
+		generators = append(generators, generator)
+	}
+
+	sort.Slice(generators, func(i, j int) bool {
+		return generators[i].Name() < generators[j].Name()
+	})
+
+	return generators
+}
+
+// Run runs a generator by name with the given option
+func (r *Registry) Run(ctx *GenerationContext, name, option string) error {
+	generator, exists := r.Get(name)
+	if !exists {
+		return fmt.Errorf("unknown generator: %s", name)
+	}
+
+	return generator.Generate(ctx, option)
+}
+
+// ParseGeneratorSpec splits a generator reference of the form "name" or
+// "name:option" into its name and option parts
+func ParseGeneratorSpec(spec string) (name, option string) {
+	name, option, _ = strings.Cut(strings.TrimSpace(spec), ":")
+	return strings.ToLower(name), option
+}
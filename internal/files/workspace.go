@@ -0,0 +1,108 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package files
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pterm/pterm"
+)
+
+// Workspace file formats
+const (
+	WorkspaceFormatVSCode    = "vscode"
+	WorkspaceFormatJetBrains = "jetbrains"
+)
+
+// GetAvailableWorkspaceFormats returns the supported multi-root workspace formats
+func GetAvailableWorkspaceFormats() []string {
+	return []string{WorkspaceFormatVSCode, WorkspaceFormatJetBrains}
+}
+
+// codeWorkspace is the JSON structure of a VS Code .code-workspace file
+type codeWorkspace struct {
+	Folders  []codeWorkspaceFolder `json:"folders"`
+	Settings map[string]any        `json:"settings"`
+}
+
+// codeWorkspaceFolder is a single root folder of a VS Code workspace
+type codeWorkspaceFolder struct {
+	Path string `json:"path"`
+}
+
+// GenerateCodeWorkspace generates <name>.code-workspace in root referencing
+// the member directories and returns its path
+func (fg *FileGenerator) GenerateCodeWorkspace(root, name string, members []string) (string, error) {
+	workspace := codeWorkspace{
+		Folders:  make([]codeWorkspaceFolder, 0, len(members)),
+		Settings: map[string]any{},
+	}
+	for _, member := range members {
+		workspace.Folders = append(workspace.Folders, codeWorkspaceFolder{Path: member})
+	}
+
+	data, err := json.MarshalIndent(workspace, "", "\t")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode workspace file: %w", err)
+	}
+
+	filePath := filepath.Join(root, name+".code-workspace")
+
+	if fg.Verbose {
+		pterm.Debug.Printf("Generating VS Code workspace with %d folders", len(members))
+	}
+
+	return filePath, fg.fsOps.CreateFile(filePath, string(data)+"\n", 0644)
+}
+
+// GenerateJetBrainsProject generates a .idea project in root with one module
+// per member directory and returns the project directory
+func (fg *FileGenerator) GenerateJetBrainsProject(root string, members []string) (string, error) {
+	var modules strings.Builder
+	for _, member := range members {
+		modulePath := fmt.Sprintf("$PROJECT_DIR$/%s/%s.iml", member, member)
+		modules.WriteString(fmt.Sprintf("      <module fileurl=\"file://%s\" filepath=\"%s\" />\n", modulePath, modulePath))
+	}
+
+	modulesXML := `<?xml version="1.0" encoding="UTF-8"?>
+<project version="4">
+  <component name="ProjectModuleManager">
+    <modules>
+` + modules.String() + `    </modules>
+  </component>
+</project>
+`
+
+	moduleXML := `<?xml version="1.0" encoding="UTF-8"?>
+<module type="WEB_MODULE" version="4">
+  <component name="NewModuleRootManager">
+    <content url="file://$MODULE_DIR$" />
+    <orderEntry type="sourceFolder" forTests="false" />
+  </component>
+</module>
+`
+
+	if fg.Verbose {
+		pterm.Debug.Printf("Generating JetBrains project with %d modules", len(members))
+	}
+
+	if err := fg.fsOps.CreateFile(filepath.Join(root, ".idea", "modules.xml"), modulesXML, 0644); err != nil {
+		return "", err
+	}
+
+	for _, member := range members {
+		modulePath := filepath.Join(root, member, member+".iml")
+		if err := fg.fsOps.CreateFile(modulePath, moduleXML, 0644); err != nil {
+			return "", err
+		}
+	}
+
+	return root, nil
+}
@@ -10,16 +10,23 @@ Licensed under the MIT License. See LICENSE file for details.
 package git
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/mochajutsu/mkcd/internal/utils"
 	"github.com/pterm/pterm"
 )
 
@@ -29,6 +36,10 @@ type GitManager struct {
 	Verbose   bool
 	UserName  string
 	UserEmail string
+
+	// Retry retries repository writes that fail with transient filesystem
+	// errors, e.g. on network mounts
+	Retry utils.RetryPolicy
 }
 
 // NewGitManager creates a new GitManager instance
@@ -54,8 +65,17 @@ func (gm *GitManager) InitRepository(path string, defaultBranch string) error {
 		return nil
 	}
 
-	// Initialize repository
-	repo, err := git.PlainInit(path, false)
+	// Initialize repository. A failed attempt may leave a partial .git
+	// behind, which the next attempt opens instead.
+	var repo *git.Repository
+	err := gm.Retry.Do("git init "+path, func() error {
+		var err error
+		repo, err = git.PlainInit(path, false)
+		if errors.Is(err, git.ErrRepositoryAlreadyExists) {
+			repo, err = git.PlainOpen(path)
+		}
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to initialize Git repository: %w", err)
 	}
@@ -136,8 +156,19 @@ func (gm *GitManager) AddRemote(repoPath, remoteName, remoteURL string) error {
 
 // CreateInitialCommit creates an initial commit with any existing files
 func (gm *GitManager) CreateInitialCommit(repoPath, message string) error {
+	return gm.CreateInitialCommitWithFiles(repoPath, message, nil)
+}
+
+// CreateInitialCommitWithFiles creates an initial commit containing only the
+// specified files (relative to the repository root). A nil or empty list
+// commits every file in the working tree; listed files that don't exist are ignored.
+func (gm *GitManager) CreateInitialCommitWithFiles(repoPath, message string, files []string) error {
 	if gm.DryRun {
-		pterm.Info.Printf("[DRY RUN] Would create initial commit: %s", message)
+		if len(files) > 0 {
+			pterm.Info.Printf("[DRY RUN] Would create initial commit with %s: %s", strings.Join(files, ", "), message)
+		} else {
+			pterm.Info.Printf("[DRY RUN] Would create initial commit: %s", message)
+		}
 		return nil
 	}
 
@@ -153,26 +184,56 @@ func (gm *GitManager) CreateInitialCommit(repoPath, message string) error {
 		return fmt.Errorf("failed to get working tree: %w", err)
 	}
 
-	// Add all files
-	if err := worktree.AddGlob("."); err != nil {
-		return fmt.Errorf("failed to add files to staging: %w", err)
+	if len(files) == 0 {
+		// Add all files
+		if err := gm.Retry.Do("git add", func() error { return worktree.AddGlob(".") }); err != nil {
+			return fmt.Errorf("failed to add files to staging: %w", err)
+		}
+	} else {
+		// Add only the requested files
+		for _, file := range files {
+			if _, err := os.Stat(filepath.Join(repoPath, file)); os.IsNotExist(err) {
+				pterm.Debug.Printf("Skipping missing file for initial commit: %s", file)
+				continue
+			}
+			err := gm.Retry.Do("git add "+file, func() error {
+				_, err := worktree.Add(file)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("failed to add %s to staging: %w", file, err)
+			}
+		}
 	}
 
-	// Check if there are any changes to commit
+	// Check if there are any staged changes to commit
 	status, err := worktree.Status()
 	if err != nil {
 		return fmt.Errorf("failed to get repository status: %w", err)
 	}
 
-	if status.IsClean() {
+	hasStaged := false
+	for _, fileStatus := range status {
+		if fileStatus.Staging != git.Unmodified && fileStatus.Staging != git.Untracked {
+			hasStaged = true
+			break
+		}
+	}
+
+	if !hasStaged {
 		pterm.Debug.Println("No changes to commit")
 		return nil
 	}
 
 	// Create commit
 	author := gm.getCommitAuthor()
-	commitHash, err := worktree.Commit(message, &git.CommitOptions{
-		Author: author,
+	var commitHash plumbing.Hash
+	err = gm.Retry.Do("git commit", func() error {
+		var err error
+		commitHash, err = worktree.Commit(message, &git.CommitOptions{
+			Author: author,
+		})
+		return err
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create commit: %w", err)
@@ -182,6 +243,42 @@ func (gm *GitManager) CreateInitialCommit(repoPath, message string) error {
 	return nil
 }
 
+// CommitMessageData contains the variables available to commit message templates
+type CommitMessageData struct {
+	ProjectName string
+	Profile     string
+	Template    string
+	Branch      string
+	Author      string
+	Email       string
+	Date        string
+}
+
+// RenderCommitMessage renders a commit message template using Go text/template
+// syntax, e.g. "chore: scaffold {{.ProjectName}} via mkcd"
+func RenderCommitMessage(messageTemplate string, data CommitMessageData) (string, error) {
+	if messageTemplate == "" {
+		return "Initial commit", nil
+	}
+
+	tmpl, err := template.New("commit-message").Option("missingkey=error").Parse(messageTemplate)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse commit message template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render commit message template: %w", err)
+	}
+
+	message := strings.TrimSpace(buf.String())
+	if message == "" {
+		return "", fmt.Errorf("commit message template rendered an empty message")
+	}
+
+	return message, nil
+}
+
 // getCommitAuthor returns the commit author information
 func (gm *GitManager) getCommitAuthor() *object.Signature {
 	name := gm.UserName
@@ -206,7 +303,7 @@ func (gm *GitManager) getCommitAuthor() *object.Signature {
 	return &object.Signature{
 		Name:  name,
 		Email: email,
-		When:  time.Now(),
+		When:  utils.Now(),
 	}
 }
 
@@ -233,6 +330,9 @@ func (gm *GitManager) GetRepositoryInfo(repoPath string) (*RepositoryInfo, error
 	head, err := repo.Head()
 	if err == nil {
 		info.CurrentBranch = head.Name().Short()
+	} else if ref, refErr := repo.Reference(plumbing.HEAD, false); refErr == nil && ref.Type() == plumbing.SymbolicReference {
+		// A repository without commits is still on its initial branch
+		info.CurrentBranch = ref.Target().Short()
 	}
 
 	// Get remotes
@@ -247,6 +347,13 @@ func (gm *GitManager) GetRepositoryInfo(repoPath string) (*RepositoryInfo, error
 		}
 	}
 
+	// Untracked files count as changes; bare repositories have no worktree
+	if worktree, err := repo.Worktree(); err == nil {
+		if status, err := worktree.Status(); err == nil {
+			info.Dirty = !status.IsClean()
+		}
+	}
+
 	// Get last commit
 	if head != nil {
 		commit, err := repo.CommitObject(head.Hash())
@@ -269,6 +376,9 @@ type RepositoryInfo struct {
 	CurrentBranch string
 	Remotes       map[string]string
 	LastCommit    *CommitInfo
+
+	// Dirty is set when the worktree has uncommitted or untracked changes
+	Dirty bool
 }
 
 // CommitInfo contains information about a Git commit
@@ -279,6 +389,20 @@ type CommitInfo struct {
 	Date    time.Time
 }
 
+// conventionalCommitPattern matches a Conventional Commits header,
+// e.g. "feat(api)!: add endpoint"
+var conventionalCommitPattern = regexp.MustCompile(`^(build|chore|ci|docs|feat|fix|perf|refactor|revert|style|test)(\([\w./-]+\))?!?: \S.*`)
+
+// ValidateConventionalCommit checks that the first line of a commit message
+// follows the Conventional Commits specification
+func ValidateConventionalCommit(message string) error {
+	header, _, _ := strings.Cut(message, "\n")
+	if !conventionalCommitPattern.MatchString(header) {
+		return fmt.Errorf("commit message %q does not follow Conventional Commits (expected '<type>[(scope)]: <description>')", header)
+	}
+	return nil
+}
+
 // ValidateRemoteURL validates a Git remote URL
 func ValidateRemoteURL(url string) error {
 	if url == "" {
@@ -335,6 +459,73 @@ func (gm *GitManager) CloneRepository(url, path string, shallow bool) error {
 	return nil
 }
 
+// CloneShallow clones the default branch of url into path with a depth of
+// one, writing progress to progress when it is not nil. Unlike
+// CloneRepository it prints nothing, so it can run while stdout is reserved.
+func CloneShallow(ctx context.Context, url, path string, progress io.Writer) error {
+	_, err := git.PlainCloneContext(ctx, path, false, &git.CloneOptions{
+		URL:      url,
+		Depth:    1,
+		Progress: progress,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to clone %s: %w", url, err)
+	}
+	return nil
+}
+
+// CloneAt clones url into path and checks out ref, a commit hash, tag or
+// branch. The whole history is fetched, as a commit may be anywhere in it.
+func CloneAt(ctx context.Context, url, path, ref string, progress io.Writer) error {
+	repo, err := git.PlainCloneContext(ctx, path, false, &git.CloneOptions{
+		URL:      url,
+		Tags:     git.AllTags,
+		Progress: progress,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to clone %s: %w", url, err)
+	}
+
+	hash, err := resolveRef(repo, ref)
+	if err != nil {
+		return fmt.Errorf("%s: %w", url, err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+	if err := worktree.Checkout(&git.CheckoutOptions{Hash: hash, Force: true}); err != nil {
+		return fmt.Errorf("failed to check out %s: %w", ref, err)
+	}
+	return nil
+}
+
+// AtRef reports whether the repository in path has ref, a commit hash, tag
+// or branch, checked out
+func AtRef(path, ref string) bool {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return false
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return false
+	}
+	hash, err := resolveRef(repo, ref)
+	return err == nil && hash == head.Hash()
+}
+
+// resolveRef returns the commit of ref, a commit hash, tag or branch of the
+// origin remote
+func resolveRef(repo *git.Repository, ref string) (plumbing.Hash, error) {
+	for _, revision := range []string{ref, "refs/remotes/origin/" + ref} {
+		if hash, err := repo.ResolveRevision(plumbing.Revision(revision)); err == nil {
+			return *hash, nil
+		}
+	}
+	return plumbing.ZeroHash, fmt.Errorf("unknown revision %s", ref)
+}
+
 // GetBranches returns a list of branches in the repository
 func (gm *GitManager) GetBranches(repoPath string) ([]string, error) {
 	repo, err := git.PlainOpen(repoPath)
@@ -0,0 +1,234 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package history
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportVersion is the export schema written by WriteExport. It is raised
+// whenever a field changes meaning or is removed; added fields keep it.
+const ExportVersion = 1
+
+// Export formats
+const (
+	ExportJSON = "json"
+	ExportCSV  = "csv"
+)
+
+// GetExportFormats returns the supported export formats
+func GetExportFormats() []string {
+	return []string{ExportJSON, ExportCSV}
+}
+
+// Export is the JSON export of the history
+type Export struct {
+	Version  int       `json:"version"`
+	Exported time.Time `json:"exported"`
+	Entries  []Entry   `json:"entries"`
+}
+
+// csvVersionPrefix starts the first line of CSV exports, which carries the
+// schema version in front of the header
+const csvVersionPrefix = "# mkcd history export version "
+
+// csvColumns are the columns of CSV exports; flags and files are joined by
+// semicolons
+var csvColumns = []string{"id", "timestamp", "path", "profile", "template", "git", "flags", "files"}
+
+// WriteExport writes the entries in format to w
+func WriteExport(w io.Writer, format string, entries []Entry, now time.Time) error {
+	switch format {
+	case ExportJSON:
+		data, err := json.MarshalIndent(Export{Version: ExportVersion, Exported: now, Entries: entries}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode history: %w", err)
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	case ExportCSV:
+		if _, err := fmt.Fprintf(w, "%s%d\n", csvVersionPrefix, ExportVersion); err != nil {
+			return err
+		}
+		writer := csv.NewWriter(w)
+		_ = writer.Write(csvColumns)
+		for _, entry := range entries {
+			_ = writer.Write([]string{
+				entry.ID,
+				entry.Timestamp.Format(time.RFC3339Nano),
+				entry.Path,
+				entry.Profile,
+				entry.Template,
+				strconv.FormatBool(entry.Git),
+				strings.Join(entry.Flags, ";"),
+				strings.Join(entry.Files, ";"),
+			})
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		return fmt.Errorf("unknown export format '%s' (expected %s)", format, strings.Join(GetExportFormats(), ", "))
+	}
+}
+
+// ReadExport reads the entries of an export in format from r
+func ReadExport(r io.Reader, format string) ([]Entry, error) {
+	switch format {
+	case ExportJSON:
+		var export Export
+		if err := json.NewDecoder(r).Decode(&export); err != nil {
+			return nil, fmt.Errorf("failed to parse export: %w", err)
+		}
+		if err := checkExportVersion(export.Version); err != nil {
+			return nil, err
+		}
+		return export.Entries, nil
+	case ExportCSV:
+		return readCSVExport(r)
+	default:
+		return nil, fmt.Errorf("unknown export format '%s' (expected %s)", format, strings.Join(GetExportFormats(), ", "))
+	}
+}
+
+// checkExportVersion rejects exports without a version or of a newer schema
+func checkExportVersion(version int) error {
+	if version < 1 {
+		return fmt.Errorf("export has no schema version; is it an mkcd history export?")
+	}
+	if version > ExportVersion {
+		return fmt.Errorf("export format %d is newer than supported format %d", version, ExportVersion)
+	}
+	return nil
+}
+
+// readCSVExport reads a CSV export. Columns are matched by name, so their
+// order may change and unknown ones are ignored.
+func readCSVExport(r io.Reader) ([]Entry, error) {
+	reader := bufio.NewReader(r)
+	first, err := reader.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read export: %w", err)
+	}
+	version := 0
+	if value, found := strings.CutPrefix(strings.TrimSpace(first), csvVersionPrefix); found {
+		version, _ = strconv.Atoi(value)
+	}
+	if err := checkExportVersion(version); err != nil {
+		return nil, err
+	}
+
+	records, err := csv.NewReader(reader).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse export: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("export has no header")
+	}
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, required := range []string{"timestamp", "path"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("export has no %s column", required)
+		}
+	}
+
+	entries := []Entry{}
+	for line, record := range records[1:] {
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+		split := func(value string) []string {
+			if value == "" {
+				return nil
+			}
+			return strings.Split(value, ";")
+		}
+
+		timestamp, err := time.Parse(time.RFC3339Nano, field("timestamp"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp in export record %d: %w", line+1, err)
+		}
+		git, _ := strconv.ParseBool(field("git"))
+		entries = append(entries, Entry{
+			ID:        field("id"),
+			Timestamp: timestamp,
+			Path:      field("path"),
+			Profile:   field("profile"),
+			Template:  field("template"),
+			Git:       git,
+			Flags:     split(field("flags")),
+			Files:     split(field("files")),
+		})
+	}
+	return entries, nil
+}
+
+// Import merges entries into the history in time order, skipping those
+// already recorded, and returns the added ones. Entries without an ID get
+// one; the limit applies as on every write, and with dryRun nothing is
+// changed.
+func (s *Store) Import(entries []Entry, dryRun bool) ([]Entry, error) {
+	if !dryRun {
+		lock, err := s.lock(s.Path)
+		if err != nil {
+			return nil, err
+		}
+		defer lock.Unlock()
+	}
+
+	existing, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	for _, entry := range existing {
+		seen[entry.ID] = true
+		seen[importKey(entry)] = true
+	}
+
+	added := []Entry{}
+	for _, entry := range entries {
+		if entry.Path == "" || seen[importKey(entry)] || (entry.ID != "" && seen[entry.ID]) {
+			continue
+		}
+		if entry.ID == "" {
+			entry.ID = NewID(entry.Timestamp, entry.Path)
+		}
+		seen[entry.ID] = true
+		seen[importKey(entry)] = true
+		added = append(added, entry)
+	}
+	if dryRun || len(added) == 0 {
+		return added, nil
+	}
+
+	merged := append(existing, added...)
+	slices.SortStableFunc(merged, func(a, b Entry) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+	return added, s.save(merged)
+}
+
+// importKey identifies an entry by its time and path, which survive
+// exports without IDs
+func importKey(entry Entry) string {
+	return entry.Timestamp.UTC().Format(time.RFC3339Nano) + "\x00" + entry.Path
+}
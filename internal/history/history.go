@@ -0,0 +1,384 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+// Package history records the directories created by mkcd so that usage can
+// be analysed and past operations inspected.
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/mochajutsu/mkcd/internal/utils"
+)
+
+// storeLockWait bounds how long a write waits for another invocation
+// updating the same file
+const storeLockWait = 10 * time.Second
+
+// Entry represents a single recorded mkcd operation
+type Entry struct {
+	ID        string    `json:"id,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Path      string    `json:"path"`
+	Profile   string    `json:"profile,omitempty"`
+	Template  string    `json:"template,omitempty"`
+	Git       bool      `json:"git,omitempty"`
+
+	// Flags are the command-line flags the operation was run with, e.g.
+	// "--git" or "--template=go"
+	Flags []string `json:"flags,omitempty"`
+
+	// Files are the files the operation wrote, slash-separated and
+	// relative to Path
+	Files []string `json:"files,omitempty"`
+}
+
+// Store persists history entries as JSON Lines, keeping at most Limit entries
+type Store struct {
+	Path  string
+	Limit int
+}
+
+// NewStore creates a new Store for the given file and entry limit
+func NewStore(path string, limit int) *Store {
+	return &Store{
+		Path:  path,
+		Limit: limit,
+	}
+}
+
+// StateDir returns the directory holding mkcd state such as history and
+// manifests, $XDG_DATA_HOME/mkcd or ~/.local/share/mkcd
+func StateDir() (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		homeDir, err := homedir.Dir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		dataHome = filepath.Join(homeDir, ".local", "share")
+	}
+
+	return filepath.Join(dataHome, "mkcd"), nil
+}
+
+// DefaultPath returns the default history file location inside the state directory
+func DefaultPath() (string, error) {
+	stateDir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(stateDir, "history.jsonl"), nil
+}
+
+// NewID derives a short identifier for an entry from its time, its path and
+// random bytes, so entries recorded in the same instant for the same path
+// never share an ID
+func NewID(timestamp time.Time, path string) string {
+	var salt [8]byte
+	if _, err := rand.Read(salt[:]); err != nil {
+		// crypto/rand does not fail on supported platforms
+		panic(fmt.Sprintf("failed to generate history ID: %v", err))
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d\x00%s\x00%x", timestamp.UnixNano(), path, salt)))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// Load reads all entries from the store, oldest first.
+// A missing history file yields no entries.
+func (s *Store) Load() ([]Entry, error) {
+	file, err := os.Open(s.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Entry{}, nil
+		}
+		return nil, fmt.Errorf("failed to open history file %s: %w", s.Path, err)
+	}
+	defer file.Close()
+
+	// Lines have no length limit, as an entry lists every file a creation
+	// wrote, which a large template makes long
+	entries := []Entry{}
+	reader := bufio.NewReader(file)
+	for line := 1; ; line++ {
+		data, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return nil, fmt.Errorf("failed to read history file %s: %w", s.Path, readErr)
+		}
+
+		if text := bytes.TrimSpace(data); len(text) > 0 {
+			var entry Entry
+			if err := json.Unmarshal(text, &entry); err != nil {
+				return nil, fmt.Errorf("failed to parse history file %s at line %d: %w", s.Path, line, err)
+			}
+			entries = append(entries, entry)
+		}
+		if readErr == io.EOF {
+			return entries, nil
+		}
+	}
+}
+
+// lock takes the lock of a file of the store, held while it is read and
+// rewritten so concurrent invocations do not lose each other's changes
+func (s *Store) lock(path string) (*utils.PathLock, error) {
+	return utils.LockPath(filepath.Join(filepath.Dir(s.Path), "locks"), path, storeLockWait)
+}
+
+// Append records an entry, dropping the oldest entries beyond the limit
+func (s *Store) Append(entry Entry) error {
+	lock, err := s.lock(s.Path)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	entries, err := s.Load()
+	if err != nil {
+		return err
+	}
+
+	return s.save(append(entries, entry))
+}
+
+// save rewrites the history file with the given entries, which callers
+// hold the lock for. The oldest entries beyond the limit are dropped on
+// every write, so a lowered limit takes effect with the next one.
+func (s *Store) save(entries []Entry) error {
+	if s.Limit > 0 && len(entries) > s.Limit {
+		s.removeManifests(entries[:len(entries)-s.Limit])
+		entries = entries[len(entries)-s.Limit:]
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	var builder strings.Builder
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to encode history entry: %w", err)
+		}
+		builder.Write(data)
+		builder.WriteByte('\n')
+	}
+
+	if err := utils.WriteFileAtomic(s.Path, []byte(builder.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+
+	return nil
+}
+
+// removeManifests removes the manifests of dropped entries, which are no
+// longer reachable
+func (s *Store) removeManifests(entries []Entry) {
+	for _, entry := range entries {
+		if entry.ID != "" {
+			_ = os.Remove(s.ManifestPath(entry.ID))
+		}
+	}
+}
+
+// Remove deletes the entry with the given ID and its manifest
+func (s *Store) Remove(id string) error {
+	lock, err := s.lock(s.Path)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	entries, err := s.Load()
+	if err != nil {
+		return err
+	}
+
+	kept := []Entry{}
+	for _, entry := range entries {
+		if entry.ID != id {
+			kept = append(kept, entry)
+		}
+	}
+	if err := os.Remove(s.ManifestPath(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove manifest: %w", err)
+	}
+	if len(kept) == len(entries) {
+		return nil
+	}
+	return s.save(kept)
+}
+
+// Prune deletes the entries for which drop returns true, and the oldest
+// beyond the limit, together with their manifests. It returns the deleted
+// entries, oldest first; with dryRun nothing is changed.
+func (s *Store) Prune(drop func(Entry) bool, dryRun bool) ([]Entry, error) {
+	if !dryRun {
+		lock, err := s.lock(s.Path)
+		if err != nil {
+			return nil, err
+		}
+		defer lock.Unlock()
+	}
+
+	entries, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	kept := []Entry{}
+	pruned := []Entry{}
+	for _, entry := range entries {
+		if drop(entry) {
+			pruned = append(pruned, entry)
+		} else {
+			kept = append(kept, entry)
+		}
+	}
+	if s.Limit > 0 && len(kept) > s.Limit {
+		pruned = append(pruned, kept[:len(kept)-s.Limit]...)
+		kept = kept[len(kept)-s.Limit:]
+		slices.SortStableFunc(pruned, func(a, b Entry) int {
+			return a.Timestamp.Compare(b.Timestamp)
+		})
+	}
+	if dryRun || len(pruned) == 0 {
+		return pruned, nil
+	}
+
+	s.removeManifests(pruned)
+	return pruned, s.save(kept)
+}
+
+// Find returns the entry with the given ID or unique ID prefix
+func (s *Store) Find(id string) (*Entry, error) {
+	entries, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	var found *Entry
+	for i := range entries {
+		if entries[i].ID == "" || !strings.HasPrefix(entries[i].ID, id) {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("history ID '%s' is ambiguous", id)
+		}
+		found = &entries[i]
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("no history entry with ID '%s'", id)
+	}
+	return found, nil
+}
+
+// Filter selects history entries; zero fields match every entry
+type Filter struct {
+	// Since and Until bound the creation time, both inclusive
+	Since time.Time
+	Until time.Time
+
+	Profile string
+
+	// Template matches entries that applied it, alone or as a layer
+	Template string
+
+	// Path matches entries whose path contains it, ignoring case
+	Path string
+}
+
+// Match reports whether entry passes the filter
+func (f Filter) Match(entry Entry) bool {
+	if !f.Since.IsZero() && entry.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && entry.Timestamp.After(f.Until) {
+		return false
+	}
+	if f.Profile != "" && entry.Profile != f.Profile {
+		return false
+	}
+	if f.Template != "" && !slices.Contains(templateNames(entry.Template), f.Template) {
+		return false
+	}
+	if f.Path != "" && !strings.Contains(strings.ToLower(entry.Path), strings.ToLower(f.Path)) {
+		return false
+	}
+	return true
+}
+
+// Apply returns the entries passing the filter, in their order
+func (f Filter) Apply(entries []Entry) []Entry {
+	matched := []Entry{}
+	for _, entry := range entries {
+		if f.Match(entry) {
+			matched = append(matched, entry)
+		}
+	}
+	return matched
+}
+
+// templateNames splits the comma-separated templates of an entry
+func templateNames(value string) []string {
+	names := []string{}
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// ProfileUsage counts how many recorded operations used each profile
+func ProfileUsage(entries []Entry) map[string]int {
+	usage := make(map[string]int)
+	for _, entry := range entries {
+		if entry.Profile != "" {
+			usage[entry.Profile]++
+		}
+	}
+	return usage
+}
+
+// ProfileLastUsed returns the time each profile was last used
+func ProfileLastUsed(entries []Entry) map[string]time.Time {
+	lastUsed := make(map[string]time.Time)
+	for _, entry := range entries {
+		if entry.Profile == "" {
+			continue
+		}
+		if entry.Timestamp.After(lastUsed[entry.Profile]) {
+			lastUsed[entry.Profile] = entry.Timestamp
+		}
+	}
+	return lastUsed
+}
+
+// IsStale reports whether a profile last used at lastUsed (zero if never)
+// has been unused for at least the given number of months before now
+func IsStale(lastUsed, now time.Time, months int) bool {
+	if lastUsed.IsZero() {
+		return true
+	}
+	return !lastUsed.After(now.AddDate(0, -months, 0))
+}
@@ -0,0 +1,142 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestNewIDUnique checks that entries recorded in the same instant for the
+// same path, as in deterministic mode, get distinct IDs
+func TestNewIDUnique(t *testing.T) {
+	timestamp := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		id := NewID(timestamp, "/tmp/api")
+		if len(id) != 12 {
+			t.Fatalf("expected a 12 character ID, got %q", id)
+		}
+		if seen[id] {
+			t.Fatalf("duplicate ID %s", id)
+		}
+		seen[id] = true
+	}
+}
+
+// TestFindDistinguishesSameInstant finds entries sharing a timestamp by ID
+func TestFindDistinguishesSameInstant(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "history.jsonl"), 0)
+	timestamp := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	ids := []string{}
+	for _, path := range []string{"/tmp/a", "/tmp/a"} {
+		entry := Entry{ID: NewID(timestamp, path), Timestamp: timestamp, Path: path}
+		if err := store.Append(entry); err != nil {
+			t.Fatalf("append failed: %v", err)
+		}
+		ids = append(ids, entry.ID)
+	}
+
+	for _, id := range ids {
+		entry, err := store.Find(id)
+		if err != nil || entry.ID != id {
+			t.Errorf("Find(%s) = %+v, %v", id, entry, err)
+		}
+	}
+}
+
+// TestAppendConcurrent checks that concurrent appends neither lose entries
+// nor leave temporary files behind, including when they trim the history
+func TestAppendConcurrent(t *testing.T) {
+	for _, limit := range []int{0, 5} {
+		t.Run(fmt.Sprintf("limit %d", limit), func(t *testing.T) {
+			dir := t.TempDir()
+			store := NewStore(filepath.Join(dir, "history.jsonl"), limit)
+
+			const appends = 12
+			var wg sync.WaitGroup
+			errs := make(chan error, appends)
+			for i := 0; i < appends; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					path := fmt.Sprintf("/tmp/%d", i)
+					errs <- store.Append(Entry{ID: NewID(time.Now(), path), Timestamp: time.Now(), Path: path})
+				}(i)
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				if err != nil {
+					t.Fatalf("append failed: %v", err)
+				}
+			}
+
+			entries, err := store.Load()
+			if err != nil {
+				t.Fatalf("load failed: %v", err)
+			}
+			expected := appends
+			if limit > 0 {
+				expected = limit
+			}
+			if len(entries) != expected {
+				t.Errorf("expected %d entries, got %d", expected, len(entries))
+			}
+
+			files, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatalf("failed to read %s: %v", dir, err)
+			}
+			for _, file := range files {
+				if file.Name() != "history.jsonl" && file.Name() != "locks" {
+					t.Errorf("unexpected file %s left in the state directory", file.Name())
+				}
+			}
+		})
+	}
+}
+
+// TestLoadLongEntry checks that an entry listing the files of a large
+// template, longer than a default scanner line, loads again
+func TestLoadLongEntry(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "history.jsonl"), 0)
+	timestamp := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	files := []string{}
+	for i := 0; i < 5000; i++ {
+		files = append(files, fmt.Sprintf("src/components/generated/component_%04d.tsx", i))
+	}
+	entries := []Entry{
+		{ID: NewID(timestamp, "/tmp/large"), Timestamp: timestamp, Path: "/tmp/large", Files: files},
+		{ID: NewID(timestamp, "/tmp/small"), Timestamp: timestamp, Path: "/tmp/small"},
+	}
+	for _, entry := range entries {
+		if err := store.Append(entry); err != nil {
+			t.Fatalf("append failed: %v", err)
+		}
+	}
+
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if len(loaded) != len(entries) {
+		t.Fatalf("expected %d entries, got %d", len(entries), len(loaded))
+	}
+	if len(loaded[0].Files) != len(files) || loaded[0].Files[len(files)-1] != files[len(files)-1] {
+		t.Errorf("expected %d files, got %d", len(files), len(loaded[0].Files))
+	}
+	if loaded[1].Path != "/tmp/small" {
+		t.Errorf("expected the entry after the long one, got %+v", loaded[1])
+	}
+}
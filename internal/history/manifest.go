@@ -0,0 +1,126 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ManifestVersion is the manifest format written by SaveManifest
+const ManifestVersion = 1
+
+// Manifest is a machine-readable record of everything mkcd generated for a
+// single creation, kept in the state directory for provenance audits and
+// optionally in the workspace itself
+type Manifest struct {
+	Version   int           `json:"version" toml:"version"`
+	ID        string        `json:"id" toml:"id"`
+	Path      string        `json:"path" toml:"path"`
+	Created   time.Time     `json:"created" toml:"created"`
+	Profile   string        `json:"profile,omitempty" toml:"profile,omitempty"`
+	Templates []TemplateRef `json:"templates" toml:"templates"`
+	Files     []FileRef     `json:"files" toml:"files"`
+
+	// Template is the template expression the creation was run with and
+	// Variables the template variable values given to it
+	Template  string            `json:"template,omitempty" toml:"template,omitempty"`
+	Variables map[string]string `json:"variables,omitempty" toml:"variables,omitempty"`
+
+	// Flags are the command-line flags the creation was run with
+	Flags []string `json:"flags,omitempty" toml:"flags,omitempty"`
+
+	// Directories are the directories the creation made, outermost first,
+	// and Symlinks the symlinks; both are relative to Path like files
+	Directories []string `json:"directories,omitempty" toml:"directories,omitempty"`
+	Symlinks    []string `json:"symlinks,omitempty" toml:"symlinks,omitempty"`
+
+	// Git is set when the Git repository was initialized in a directory
+	// the creation made, and GitHead is the commit it was left at, empty
+	// without an initial commit
+	Git     bool   `json:"git,omitempty" toml:"git,omitempty"`
+	GitHead string `json:"git_head,omitempty" toml:"git_head,omitempty"`
+}
+
+// TemplateRef identifies a template and the version that was applied
+type TemplateRef struct {
+	Name    string `json:"name" toml:"name"`
+	Version string `json:"version" toml:"version"`
+}
+
+// FileRef describes a generated file, what produced it and its checksum
+type FileRef struct {
+	Path   string `json:"path" toml:"path"`
+	Source string `json:"source" toml:"source"`
+	SHA256 string `json:"sha256" toml:"sha256"`
+	Size   int64  `json:"size" toml:"size"`
+}
+
+// ManifestPath returns the location of the manifest for an entry ID
+func (s *Store) ManifestPath(id string) string {
+	return filepath.Join(filepath.Dir(s.Path), "manifests", id+".json")
+}
+
+// SaveManifest writes a manifest next to the history
+func (s *Store) SaveManifest(manifest *Manifest) error {
+	manifest.Version = ManifestVersion
+
+	path := s.ManifestPath(manifest.ID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create manifest directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write manifest %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadManifest reads the manifest for an entry ID
+func (s *Store) LoadManifest(id string) (*Manifest, error) {
+	data, err := os.ReadFile(s.ManifestPath(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no manifest recorded for '%s'", id)
+		}
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// FileChecksum returns the hex SHA-256 checksum and size of a file
+func FileChecksum(path string) (string, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
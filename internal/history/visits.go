@@ -0,0 +1,174 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mochajutsu/mkcd/internal/utils"
+)
+
+// VisitsVersion is the visits format written by SaveVisits
+const VisitsVersion = 1
+
+// Visit counts how often a directory was changed into through mkcd
+type Visit struct {
+	Count int       `json:"count"`
+	Last  time.Time `json:"last"`
+}
+
+// Visits are the visits of directories, keyed by path
+type Visits struct {
+	Version int              `json:"version"`
+	Paths   map[string]Visit `json:"paths"`
+}
+
+// VisitsPath returns the location of the visits next to the history
+func (s *Store) VisitsPath() string {
+	return filepath.Join(filepath.Dir(s.Path), "visits.json")
+}
+
+// LoadVisits reads the recorded visits; a missing file has none
+func (s *Store) LoadVisits() (*Visits, error) {
+	visits := &Visits{Version: VisitsVersion, Paths: map[string]Visit{}}
+	data, err := os.ReadFile(s.VisitsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return visits, nil
+		}
+		return nil, fmt.Errorf("failed to read visits: %w", err)
+	}
+
+	if err := json.Unmarshal(data, visits); err != nil {
+		return nil, fmt.Errorf("failed to parse visits %s: %w", s.VisitsPath(), err)
+	}
+	if visits.Version > VisitsVersion {
+		return nil, fmt.Errorf("visits format %d is newer than supported format %d", visits.Version, VisitsVersion)
+	}
+	if visits.Paths == nil {
+		visits.Paths = map[string]Visit{}
+	}
+	return visits, nil
+}
+
+// SaveVisits writes the visits next to the history
+func (s *Store) SaveVisits(visits *Visits) error {
+	visits.Version = VisitsVersion
+	data, err := json.MarshalIndent(visits, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode visits: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	if err := utils.WriteFileAtomic(s.VisitsPath(), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write visits: %w", err)
+	}
+	return nil
+}
+
+// RecordVisit counts a visit of path at the given time
+func (s *Store) RecordVisit(path string, at time.Time) error {
+	lock, err := s.lock(s.VisitsPath())
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	visits, err := s.LoadVisits()
+	if err != nil {
+		return err
+	}
+
+	visit := visits.Paths[path]
+	visit.Count++
+	if at.After(visit.Last) {
+		visit.Last = at
+	}
+	visits.Paths[path] = visit
+	return s.SaveVisits(visits)
+}
+
+// PruneVisits deletes the visits for which drop returns true and returns
+// how many were deleted; with dryRun nothing is changed
+func (s *Store) PruneVisits(drop func(path string, visit Visit) bool, dryRun bool) (int, error) {
+	if !dryRun {
+		lock, err := s.lock(s.VisitsPath())
+		if err != nil {
+			return 0, err
+		}
+		defer lock.Unlock()
+	}
+
+	visits, err := s.LoadVisits()
+	if err != nil {
+		return 0, err
+	}
+
+	pruned := 0
+	for path, visit := range visits.Paths {
+		if drop(path, visit) {
+			delete(visits.Paths, path)
+			pruned++
+		}
+	}
+	if dryRun || pruned == 0 {
+		return pruned, nil
+	}
+	return pruned, s.SaveVisits(visits)
+}
+
+// Frecencies combines the creations in entries with the visits into a
+// score per path: every creation and visit counts once, and the total is
+// weighted by how recently the path was last used, like zoxide does
+func Frecencies(entries []Entry, visits *Visits, now time.Time) map[string]float64 {
+	combined := map[string]Visit{}
+	for _, entry := range entries {
+		visit := combined[entry.Path]
+		visit.Count++
+		if entry.Timestamp.After(visit.Last) {
+			visit.Last = entry.Timestamp
+		}
+		combined[entry.Path] = visit
+	}
+	if visits != nil {
+		for path, v := range visits.Paths {
+			visit := combined[path]
+			visit.Count += v.Count
+			if v.Last.After(visit.Last) {
+				visit.Last = v.Last
+			}
+			combined[path] = visit
+		}
+	}
+
+	scores := make(map[string]float64, len(combined))
+	for path, visit := range combined {
+		scores[path] = float64(visit.Count) * recencyWeight(now.Sub(visit.Last))
+	}
+	return scores
+}
+
+// recencyWeight weights a use by its age: within the hour counts four
+// times, within the day twice, within the week half and older a quarter
+func recencyWeight(age time.Duration) float64 {
+	switch {
+	case age < time.Hour:
+		return 4
+	case age < 24*time.Hour:
+		return 2
+	case age < 7*24*time.Hour:
+		return 0.5
+	default:
+		return 0.25
+	}
+}
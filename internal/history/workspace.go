@@ -0,0 +1,65 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package history
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// WorkspaceDir is the directory inside a workspace holding its manifest
+const WorkspaceDir = ".mkcd"
+
+// WorkspaceManifestPath returns the location of the manifest inside the
+// workspace at dir
+func WorkspaceManifestPath(dir string) string {
+	return filepath.Join(dir, WorkspaceDir, "manifest.toml")
+}
+
+// WriteWorkspaceManifest writes the manifest into its workspace, so the
+// creation can be undone or repeated without the history
+func WriteWorkspaceManifest(manifest *Manifest) error {
+	manifest.Version = ManifestVersion
+
+	var buf bytes.Buffer
+	buf.WriteString("# mkcd manifest: what mkcd generated in this directory. It lets 'mkcd undo\n")
+	buf.WriteString("# <dir>' and 'mkcd redo --from <dir>' work even without the history.\n")
+	if err := toml.NewEncoder(&buf).Encode(manifest); err != nil {
+		return fmt.Errorf("failed to encode workspace manifest: %w", err)
+	}
+
+	path := WorkspaceManifestPath(manifest.Path)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write workspace manifest %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadWorkspaceManifest reads the manifest inside the workspace at dir.
+// Its path is the directory's current location, which differs from the
+// recorded one after the workspace was moved.
+func LoadWorkspaceManifest(dir string) (*Manifest, error) {
+	var manifest Manifest
+	if _, err := toml.DecodeFile(WorkspaceManifestPath(dir), &manifest); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s has no workspace manifest", dir)
+		}
+		return nil, fmt.Errorf("failed to parse workspace manifest: %w", err)
+	}
+	if manifest.Version > ManifestVersion {
+		return nil, fmt.Errorf("manifest format %d is newer than supported format %d", manifest.Version, ManifestVersion)
+	}
+	manifest.Path = dir
+	return &manifest, nil
+}
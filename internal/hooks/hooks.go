@@ -0,0 +1,154 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+// Package hooks runs the shell commands configured as hooks under a
+// watchdog: each command gets a time limit, no terminal input, an optional
+// allowlisted environment and optionally a restricted shell, and its output
+// is captured for the caller to show.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// waitDelay bounds how long a command is waited for once it was stopped,
+// e.g. when a background process it started keeps its output open
+const waitDelay = 5 * time.Second
+
+// alwaysAllowed are passed to hooks even with an environment allowlist,
+// since hardly any command works without them
+var alwaysAllowed = []string{"PATH", "HOME"}
+
+// Runner runs hook commands
+type Runner struct {
+	// Timeout stops a command running longer; 0 means no limit
+	Timeout time.Duration
+
+	// Env lists the environment variables passed to commands, with a
+	// trailing * matching a prefix (e.g. "LC_*"); empty passes all of them.
+	// PATH and HOME are always passed.
+	Env []string
+
+	// Restricted runs commands with a restricted shell (bash -r), which
+	// cannot change directory, set PATH or run commands by path
+	Restricted bool
+}
+
+// Result is the outcome of a command
+type Result struct {
+	// Output is the combined stdout and stderr of the command
+	Output   string
+	Duration time.Duration
+}
+
+// TimeoutError reports a command stopped by the watchdog
+type TimeoutError struct {
+	Command string
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("'%s' did not finish within %s and was stopped", e.Command, e.Timeout)
+}
+
+// Run runs command with the shell in dir, adding extraEnv (KEY=VALUE) to
+// its environment. The result holds the output even when the command
+// fails.
+func (r *Runner) Run(dir, command string, extraEnv ...string) (*Result, error) {
+	ctx := context.Background()
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	cmd, err := r.command(ctx, command)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Dir = dir
+	cmd.Env = append(r.environment(os.Environ()), extraEnv...)
+
+	// Hooks run unattended: a command waiting for input fails instead of
+	// waiting forever
+	cmd.Stdin = nil
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	cmd.WaitDelay = waitDelay
+	setProcessGroup(cmd)
+
+	start := time.Now()
+	err = cmd.Run()
+	result := &Result{Output: output.String(), Duration: time.Since(start)}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return result, &TimeoutError{Command: command, Timeout: r.Timeout}
+	}
+	if err != nil {
+		return result, fmt.Errorf("'%s' failed: %w", command, err)
+	}
+	return result, nil
+}
+
+// command returns the shell command running command
+func (r *Runner) command(ctx context.Context, command string) (*exec.Cmd, error) {
+	if r.Restricted {
+		if runtime.GOOS == "windows" {
+			return nil, fmt.Errorf("restricted hooks are not supported on Windows")
+		}
+		bash, err := exec.LookPath("bash")
+		if err != nil {
+			return nil, fmt.Errorf("restricted hooks need bash: %w", err)
+		}
+		return exec.CommandContext(ctx, bash, "-r", "-c", command), nil
+	}
+
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command), nil
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command), nil
+}
+
+// environment filters environ (KEY=VALUE) down to the allowlist
+func (r *Runner) environment(environ []string) []string {
+	if len(r.Env) == 0 {
+		return environ
+	}
+
+	filtered := []string{}
+	for _, entry := range environ {
+		name, _, _ := strings.Cut(entry, "=")
+		if Allowed(name, append(r.Env, alwaysAllowed...)) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// Allowed reports whether the variable name matches an allowlist entry: the
+// name itself, or a prefix followed by *
+func Allowed(name string, allowlist []string) bool {
+	for _, allowed := range allowlist {
+		if prefix, found := strings.CutSuffix(allowed, "*"); found {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if name == allowed {
+			return true
+		}
+	}
+	return false
+}
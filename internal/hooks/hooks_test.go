@@ -0,0 +1,102 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package hooks_test
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mochajutsu/mkcd/internal/hooks"
+	"github.com/mochajutsu/mkcd/internal/testsupport"
+)
+
+// skipOnWindows skips tests written for sh
+func skipOnWindows(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("hook tests use sh")
+	}
+}
+
+// TestRunWritesInDirectory runs a hook creating files in the project
+func TestRunWritesInDirectory(t *testing.T) {
+	skipOnWindows(t)
+	ws := testsupport.NewWorkspace(t)
+
+	result, err := (&hooks.Runner{}).Run(ws.Root, `mkdir build && echo "$NAME" > build/name.txt && echo done`, "NAME=api")
+	if err != nil {
+		t.Fatalf("hook failed: %v", err)
+	}
+	if strings.TrimSpace(result.Output) != "done" {
+		t.Errorf("expected output 'done', got %q", result.Output)
+	}
+	ws.Tree(".").
+		Entries("build/", "build/name.txt").
+		FileEquals("build/name.txt", "api\n")
+}
+
+// TestRunFailureKeepsOutput checks that failing hooks report their output
+func TestRunFailureKeepsOutput(t *testing.T) {
+	skipOnWindows(t)
+	ws := testsupport.NewWorkspace(t)
+
+	result, err := (&hooks.Runner{}).Run(ws.Root, "echo broken >&2; exit 3")
+	if err == nil {
+		t.Fatal("expected the hook to fail")
+	}
+	if result == nil || strings.TrimSpace(result.Output) != "broken" {
+		t.Errorf("expected the output of the failed hook, got %+v", result)
+	}
+}
+
+// TestRunTimeout stops a hook running past its timeout
+func TestRunTimeout(t *testing.T) {
+	skipOnWindows(t)
+	ws := testsupport.NewWorkspace(t)
+
+	_, err := (&hooks.Runner{Timeout: 100 * time.Millisecond}).Run(ws.Root, "sleep 5; touch late")
+	var timeoutErr *hooks.TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+	ws.Tree(".").Empty()
+}
+
+// TestRunEnvironmentAllowlist passes only the allowed variables
+func TestRunEnvironmentAllowlist(t *testing.T) {
+	skipOnWindows(t)
+	ws := testsupport.NewWorkspace(t)
+	t.Setenv("MKCD_TEST_ALLOWED", "yes")
+	t.Setenv("MKCD_TEST_SECRET", "no")
+
+	runner := &hooks.Runner{Env: []string{"MKCD_TEST_A*"}}
+	if _, err := runner.Run(ws.Root, `echo "${MKCD_TEST_ALLOWED}:${MKCD_TEST_SECRET}" > env.txt`); err != nil {
+		t.Fatalf("hook failed: %v", err)
+	}
+	ws.Tree(".").FileEquals("env.txt", "yes:\n")
+}
+
+func TestAllowed(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		{"LANG", true},
+		{"LC_ALL", true},
+		{"LC", false},
+		{"GITHUB_TOKEN", false},
+	}
+
+	for _, tt := range tests {
+		if got := hooks.Allowed(tt.name, []string{"LANG", "LC_*"}); got != tt.expected {
+			t.Errorf("Allowed(%q) = %v, expected %v", tt.name, got, tt.expected)
+		}
+	}
+}
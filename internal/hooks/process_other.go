@@ -0,0 +1,15 @@
+//go:build !unix
+
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package hooks
+
+import "os/exec"
+
+// setProcessGroup keeps the default: a timeout stops the shell, and
+// WaitDelay bounds the wait for the processes it started
+func setProcessGroup(cmd *exec.Cmd) {}
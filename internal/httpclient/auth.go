@@ -0,0 +1,93 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package httpclient
+
+import (
+	"net/http"
+	"os"
+)
+
+// Authenticator adds credentials to outgoing requests
+type Authenticator interface {
+	Apply(req *http.Request)
+}
+
+// BearerToken authenticates with an "Authorization: Bearer" header
+type BearerToken string
+
+// Apply sets the Authorization header
+func (t BearerToken) Apply(req *http.Request) {
+	req.Header.Set("Authorization", "Bearer "+string(t))
+}
+
+// HeaderToken authenticates with a token in a custom header
+type HeaderToken struct {
+	Header string
+	Token  string
+}
+
+// Apply sets the token header
+func (t HeaderToken) Apply(req *http.Request) {
+	req.Header.Set(t.Header, t.Token)
+}
+
+// BasicAuth authenticates with HTTP basic authentication
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// Apply sets basic authentication
+func (a BasicAuth) Apply(req *http.Request) {
+	req.SetBasicAuth(a.Username, a.Password)
+}
+
+// Provider describes a hosting provider and where its credentials come from
+type Provider struct {
+	Name    string
+	Hosts   []string // API and content hosts that receive the credentials
+	EnvVars []string // environment variables checked in order for a token
+	Header  string   // token header; empty means "Authorization: Bearer"
+}
+
+// AuthFromEnv returns the provider's authenticator from the first set
+// environment variable, or nil when no token is configured
+func (p Provider) AuthFromEnv() Authenticator {
+	for _, name := range p.EnvVars {
+		token := os.Getenv(name)
+		if token == "" {
+			continue
+		}
+		if p.Header != "" {
+			return HeaderToken{Header: p.Header, Token: token}
+		}
+		return BearerToken(token)
+	}
+	return nil
+}
+
+// DefaultProviders returns the hosting providers known to mkcd
+func DefaultProviders() []Provider {
+	return []Provider{
+		{
+			Name:    "github",
+			Hosts:   []string{"api.github.com", "raw.githubusercontent.com", "codeload.github.com"},
+			EnvVars: []string{"MKCD_GITHUB_TOKEN", "GITHUB_TOKEN", "GH_TOKEN"},
+		},
+		{
+			Name:    "gitlab",
+			Hosts:   []string{"gitlab.com"},
+			EnvVars: []string{"MKCD_GITLAB_TOKEN", "GITLAB_TOKEN"},
+			Header:  "PRIVATE-TOKEN",
+		},
+		{
+			Name:    "bitbucket",
+			Hosts:   []string{"api.bitbucket.org"},
+			EnvVars: []string{"MKCD_BITBUCKET_TOKEN", "BITBUCKET_TOKEN"},
+		},
+	}
+}
@@ -0,0 +1,352 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+// Package httpclient provides the HTTP client shared by all hosting and
+// template integrations. It retries transient failures with exponential
+// backoff, honours rate limits (including GitHub's secondary limits),
+// respects context cancellation and injects per-provider authentication.
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default client settings
+const (
+	DefaultMaxRetries = 4
+	DefaultBaseDelay  = 500 * time.Millisecond
+	DefaultMaxDelay   = 30 * time.Second
+	DefaultMaxWait    = 2 * time.Minute
+	DefaultTimeout    = 30 * time.Second
+	DefaultUserAgent  = "mkcd"
+)
+
+// RateLimitError is returned when a provider's rate limit would require
+// waiting longer than the client's MaxWait
+type RateLimitError struct {
+	Host  string
+	Reset time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limit exceeded for %s, resets at %s", e.Host, e.Reset.Format(time.RFC3339))
+}
+
+// StatusError is returned by GetJSON for unsuccessful responses
+type StatusError struct {
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("request to %s failed with status %d: %s", e.URL, e.StatusCode, e.Body)
+}
+
+// Client is an HTTP client with retries, rate-limit handling and auth injection
+type Client struct {
+	HTTPClient *http.Client
+	UserAgent  string
+	MaxRetries int           // retries after the first attempt
+	BaseDelay  time.Duration // first backoff delay, doubled per retry
+	MaxDelay   time.Duration // upper bound for a single backoff delay
+	MaxWait    time.Duration // longest rate-limit wait before giving up
+
+	// MinInterval spaces consecutive requests to the same host
+	MinInterval time.Duration
+
+	mu       sync.Mutex
+	auth     map[string]Authenticator
+	lastSent map[string]time.Time
+	sleep    func(ctx context.Context, d time.Duration) error
+}
+
+// NewClient creates a new Client with default settings and the
+// authentication of the known providers taken from the environment
+func NewClient() *Client {
+	client := &Client{
+		HTTPClient: &http.Client{Timeout: DefaultTimeout},
+		UserAgent:  DefaultUserAgent,
+		MaxRetries: DefaultMaxRetries,
+		BaseDelay:  DefaultBaseDelay,
+		MaxDelay:   DefaultMaxDelay,
+		MaxWait:    DefaultMaxWait,
+		auth:       make(map[string]Authenticator),
+		lastSent:   make(map[string]time.Time),
+		sleep:      sleepContext,
+	}
+
+	for _, provider := range DefaultProviders() {
+		if auth := provider.AuthFromEnv(); auth != nil {
+			for _, host := range provider.Hosts {
+				client.SetAuth(host, auth)
+			}
+		}
+	}
+
+	return client
+}
+
+// SetAuth sets the authenticator used for requests to host (nil removes it)
+func (c *Client) SetAuth(host string, auth Authenticator) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	host = strings.ToLower(host)
+	if auth == nil {
+		delete(c.auth, host)
+		return
+	}
+	c.auth[host] = auth
+}
+
+// Get performs a GET request
+func (c *Client) Get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	return c.Do(req)
+}
+
+// GetJSON performs a GET request and decodes a successful JSON response into v
+func (c *Client) GetJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &StatusError{URL: url, StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response from %s: %w", url, err)
+	}
+	return nil
+}
+
+// Do sends a request, retrying transient failures and rate-limited
+// responses. The request's context bounds the whole operation, including
+// waits. Only idempotent requests are retried, so a POST is sent once
+// unless it carries an Idempotency-Key header; requests with a body must
+// also be replayable via GetBody.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	host := strings.ToLower(req.URL.Hostname())
+
+	c.prepare(req, host)
+
+	for attempt := 0; ; attempt++ {
+		if err := c.throttle(ctx, host); err != nil {
+			return nil, err
+		}
+
+		resp, err := c.HTTPClient.Do(req)
+
+		// Decide whether and how long to wait before retrying
+		var delay time.Duration
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			delay = c.backoff(attempt)
+		case isRateLimited(resp):
+			reset, known := rateLimitReset(resp)
+			if known {
+				delay = time.Until(reset)
+				if delay > c.MaxWait {
+					resp.Body.Close()
+					return nil, &RateLimitError{Host: host, Reset: reset}
+				}
+			} else {
+				delay = c.backoff(attempt)
+			}
+		case isRetryableStatus(resp.StatusCode):
+			delay = c.backoff(attempt)
+		default:
+			return resp, nil
+		}
+
+		if attempt >= c.MaxRetries || !retryable(req) {
+			if err != nil {
+				return nil, fmt.Errorf("request to %s failed after %d attempts: %w", req.URL, attempt+1, err)
+			}
+			return resp, nil
+		}
+
+		if resp != nil {
+			// Drain so the connection can be reused
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+			resp.Body.Close()
+		}
+
+		if delay < 0 {
+			delay = 0
+		}
+		if err := c.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to rewind request body: %w", err)
+			}
+			req.Body = body
+		}
+	}
+}
+
+// prepare sets the user agent and provider authentication on a request
+func (c *Client) prepare(req *http.Request, host string) {
+	if req.Header.Get("User-Agent") == "" && c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	c.mu.Lock()
+	auth := c.auth[host]
+	c.mu.Unlock()
+
+	if auth != nil && req.Header.Get("Authorization") == "" {
+		auth.Apply(req)
+	}
+}
+
+// throttle waits until MinInterval has passed since the last request to host
+func (c *Client) throttle(ctx context.Context, host string) error {
+	if c.MinInterval <= 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	wait := time.Until(c.lastSent[host].Add(c.MinInterval))
+	if wait < 0 {
+		wait = 0
+	}
+	c.lastSent[host] = time.Now().Add(wait)
+	c.mu.Unlock()
+
+	return c.sleep(ctx, wait)
+}
+
+// backoff returns the exponential backoff delay with jitter for an attempt
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.BaseDelay << uint(attempt)
+	if delay <= 0 || delay > c.MaxDelay {
+		delay = c.MaxDelay
+	}
+
+	// Full jitter in [delay/2, delay) spreads out concurrent retries
+	half := int64(delay / 2)
+	if half <= 0 {
+		return delay
+	}
+	return time.Duration(half + rand.Int63n(half))
+}
+
+// isRateLimited reports whether a response signals an exhausted rate limit.
+// GitHub answers primary and secondary limits with 403 or 429.
+func isRateLimited(resp *http.Response) bool {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if resp.StatusCode != http.StatusForbidden {
+		return false
+	}
+	return resp.Header.Get("Retry-After") != "" || resp.Header.Get("X-RateLimit-Remaining") == "0"
+}
+
+// rateLimitReset returns when a rate limit resets, from Retry-After or
+// X-RateLimit-Reset, and whether it could be determined
+func rateLimitReset(resp *http.Response) (time.Time, bool) {
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			return time.Now().Add(time.Duration(seconds) * time.Second), true
+		}
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			return date, true
+		}
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if epoch, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return time.Unix(epoch, 0), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// isRetryableStatus reports whether a status code indicates a transient failure
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryable reports whether a request can be sent again: whether sending
+// it twice has the effect of sending it once, and its body can be replayed
+func retryable(req *http.Request) bool {
+	if !idempotent(req) {
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// idempotent reports whether a request is idempotent by its method, or by
+// an idempotency key letting the server recognize a repeated request
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != "" || req.Header.Get("X-Idempotency-Key") != ""
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// IsRateLimit reports whether err is a RateLimitError
+func IsRateLimit(err error) bool {
+	var rateLimitErr *RateLimitError
+	return errors.As(err, &rateLimitErr)
+}
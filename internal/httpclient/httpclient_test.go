@@ -0,0 +1,72 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestDoRetriesOnlyIdempotentRequests checks that a request whose repetition
+// could have another effect, like posting a notification, is sent once
+func TestDoRetriesOnlyIdempotentRequests(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		key      string
+		expected int32
+	}{
+		{name: "get", method: http.MethodGet, expected: 3},
+		{name: "put", method: http.MethodPut, expected: 3},
+		{name: "delete", method: http.MethodDelete, expected: 3},
+		{name: "post", method: http.MethodPost, expected: 1},
+		{name: "patch", method: http.MethodPatch, expected: 1},
+		{name: "post with idempotency key", method: http.MethodPost, key: "Idempotency-Key", expected: 3},
+		{name: "post with legacy idempotency key", method: http.MethodPost, key: "X-Idempotency-Key", expected: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer server.Close()
+
+			client := NewClient()
+			client.MaxRetries = 2
+			client.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+
+			req, err := http.NewRequestWithContext(context.Background(), tt.method, server.URL, bytes.NewReader([]byte("{}")))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			if tt.key != "" {
+				req.Header.Set(tt.key, "3f2a9c")
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != http.StatusServiceUnavailable {
+				t.Errorf("expected status %d, got %d", http.StatusServiceUnavailable, resp.StatusCode)
+			}
+			if got := requests.Load(); got != tt.expected {
+				t.Errorf("expected %d request(s), got %d", tt.expected, got)
+			}
+		})
+	}
+}
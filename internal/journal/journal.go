@@ -0,0 +1,94 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+// Package journal lays out dated journal directories, e.g.
+// ~/notes/2025/06/10, and renders their daily notes.
+package journal
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultNote is the daily note template used when none is configured
+const DefaultNote = "# {{.Title}}\n\n"
+
+// NoteData is available to daily note templates
+type NoteData struct {
+	Date    string
+	Title   string
+	Weekday string
+	Year    int
+	Month   string
+	Day     int
+	Path    string
+}
+
+// ParseDate returns the day value names relative to now: empty or
+// "today", "yesterday", "tomorrow" or a YYYY-MM-DD date
+func ParseDate(value string, now time.Time) (time.Time, error) {
+	switch value {
+	case "", "today":
+		return now, nil
+	case "yesterday":
+		return now.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return now.AddDate(0, 0, 1), nil
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", value, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date '%s' (expected YYYY-MM-DD, today, yesterday or tomorrow)", value)
+	}
+	return date, nil
+}
+
+// Dir returns the directory of date below root, laid out with the Go time
+// layout
+func Dir(root, layout string, date time.Time) string {
+	return filepath.Join(root, filepath.FromSlash(date.Format(layout)))
+}
+
+// Day returns the YYYY-MM-DD day of a dated directory below root
+func Day(root, layout, dir string) (string, error) {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("%s is not in %s", dir, root)
+	}
+	date, err := time.Parse(layout, filepath.ToSlash(rel))
+	if err != nil {
+		return "", err
+	}
+	return date.Format("2006-01-02"), nil
+}
+
+// RenderNote renders the daily note template text for date and its
+// directory
+func RenderNote(text string, date time.Time, dir string) (string, error) {
+	tmpl, err := template.New("note").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid journal note template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, NoteData{
+		Date:    date.Format("2006-01-02"),
+		Title:   date.Format("Monday, January 2, 2006"),
+		Weekday: date.Weekday().String(),
+		Year:    date.Year(),
+		Month:   date.Month().String(),
+		Day:     date.Day(),
+		Path:    dir,
+	})
+	if err != nil {
+		return "", fmt.Errorf("invalid journal note template: %w", err)
+	}
+	return buf.String(), nil
+}
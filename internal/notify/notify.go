@@ -0,0 +1,180 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+// Package notify reports finished provisioning runs, such as blueprints and
+// batch creations, to a webhook (Slack, Microsoft Teams or any HTTP
+// endpoint) or by email, so unattended runs need not be watched.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/mochajutsu/mkcd/internal/httpclient"
+)
+
+// Webhook payload formats
+const (
+	FormatJSON  = "json"
+	FormatSlack = "slack"
+	FormatTeams = "teams"
+)
+
+// PasswordEnv holds the SMTP password, which is never kept in the configuration
+const PasswordEnv = "MKCD_SMTP_PASSWORD"
+
+// GetAvailableFormats returns the supported webhook formats
+func GetAvailableFormats() []string {
+	return []string{FormatJSON, FormatSlack, FormatTeams}
+}
+
+// Item is the result of one part of a run: a step or a directory
+type Item struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Summary describes a finished run. It is the payload of json webhooks.
+type Summary struct {
+	// Run names what ran, e.g. "blueprint shop"
+	Run        string    `json:"run"`
+	Host       string    `json:"host"`
+	Succeeded  bool      `json:"succeeded"`
+	Error      string    `json:"error,omitempty"`
+	Items      []Item    `json:"items"`
+	Started    time.Time `json:"started"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+// Title returns a one-line description of the run
+func (s Summary) Title() string {
+	result := "succeeded"
+	if !s.Succeeded {
+		result = "failed"
+	}
+	duration := (time.Duration(s.DurationMs) * time.Millisecond).Round(time.Second)
+	return fmt.Sprintf("mkcd: %s %s on %s after %s", s.Run, result, s.Host, duration)
+}
+
+// Text returns the title followed by a line per item and the error
+func (s Summary) Text() string {
+	var sb strings.Builder
+	sb.WriteString(s.Title())
+	sb.WriteByte('\n')
+	for _, item := range s.Items {
+		fmt.Fprintf(&sb, "\n%s: %s", item.Status, item.Name)
+		if item.Reason != "" {
+			fmt.Fprintf(&sb, " (%s)", item.Reason)
+		}
+	}
+	if s.Error != "" {
+		fmt.Fprintf(&sb, "\n\nError: %s", s.Error)
+	}
+	return sb.String()
+}
+
+// Payload returns the webhook body of the summary in format
+func Payload(format string, s Summary) ([]byte, error) {
+	switch format {
+	case "", FormatJSON:
+		return json.Marshal(s)
+	case FormatSlack:
+		return json.Marshal(map[string]string{"text": s.Text()})
+	case FormatTeams:
+		// Teams renders markdown, where single newlines are not breaks
+		body := strings.TrimPrefix(s.Text(), s.Title()+"\n")
+		color := "2EB67D"
+		if !s.Succeeded {
+			color = "E01E5A"
+		}
+		return json.Marshal(map[string]string{
+			"@type":      "MessageCard",
+			"@context":   "https://schema.org/extensions",
+			"summary":    s.Title(),
+			"title":      s.Title(),
+			"text":       strings.ReplaceAll(strings.TrimSpace(body), "\n", "\n\n"),
+			"themeColor": color,
+		})
+	default:
+		return nil, fmt.Errorf("unknown webhook format '%s' (expected %s)", format, strings.Join(GetAvailableFormats(), ", "))
+	}
+}
+
+// PostWebhook posts the summary to url in format. It is sent once, as the
+// client does not retry a POST that could arrive twice.
+func PostWebhook(ctx context.Context, client *httpclient.Client, url, format string, s Summary) error {
+	payload, err := Payload(format, s)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &httpclient.StatusError{URL: url, StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+	}
+	return nil
+}
+
+// Mail is an SMTP server and the addresses of notification emails
+type Mail struct {
+	// Host is host:port of the SMTP server
+	Host     string
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// Send emails the summary as plain text. Authentication is only used
+// when a username is set; net/smtp refuses it without TLS except to
+// localhost.
+func (m Mail) Send(s Summary, now time.Time) error {
+	var auth smtp.Auth
+	if m.Username != "" {
+		host, _, err := net.SplitHostPort(m.Host)
+		if err != nil {
+			return fmt.Errorf("invalid SMTP host '%s': %w", m.Host, err)
+		}
+		auth = smtp.PlainAuth("", m.Username, m.Password, host)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", m.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(m.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", s.Title()))
+	fmt.Fprintf(&msg, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(s.Text(), "\n", "\r\n"))
+	msg.WriteString("\r\n")
+
+	if err := smtp.SendMail(m.Host, auth, m.From, m.To, msg.Bytes()); err != nil {
+		return fmt.Errorf("failed to send notification email: %w", err)
+	}
+	return nil
+}
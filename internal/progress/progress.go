@@ -0,0 +1,166 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+// Package progress emits machine-readable progress events as
+// newline-delimited JSON, so GUI wrappers and editor plugins can show
+// their own progress for long steps such as clones and template installs.
+package progress
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mochajutsu/mkcd/internal/utils"
+)
+
+// Event kinds
+const (
+	EventStarted  = "started"
+	EventProgress = "progress"
+	EventFinished = "finished"
+)
+
+// Event is a single progress line. Started events have percent 0 and
+// finished events 100, also when the step failed.
+type Event struct {
+	Event string `json:"event"`
+	Step  string `json:"step"`
+
+	// Target is what the step works on: a created directory or a template
+	Target  string `json:"target,omitempty"`
+	Percent int    `json:"percent"`
+	Message string `json:"message,omitempty"`
+
+	// Error is set on finished events of failed steps
+	Error string `json:"error,omitempty"`
+
+	// DurationMs is set on finished events
+	DurationMs int64  `json:"duration_ms,omitempty"`
+	Time       string `json:"time"`
+}
+
+// Emitter writes events to a writer, one JSON object per line. A nil
+// Emitter emits nothing, so callers need not check whether progress is
+// reported. Write errors are ignored: a frontend that stops reading must
+// not fail the operation.
+type Emitter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	started map[string]time.Time
+	percent map[string]int
+}
+
+// NewEmitter returns an emitter writing to w
+func NewEmitter(w io.Writer) *Emitter {
+	return &Emitter{
+		w:       w,
+		started: make(map[string]time.Time),
+		percent: make(map[string]int),
+	}
+}
+
+// Start reports that step started on target
+func (e *Emitter) Start(step, target, message string) {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	key := step + "\x00" + target
+	e.started[key] = time.Now()
+	e.percent[key] = 0
+	e.emit(Event{Event: EventStarted, Step: step, Target: target, Message: message})
+}
+
+// Progress reports how far step got on target. Percentages are clamped to
+// 0-100 and only reported when they change.
+func (e *Emitter) Progress(step, target string, percent int, message string) {
+	if e == nil {
+		return
+	}
+
+	percent = min(max(percent, 0), 100)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	key := step + "\x00" + target
+	if last, exists := e.percent[key]; exists && last == percent {
+		return
+	}
+	e.percent[key] = percent
+	e.emit(Event{Event: EventProgress, Step: step, Target: target, Percent: percent, Message: message})
+}
+
+// Finish reports that step finished on target, failed when err is not nil
+func (e *Emitter) Finish(step, target string, err error) {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	key := step + "\x00" + target
+	event := Event{Event: EventFinished, Step: step, Target: target, Percent: 100}
+	if start, exists := e.started[key]; exists {
+		event.DurationMs = utils.Since(start).Milliseconds()
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	delete(e.started, key)
+	delete(e.percent, key)
+	e.emit(event)
+}
+
+// emit writes a single event; e.mu is held
+func (e *Emitter) emit(event Event) {
+	event.Time = utils.Now().UTC().Format(time.RFC3339Nano)
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	_, _ = e.w.Write(append(data, '\n'))
+}
+
+// gitProgress matches the phases of git progress output, such as
+// "Receiving objects:  45% (450/1000)"
+var gitProgress = regexp.MustCompile(`([A-Za-z][A-Za-z ]*):\s+(\d{1,3})%`)
+
+// Writer returns a writer for the progress output of git, which reports
+// the percentages of its phases as progress of step on target. Git
+// separates updates with carriage returns.
+func (e *Emitter) Writer(step, target string) io.Writer {
+	return &gitWriter{emitter: e, step: step, target: target}
+}
+
+// gitWriter turns git progress output into events
+type gitWriter struct {
+	emitter      *Emitter
+	step, target string
+	pending      string
+}
+
+func (w *gitWriter) Write(p []byte) (int, error) {
+	w.pending += string(p)
+	for {
+		i := strings.IndexAny(w.pending, "\r\n")
+		if i < 0 {
+			return len(p), nil
+		}
+		line := w.pending[:i]
+		w.pending = w.pending[i+1:]
+
+		if match := gitProgress.FindStringSubmatch(line); match != nil {
+			percent, _ := strconv.Atoi(match[2])
+			w.emitter.Progress(w.step, w.target, percent, strings.TrimSpace(match[1]))
+		}
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package project
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/mochajutsu/mkcd/internal/check"
+)
+
+// Conventions are those of an existing project that files generated inside
+// it follow unless told otherwise
+type Conventions struct {
+	// Root is the project the conventions were read from; empty when dir
+	// is not inside a project
+	Root string
+
+	// Language is a language of check.Languages, e.g. "go"
+	Language string
+
+	// License is a license generator option, e.g. "mit"
+	License string
+
+	// Indent is "tabs" or "spaces"
+	Indent string
+}
+
+// licenseMarkers identify license texts by a phrase, in order
+var licenseMarkers = []struct {
+	phrase  string
+	license string
+}{
+	{"Permission is hereby granted, free of charge", "mit"},
+	{"Apache License", "apache-2.0"},
+}
+
+// DetectConventions returns the conventions of the project containing dir:
+// the nearest directory, dir included, with a Git repository, a project
+// file or a language marker such as go.mod. The home directory and the
+// filesystem root are never projects.
+func DetectConventions(dir string) Conventions {
+	home, _ := homedir.Dir()
+
+	for dir = filepath.Clean(dir); ; dir = filepath.Dir(dir) {
+		if dir == home || dir == filepath.Dir(dir) {
+			return Conventions{}
+		}
+		if language, found := projectRoot(dir); found {
+			return Conventions{
+				Root:     dir,
+				Language: language,
+				License:  detectLicense(dir),
+				Indent:   detectIndent(dir, language),
+			}
+		}
+	}
+}
+
+// projectRoot reports whether dir is a project root and its language
+func projectRoot(dir string) (string, bool) {
+	for _, language := range check.Languages {
+		for _, marker := range language.Markers {
+			if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+				return language.Name, true
+			}
+		}
+	}
+	for _, marker := range []string{".git", FileName} {
+		if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+			return "", true
+		}
+	}
+	return "", false
+}
+
+// detectLicense returns the license generator option matching the license
+// file of dir, or "" for none or another license
+func detectLicense(dir string) string {
+	for _, name := range []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "LICENCE", "COPYING"} {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		for _, marker := range licenseMarkers {
+			if bytes.Contains(content, []byte(marker.phrase)) {
+				return marker.license
+			}
+		}
+		return ""
+	}
+	return ""
+}
+
+// detectIndent returns the indent style of the .editorconfig of dir, or
+// tabs for Go, whose formatter uses them
+func detectIndent(dir, language string) string {
+	if file, err := os.Open(filepath.Join(dir, ".editorconfig")); err == nil {
+		defer file.Close()
+
+		// The first indent_style applies to most files, usually [*]
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			key, value, found := strings.Cut(scanner.Text(), "=")
+			if !found || strings.TrimSpace(key) != "indent_style" {
+				continue
+			}
+			switch strings.ToLower(strings.TrimSpace(value)) {
+			case "tab":
+				return "tabs"
+			case "space":
+				return "spaces"
+			}
+		}
+	}
+
+	if language == "go" {
+		return "tabs"
+	}
+	return ""
+}
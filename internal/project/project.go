@@ -0,0 +1,114 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+// Package project manages the .mkcd.toml file that gives every directory
+// created by mkcd a stable identity across machines.
+package project
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/mochajutsu/mkcd/internal/utils"
+)
+
+// FileName is the name of the project file in a project's root
+const FileName = ".mkcd.toml"
+
+// uuidPattern matches a canonical UUID
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// Project is the content of a project file
+type Project struct {
+	ID      string    `toml:"id"`
+	Name    string    `toml:"name"`
+	Created time.Time `toml:"created"`
+	Profile string    `toml:"profile,omitempty"`
+
+	// Tags describe the project, e.g. its languages; 'registry scan'
+	// infers them for imported directories
+	Tags []string `toml:"tags,omitempty"`
+}
+
+// New creates a project with a fresh ID for the directory at path
+func New(path, profile string) *Project {
+	return &Project{
+		ID:      NewID(path),
+		Name:    filepath.Base(path),
+		Created: utils.Now().UTC().Truncate(time.Second),
+		Profile: profile,
+	}
+}
+
+// NewID returns a random (version 4) UUID. In deterministic mode the UUID is
+// derived from seed instead, so repeated runs produce identical output.
+func NewID(seed string) string {
+	var id [16]byte
+	if utils.IsDeterministic() {
+		sum := sha256.Sum256([]byte(seed))
+		copy(id[:], sum[:16])
+	} else if _, err := rand.Read(id[:]); err != nil {
+		// crypto/rand does not fail on supported platforms
+		panic(fmt.Sprintf("failed to generate project ID: %v", err))
+	}
+
+	id[6] = (id[6] & 0x0f) | 0x40 // version 4
+	id[8] = (id[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:16])
+}
+
+// ValidID reports whether id is a canonical UUID
+func ValidID(id string) bool {
+	return uuidPattern.MatchString(id)
+}
+
+// Path returns the location of the project file in dir
+func Path(dir string) string {
+	return filepath.Join(dir, FileName)
+}
+
+// Load reads the project file in dir
+func Load(dir string) (*Project, error) {
+	var p Project
+	if _, err := toml.DecodeFile(Path(dir), &p); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s is not an mkcd project (no %s)", dir, FileName)
+		}
+		return nil, fmt.Errorf("failed to parse %s: %w", Path(dir), err)
+	}
+
+	if !ValidID(p.ID) {
+		return nil, fmt.Errorf("%s has an invalid project id '%s'", Path(dir), p.ID)
+	}
+	return &p, nil
+}
+
+// Encode returns the TOML content of the project file
+func (p *Project) Encode() (string, error) {
+	var buf bytes.Buffer
+	buf.WriteString("# mkcd project file. The id identifies this project across machines.\n")
+	if err := toml.NewEncoder(&buf).Encode(p); err != nil {
+		return "", fmt.Errorf("failed to encode project file: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// Write writes the project file into dir
+func (p *Project) Write(fsOps *utils.FileSystemOperations, dir string) error {
+	content, err := p.Encode()
+	if err != nil {
+		return err
+	}
+	return fsOps.CreateFile(Path(dir), content, 0644)
+}
@@ -0,0 +1,167 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package project
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+
+	"github.com/mochajutsu/mkcd/internal/utils"
+)
+
+// Edit is a file rewritten to rename a project
+type Edit struct {
+	// Path is relative to the project
+	Path    string
+	Reason  string
+	Content []byte
+	Mode    os.FileMode
+}
+
+// nameRule rewrites the field of a well-known file holding the project name
+type nameRule struct {
+	file   string
+	reason string
+
+	// pattern matches the name field; its groups are the text before the
+	// name, the name and the text after it
+	pattern func(name string) string
+}
+
+// nameRules are the fields updated in files that may have been edited since
+// they were generated. Only the first match of each is changed.
+var nameRules = []nameRule{
+	{"README.md", "title", func(name string) string {
+		return `(?m)^(#[ \t]+)(` + regexp.QuoteMeta(name) + `)([ \t]*)$`
+	}},
+	{"go.mod", "module path", func(name string) string {
+		return `(?m)^(module[ \t]+(?:\S*/)?)(` + regexp.QuoteMeta(name) + `)([ \t]*)$`
+	}},
+	{"package.json", "package name", func(name string) string {
+		return `(?m)^([ \t]*"name"[ \t]*:[ \t]*"(?:@[^/"]+/)?)(` + regexp.QuoteMeta(name) + `)(")`
+	}},
+	{"pyproject.toml", "project name", func(name string) string {
+		return `(?m)^(name[ \t]*=[ \t]*")(` + regexp.QuoteMeta(name) + `)(")`
+	}},
+	{"Cargo.toml", "package name", func(name string) string {
+		return `(?m)^(name[ \t]*=[ \t]*")(` + regexp.QuoteMeta(name) + `)(")`
+	}},
+}
+
+// RenameEdits returns the edits renaming the project in dir from oldName
+// to newName. The files in generated, which mkcd wrote and nobody changed
+// since, have every occurrence of the name as a word replaced; in the other
+// files only the name fields of README.md, go.mod, package.json,
+// pyproject.toml and Cargo.toml change. The project file is left to the
+// caller.
+func RenameEdits(dir, oldName, newName string, generated []string) ([]Edit, error) {
+	edits := map[string]Edit{}
+
+	for _, rel := range generated {
+		if rel == FileName {
+			continue
+		}
+		content, info, err := readProjectFile(dir, rel)
+		if err != nil || content == nil || utils.IsBinary(content) {
+			continue
+		}
+		replaced := replaceWord(content, oldName, newName)
+		if string(replaced) != string(content) {
+			edits[rel] = Edit{Path: rel, Reason: "generated file", Content: replaced, Mode: info.Mode().Perm()}
+		}
+	}
+
+	for _, rule := range nameRules {
+		if _, done := edits[rule.file]; done || slices.Contains(generated, rule.file) {
+			continue
+		}
+		content, info, err := readProjectFile(dir, rule.file)
+		if err != nil {
+			return nil, err
+		}
+		if content == nil {
+			continue
+		}
+
+		re := regexp.MustCompile(rule.pattern(oldName))
+		loc := re.FindSubmatchIndex(content)
+		if loc == nil {
+			continue
+		}
+		replaced := make([]byte, 0, len(content)+len(newName))
+		replaced = append(replaced, content[:loc[4]]...)
+		replaced = append(replaced, newName...)
+		replaced = append(replaced, content[loc[5]:]...)
+		edits[rule.file] = Edit{Path: rule.file, Reason: rule.reason, Content: replaced, Mode: info.Mode().Perm()}
+	}
+
+	result := make([]Edit, 0, len(edits))
+	for _, edit := range edits {
+		result = append(result, edit)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	return result, nil
+}
+
+// replaceWord replaces oldName with newName where it is a whole word, not
+// part of a longer name such as "api-client" or "api_v2"
+func replaceWord(content []byte, oldName, newName string) []byte {
+	var b bytes.Buffer
+	start := 0
+	for {
+		i := bytes.Index(content[start:], []byte(oldName))
+		if i < 0 {
+			break
+		}
+		i += start
+		end := i + len(oldName)
+		b.Write(content[start:i])
+		if nameBoundary(content, i-1) && nameBoundary(content, end) {
+			b.WriteString(newName)
+		} else {
+			b.WriteString(oldName)
+		}
+		start = end
+	}
+	b.Write(content[start:])
+	return b.Bytes()
+}
+
+// nameBoundary reports whether the byte at i cannot continue a name
+func nameBoundary(content []byte, i int) bool {
+	if i < 0 || i >= len(content) {
+		return true
+	}
+	c := content[i]
+	return !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-')
+}
+
+// readProjectFile reads the regular file rel of the project in dir; a
+// missing file has nil content
+func readProjectFile(dir, rel string) ([]byte, os.FileInfo, error) {
+	path := filepath.Join(dir, filepath.FromSlash(rel))
+	info, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, nil, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", rel, err)
+	}
+	return content, info, nil
+}
@@ -0,0 +1,248 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mochajutsu/mkcd/internal/history"
+	"github.com/mochajutsu/mkcd/internal/utils"
+)
+
+// FrozenModesVersion is the format of the frozen modes written by Save
+const FrozenModesVersion = 1
+
+// FrozenModes are the permissions the files of a frozen project had before
+// it was frozen, kept on this machine to restore them when it is unfrozen
+type FrozenModes struct {
+	Version int    `json:"version"`
+	Path    string `json:"path"`
+
+	// Modes are keyed by slash-separated paths relative to Path, "." for
+	// Path itself
+	Modes map[string]fs.FileMode `json:"modes"`
+
+	// Immutable records that the immutable attribute was set as well
+	Immutable bool `json:"immutable,omitempty"`
+}
+
+// Freeze marks the project id as frozen on host, returning whether it was
+// registered there and not frozen yet
+func (r *Registry) Freeze(id, host string) bool {
+	entry, exists := r.Projects[id]
+	if !exists || entry.Paths[host] == "" {
+		return false
+	}
+	if _, frozen := entry.Frozen[host]; frozen {
+		return false
+	}
+
+	now := time.Now().UTC()
+	frozen := map[string]time.Time{}
+	for h, at := range entry.Frozen {
+		frozen[h] = at
+	}
+	frozen[host] = now
+
+	entry.Frozen = frozen
+	entry.Updated = now
+	r.Projects[id] = entry
+	return true
+}
+
+// Unfreeze removes the frozen mark of the project id on host, returning
+// whether it was frozen there
+func (r *Registry) Unfreeze(id, host string) bool {
+	entry, exists := r.Projects[id]
+	if !exists {
+		return false
+	}
+	if _, frozen := entry.Frozen[host]; !frozen {
+		return false
+	}
+
+	frozen := map[string]time.Time{}
+	for h, at := range entry.Frozen {
+		if h != host {
+			frozen[h] = at
+		}
+	}
+	if len(frozen) == 0 {
+		frozen = nil
+	}
+
+	entry.Frozen = frozen
+	entry.Updated = time.Now().UTC()
+	r.Projects[id] = entry
+	return true
+}
+
+// FrozenContaining returns the project frozen on host whose path is path
+// or one of its parent directories, with that path, or nil
+func (r *Registry) FrozenContaining(path, host string) (*Entry, string) {
+	for _, entry := range r.Entries() {
+		root, exists := entry.Paths[host]
+		if _, frozen := entry.Frozen[host]; !exists || !frozen {
+			continue
+		}
+		if rel, err := filepath.Rel(root, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return &entry, root
+		}
+	}
+	return nil, ""
+}
+
+// FrozenModesPath returns where the permissions of the frozen project id
+// are kept inside the state directory
+func FrozenModesPath(id string) (string, error) {
+	stateDir, err := history.StateDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(stateDir, "frozen", id+".json"), nil
+}
+
+// LoadFrozenModes reads the permissions kept for the frozen project id,
+// or returns nil when none are kept
+func LoadFrozenModes(id string) (*FrozenModes, error) {
+	path, err := FrozenModesPath(id)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read frozen permissions: %w", err)
+	}
+
+	var modes FrozenModes
+	if err := json.Unmarshal(data, &modes); err != nil {
+		return nil, fmt.Errorf("failed to parse frozen permissions %s: %w", path, err)
+	}
+	if modes.Version > FrozenModesVersion {
+		return nil, fmt.Errorf("frozen permissions format %d is newer than supported format %d", modes.Version, FrozenModesVersion)
+	}
+	return &modes, nil
+}
+
+// Save keeps the permissions of the frozen project id
+func (m *FrozenModes) Save(id string) error {
+	path, err := FrozenModesPath(id)
+	if err != nil {
+		return err
+	}
+
+	m.Version = FrozenModesVersion
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode frozen permissions: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create frozen permissions directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write frozen permissions %s: %w", path, err)
+	}
+	return nil
+}
+
+// RemoveFrozenModes removes the permissions kept for the project id
+func RemoveFrozenModes(id string) error {
+	path, err := FrozenModesPath(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove frozen permissions: %w", err)
+	}
+	return nil
+}
+
+// MakeReadOnly removes the write permissions of dir and everything in it
+// and returns the permissions they had. Symlinks are left alone. On
+// failure, the permissions changed so far are restored.
+func MakeReadOnly(dir string) (map[string]fs.FileMode, error) {
+	modes := map[string]fs.FileMode{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		mode := info.Mode().Perm()
+		if mode&0222 == 0 {
+			return nil
+		}
+		if err := os.Chmod(path, mode&^0222); err != nil {
+			return err
+		}
+		modes[filepath.ToSlash(rel)] = mode
+		return nil
+	})
+	if err != nil {
+		_ = RestoreModes(dir, modes)
+		return nil, fmt.Errorf("failed to make %s read-only: %w", dir, err)
+	}
+	return modes, nil
+}
+
+// RestoreModes gives the files below dir back the permissions returned by
+// MakeReadOnly. Files that no longer exist are skipped.
+func RestoreModes(dir string, modes map[string]fs.FileMode) error {
+	for _, rel := range utils.SortedKeys(modes) {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.Chmod(path, modes[rel]); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to restore the permissions of %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// SetImmutable sets or clears the immutable attribute of dir and
+// everything in it with chattr, which only exists on Linux and needs root
+// or CAP_LINUX_IMMUTABLE
+func SetImmutable(dir string, immutable bool) error {
+	chattr, err := exec.LookPath("chattr")
+	if err != nil {
+		return fmt.Errorf("chattr is not available")
+	}
+
+	flag := "-i"
+	if immutable {
+		flag = "+i"
+	}
+	if output, err := exec.Command(chattr, "-R", flag, dir).CombinedOutput(); err != nil {
+		if text := strings.TrimSpace(string(output)); text != "" {
+			return fmt.Errorf("%w: %s", err, text)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/mochajutsu/mkcd/internal/project"
+	"github.com/mochajutsu/mkcd/internal/utils"
+)
+
+// Reasons an entry's path on this machine is orphaned
+const (
+	ReasonMissing  = "missing"
+	ReasonTrashed  = "moved to trash"
+	ReasonInTrash  = "inside trash"
+	ReasonReplaced = "different project"
+)
+
+// Orphan is a registry entry whose path on this machine no longer holds
+// the project
+type Orphan struct {
+	Entry  Entry
+	Path   string
+	Reason string
+
+	// TrashPath is where the project was found in the trash, if anywhere
+	TrashPath string
+}
+
+// TrashDirs returns the directories desktop environments move deleted
+// files into: the freedesktop.org trash and the macOS trash
+func TrashDirs() []string {
+	dirs := []string{}
+
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	homeDir, err := homedir.Dir()
+	if dataHome == "" && err == nil {
+		dataHome = filepath.Join(homeDir, ".local", "share")
+	}
+	if dataHome != "" {
+		dirs = append(dirs, filepath.Join(dataHome, "Trash", "files"))
+	}
+	if err == nil {
+		dirs = append(dirs, filepath.Join(homeDir, ".Trash"))
+	}
+	return dirs
+}
+
+// Orphans returns the entries whose path on host is gone, lies inside one
+// of trashDirs, or now holds a different project
+func (r *Registry) Orphans(host string, trashDirs []string) []Orphan {
+	var trashed map[string]string
+
+	orphans := []Orphan{}
+	for _, entry := range r.Entries() {
+		path, exists := entry.Paths[host]
+		if !exists {
+			continue
+		}
+
+		if inTrash(path, trashDirs) {
+			orphans = append(orphans, Orphan{Entry: entry, Path: path, Reason: ReasonInTrash, TrashPath: path})
+			continue
+		}
+
+		if !utils.IsDirectory(path) {
+			// Only scan the trash once, and only when something is missing
+			if trashed == nil {
+				trashed = trashedProjects(trashDirs)
+			}
+			orphan := Orphan{Entry: entry, Path: path, Reason: ReasonMissing}
+			if trashPath, found := trashed[entry.ID]; found {
+				orphan.Reason = ReasonTrashed
+				orphan.TrashPath = trashPath
+			}
+			orphans = append(orphans, orphan)
+			continue
+		}
+
+		if p, err := project.Load(path); err != nil || p.ID != entry.ID {
+			orphans = append(orphans, Orphan{Entry: entry, Path: path, Reason: ReasonReplaced})
+		}
+	}
+	return orphans
+}
+
+// Forget removes the path of a project on host. The removal is recorded so
+// that syncing with a registry that still has the path does not restore it.
+func (r *Registry) Forget(id, host string) bool {
+	entry, exists := r.Projects[id]
+	if !exists {
+		return false
+	}
+	if _, registered := entry.Paths[host]; !registered {
+		return false
+	}
+
+	now := time.Now().UTC()
+	paths := map[string]string{}
+	for h, path := range entry.Paths {
+		if h != host {
+			paths[h] = path
+		}
+	}
+	forgotten := map[string]time.Time{}
+	for h, at := range entry.Forgotten {
+		forgotten[h] = at
+	}
+	forgotten[host] = now
+
+	entry.Paths = paths
+	entry.Forgotten = forgotten
+	entry.Updated = now
+	r.Projects[id] = entry
+	return true
+}
+
+// inTrash reports whether path lies inside one of trashDirs
+func inTrash(path string, trashDirs []string) bool {
+	for _, dir := range trashDirs {
+		if rel, err := filepath.Rel(dir, path); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			return true
+		}
+	}
+	return false
+}
+
+// trashedProjects maps the IDs of projects found at the top level of the
+// trash directories to their location there
+func trashedProjects(trashDirs []string) map[string]string {
+	found := map[string]string{}
+	for _, dir := range trashDirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if p, err := project.Load(path); err == nil {
+				found[p.ID] = path
+			}
+		}
+	}
+	return found
+}
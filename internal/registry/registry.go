@@ -0,0 +1,288 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+// Package registry keeps track of mkcd projects by their project ID and
+// synchronises that registry between machines.
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mochajutsu/mkcd/internal/history"
+	"github.com/mochajutsu/mkcd/internal/project"
+)
+
+// FormatVersion is the registry format written by Save
+const FormatVersion = 1
+
+// FileName is the name of the registry file, locally and on sync remotes
+const FileName = "registry.json"
+
+// Entry is a registered project. A project can live at a different path on
+// every machine, so paths are keyed by host name.
+type Entry struct {
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	Paths   map[string]string `json:"paths"`
+	Tags    []string          `json:"tags,omitempty"`
+	Created time.Time         `json:"created"`
+	Updated time.Time         `json:"updated"`
+
+	// Forgotten records when the path on a host was removed (registry gc),
+	// so a sync with an older copy of the entry does not bring it back
+	Forgotten map[string]time.Time `json:"forgotten,omitempty"`
+
+	// Frozen records when the project was frozen on a host; mkcd refuses
+	// to change it there until it is unfrozen
+	Frozen map[string]time.Time `json:"frozen,omitempty"`
+}
+
+// Registry is the set of known projects keyed by project ID
+type Registry struct {
+	Version  int              `json:"version"`
+	Projects map[string]Entry `json:"projects"`
+}
+
+// New creates an empty registry
+func New() *Registry {
+	return &Registry{
+		Version:  FormatVersion,
+		Projects: map[string]Entry{},
+	}
+}
+
+// DefaultPath returns the registry location inside the state directory
+func DefaultPath() (string, error) {
+	stateDir, err := history.StateDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(stateDir, FileName), nil
+}
+
+// Hostname returns the name under which this machine's paths are recorded
+func Hostname() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		return "localhost"
+	}
+	return host
+}
+
+// Load reads the registry at path; a missing file is an empty registry
+func Load(path string) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return New(), nil
+		}
+		return nil, fmt.Errorf("failed to read registry: %w", err)
+	}
+	return Decode(data)
+}
+
+// Decode parses a registry
+func Decode(data []byte) (*Registry, error) {
+	reg := New()
+	if err := json.Unmarshal(data, reg); err != nil {
+		return nil, fmt.Errorf("failed to parse registry: %w", err)
+	}
+	if reg.Version > FormatVersion {
+		return nil, fmt.Errorf("registry format %d is newer than supported format %d", reg.Version, FormatVersion)
+	}
+	if reg.Projects == nil {
+		reg.Projects = map[string]Entry{}
+	}
+	return reg, nil
+}
+
+// Encode returns the registry as indented JSON
+func (r *Registry) Encode() ([]byte, error) {
+	r.Version = FormatVersion
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode registry: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// Save writes the registry to path
+func (r *Registry) Save(path string) error {
+	data, err := r.Encode()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create registry directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write registry %s: %w", path, err)
+	}
+	return nil
+}
+
+// Register records a project at path on host, returning whether the
+// registry changed
+func (r *Registry) Register(p *project.Project, path, host string) bool {
+	entry, exists := r.Projects[p.ID]
+	if exists && entry.Name == p.Name && entry.Paths[host] == path && strings.Join(entry.Tags, ",") == strings.Join(p.Tags, ",") {
+		return false
+	}
+
+	if !exists {
+		entry = Entry{
+			ID:      p.ID,
+			Created: p.Created,
+			Paths:   map[string]string{},
+		}
+	}
+	entry.Name = p.Name
+	entry.Tags = p.Tags
+	entry.Paths[host] = path
+	entry.Updated = time.Now().UTC()
+	if _, forgotten := entry.Forgotten[host]; forgotten {
+		delete(entry.Forgotten, host)
+	}
+
+	r.Projects[p.ID] = entry
+	return true
+}
+
+// Merge adds the projects of other to r. For projects known to both, paths
+// are combined and the more recently updated entry wins on conflicts.
+// It returns the number of entries that changed.
+func (r *Registry) Merge(other *Registry) int {
+	changed := 0
+	for id, theirs := range other.Projects {
+		ours, exists := r.Projects[id]
+		if !exists {
+			r.Projects[id] = theirs
+			changed++
+			continue
+		}
+
+		merged := ours
+		merged.Paths = map[string]string{}
+		newer, older := ours, theirs
+		if theirs.Updated.After(ours.Updated) {
+			newer, older = theirs, ours
+			merged.Name = theirs.Name
+			merged.Tags = theirs.Tags
+			merged.Updated = theirs.Updated
+		}
+		for host, path := range older.Paths {
+			merged.Paths[host] = path
+		}
+		for host, path := range newer.Paths {
+			merged.Paths[host] = path
+		}
+
+		// A path removed after the entry holding it was last updated stays removed
+		merged.Forgotten = nil
+		for _, side := range []Entry{older, newer} {
+			for host, at := range side.Forgotten {
+				if merged.Forgotten == nil {
+					merged.Forgotten = map[string]time.Time{}
+				}
+				if at.After(merged.Forgotten[host]) {
+					merged.Forgotten[host] = at
+				}
+			}
+		}
+		for host, at := range merged.Forgotten {
+			source := older
+			if _, exists := newer.Paths[host]; exists {
+				source = newer
+			}
+			if _, exists := merged.Paths[host]; exists && !source.Updated.After(at) {
+				delete(merged.Paths, host)
+			} else if exists {
+				delete(merged.Forgotten, host)
+			}
+		}
+		if len(merged.Forgotten) == 0 {
+			merged.Forgotten = nil
+		}
+		merged.Frozen = newer.Frozen
+		if theirs.Created.Before(ours.Created) && !theirs.Created.IsZero() {
+			merged.Created = theirs.Created
+		}
+
+		if !sameEntry(ours, merged) {
+			r.Projects[id] = merged
+			changed++
+		}
+	}
+	return changed
+}
+
+// Entries returns all entries sorted by name, then ID
+func (r *Registry) Entries() []Entry {
+	entries := make([]Entry, 0, len(r.Projects))
+	for _, entry := range r.Projects {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Name != entries[j].Name {
+			return entries[i].Name < entries[j].Name
+		}
+		return entries[i].ID < entries[j].ID
+	})
+	return entries
+}
+
+// Find returns the entry whose ID starts with query or whose name equals it
+func (r *Registry) Find(query string) (*Entry, error) {
+	matches := []Entry{}
+	for _, entry := range r.Entries() {
+		if entry.Name == query || (query != "" && strings.HasPrefix(entry.ID, query)) {
+			matches = append(matches, entry)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no registered project matches '%s'", query)
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, fmt.Errorf("'%s' matches %d projects, use a longer ID", query, len(matches))
+	}
+}
+
+// sameEntry reports whether two entries are identical
+func sameEntry(a, b Entry) bool {
+	if a.Name != b.Name || strings.Join(a.Tags, ",") != strings.Join(b.Tags, ",") || !a.Created.Equal(b.Created) || !a.Updated.Equal(b.Updated) || len(a.Paths) != len(b.Paths) {
+		return false
+	}
+	for host, path := range a.Paths {
+		if b.Paths[host] != path {
+			return false
+		}
+	}
+	return sameTimes(a.Forgotten, b.Forgotten) && sameTimes(a.Frozen, b.Frozen)
+}
+
+// sameTimes reports whether two maps of hosts to times are identical
+func sameTimes(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for host, at := range a {
+		if !b[host].Equal(at) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,106 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package registry
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mochajutsu/mkcd/internal/check"
+	"github.com/mochajutsu/mkcd/internal/project"
+)
+
+// TagGit marks projects that are git repositories
+const TagGit = "git"
+
+// Candidate is a project root found by Scan
+type Candidate struct {
+	Path string
+
+	// Tags are inferred from the directory: its languages and "git"
+	Tags []string
+
+	// Project is the existing project file, if the directory has one
+	Project *project.Project
+}
+
+// Scan walks root up to maxDepth levels deep and returns the directories
+// that look like project roots: git repositories, directories with a
+// language manifest (go.mod, package.json, ...) and mkcd projects. The
+// walk does not descend into project roots, hidden directories or
+// dependency directories.
+func Scan(root string, maxDepth int) ([]Candidate, error) {
+	root = filepath.Clean(root)
+	candidates := []Candidate{}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Unreadable directories are skipped, not fatal
+			if d != nil && d.IsDir() && path != root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+
+		name := d.Name()
+		if path != root && (strings.HasPrefix(name, ".") || name == "node_modules" || name == "vendor") {
+			return filepath.SkipDir
+		}
+
+		if candidate, found := detectProject(path); found {
+			candidates = append(candidates, candidate)
+			return filepath.SkipDir
+		}
+
+		rel, _ := filepath.Rel(root, path)
+		if rel != "." && strings.Count(rel, string(filepath.Separator))+1 >= maxDepth {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return candidates, nil
+}
+
+// detectProject reports whether dir is a project root and what it holds
+func detectProject(dir string) (Candidate, bool) {
+	candidate := Candidate{Path: dir}
+	found := false
+
+	if p, err := project.Load(dir); err == nil {
+		candidate.Project = p
+		found = true
+	}
+
+	tags := map[string]bool{}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		tags[TagGit] = true
+		found = true
+	}
+	for _, language := range check.Languages {
+		for _, marker := range language.Markers {
+			if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+				tags[language.Name] = true
+				found = true
+			}
+		}
+	}
+
+	for tag := range tags {
+		candidate.Tags = append(candidate.Tags, tag)
+	}
+	sort.Strings(candidate.Tags)
+	return candidate, found
+}
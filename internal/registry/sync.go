@@ -0,0 +1,301 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/mochajutsu/mkcd/internal/httpclient"
+)
+
+// Sync backend names
+const (
+	BackendGit    = "git"
+	BackendWebDAV = "webdav"
+)
+
+// PasswordEnv holds the password for authenticated WebDAV endpoints, so it
+// never has to be stored in the configuration file
+const PasswordEnv = "MKCD_SYNC_PASSWORD"
+
+// syncAttempts bounds how often Sync pulls and merges again when the remote
+// changed while it was merging
+const syncAttempts = 3
+
+// ErrRemoteChanged is returned by Push when the remote registry changed
+// since the last Pull, so pushing would discard another host's changes
+var ErrRemoteChanged = errors.New("remote registry changed since it was pulled")
+
+// GetAvailableBackends returns the supported sync backends
+func GetAvailableBackends() []string {
+	return []string{BackendGit, BackendWebDAV}
+}
+
+// Backend stores a copy of the registry on a remote
+type Backend interface {
+	// Pull fetches the remote registry, which is empty if none exists yet
+	Pull(ctx context.Context) (*Registry, error)
+	// Push uploads the registry to the remote, failing with
+	// ErrRemoteChanged when the remote moved on since the last Pull
+	Push(ctx context.Context, reg *Registry) error
+}
+
+// SyncResult reports the outcome of a sync
+type SyncResult struct {
+	Pulled int // local entries added or updated from the remote
+	Total  int // entries in the registry after the sync
+}
+
+// Sync merges the remote registry into local and pushes the result back.
+// When another host pushed in between, the remote is pulled and merged
+// again, so no host's changes are lost.
+func Sync(ctx context.Context, backend Backend, local *Registry) (*SyncResult, error) {
+	pulled := 0
+	for attempt := 1; ; attempt++ {
+		remote, err := backend.Pull(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pull registry: %w", err)
+		}
+
+		pulled += local.Merge(remote)
+
+		err = backend.Push(ctx, local)
+		if errors.Is(err, ErrRemoteChanged) && attempt < syncAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to push registry: %w", err)
+		}
+		return &SyncResult{Pulled: pulled, Total: len(local.Projects)}, nil
+	}
+}
+
+// NewBackend creates the sync backend of the given kind. The git backend
+// keeps its working copy in workDir.
+func NewBackend(kind, url, username, workDir string) (Backend, error) {
+	if url == "" {
+		return nil, fmt.Errorf("sync url is not configured")
+	}
+
+	switch kind {
+	case BackendGit:
+		return &GitBackend{URL: url, Dir: workDir}, nil
+	case BackendWebDAV:
+		client := httpclient.NewClient()
+		if username != "" {
+			req, err := http.NewRequest(http.MethodGet, url, nil)
+			if err != nil {
+				return nil, fmt.Errorf("invalid sync url %s: %w", url, err)
+			}
+			client.SetAuth(req.URL.Hostname(), httpclient.BasicAuth{Username: username, Password: os.Getenv(PasswordEnv)})
+		}
+		return &WebDAVBackend{URL: url, Client: client}, nil
+	case "":
+		return nil, fmt.Errorf("sync backend is not configured (expected %s)", strings.Join(GetAvailableBackends(), " or "))
+	default:
+		return nil, fmt.Errorf("unknown sync backend '%s' (expected %s)", kind, strings.Join(GetAvailableBackends(), " or "))
+	}
+}
+
+// GitBackend syncs the registry through a user-provided git repository. It
+// shells out to git so the user's credential helpers and SSH setup apply.
+//
+// The working copy only carries the registry between hosts: Pull resets it
+// to the remote branch, dropping a commit whose push was rejected, as the
+// local registry still holds its changes and Sync merges them again.
+type GitBackend struct {
+	URL string
+	Dir string // local working copy
+}
+
+// Pull clones or updates the working copy and reads its registry
+func (b *GitBackend) Pull(ctx context.Context) (*Registry, error) {
+	if _, err := os.Stat(filepath.Join(b.Dir, ".git")); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(b.Dir), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create sync directory: %w", err)
+		}
+		if _, err := runGit(ctx, "", "clone", "--quiet", b.URL, b.Dir); err != nil {
+			return nil, err
+		}
+	} else {
+		if _, err := runGit(ctx, b.Dir, "fetch", "--quiet", "--prune", "origin"); err != nil {
+			return nil, err
+		}
+		// A freshly created remote has no branch to reset to yet
+		upstream, err := b.upstream(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if upstream != "" {
+			if _, err := runGit(ctx, b.Dir, "reset", "--quiet", "--hard", upstream); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return Load(filepath.Join(b.Dir, FileName))
+}
+
+// upstream returns the remote-tracking branch of the working copy's branch,
+// or "" when the remote does not have it
+func (b *GitBackend) upstream(ctx context.Context) (string, error) {
+	branch, err := runGit(ctx, b.Dir, "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	upstream := "origin/" + branch
+	if _, err := runGit(ctx, b.Dir, "rev-parse", "--verify", "--quiet", "refs/remotes/"+upstream); err != nil {
+		return "", nil
+	}
+	return upstream, nil
+}
+
+// Push commits the registry to the working copy and pushes it
+func (b *GitBackend) Push(ctx context.Context, reg *Registry) error {
+	if err := reg.Save(filepath.Join(b.Dir, FileName)); err != nil {
+		return err
+	}
+
+	if _, err := runGit(ctx, b.Dir, "add", FileName); err != nil {
+		return err
+	}
+	status, err := runGit(ctx, b.Dir, "status", "--porcelain", "--", FileName)
+	if err != nil {
+		return err
+	}
+	if status == "" {
+		return nil
+	}
+
+	message := fmt.Sprintf("Update mkcd registry from %s", Hostname())
+	if _, err := runGit(ctx, b.Dir, "commit", "--quiet", "-m", message, "--", FileName); err != nil {
+		return err
+	}
+	_, err = runGit(ctx, b.Dir, "push", "--quiet", "--porcelain", "origin", "HEAD")
+	var gitErr *gitError
+	if errors.As(err, &gitErr) && strings.Contains(gitErr.output, "[rejected]") {
+		return fmt.Errorf("%w: %w", ErrRemoteChanged, err)
+	}
+	return err
+}
+
+// gitError reports a failed git command with what it printed
+type gitError struct {
+	args   []string
+	err    error
+	output string
+}
+
+func (e *gitError) Error() string {
+	return fmt.Sprintf("git %s failed: %v: %s", e.args[0], e.err, e.output)
+}
+
+func (e *gitError) Unwrap() error {
+	return e.err
+}
+
+// runGit runs a git command and returns its trimmed output
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		// Some commands, like push --porcelain, report on stdout
+		output := strings.TrimSpace(stderr.String() + "\n" + string(out))
+		return "", &gitError{args: args, err: err, output: output}
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// WebDAVBackend syncs the registry as a single file on a WebDAV endpoint.
+// Uploads are conditional on the ETag of the pulled file, so a file
+// replaced by another host in between is never overwritten.
+type WebDAVBackend struct {
+	URL    string // URL of the registry file
+	Client *httpclient.Client
+
+	// etag is the ETag of the last pulled file, "" when it did not exist
+	// or the server sent none
+	etag    string
+	pulled  bool
+	existed bool
+}
+
+// Pull downloads the registry file
+func (b *WebDAVBackend) Pull(ctx context.Context) (*Registry, error) {
+	resp, err := b.Client.Get(ctx, b.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		b.pulled, b.existed, b.etag = true, false, ""
+		return New(), nil
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", b.URL, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &httpclient.StatusError{URL: b.URL, StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(data))}
+	}
+
+	b.pulled, b.existed, b.etag = true, true, resp.Header.Get("ETag")
+	return Decode(data)
+}
+
+// Push uploads the registry file
+func (b *WebDAVBackend) Push(ctx context.Context, reg *Registry) error {
+	data, err := reg.Encode()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, b.URL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if b.pulled {
+		switch {
+		case !b.existed:
+			req.Header.Set("If-None-Match", "*")
+		case b.etag != "":
+			req.Header.Set("If-Match", b.etag)
+		}
+	}
+
+	resp, err := b.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		b.etag = resp.Header.Get("ETag")
+		b.existed = true
+		return nil
+	case http.StatusPreconditionFailed:
+		return ErrRemoteChanged
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return &httpclient.StatusError{URL: b.URL, StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/mochajutsu/mkcd/internal/httpclient"
+	"github.com/mochajutsu/mkcd/internal/project"
+)
+
+// registryWith returns a registry with a project per name, all on host
+func registryWith(host string, names ...string) *Registry {
+	reg := New()
+	for _, name := range names {
+		path := "/src/" + name
+		reg.Register(project.New(path, ""), path, host)
+	}
+	return reg
+}
+
+// setupGit isolates git from the user's configuration and returns the URL
+// of a new bare repository
+func setupGit(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(home, ".gitconfig"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "mkcd")
+	t.Setenv("GIT_AUTHOR_EMAIL", "mkcd@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "mkcd")
+	t.Setenv("GIT_COMMITTER_EMAIL", "mkcd@example.com")
+
+	remote := filepath.Join(t.TempDir(), "registry.git")
+	if _, err := runGit(context.Background(), "", "init", "--quiet", "--bare", remote); err != nil {
+		t.Fatalf("failed to create remote: %v", err)
+	}
+	return remote
+}
+
+// TestGitSyncRecoversFromRejectedPush checks that a host whose push was
+// rejected syncs again instead of diverging from the remote for good
+func TestGitSyncRecoversFromRejectedPush(t *testing.T) {
+	remote := setupGit(t)
+	ctx := context.Background()
+	laptop := &GitBackend{URL: remote, Dir: filepath.Join(t.TempDir(), "sync")}
+	desktop := &GitBackend{URL: remote, Dir: filepath.Join(t.TempDir(), "sync")}
+
+	laptopReg := registryWith("laptop", "api")
+	if _, err := Sync(ctx, laptop, laptopReg); err != nil {
+		t.Fatalf("first sync failed: %v", err)
+	}
+	desktopReg := registryWith("desktop", "web")
+	if _, err := Sync(ctx, desktop, desktopReg); err != nil {
+		t.Fatalf("second sync failed: %v", err)
+	}
+
+	// The desktop pushes between the laptop's pull and push
+	if _, err := laptop.Pull(ctx); err != nil {
+		t.Fatalf("pull failed: %v", err)
+	}
+	desktopReg.Register(project.New("/src/cli", ""), "/src/cli", "desktop")
+	if _, err := Sync(ctx, desktop, desktopReg); err != nil {
+		t.Fatalf("desktop sync failed: %v", err)
+	}
+	laptopReg.Register(project.New("/src/docs", ""), "/src/docs", "laptop")
+	if err := laptop.Push(ctx, laptopReg); !errors.Is(err, ErrRemoteChanged) {
+		t.Fatalf("expected the push to be rejected, got %v", err)
+	}
+
+	result, err := Sync(ctx, laptop, laptopReg)
+	if err != nil {
+		t.Fatalf("sync after a rejected push failed: %v", err)
+	}
+	if result.Total != 4 {
+		t.Errorf("expected 4 projects after the sync, got %d", result.Total)
+	}
+
+	merged, err := desktop.Pull(ctx)
+	if err != nil {
+		t.Fatalf("pull failed: %v", err)
+	}
+	if len(merged.Projects) != 4 {
+		t.Errorf("expected the remote to hold 4 projects, got %d", len(merged.Projects))
+	}
+}
+
+// webDAVServer is a WebDAV endpoint for a single file honoring If-Match
+// and If-None-Match like a real server
+type webDAVServer struct {
+	mu      sync.Mutex
+	data    []byte
+	version int
+
+	// beforePut runs once before the next PUT, to simulate another host
+	beforePut func()
+}
+
+func (s *webDAVServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPut && s.beforePut != nil {
+		hook := s.beforePut
+		s.beforePut = nil
+		hook()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	etag := fmt.Sprintf(`"v%d"`, s.version)
+
+	switch r.Method {
+	case http.MethodGet:
+		if s.data == nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write(s.data)
+	case http.MethodPut:
+		if match := r.Header.Get("If-Match"); match != "" && (s.data == nil || match != etag) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		if r.Header.Get("If-None-Match") == "*" && s.data != nil {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		s.data, _ = io.ReadAll(r.Body)
+		s.version++
+		w.Header().Set("ETag", fmt.Sprintf(`"v%d"`, s.version))
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// TestWebDAVSyncDoesNotOverwriteConcurrentUpload checks that an upload by
+// another host between pull and push is merged instead of overwritten
+func TestWebDAVSyncDoesNotOverwriteConcurrentUpload(t *testing.T) {
+	server := &webDAVServer{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	ctx := context.Background()
+	url := ts.URL + "/registry.json"
+
+	desktopReg := registryWith("desktop", "web")
+	desktop := &WebDAVBackend{URL: url, Client: httpclient.NewClient()}
+	if _, err := Sync(ctx, desktop, desktopReg); err != nil {
+		t.Fatalf("desktop sync failed: %v", err)
+	}
+
+	server.beforePut = func() {
+		desktopReg.Register(project.New("/src/cli", ""), "/src/cli", "desktop")
+		if _, err := Sync(ctx, desktop, desktopReg); err != nil {
+			t.Errorf("concurrent desktop sync failed: %v", err)
+		}
+	}
+	laptop := &WebDAVBackend{URL: url, Client: httpclient.NewClient()}
+	result, err := Sync(ctx, laptop, registryWith("laptop", "api"))
+	if err != nil {
+		t.Fatalf("laptop sync failed: %v", err)
+	}
+	if result.Total != 3 {
+		t.Errorf("expected 3 projects after the sync, got %d", result.Total)
+	}
+
+	merged, err := Decode(server.data)
+	if err != nil {
+		t.Fatalf("failed to decode the uploaded registry: %v", err)
+	}
+	if len(merged.Projects) != 3 {
+		t.Errorf("expected the remote to hold 3 projects, got %d", len(merged.Projects))
+	}
+}
+
+// TestWebDAVPushWithoutPullIsUnconditional keeps plain uploads working
+func TestWebDAVPushWithoutPullIsUnconditional(t *testing.T) {
+	server := &webDAVServer{data: []byte(`{"version":1,"projects":{}}`)}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	backend := &WebDAVBackend{URL: ts.URL + "/registry.json", Client: httpclient.NewClient()}
+	if err := backend.Push(context.Background(), registryWith("laptop", "api")); err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package shell
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Markers delimit the block managed by 'mkcd shell-init --install' in a
+// startup file. Everything between them is replaced or removed as a whole.
+const (
+	BeginMarker = "# >>> mkcd shell integration >>>"
+	EndMarker   = "# <<< mkcd shell integration <<<"
+)
+
+// initSignature identifies a hand-written line loading the wrapper
+const initSignature = "mkcd shell-init"
+
+// StartupFile returns the file a shell reads for interactive sessions,
+// relative to home. Only bash, zsh and fish can be installed into.
+func StartupFile(shell, home string) (string, error) {
+	switch Normalize(shell) {
+	case Bash:
+		return filepath.Join(home, ".bashrc"), nil
+	case Zsh:
+		if zdotdir := os.Getenv("ZDOTDIR"); zdotdir != "" {
+			return filepath.Join(zdotdir, ".zshrc"), nil
+		}
+		return filepath.Join(home, ".zshrc"), nil
+	case Fish:
+		configHome := os.Getenv("XDG_CONFIG_HOME")
+		if configHome == "" {
+			configHome = filepath.Join(home, ".config")
+		}
+		return filepath.Join(configHome, "fish", "config.fish"), nil
+	case "":
+		return "", fmt.Errorf("could not detect the shell from $SHELL; pass it as an argument")
+	default:
+		return "", fmt.Errorf("cannot install into %s startup files; add this line yourself: %s", shell, InitCommand(shell))
+	}
+}
+
+// Block returns the managed block loading the wrapper for shell, ending
+// with a newline
+func Block(shell string) string {
+	return BeginMarker + "\n" + InitCommand(shell) + "\n" + EndMarker + "\n"
+}
+
+// findBlock returns the byte range of the managed block in content,
+// including the newline after the end marker
+func findBlock(content string) (start, end int, found bool) {
+	start = strings.Index(content, BeginMarker)
+	if start < 0 {
+		return 0, 0, false
+	}
+	rel := strings.Index(content[start:], EndMarker)
+	if rel < 0 {
+		return 0, 0, false
+	}
+	end = start + rel + len(EndMarker)
+	if end < len(content) && content[end] == '\n' {
+		end++
+	}
+	return start, end, true
+}
+
+// HasManualInit reports whether content loads the wrapper outside the
+// managed block, i.e. the user set up the integration by hand
+func HasManualInit(content string) bool {
+	if start, end, found := findBlock(content); found {
+		content = content[:start] + content[end:]
+	}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.Contains(line, initSignature) && !strings.HasPrefix(line, "#") {
+			return true
+		}
+	}
+	return false
+}
+
+// AddBlock returns content with the managed block for shell appended, or
+// replaced in place when an older block exists. The boolean reports whether
+// the content changed.
+func AddBlock(content, shell string) (string, bool) {
+	block := Block(shell)
+
+	if start, end, found := findBlock(content); found {
+		updated := content[:start] + block + content[end:]
+		return updated, updated != content
+	}
+
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	if content != "" {
+		content += "\n"
+	}
+	return content + block, true
+}
+
+// RemoveBlock returns content without the managed block, along with the
+// blank line AddBlock put before it. The boolean reports whether a block
+// was found.
+func RemoveBlock(content string) (string, bool) {
+	start, end, found := findBlock(content)
+	if !found {
+		return content, false
+	}
+
+	before := content[:start]
+	if strings.HasSuffix(before, "\n\n") {
+		before = before[:len(before)-1]
+	}
+	return before + content[end:], true
+}
@@ -0,0 +1,583 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+// Package shell generates the shell functions that let mkcd change the
+// working directory of the calling shell.
+package shell
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// Supported shells
+const (
+	Bash       = "bash"
+	Zsh        = "zsh"
+	Fish       = "fish"
+	PowerShell = "powershell"
+	Nu         = "nu"
+	Cmd        = "cmd"
+)
+
+// PrintPathArgs are the arguments every wrapper passes to the binary to
+// create directories. The protocol is shell-agnostic: the binary prints at
+// most one line on stdout, the absolute path to change into (nothing in a
+// dry run), and sends all other output to stderr. A non-zero exit status
+// means creation failed; a path printed anyway (see CdOnFailure policies)
+// is still changed into, and the wrapper returns the status.
+const PrintPathArgs = "mkcd --print-path"
+
+// CompletePathsCmd is the hidden command the bash, zsh and fish wrappers
+// run to complete the first argument with recent directories. It prints the
+// matching directories one per line.
+const CompletePathsCmd = "__complete-paths"
+
+// IntegrationEnv is set by every wrapper to the name of its shell, so the
+// binary can tell that it runs with shell integration
+const IntegrationEnv = "MKCD_SHELL_INTEGRATION"
+
+// Policies for where the wrapper changes to when creation fails midway
+const (
+	CdStay    = "stay"
+	CdPartial = "partial"
+	CdParent  = "parent"
+)
+
+// GetSupportedShells returns the shells a wrapper can be generated for
+func GetSupportedShells() []string {
+	return []string{Bash, Zsh, Fish, PowerShell, Nu, Cmd}
+}
+
+// GetCdOnFailurePolicies returns the supported cd-on-failure policies
+func GetCdOnFailurePolicies() []string {
+	return []string{CdStay, CdPartial, CdParent}
+}
+
+// Normalize maps alternative shell names (pwsh, powershell.exe, nushell,
+// cmd.exe) to the supported name
+func Normalize(shell string) string {
+	shell = strings.TrimSuffix(strings.ToLower(shell), ".exe")
+	switch shell {
+	case "pwsh":
+		return PowerShell
+	case "nushell":
+		return Nu
+	}
+	return shell
+}
+
+// Detect returns the user's shell from $SHELL, PowerShell on Windows when
+// $SHELL is unset, or an empty string
+func Detect() string {
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		if runtime.GOOS == "windows" {
+			return PowerShell
+		}
+		return ""
+	}
+	return Normalize(filepath.Base(shell))
+}
+
+// Quote quotes a string as a single literal argument for a shell. Values
+// made only of characters no shell treats specially are returned as-is;
+// anything else, including spaces, quotes and UTF-8 names, is quoted by
+// the rules of the shell. In cmd.exe, %NAME% inside a path still expands
+// when a variable of that name exists, as cmd offers no way to escape it
+// on the command line.
+func Quote(shell, value string) string {
+	if isPlainWord(value) {
+		return value
+	}
+	return quote(shell, value)
+}
+
+// quote always quotes a string as a single literal argument for a shell
+func quote(shell, value string) string {
+	// Control characters, e.g. a newline, are spelled as escapes where the
+	// shell has them, so printing the command cannot garble the terminal
+	control := strings.IndexFunc(value, unicode.IsControl) >= 0
+
+	switch Normalize(shell) {
+	case PowerShell:
+		if control {
+			return powerShellDoubleQuote(value)
+		}
+		// Single-quoted strings are literal; a quote is escaped by doubling
+		// it, including the typographic quotes PowerShell also accepts
+		var b strings.Builder
+		b.WriteByte('\'')
+		for _, r := range value {
+			if isPowerShellQuote(r) {
+				b.WriteRune(r)
+			}
+			b.WriteRune(r)
+		}
+		b.WriteByte('\'')
+		return b.String()
+	case Fish:
+		// Escapes only work outside quotes, so quoted runs are joined with them
+		var b strings.Builder
+		quoted := false
+		for _, r := range value {
+			if unicode.IsControl(r) {
+				if quoted {
+					b.WriteByte('\'')
+					quoted = false
+				}
+				b.WriteString(controlEscape(r, `\u%04x`))
+				continue
+			}
+			if !quoted {
+				b.WriteByte('\'')
+				quoted = true
+			}
+			if r == '\\' || r == '\'' {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		}
+		if b.Len() == 0 {
+			return "''"
+		}
+		if quoted {
+			b.WriteByte('\'')
+		}
+		return b.String()
+	case Cmd:
+		// Windows paths cannot contain double quotes or control characters,
+		// so there is nothing to escape inside them
+		return `"` + value + `"`
+	case Nu:
+		// Single-quoted strings are raw and cannot contain a quote
+		if !strings.Contains(value, "'") && !control {
+			return "'" + value + "'"
+		}
+		var b strings.Builder
+		b.WriteByte('"')
+		for _, r := range value {
+			switch {
+			case r == '\\' || r == '"':
+				b.WriteByte('\\')
+				b.WriteRune(r)
+			case r == '\n' || r == '\t':
+				b.WriteString(controlEscape(r, ""))
+			case unicode.IsControl(r):
+				// Nushell has no \x escapes
+				fmt.Fprintf(&b, `\u{%x}`, r)
+			default:
+				b.WriteRune(r)
+			}
+		}
+		b.WriteByte('"')
+		return b.String()
+	default:
+		if control {
+			return ansiCQuote(value)
+		}
+		return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+	}
+}
+
+// ansiCQuote quotes a string for bash and zsh as $'...', which spells
+// control characters as escapes
+func ansiCQuote(value string) string {
+	var b strings.Builder
+	b.WriteString("$'")
+	for _, r := range value {
+		switch {
+		case r == '\\' || r == '\'':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case unicode.IsControl(r):
+			b.WriteString(controlEscape(r, `\u%04x`))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
+
+// powerShellDoubleQuote quotes a string for PowerShell in double quotes,
+// where control characters can be written as $([char]0x..)
+func powerShellDoubleQuote(value string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range value {
+		switch {
+		case r == '`' || r == '$' || isPowerShellDoubleQuote(r):
+			b.WriteByte('`')
+			b.WriteRune(r)
+		case unicode.IsControl(r):
+			fmt.Fprintf(&b, "$([char]0x%02x)", r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// controlEscape spells a control character as a backslash escape: \n and
+// \t, \xHH for ASCII, and wide for the others
+func controlEscape(r rune, wide string) string {
+	switch {
+	case r == '\n':
+		return `\n`
+	case r == '\t':
+		return `\t`
+	case r < 0x80:
+		return fmt.Sprintf(`\x%02x`, r)
+	default:
+		return fmt.Sprintf(wide, r)
+	}
+}
+
+// isPlainWord reports whether value needs no quoting in any supported
+// shell: ASCII letters, digits and a few punctuation characters, not
+// starting with a dash (an option) or being empty. Commas and @ are left
+// out, as PowerShell reads them as array and splatting syntax and cmd
+// splits arguments at commas.
+func isPlainWord(value string) bool {
+	if value == "" || value[0] == '-' {
+		return false
+	}
+	for _, r := range value {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("_./-+:", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isPowerShellDoubleQuote reports whether PowerShell treats r as a double
+// quote
+func isPowerShellDoubleQuote(r rune) bool {
+	switch r {
+	case '"', '\u201C', '\u201D', '\u201E':
+		return true
+	}
+	return false
+}
+
+// isPowerShellQuote reports whether PowerShell treats r as a single quote
+func isPowerShellQuote(r rune) bool {
+	switch r {
+	case '\'', '\u2018', '\u2019', '\u201A', '\u201B':
+		return true
+	}
+	return false
+}
+
+// ChangeDirCommand returns the command that changes into path in a shell
+func ChangeDirCommand(shell, path string) string {
+	switch Normalize(shell) {
+	case PowerShell:
+		return "Set-Location -LiteralPath " + Quote(shell, path)
+	case Fish, Nu:
+		return "cd " + Quote(shell, path)
+	case Cmd:
+		return "cd /d " + Quote(shell, path)
+	default:
+		return "cd -- " + Quote(shell, path)
+	}
+}
+
+// InitCommand returns the line that loads the wrapper in a shell's startup file
+func InitCommand(shell string) string {
+	switch Normalize(shell) {
+	case PowerShell:
+		return "Invoke-Expression (& mkcd shell-init powershell | Out-String)"
+	case Fish:
+		return "mkcd shell-init fish | source"
+	case Nu:
+		// Nushell sources files at parse time, so the wrapper is saved first
+		return "source ~/.cache/mkcd/init.nu (save it with: mkcd shell-init nu | save -f ~/.cache/mkcd/init.nu)"
+	case Cmd:
+		// cmd.exe has no startup file, so a doskey macro calls the saved wrapper
+		return `doskey mkcd=call "%USERPROFILE%\.mkcd\mkcd.cmd" $* (save it with: mkcd shell-init cmd > "%USERPROFILE%\.mkcd\mkcd.cmd")`
+	default:
+		return fmt.Sprintf(`eval "$(mkcd shell-init %s)"`, shell)
+	}
+}
+
+// Options configures the generated wrapper
+type Options struct {
+	// Passthrough lists first arguments handed to the binary unchanged,
+	// such as subcommands; an empty first argument always passes through
+	Passthrough []string
+
+	// CdCommands lists subcommands that emit a path to change into, such
+	// as open. They are run with --print-path appended, and the printed
+	// path is changed into like a created one.
+	CdCommands []string
+
+	// Completion is a completion script appended to the wrapper (fish only,
+	// where completions are registered by sourcing them)
+	Completion string
+}
+
+// templateData is the data passed to the wrapper templates
+type templateData struct {
+	Shell         string
+	PrintPath     string
+	CompletePaths string
+	Env           string
+	Passthrough   []string
+	CdCommands    []string
+	Completion    string
+}
+
+// wrapperTemplates are the wrapper functions per shell. Each wrapper runs
+// the binary with PrintPathArgs and changes into the printed path.
+var wrapperTemplates = map[string]string{
+	Bash:       posixTemplate,
+	Zsh:        posixTemplate,
+	Fish:       fishTemplate,
+	PowerShell: powerShellTemplate,
+	Nu:         nuTemplate,
+	Cmd:        cmdTemplate,
+}
+
+// posixTemplate is the wrapper for bash and zsh
+const posixTemplate = `# mkcd shell integration for {{.Shell}}
+# Add to your shell startup file: eval "$(mkcd shell-init {{.Shell}})"
+export {{.Env}}={{.Shell}}
+mkcd() {
+  local mkcd_dir mkcd_status
+  case "$1" in{{if .CdCommands}}
+    {{range $i, $c := .CdCommands}}{{if $i}}|{{end}}{{$c}}{{end}})
+      mkcd_dir="$(command mkcd "$@" --print-path)"
+      ;;{{end}}
+    ""{{range .Passthrough}}|{{.}}{{end}})
+      command mkcd "$@"
+      return
+      ;;
+    *)
+      mkcd_dir="$(command mkcd {{.PrintPath}} "$@")"
+      ;;
+  esac
+  mkcd_status=$?
+  if [ -n "$mkcd_dir" ] && [ -d "$mkcd_dir" ]; then
+    cd -- "$mkcd_dir" || return
+  fi
+  return $mkcd_status
+}
+{{if eq .Shell "bash"}}
+# Complete the first argument with subcommands, recent directories and local
+# directories; later arguments use the mkcd completion when it is loaded
+_mkcd_complete() {
+  local cur="${COMP_WORDS[COMP_CWORD]}"
+  if [ "$COMP_CWORD" -gt 1 ]; then
+    if declare -F __start_mkcd >/dev/null; then
+      __start_mkcd "$@"
+    else
+      compopt -o default 2>/dev/null
+      COMPREPLY=()
+    fi
+    return
+  fi
+
+  local IFS=$'\n'
+  local -a mkcd_commands=({{range .Passthrough}} {{.}}{{end}})
+  COMPREPLY=($(compgen -W "${mkcd_commands[*]}" -- "$cur")
+    $(command mkcd {{.CompletePaths}} -- "$cur" 2>/dev/null)
+    $(compgen -d -- "$cur"))
+  compopt -o filenames 2>/dev/null
+}
+complete -F _mkcd_complete mkcd
+{{else}}
+# Complete the first argument with subcommands, recent directories and local
+# directories; later arguments use the mkcd completion when it is loaded
+_mkcd_complete() {
+  if (( CURRENT > 2 )); then
+    if (( $+functions[_mkcd] )); then
+      _mkcd "$@"
+    else
+      _files
+    fi
+    return
+  fi
+
+  local -a mkcd_commands mkcd_paths
+  mkcd_commands=({{range .Passthrough}} {{.}}{{end}})
+  mkcd_paths=(${(f)"$(command mkcd {{.CompletePaths}} -- "$PREFIX" 2>/dev/null)"})
+  compadd -a mkcd_commands
+  # Recent directories also match by name, so the prefix is not enforced
+  (( ${#mkcd_paths} )) && compadd -U -f -- "${mkcd_paths[@]}"
+  _path_files -/
+}
+(( $+functions[compdef] )) && compdef _mkcd_complete mkcd
+{{end}}`
+
+// fishTemplate is the wrapper for fish
+const fishTemplate = `# mkcd shell integration for fish
+# Add to ~/.config/fish/config.fish: mkcd shell-init fish | source
+set -gx {{.Env}} fish
+function mkcd --description 'Create a directory and change into it'
+    set -l mkcd_dir
+    set -l mkcd_status
+    switch "$argv[1]"{{if .CdCommands}}
+        case{{range .CdCommands}} '{{.}}'{{end}}
+            set mkcd_dir (command mkcd $argv --print-path)
+            set mkcd_status $status{{end}}
+        case ''{{range .Passthrough}} '{{.}}'{{end}}
+            command mkcd $argv
+            return
+        case '*'
+            set mkcd_dir (command mkcd {{.PrintPath}} $argv)
+            set mkcd_status $status
+    end
+    if test (count $mkcd_dir) -eq 1; and test -d "$mkcd_dir"
+        cd -- $mkcd_dir
+    end
+    return $mkcd_status
+end
+{{if .Completion}}
+{{.Completion}}{{end}}
+# Complete the first argument with recent directories as well
+complete -c mkcd -n 'test (count (commandline -opc)) -eq 1' -a '(command mkcd {{.CompletePaths}} -- (commandline -ct) 2>/dev/null)' -d 'Recent directory'
+`
+
+// powerShellTemplate is the wrapper for Windows PowerShell and PowerShell 7.
+// The function shadows mkcd.exe, so the binary is resolved as an application.
+const powerShellTemplate = `# mkcd shell integration for PowerShell
+# Add to your $PROFILE: Invoke-Expression (& mkcd shell-init powershell | Out-String)
+$env:{{.Env}} = 'powershell'
+function mkcd {
+    $mkcdPassthrough = @(''{{range .Passthrough}}, {{quote .}}{{end}})
+    $mkcdCdCommands = @({{range $i, $c := .CdCommands}}{{if $i}}, {{end}}{{quote $c}}{{end}})
+    $mkcdExe = (Get-Command -Name mkcd -CommandType Application -ErrorAction Stop | Select-Object -First 1).Source
+    $mkcdFirst = if ($args.Count -gt 0) { [string]$args[0] } else { '' }
+
+    if ($mkcdCdCommands -ccontains $mkcdFirst) {
+        $mkcdDir = & $mkcdExe @args --print-path
+    } elseif ($mkcdPassthrough -ccontains $mkcdFirst) {
+        & $mkcdExe @args
+        return
+    } else {
+        $mkcdDir = & $mkcdExe {{.PrintPath}} @args
+    }
+    if ($mkcdDir -is [string] -and $mkcdDir -ne '' -and (Test-Path -LiteralPath $mkcdDir -PathType Container)) {
+        Set-Location -LiteralPath $mkcdDir
+    }
+}
+`
+
+// nuTemplate is the wrapper for Nushell, a --env custom command so that
+// cd affects the caller
+const nuTemplate = `# mkcd shell integration for nushell
+# Nushell cannot eval generated code, so save the wrapper and source it from config.nu:
+#   mkdir ~/.cache/mkcd; mkcd shell-init nu | save -f ~/.cache/mkcd/init.nu
+#   source ~/.cache/mkcd/init.nu
+$env.{{.Env}} = 'nu'
+def --env --wrapped mkcd [...args: string] {
+    let passthrough = [''{{range .Passthrough}} {{quote .}}{{end}}]
+    let cd_commands = [{{range $i, $c := .CdCommands}}{{if $i}} {{end}}{{quote $c}}{{end}}]
+    let first = if ($args | is-empty) { '' } else { $args | first }
+
+    if ($first in $passthrough) and not ($first in $cd_commands) {
+        ^mkcd ...$args
+        return
+    }
+
+    let mkcd_dir = if $first in $cd_commands {
+        do --ignore-errors { ^mkcd ...$args --print-path } | str trim
+    } else {
+        do --ignore-errors { ^mkcd {{.PrintPath}} ...$args } | str trim
+    }
+    let mkcd_status = $env.LAST_EXIT_CODE
+    if ($mkcd_dir | is-not-empty) and ($mkcd_dir | path exists) {
+        cd $mkcd_dir
+    }
+    if $mkcd_status != 0 {
+        error make --unspanned { msg: $"mkcd exited with status ($mkcd_status)" }
+    }
+}
+`
+
+// cmdTemplate is the wrapper for the Windows command prompt, a batch file
+// called through a doskey macro. The binary is run as mkcd.exe so the macro
+// never calls itself. Its path goes through a temporary file rather than a
+// for /f loop, which would lose the exit status.
+const cmdTemplate = `@echo off
+rem mkcd shell integration for cmd.exe
+rem Save as %USERPROFILE%\.mkcd\mkcd.cmd: mkcd shell-init cmd > "%USERPROFILE%\.mkcd\mkcd.cmd"
+rem Then load it in every prompt (AutoRun runs doskey when cmd.exe starts):
+rem   reg add "HKCU\Software\Microsoft\Command Processor" /v AutoRun /t REG_EXPAND_SZ /d "doskey mkcd=call \"%%USERPROFILE%%\.mkcd\mkcd.cmd\" $*" /f
+set "{{.Env}}=cmd"
+if "%~1"=="" goto mkcd_passthrough
+{{if .CdCommands}}for %%P in ({{range $i, $c := .CdCommands}}{{if $i}} {{end}}{{$c}}{{end}}) do if /i "%~1"=="%%P" goto mkcd_cd
+{{end}}for %%P in ({{range $i, $p := .Passthrough}}{{if $i}} {{end}}{{$p}}{{end}}) do if /i "%~1"=="%%P" goto mkcd_passthrough
+
+set "MKCD_OUT=%TEMP%\mkcd-%RANDOM%%RANDOM%.path"
+mkcd.exe {{.PrintPath}} %* > "%MKCD_OUT%"
+set "MKCD_STATUS=%ERRORLEVEL%"
+goto mkcd_change
+
+:mkcd_cd
+set "MKCD_OUT=%TEMP%\mkcd-%RANDOM%%RANDOM%.path"
+mkcd.exe %* --print-path > "%MKCD_OUT%"
+set "MKCD_STATUS=%ERRORLEVEL%"
+
+:mkcd_change
+set "MKCD_DIR="
+set /p MKCD_DIR=<"%MKCD_OUT%"
+del "%MKCD_OUT%" 2>nul
+if defined MKCD_DIR if exist "%MKCD_DIR%\" cd /d "%MKCD_DIR%"
+set "MKCD_DIR="
+set "MKCD_OUT="
+set "MKCD_STATUS=" & exit /b %MKCD_STATUS%
+
+:mkcd_passthrough
+mkcd.exe %*
+`
+
+// Wrapper returns the wrapper function for a shell
+func Wrapper(shell string, opts Options) (string, error) {
+	shell = Normalize(shell)
+	text, exists := wrapperTemplates[shell]
+	if !exists {
+		return "", fmt.Errorf("unsupported shell '%s' (supported: %s)", shell, strings.Join(GetSupportedShells(), ", "))
+	}
+
+	funcs := template.FuncMap{
+		"quote": func(value string) string { return quote(shell, value) },
+	}
+	tmpl, err := template.New(shell).Funcs(funcs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s wrapper template: %w", shell, err)
+	}
+
+	var b strings.Builder
+	data := templateData{
+		Shell:         shell,
+		PrintPath:     PrintPathArgs,
+		CompletePaths: CompletePathsCmd,
+		Env:           IntegrationEnv,
+		Passthrough:   opts.Passthrough,
+		CdCommands:    opts.CdCommands,
+		Completion:    opts.Completion,
+	}
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render %s wrapper: %w", shell, err)
+	}
+	if shell == Cmd {
+		// cmd.exe can miss labels in batch files with bare LF line endings
+		return strings.ReplaceAll(b.String(), "\n", "\r\n"), nil
+	}
+	return b.String(), nil
+}
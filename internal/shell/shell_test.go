@@ -0,0 +1,233 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package shell
+
+import "testing"
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected map[string]string
+	}{
+		{
+			name:  "plain",
+			value: "/home/me/src/api-v2.1",
+			expected: map[string]string{
+				Bash: "/home/me/src/api-v2.1", Zsh: "/home/me/src/api-v2.1", Fish: "/home/me/src/api-v2.1",
+				PowerShell: "/home/me/src/api-v2.1", Nu: "/home/me/src/api-v2.1", Cmd: "/home/me/src/api-v2.1",
+			},
+		},
+		{
+			name:  "empty",
+			value: "",
+			expected: map[string]string{
+				Bash: "''", Zsh: "''", Fish: "''", PowerShell: "''", Nu: "''", Cmd: `""`,
+			},
+		},
+		{
+			name:  "leading dash",
+			value: "-rf",
+			expected: map[string]string{
+				Bash: "'-rf'", Zsh: "'-rf'", Fish: "'-rf'", PowerShell: "'-rf'", Nu: "'-rf'", Cmd: `"-rf"`,
+			},
+		},
+		{
+			name:  "spaces",
+			value: "/tmp/my project",
+			expected: map[string]string{
+				Bash: "'/tmp/my project'", Zsh: "'/tmp/my project'", Fish: "'/tmp/my project'",
+				PowerShell: "'/tmp/my project'", Nu: "'/tmp/my project'", Cmd: `"/tmp/my project"`,
+			},
+		},
+		{
+			name:  "single quote",
+			value: "/tmp/it's",
+			expected: map[string]string{
+				Bash: `'/tmp/it'\''s'`, Zsh: `'/tmp/it'\''s'`, Fish: `'/tmp/it\'s'`,
+				PowerShell: "'/tmp/it''s'", Nu: `"/tmp/it's"`, Cmd: `"/tmp/it's"`,
+			},
+		},
+		{
+			name:  "double quote and dollar",
+			value: `/tmp/"$HOME"`,
+			expected: map[string]string{
+				Bash: `'/tmp/"$HOME"'`, Zsh: `'/tmp/"$HOME"'`, Fish: `'/tmp/"$HOME"'`,
+				PowerShell: `'/tmp/"$HOME"'`, Nu: `'/tmp/"$HOME"'`, Cmd: `"/tmp/"$HOME""`,
+			},
+		},
+		{
+			name:  "comma and at sign",
+			value: "/tmp/a,b@c",
+			expected: map[string]string{
+				Bash: "'/tmp/a,b@c'", Zsh: "'/tmp/a,b@c'", Fish: "'/tmp/a,b@c'",
+				PowerShell: "'/tmp/a,b@c'", Nu: "'/tmp/a,b@c'", Cmd: `"/tmp/a,b@c"`,
+			},
+		},
+		{
+			name:  "leading at sign",
+			value: "@scope",
+			expected: map[string]string{
+				Bash: "'@scope'", Zsh: "'@scope'", Fish: "'@scope'",
+				PowerShell: "'@scope'", Nu: "'@scope'", Cmd: `"@scope"`,
+			},
+		},
+		{
+			name:  "non-ASCII",
+			value: "/tmp/café/日本",
+			expected: map[string]string{
+				Bash: "'/tmp/café/日本'", Zsh: "'/tmp/café/日本'", Fish: "'/tmp/café/日本'",
+				PowerShell: "'/tmp/café/日本'", Nu: "'/tmp/café/日本'", Cmd: `"/tmp/café/日本"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, shell := range GetSupportedShells() {
+				if got := Quote(shell, tt.value); got != tt.expected[shell] {
+					t.Errorf("Quote(%s, %q) = %s, expected %s", shell, tt.value, got, tt.expected[shell])
+				}
+			}
+		})
+	}
+}
+
+func TestChangeDirCommand(t *testing.T) {
+	path := "/tmp/my project"
+	expected := map[string]string{
+		Bash:       "cd -- '/tmp/my project'",
+		Zsh:        "cd -- '/tmp/my project'",
+		Fish:       "cd '/tmp/my project'",
+		PowerShell: "Set-Location -LiteralPath '/tmp/my project'",
+		Nu:         "cd '/tmp/my project'",
+		Cmd:        `cd /d "/tmp/my project"`,
+	}
+
+	for _, shell := range GetSupportedShells() {
+		if got := ChangeDirCommand(shell, path); got != expected[shell] {
+			t.Errorf("ChangeDirCommand(%s) = %s, expected %s", shell, got, expected[shell])
+		}
+	}
+}
+
+// TestQuoteControlAndTypographic covers the characters quoted differently
+// from plain single quoting: control characters, which are spelled as
+// escapes, and the typographic quotes PowerShell treats as quotes
+func TestQuoteControlAndTypographic(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected map[string]string
+	}{
+		{
+			name:  "newline",
+			value: "a\nb",
+			expected: map[string]string{
+				Bash: `$'a\nb'`, Zsh: `$'a\nb'`, Fish: `'a'\n'b'`,
+				PowerShell: `"a$([char]0x0a)b"`, Nu: `"a\nb"`,
+				// Windows paths cannot contain control characters
+				Cmd: "\"a\nb\"",
+			},
+		},
+		{
+			name:  "tab",
+			value: "a\tb",
+			expected: map[string]string{
+				Bash: `$'a\tb'`, Zsh: `$'a\tb'`, Fish: `'a'\t'b'`,
+				PowerShell: `"a$([char]0x09)b"`, Nu: `"a\tb"`, Cmd: "\"a\tb\"",
+			},
+		},
+		{
+			name:  "escape",
+			value: "a\x1b[31mb",
+			expected: map[string]string{
+				Bash: `$'a\x1b[31mb'`, Zsh: `$'a\x1b[31mb'`, Fish: `'a'\x1b'[31mb'`,
+				PowerShell: `"a$([char]0x1b)[31mb"`, Nu: `"a\u{1b}[31mb"`, Cmd: "\"a\x1b[31mb\"",
+			},
+		},
+		{
+			name:  "quote and newline",
+			value: "it's\n",
+			expected: map[string]string{
+				Bash: `$'it\'s\n'`, Zsh: `$'it\'s\n'`, Fish: `'it\'s'\n`,
+				PowerShell: `"it's$([char]0x0a)"`, Nu: `"it's\n"`, Cmd: "\"it's\n\"",
+			},
+		},
+		{
+			name:  "typographic single quote",
+			value: "it’s",
+			expected: map[string]string{
+				Bash: "'it’s'", Zsh: "'it’s'", Fish: "'it’s'",
+				PowerShell: "'it’’s'", Nu: "'it’s'", Cmd: `"it’s"`,
+			},
+		},
+		{
+			name:  "typographic double quotes",
+			value: "a“b”",
+			expected: map[string]string{
+				Bash: "'a“b”'", Zsh: "'a“b”'", Fish: "'a“b”'",
+				PowerShell: "'a“b”'", Nu: "'a“b”'", Cmd: `"a“b”"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, shell := range GetSupportedShells() {
+				if got := quote(shell, tt.value); got != tt.expected[shell] {
+					t.Errorf("quote(%s, %q) = %q, expected %q", shell, tt.value, got, tt.expected[shell])
+				}
+			}
+		})
+	}
+}
+
+func TestANSICQuote(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"plain", `$'plain'`},
+		{"a\nb", `$'a\nb'`},
+		{"a\tb", `$'a\tb'`},
+		{"a\x1bb", `$'a\x1bb'`},
+		{"a\u0085b", `$'a\u0085b'`},
+		{`back\slash`, `$'back\\slash'`},
+		{"it's", `$'it\'s'`},
+		{"it’s", "$'it’s'"},
+	}
+
+	for _, tt := range tests {
+		if got := ansiCQuote(tt.value); got != tt.expected {
+			t.Errorf("ansiCQuote(%q) = %s, expected %s", tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestPowerShellDoubleQuote(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"plain", `"plain"`},
+		{"a\nb", `"a$([char]0x0a)b"`},
+		{"a\tb", `"a$([char]0x09)b"`},
+		{"a\x1bb", `"a$([char]0x1b)b"`},
+		{"$HOME", "\"`$HOME\""},
+		{"back`tick", "\"back``tick\""},
+		{`say "hi"`, "\"say `\"hi`\"\""},
+		{"a“b”„c", "\"a`“b`”`„c\""},
+		{"it’s", `"it’s"`},
+	}
+
+	for _, tt := range tests {
+		if got := powerShellDoubleQuote(tt.value); got != tt.expected {
+			t.Errorf("powerShellDoubleQuote(%q) = %s, expected %s", tt.value, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package shell_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mochajutsu/mkcd/internal/shell"
+	"github.com/mochajutsu/mkcd/internal/testsupport"
+)
+
+// wrapperFiles names the rendered wrapper of each shell in the golden tree
+var wrapperFiles = map[string]string{
+	shell.Bash:       "mkcd.bash",
+	shell.Zsh:        "mkcd.zsh",
+	shell.Fish:       "mkcd.fish",
+	shell.PowerShell: "mkcd.ps1",
+	shell.Nu:         "mkcd.nu",
+	shell.Cmd:        "mkcd.cmd",
+}
+
+// TestWrappersMatchGolden renders the wrapper of every shell with fixed
+// options and compares them with the golden tree. Run with
+// MKCD_UPDATE_GOLDEN=1 after deliberate changes and review the diff.
+func TestWrappersMatchGolden(t *testing.T) {
+	ws := testsupport.NewWorkspace(t)
+	opts := shell.Options{
+		Passthrough: []string{"help", "version", "shell-init"},
+		CdCommands:  []string{"open", "go"},
+		Completion:  "# completions\n",
+	}
+
+	for _, name := range shell.GetSupportedShells() {
+		file, exists := wrapperFiles[name]
+		if !exists {
+			t.Fatalf("no golden file for the %s wrapper", name)
+		}
+		wrapper, err := shell.Wrapper(name, opts)
+		if err != nil {
+			t.Fatalf("failed to render the %s wrapper: %v", name, err)
+		}
+		ws.WriteFile(file, wrapper)
+	}
+
+	testsupport.AssertGoldenTree(t, ws.Root, "wrappers")
+}
+
+// TestWrappersUseProtocol checks what every wrapper must do regardless of
+// its syntax
+func TestWrappersUseProtocol(t *testing.T) {
+	for _, name := range shell.GetSupportedShells() {
+		wrapper, err := shell.Wrapper(name, shell.Options{Passthrough: []string{"help"}, CdCommands: []string{"open"}})
+		if err != nil {
+			t.Fatalf("failed to render the %s wrapper: %v", name, err)
+		}
+		for _, expected := range []string{shell.PrintPathArgs[len("mkcd "):], shell.IntegrationEnv, "help", "open"} {
+			if !strings.Contains(wrapper, expected) {
+				t.Errorf("%s wrapper does not contain %q", name, expected)
+			}
+		}
+	}
+
+	if _, err := shell.Wrapper("tcsh", shell.Options{}); err == nil {
+		t.Error("expected an error for an unsupported shell")
+	}
+}
+
+// TestCmdWrapperReturnsStatus checks that the cmd wrapper returns the exit
+// status of mkcd.exe, as a for /f loop would lose it
+func TestCmdWrapperReturnsStatus(t *testing.T) {
+	wrapper, err := shell.Wrapper(shell.Cmd, shell.Options{CdCommands: []string{"open"}})
+	if err != nil {
+		t.Fatalf("failed to render the cmd wrapper: %v", err)
+	}
+	lines := strings.Split(strings.ReplaceAll(wrapper, "\r\n", "\n"), "\n")
+
+	calls := 0
+	for i, line := range lines {
+		if strings.HasPrefix(line, "mkcd.exe") && strings.Contains(line, "--print-path") {
+			calls++
+			if i+1 >= len(lines) || lines[i+1] != `set "MKCD_STATUS=%ERRORLEVEL%"` {
+				t.Errorf("status not saved right after %q", line)
+			}
+		}
+		if strings.HasPrefix(line, "exit /b") || strings.HasPrefix(line, "for /f") {
+			t.Errorf("unexpected %q", line)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls of mkcd.exe with --print-path, got %d", calls)
+	}
+	if !strings.Contains(wrapper, `exit /b %MKCD_STATUS%`) {
+		t.Errorf("wrapper does not exit with the saved status:\n%s", wrapper)
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+// Package stats aggregates opt-in usage metrics in a local file. Only
+// command and step names with their counts and durations are recorded;
+// paths, directory names and arguments never are.
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/mochajutsu/mkcd/internal/history"
+	"github.com/mochajutsu/mkcd/internal/utils"
+)
+
+// FormatVersion is the metrics format written by Save
+const FormatVersion = 1
+
+// FileName is the name of the metrics file inside the state directory
+const FileName = "stats.json"
+
+// Counter aggregates the runs of one command or pipeline step
+type Counter struct {
+	Count    int64         `json:"count"`
+	Failures int64         `json:"failures"`
+	Total    time.Duration `json:"total_ns"`
+	Max      time.Duration `json:"max_ns"`
+}
+
+// Mean returns the average duration of a run
+func (c *Counter) Mean() time.Duration {
+	if c.Count == 0 {
+		return 0
+	}
+	return c.Total / time.Duration(c.Count)
+}
+
+// add records one run
+func (c *Counter) add(duration time.Duration, failed bool) {
+	c.Count++
+	if failed {
+		c.Failures++
+	}
+	c.Total += duration
+	if duration > c.Max {
+		c.Max = duration
+	}
+}
+
+// Metrics is the aggregated usage on this machine
+type Metrics struct {
+	Version  int                 `json:"version"`
+	Since    time.Time           `json:"since"`
+	Commands map[string]*Counter `json:"commands"`
+	Steps    map[string]*Counter `json:"steps"`
+}
+
+// Export is the form printed by 'mkcd stats export'. It adds the mkcd
+// version and platform so collected files can be compared across machines.
+type Export struct {
+	*Metrics
+	MkcdVersion string    `json:"mkcd_version"`
+	OS          string    `json:"os"`
+	Arch        string    `json:"arch"`
+	Exported    time.Time `json:"exported"`
+}
+
+// New creates empty metrics
+func New() *Metrics {
+	return &Metrics{
+		Version:  FormatVersion,
+		Since:    utils.Now().UTC().Truncate(24 * time.Hour),
+		Commands: map[string]*Counter{},
+		Steps:    map[string]*Counter{},
+	}
+}
+
+// DefaultPath returns the metrics location inside the state directory
+func DefaultPath() (string, error) {
+	stateDir, err := history.StateDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(stateDir, FileName), nil
+}
+
+// Load reads the metrics at path; a missing file is empty metrics
+func Load(path string) (*Metrics, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return New(), nil
+		}
+		return nil, fmt.Errorf("failed to read metrics %s: %w", path, err)
+	}
+
+	metrics := New()
+	if err := json.Unmarshal(data, metrics); err != nil {
+		return nil, fmt.Errorf("failed to parse metrics %s: %w", path, err)
+	}
+	if metrics.Version > FormatVersion {
+		return nil, fmt.Errorf("metrics %s has unsupported format version %d", path, metrics.Version)
+	}
+	if metrics.Commands == nil {
+		metrics.Commands = map[string]*Counter{}
+	}
+	if metrics.Steps == nil {
+		metrics.Steps = map[string]*Counter{}
+	}
+	return metrics, nil
+}
+
+// Save writes the metrics to path
+func (m *Metrics) Save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode metrics: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create metrics directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write metrics %s: %w", path, err)
+	}
+	return nil
+}
+
+// RecordCommand records one run of the command named by its path, e.g.
+// "mkcd" or "history undo"
+func (m *Metrics) RecordCommand(name string, duration time.Duration, failed bool) {
+	counter(m.Commands, name).add(duration, failed)
+}
+
+// RecordSteps records the validation and pipeline step durations of one
+// directory creation
+func (m *Metrics) RecordSteps(timings *utils.Timings) {
+	for _, timing := range timings.Entries() {
+		counter(m.Steps, timing.Name).add(timing.Duration, timing.Failed)
+	}
+}
+
+// Export returns the metrics with version and platform for collection
+func (m *Metrics) Export(mkcdVersion string) Export {
+	return Export{
+		Metrics:     m,
+		MkcdVersion: mkcdVersion,
+		OS:          runtime.GOOS,
+		Arch:        runtime.GOARCH,
+		Exported:    utils.Now().UTC(),
+	}
+}
+
+// counter returns the counter for name, creating it when missing
+func counter(counters map[string]*Counter, name string) *Counter {
+	c, exists := counters[name]
+	if !exists {
+		c = &Counter{}
+		counters[name] = c
+	}
+	return c
+}
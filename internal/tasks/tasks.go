@@ -0,0 +1,155 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+// Package tasks records setup chores for new projects in a task manager:
+// Taskwarrior, through its task command, or a todo.txt file.
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Supported backends
+const (
+	Taskwarrior = "taskwarrior"
+	TodoTxt     = "todotxt"
+)
+
+// commandTimeout bounds a single task command
+const commandTimeout = 10 * time.Second
+
+// Data is available to task descriptions
+type Data struct {
+	ProjectName string
+	Profile     string
+	Path        string
+}
+
+// Task is a chore for a project
+type Task struct {
+	Description string
+
+	// Project groups the tasks of a project: project:<name> in
+	// Taskwarrior, +<name> in todo.txt
+	Project string
+}
+
+// GetAvailableBackends returns the supported task backends
+func GetAvailableBackends() []string {
+	return []string{Taskwarrior, TodoTxt}
+}
+
+// Render renders the task descriptions with data; empty results are
+// dropped
+func Render(descriptions []string, data Data) ([]Task, error) {
+	result := []Task{}
+	for _, description := range descriptions {
+		tmpl, err := template.New("task").Option("missingkey=error").Parse(description)
+		if err != nil {
+			return nil, fmt.Errorf("invalid task '%s': %w", description, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("invalid task '%s': %w", description, err)
+		}
+
+		text := strings.Join(strings.Fields(buf.String()), " ")
+		if text != "" {
+			result = append(result, Task{Description: text, Project: data.ProjectName})
+		}
+	}
+	return result, nil
+}
+
+// Add records tasks with backend; file is the todo.txt file
+func Add(backend, file string, tasks []Task, now time.Time) error {
+	switch backend {
+	case Taskwarrior:
+		for _, task := range tasks {
+			if err := addTaskwarrior(task); err != nil {
+				return err
+			}
+		}
+		return nil
+	case TodoTxt:
+		return appendTodoTxt(file, tasks, now)
+	default:
+		return fmt.Errorf("unknown task backend '%s' (expected %s)", backend, strings.Join(GetAvailableBackends(), " or "))
+	}
+}
+
+// addTaskwarrior adds a task with the task command
+func addTaskwarrior(task Task) error {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	args := []string{"rc.confirmation=off", "rc.verbose=nothing", "add"}
+	if task.Project != "" {
+		args = append(args, "project:"+projectTag(task.Project))
+	}
+	// "--" keeps words such as "due:" in the description literal
+	args = append(args, "--", task.Description)
+
+	output, err := exec.CommandContext(ctx, "task", args...).CombinedOutput()
+	if err != nil {
+		if len(bytes.TrimSpace(output)) > 0 {
+			return fmt.Errorf("task add failed: %w: %s", err, bytes.TrimSpace(output))
+		}
+		return fmt.Errorf("task add failed: %w", err)
+	}
+	return nil
+}
+
+// appendTodoTxt appends tasks to a todo.txt file, creating it if needed:
+//
+//	2025-01-31 Set up CI for api +api
+func appendTodoTxt(file string, tasks []Task, now time.Time) error {
+	if file == "" {
+		return fmt.Errorf("no todo.txt file configured")
+	}
+
+	var buf bytes.Buffer
+
+	// A file not ending in a newline would join its last task with ours
+	if content, err := os.ReadFile(file); err == nil && len(content) > 0 && content[len(content)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+	for _, task := range tasks {
+		buf.WriteString(now.Format("2006-01-02") + " " + task.Description)
+		if task.Project != "" {
+			buf.WriteString(" +" + projectTag(task.Project))
+		}
+		buf.WriteByte('\n')
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(file), err)
+	}
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", file, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	return nil
+}
+
+// projectTag turns a project name into a single word usable as a project
+// in both formats
+func projectTag(name string) string {
+	return strings.Join(strings.Fields(name), "-")
+}
@@ -0,0 +1,426 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package templates
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mochajutsu/mkcd/internal/httpclient"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/pterm/pterm"
+)
+
+// archiveDownloadTimeout bounds downloading an archive template
+const archiveDownloadTimeout = 2 * time.Minute
+
+// archiveSuffixes are the extensions of archive templates
+var archiveSuffixes = []string{".tar.gz", ".tgz", ".zip"}
+
+// errArchiveTooLarge is returned for archives over the size limit
+var errArchiveTooLarge = errors.New("archive exceeds the size limit")
+
+// IsArchive reports whether a template name is a tar.gz or zip archive, a
+// local path or an HTTP(S) URL, rather than a template directory or Git
+// repository
+func IsArchive(name string) bool {
+	return archiveSuffix(archiveFileName(name)) != ""
+}
+
+// archiveSuffix returns the archive extension of file, or an empty string
+func archiveSuffix(file string) string {
+	for _, suffix := range archiveSuffixes {
+		if strings.HasSuffix(strings.ToLower(file), suffix) {
+			return suffix
+		}
+	}
+	return ""
+}
+
+// archiveFileName returns the file name of an archive, without the query
+// and fragment of URLs
+func archiveFileName(name string) string {
+	if isHTTP(name) {
+		name, _, _ = strings.Cut(name, "#")
+		name, _, _ = strings.Cut(name, "?")
+	}
+	return path.Base(filepath.ToSlash(name))
+}
+
+// isHTTP reports whether name is an HTTP(S) URL
+func isHTTP(name string) bool {
+	return strings.HasPrefix(name, "https://") || strings.HasPrefix(name, "http://")
+}
+
+// archiveSource returns the URL of an archive template, or its absolute
+// path for local archives and file:// URLs
+func archiveSource(name string) (string, error) {
+	if isHTTP(name) {
+		return name, nil
+	}
+	expanded, err := utils.ExpandPath(strings.TrimPrefix(name, "file://"))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(expanded)
+}
+
+// ArchiveCacheDir returns where an archive template is extracted, inside
+// $XDG_CACHE_HOME/mkcd/templates/archives or ~/.cache/mkcd/templates/archives.
+// The directory is named after the archive and a hash of its location.
+func ArchiveCacheDir(name string) (string, error) {
+	source, err := archiveSource(name)
+	if err != nil {
+		return "", err
+	}
+	cacheHome, err := cacheHome()
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(source))
+	return filepath.Join(cacheHome, "mkcd", "templates", "archives", archiveBaseName(source)+"-"+hex.EncodeToString(sum[:])[:12]), nil
+}
+
+// archiveBaseName returns the file name of an archive without its
+// extension, e.g. "tpl" for https://example.com/tpl.tar.gz?v=2
+func archiveBaseName(source string) string {
+	file := archiveFileName(source)
+	base := file[:len(file)-len(archiveSuffix(file))]
+	if base == "" || base == "." || base == "/" {
+		return "template"
+	}
+	return base
+}
+
+// FetchArchive returns the extracted copy of an archive template,
+// downloading and extracting it on first use. Archives may not exceed
+// maxSize bytes, neither downloaded nor extracted.
+//
+// A cached copy of a downloaded archive is fetched again when refresh is
+// set, or with autoUpdate once it is older than RemoteTemplatesTTL; if that
+// fails, e.g. offline, the cached copy is used. Local archives are
+// extracted again whenever they changed.
+func FetchArchive(name string, maxSize int64, autoUpdate, refresh bool) (string, error) {
+	source, err := archiveSource(name)
+	if err != nil {
+		return "", err
+	}
+	dir, err := ArchiveCacheDir(name)
+	if err != nil {
+		return "", err
+	}
+
+	info, statErr := os.Stat(dir)
+	if statErr == nil && !refresh {
+		stale := autoUpdate && time.Since(info.ModTime()) >= RemoteTemplatesTTL
+		if !isHTTP(source) {
+			archive, err := os.Stat(source)
+			stale = err != nil || archive.ModTime().After(info.ModTime())
+		}
+		if !stale {
+			return dir, nil
+		}
+	}
+
+	if err := extractArchiveTemplate(source, dir, maxSize); err != nil {
+		if statErr == nil {
+			pterm.Warning.Printf("Failed to update template %s, using cached copy: %v\n", source, err)
+			// Keep using the copy for another TTL instead of retrying every time
+			now := time.Now()
+			_ = os.Chtimes(dir, now, now)
+			return dir, nil
+		}
+		return "", err
+	}
+	return dir, nil
+}
+
+// extractArchiveTemplate extracts the archive at source next to dir and
+// then replaces dir, so a failed download or a rejected archive never
+// damages the cached copy
+func extractArchiveTemplate(source, dir string, maxSize int64) error {
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return fmt.Errorf("failed to create template cache: %w", err)
+	}
+	tmpDir, err := os.MkdirTemp(filepath.Dir(dir), ".extract-")
+	if err != nil {
+		return fmt.Errorf("failed to create template cache: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	archive := source
+	if isHTTP(source) {
+		archive = filepath.Join(tmpDir, "archive")
+		if err := downloadArchive(source, archive, maxSize); err != nil {
+			return fmt.Errorf("failed to download %s: %w", source, err)
+		}
+	}
+	if info, err := os.Stat(archive); err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	} else if info.Size() > maxSize {
+		return fmt.Errorf("%s: %w of %d MB", source, errArchiveTooLarge, maxSize>>20)
+	}
+
+	extracted := filepath.Join(tmpDir, "template")
+	x := &extractor{root: extracted, source: source, remaining: maxSize}
+	if archiveSuffix(archiveFileName(source)) == ".zip" {
+		err = x.zip(archive)
+	} else {
+		err = x.tarGz(archive)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to extract %s: %w", source, err)
+	}
+
+	root, err := archiveRoot(extracted)
+	if err != nil {
+		return err
+	}
+	return replaceDir(root, dir)
+}
+
+// downloadArchive downloads url to file, failing once it exceeds maxSize
+func downloadArchive(url, file string, maxSize int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), archiveDownloadTimeout)
+	defer cancel()
+
+	// Retry once only: a cached copy is usually available as a fallback
+	client := httpclient.NewClient()
+	client.MaxRetries = 1
+	client.HTTPClient.Timeout = archiveDownloadTimeout
+
+	resp, err := client.Get(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &httpclient.StatusError{URL: url, StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+	}
+	if resp.ContentLength > maxSize {
+		return fmt.Errorf("%w of %d MB", errArchiveTooLarge, maxSize>>20)
+	}
+
+	out, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	// Servers may not announce the length, or announce a wrong one
+	n, err := io.Copy(out, io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return err
+	}
+	if n > maxSize {
+		return fmt.Errorf("%w of %d MB", errArchiveTooLarge, maxSize>>20)
+	}
+	return out.Close()
+}
+
+// archiveRoot returns the directory holding the template in an extracted
+// archive: the single top-level directory archives are usually packed
+// with, such as repo-main/ in GitHub archives, or the archive root
+func archiveRoot(extracted string) (string, error) {
+	if err := os.MkdirAll(extracted, 0755); err != nil {
+		return "", fmt.Errorf("failed to read extracted archive: %w", err)
+	}
+	entries, err := os.ReadDir(extracted)
+	if err != nil {
+		return "", fmt.Errorf("failed to read extracted archive: %w", err)
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(extracted, entries[0].Name()), nil
+	}
+	return extracted, nil
+}
+
+// extractor writes archive entries below root. Entries leaving root are
+// refused, as is the whole archive with them; links and special files are
+// skipped like in template directories.
+type extractor struct {
+	root   string
+	source string
+
+	// remaining is how many bytes may still be extracted
+	remaining int64
+}
+
+// tarGz extracts a gzip-compressed tar archive
+func (x *extractor) tarGz(archive string) error {
+	file, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = x.dir(header.Name)
+		case tar.TypeReg, tar.TypeRegA:
+			err = x.file(header.Name, os.FileMode(header.Mode), tr)
+		case tar.TypeXGlobalHeader:
+			// PAX metadata, e.g. the commit of a git archive
+		default:
+			err = x.skip(header.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// zip extracts a zip archive
+func (x *extractor) zip(archive string) error {
+	zr, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	for _, entry := range zr.File {
+		mode := entry.Mode()
+		switch {
+		case mode.IsDir():
+			err = x.dir(entry.Name)
+		case mode.IsRegular():
+			var rc io.ReadCloser
+			rc, err = entry.Open()
+			if err != nil {
+				return err
+			}
+			err = x.file(entry.Name, mode, rc)
+			rc.Close()
+		default:
+			err = x.skip(entry.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// path returns where the archive entry name is extracted, or an empty path
+// for the archive root
+func (x *extractor) path(name string) (string, error) {
+	// Backslashes separate paths on Windows, where they could climb out
+	if strings.Contains(name, `\`) {
+		return "", fmt.Errorf("archive entry '%s' contains a backslash", name)
+	}
+	if err := checkRelative(name); err != nil {
+		return "", fmt.Errorf("archive entry %w", err)
+	}
+	clean := path.Clean(name)
+	if clean == "." {
+		return "", nil
+	}
+	return filepath.Join(x.root, filepath.FromSlash(clean)), nil
+}
+
+// dir creates the directory entry name
+func (x *extractor) dir(name string) error {
+	p, err := x.path(name)
+	if err != nil {
+		return err
+	}
+	if p == "" {
+		p = x.root
+	}
+	return os.MkdirAll(p, 0755)
+}
+
+// file writes the file entry name with the permissions of mode, counting
+// its size against the limit
+func (x *extractor) file(name string, mode os.FileMode, r io.Reader) error {
+	p, err := x.path(name)
+	if err != nil {
+		return err
+	}
+	if p == "" {
+		return fmt.Errorf("archive entry '%s' is not a file", name)
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+
+	// Owners keep access to their files whatever the archive says
+	out, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode.Perm()|0600)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	n, err := io.Copy(out, io.LimitReader(r, x.remaining+1))
+	if err != nil {
+		return err
+	}
+	if x.remaining -= n; x.remaining < 0 {
+		return errArchiveTooLarge
+	}
+	return out.Close()
+}
+
+// skip reports a link or special file entry that is not extracted, after
+// checking that it stays inside the archive
+func (x *extractor) skip(name string) error {
+	if _, err := x.path(name); err != nil {
+		return err
+	}
+	reject(x.source, name, fmt.Errorf("links and special files are not extracted from archives"))
+	return nil
+}
+
+// replaceDir moves src to dir, replacing what dir holds only once src is in
+// place
+func replaceDir(src, dir string) error {
+	old := dir + ".old"
+	os.RemoveAll(old)
+	if _, err := os.Stat(dir); err == nil {
+		if err := os.Rename(dir, old); err != nil {
+			return fmt.Errorf("failed to replace cached template: %w", err)
+		}
+	}
+	if err := os.Rename(src, dir); err != nil {
+		os.Rename(old, dir)
+		return fmt.Errorf("failed to replace cached template: %w", err)
+	}
+	os.RemoveAll(old)
+	return nil
+}
@@ -0,0 +1,171 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultTestProject is the project name a template is tested with when
+// its manifest does not set one
+const DefaultTestProject = "example"
+
+// sampleValue is the value of variables without a test value, default or
+// choices
+const sampleValue = "example"
+
+// Tests is the [tests] section of a manifest
+type Tests struct {
+	// Project is the name of the directory the template is applied to
+	Project string `toml:"project"`
+
+	// Vars are the variable values to test with
+	Vars map[string]string `toml:"vars"`
+
+	// Assertions must all pass on the applied template
+	Assertions []Assertion `toml:"assert"`
+}
+
+// Assertion checks the project created from a template. Exactly one of
+// Exists, Absent, File (with Contains) and Run is set.
+type Assertion struct {
+	// Exists and Absent are slash-separated paths in the project
+	Exists string `toml:"exists"`
+	Absent string `toml:"absent"`
+
+	// File must contain the text Contains
+	File     string `toml:"file"`
+	Contains string `toml:"contains"`
+
+	// Run is a shell command that must succeed in the project within
+	// Timeout; an empty Timeout uses hooks.timeout
+	Run     string `toml:"run"`
+	Timeout string `toml:"timeout"`
+}
+
+// Runner runs a command in dir, stopping it after timeout (0 for the
+// default), and returns its output
+type Runner func(dir, command string, timeout time.Duration) (string, error)
+
+// Validate checks that every assertion has exactly one kind and valid
+// paths and timeouts
+func (t Tests) Validate() error {
+	if t.Project != "" {
+		if err := ValidateName(t.Project); err != nil {
+			return fmt.Errorf("tests: project %w", err)
+		}
+	}
+
+	for i, assertion := range t.Assertions {
+		entry := fmt.Sprintf("tests.assert[%d]", i)
+
+		kinds := 0
+		for _, value := range []string{assertion.Exists, assertion.Absent, assertion.File, assertion.Run} {
+			if value != "" {
+				kinds++
+			}
+		}
+		if kinds != 1 {
+			return fmt.Errorf("%s: set exactly one of exists, absent, file or run", entry)
+		}
+
+		for _, p := range []string{assertion.Exists, assertion.Absent, assertion.File} {
+			if p == "" {
+				continue
+			}
+			if err := checkRelative(p); err != nil {
+				return fmt.Errorf("%s: path %w", entry, err)
+			}
+		}
+		if (assertion.File == "") != (assertion.Contains == "") {
+			return fmt.Errorf("%s: file and contains go together", entry)
+		}
+
+		if assertion.Timeout != "" {
+			if assertion.Run == "" {
+				return fmt.Errorf("%s: timeout only applies to run", entry)
+			}
+			if timeout, err := time.ParseDuration(assertion.Timeout); err != nil {
+				return fmt.Errorf("%s: invalid timeout '%s': %w", entry, assertion.Timeout, err)
+			} else if timeout <= 0 {
+				return fmt.Errorf("%s: timeout must be positive", entry)
+			}
+		}
+	}
+	return nil
+}
+
+// String describes the assertion for reports
+func (a Assertion) String() string {
+	switch {
+	case a.Exists != "":
+		return fmt.Sprintf("%s exists", a.Exists)
+	case a.Absent != "":
+		return fmt.Sprintf("%s is absent", a.Absent)
+	case a.File != "":
+		return fmt.Sprintf("%s contains %q", a.File, a.Contains)
+	default:
+		return fmt.Sprintf("'%s' succeeds", a.Run)
+	}
+}
+
+// Check runs the assertion against the project in dir. Commands are run
+// with run.
+func (a Assertion) Check(dir string, run Runner) error {
+	switch {
+	case a.Exists != "":
+		if _, err := os.Lstat(filepath.Join(dir, filepath.FromSlash(a.Exists))); err != nil {
+			return fmt.Errorf("%s does not exist", a.Exists)
+		}
+	case a.Absent != "":
+		if _, err := os.Lstat(filepath.Join(dir, filepath.FromSlash(a.Absent))); err == nil {
+			return fmt.Errorf("%s exists", a.Absent)
+		}
+	case a.File != "":
+		content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(a.File)))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", a.File, err)
+		}
+		if !strings.Contains(string(content), a.Contains) {
+			return fmt.Errorf("%s does not contain %q", a.File, a.Contains)
+		}
+	case a.Run != "":
+		// The manifest is validated on load
+		timeout, _ := time.ParseDuration(a.Timeout)
+		if output, err := run(dir, a.Run, timeout); err != nil {
+			if output = strings.TrimSpace(output); output != "" {
+				return fmt.Errorf("%w\n%s", err, output)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// SampleVars returns the values to test a template declaring variables
+// with: the test values, then the defaults, the first choice, or a sample
+// value for required variables
+func (t Tests) SampleVars(variables map[string]Variable) map[string]string {
+	vars := make(map[string]string)
+	for name, variable := range variables {
+		switch {
+		case t.Vars[name] != "":
+			vars[name] = t.Vars[name]
+		case variable.Default != "":
+			vars[name] = variable.Default
+		case len(variable.Choices) > 0:
+			vars[name] = variable.Choices[0]
+		case variable.Required:
+			vars[name] = sampleValue
+		}
+	}
+	return vars
+}
@@ -0,0 +1,143 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package templates
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/mochajutsu/mkcd/internal/utils"
+)
+
+// builtinFS holds the first-party templates shipped with mkcd. Go sources
+// in them end in .tmpl, so they are not packages of this module.
+//
+//go:embed all:builtin
+var builtinFS embed.FS
+
+// builtinRoot is the directory of builtinFS holding the templates
+const builtinRoot = "builtin"
+
+// BuiltinNames returns the names of the built-in templates, sorted
+func BuiltinNames() []string {
+	names := []string{}
+	entries, _ := fs.ReadDir(builtinFS, builtinRoot)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsBuiltin reports whether name is a built-in template
+func IsBuiltin(name string) bool {
+	if ValidateName(name) != nil {
+		return false
+	}
+	info, err := fs.Stat(builtinFS, path.Join(builtinRoot, name))
+	return err == nil && info.IsDir()
+}
+
+// BuiltinDir returns a directory holding the built-in template name. The
+// built-in templates are extracted on first use into
+// $XDG_CACHE_HOME/mkcd/builtin or ~/.cache/mkcd/builtin, in a directory
+// named after a hash of their contents so each release has its own copy.
+func BuiltinDir(name string) (string, error) {
+	if !IsBuiltin(name) {
+		return "", fmt.Errorf("no built-in template '%s'", name)
+	}
+
+	cacheHome, err := cacheHome()
+	if err != nil {
+		return "", err
+	}
+	sum, err := builtinHash()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(cacheHome, "mkcd", "builtin", sum)
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if err := extractBuiltin(dir); err != nil {
+			return "", fmt.Errorf("failed to extract built-in templates: %w", err)
+		}
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// extractBuiltin writes the built-in templates into a temporary directory
+// next to dir and then renames it, so an interrupted extraction is never
+// used
+func extractBuiltin(dir string) error {
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return err
+	}
+	tmpDir, err := os.MkdirTemp(filepath.Dir(dir), ".extract-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	root, err := fs.Sub(builtinFS, builtinRoot)
+	if err != nil {
+		return err
+	}
+	err = fs.WalkDir(root, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(tmpDir, filepath.FromSlash(p))
+		if d.IsDir() {
+			return os.MkdirAll(dest, 0755)
+		}
+		content, err := fs.ReadFile(root, p)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(dest, content, 0644)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Another mkcd may have extracted the same templates meanwhile
+	if err := os.Rename(tmpDir, dir); err != nil && !os.IsExist(err) && !utils.IsDirectory(dir) {
+		return err
+	}
+	return nil
+}
+
+// builtinHash returns a short hash of the paths and contents of the
+// built-in templates
+func builtinHash() (string, error) {
+	h := sha256.New()
+	err := fs.WalkDir(builtinFS, builtinRoot, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := fs.ReadFile(builtinFS, p)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", p, len(content))
+		h.Write(content)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read built-in templates: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12], nil
+}
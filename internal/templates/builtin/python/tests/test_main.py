@@ -0,0 +1,6 @@
+from main import main
+
+
+def test_main(capsys):
+    main()
+    assert "Hello" in capsys.readouterr().out
@@ -0,0 +1,219 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package templates
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/mochajutsu/mkcd/internal/project"
+	"github.com/mochajutsu/mkcd/internal/utils"
+)
+
+// IgnoreFile lists, in .gitignore syntax, files that capture leaves out of
+// a template in addition to those ignored by .gitignore
+const IgnoreFile = ".mkcdignore"
+
+// MinProjectNameLength is the shortest project name capture replaces with
+// {{.ProjectName}}; shorter names would match unrelated text
+const MinProjectNameLength = 3
+
+// CaptureResult describes a template captured from a project
+type CaptureResult struct {
+	Dir string
+
+	// Files are the captured files relative to the project, Ignored the
+	// files and directories left out
+	Files   []string
+	Ignored []string
+
+	// Parameterized are the files whose content or path mentioned the
+	// project name
+	Parameterized []string
+}
+
+// captureManifest is the template.toml written by Capture
+type captureManifest struct {
+	Description string        `toml:"description"`
+	Files       []captureFile `toml:"files,omitempty"`
+}
+
+// captureFile is a file rule of the captured manifest
+type captureFile struct {
+	Source string `toml:"source"`
+	Target string `toml:"target,omitempty"`
+	Render *bool  `toml:"render,omitempty"`
+}
+
+// Capture creates the template name from the project in source. Files
+// ignored by .gitignore or .mkcdignore, version control data and the
+// project file are left out. Text files mentioning projectName have it
+// replaced with {{.ProjectName}} and are saved as .tmpl files; paths
+// mentioning it are renamed through the manifest. Other files that contain
+// template delimiters or end in .tmpl are marked to be copied verbatim.
+func Capture(fsOps *utils.FileSystemOperations, templatesDir, name, source, projectName string) (*CaptureResult, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(templatesDir, name)
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("template '%s' already exists in %s", name, templatesDir)
+	}
+	if !utils.IsDirectory(source) {
+		return nil, fmt.Errorf("source directory does not exist: %s", source)
+	}
+	if rel, err := filepath.Rel(source, dir); err == nil && !strings.HasPrefix(rel, "..") {
+		return nil, fmt.Errorf("cannot capture template '%s' inside its own source %s", name, source)
+	}
+	if len(projectName) < MinProjectNameLength {
+		projectName = ""
+	}
+
+	result := &CaptureResult{Dir: dir, Files: []string{}, Ignored: []string{}, Parameterized: []string{}}
+	manifest := captureManifest{Description: fmt.Sprintf("Captured from %s", filepath.Base(source))}
+	verbatim := false
+
+	// Patterns are collected while walking; those of other directories
+	// never match, as each pattern is bound to its directory
+	patterns := []gitignore.Pattern{}
+
+	fsOps.SetSource("template:" + name)
+	if err := fsOps.CreateDirectory(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	err := filepath.Walk(source, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		slashPath := filepath.ToSlash(relPath)
+		components := strings.Split(slashPath, "/")
+
+		if relPath != "." {
+			ignored := fi.Name() == ".git" || fi.Name() == IgnoreFile || slashPath == project.FileName || slashPath == ManifestFile
+			if ignored || gitignore.NewMatcher(patterns).Match(components, fi.IsDir()) {
+				result.Ignored = append(result.Ignored, slashPath)
+				if fi.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+
+		if fi.IsDir() {
+			domain := components
+			if relPath == "." {
+				domain = nil
+			}
+			for _, ignoreFile := range []string{".gitignore", IgnoreFile} {
+				filePatterns, err := readIgnorePatterns(filepath.Join(path, ignoreFile), domain)
+				if err != nil {
+					return err
+				}
+				patterns = append(patterns, filePatterns...)
+			}
+			return nil
+		}
+		if !fi.Mode().IsRegular() {
+			result.Ignored = append(result.Ignored, slashPath)
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", relPath, err)
+		}
+
+		text := !utils.IsBinary(content)
+		mentioned := projectName != "" && text && bytes.Contains(content, []byte(projectName))
+		renamed := projectName != "" && strings.Contains(slashPath, projectName) && !isPattern(slashPath)
+
+		destPath := slashPath
+		var spec *captureFile
+		if mentioned {
+			// Existing delimiters are kept literally, then the name is
+			// replaced; the .tmpl suffix makes the file always render
+			content = []byte(strings.NewReplacer("{{", `{{"{{"}}`, "}}", `{{"}}"}}`, projectName, "{{.ProjectName}}").Replace(string(content)))
+			destPath += TemplateSuffix
+		} else if strings.HasSuffix(slashPath, TemplateSuffix) || (text && bytes.Contains(content, []byte("{{"))) {
+			spec = &captureFile{Source: escapePattern(slashPath), Render: &verbatim}
+		}
+		if renamed {
+			if spec == nil {
+				spec = &captureFile{Source: destPath}
+			}
+			spec.Target = strings.ReplaceAll(slashPath, projectName, "{{.ProjectName}}")
+		}
+
+		if spec != nil {
+			manifest.Files = append(manifest.Files, *spec)
+		}
+		if mentioned || renamed {
+			result.Parameterized = append(result.Parameterized, slashPath)
+		}
+		result.Files = append(result.Files, slashPath)
+		return fsOps.CreateFile(filepath.Join(dir, filepath.FromSlash(destPath)), string(content), fi.Mode().Perm())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture %s into template %s: %w", source, name, err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Template captured by 'mkcd template capture'\n")
+	if err := toml.NewEncoder(&buf).Encode(manifest); err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", ManifestFile, err)
+	}
+	if err := fsOps.CreateFile(filepath.Join(dir, ManifestFile), buf.String(), 0644); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// readIgnorePatterns parses an ignore file whose patterns apply below
+// domain; a missing file has no patterns
+func readIgnorePatterns(path string, domain []string) ([]gitignore.Pattern, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	patterns := []gitignore.Pattern{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		patterns = append(patterns, gitignore.ParsePattern(line, domain))
+	}
+	return patterns, nil
+}
+
+// escapePattern escapes path.Match syntax so a file path matches itself
+func escapePattern(p string) string {
+	var b strings.Builder
+	for _, r := range p {
+		if strings.ContainsRune(`*?[\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
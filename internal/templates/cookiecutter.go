@@ -0,0 +1,409 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/pterm/pterm"
+)
+
+// CookiecutterPrefix marks a template, local or remote, as a cookiecutter
+// template: cookiecutter:gh:audreyr/cookiecutter-pypackage
+const CookiecutterPrefix = "cookiecutter:"
+
+// CookiecutterFile holds the variables of a cookiecutter template
+const CookiecutterFile = "cookiecutter.json"
+
+// cookiecutterExpression matches a Jinja expression, with optional
+// whitespace control
+var cookiecutterExpression = regexp.MustCompile(`\{\{-?\s*(.*?)\s*-?\}\}`)
+
+// cookiecutterDefaults are the cookiecutter variables taken from the mkcd
+// project data when it has a value, so the project keeps its own name
+var cookiecutterDefaults = map[string]func(Data) string{
+	"project_name":              func(d Data) string { return d.ProjectName },
+	"full_name":                 func(d Data) string { return d.Author },
+	"author_name":               func(d Data) string { return d.Author },
+	"author":                    func(d Data) string { return d.Author },
+	"email":                     func(d Data) string { return d.Email },
+	"author_email":              func(d Data) string { return d.Email },
+	"project_short_description": func(d Data) string { return d.Description },
+	"description":               func(d Data) string { return d.Description },
+}
+
+// CookiecutterName returns the template named by a cookiecutter template
+// name, and whether name has CookiecutterPrefix
+func CookiecutterName(name string) (string, bool) {
+	return strings.CutPrefix(name, CookiecutterPrefix)
+}
+
+// cookiecutterContext is the content of cookiecutter.json
+type cookiecutterContext struct {
+	// Vars are the resolved values of the variables
+	Vars map[string]string
+
+	// CopyWithoutRender are the _copy_without_render patterns
+	CopyWithoutRender []string
+}
+
+// applyCookiecutter copies a cookiecutter template into targetPath. Only
+// what mkcd can do without Jinja is supported: {{ cookiecutter.<name> }}
+// with the string methods and filters lower, upper, strip, title,
+// capitalize and replace. Other expressions are copied unchanged with a
+// warning, and hooks are not run. The values are the first choice or the
+// default of every variable, where the mkcd project data and Vars take
+// precedence.
+func (a *Applier) applyCookiecutter(targetPath string, layer Layer, owners map[string]string) error {
+	ctx, err := a.loadCookiecutterContext(layer.Dir)
+	if err != nil {
+		return err
+	}
+
+	root, err := cookiecutterRoot(layer.Dir)
+	if err != nil {
+		return err
+	}
+	if utils.IsDirectory(filepath.Join(layer.Dir, "hooks")) {
+		pterm.Warning.Printf("Template %s has cookiecutter hooks, which mkcd does not run\n", layer.Name)
+	}
+	if a.Verbose {
+		pterm.Debug.Printf("Applying cookiecutter template %s from %s", layer.Name, root)
+	}
+
+	return filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		if relPath == "." {
+			return nil
+		}
+		slashPath := filepath.ToSlash(relPath)
+
+		// Components rendering to nothing leave out what they name
+		components := strings.Split(slashPath, "/")
+		for i, component := range components {
+			rendered, _ := renderCookiecutter(component, ctx.Vars)
+			if strings.TrimSpace(rendered) == "" {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			components[i] = rendered
+		}
+		target := path.Join(components...)
+		if err := checkRelative(target); err != nil {
+			return fmt.Errorf("path rendered from %s %w", slashPath, err)
+		}
+		destRel := filepath.FromSlash(target)
+
+		if info.IsDir() {
+			return a.createDirectory(targetPath, destRel, layer.Name)
+		}
+		if info, err = sourceFile(layer.Dir, p, info); err != nil {
+			reject(layer.Name, slashPath, err)
+			return nil
+		}
+
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return fmt.Errorf("failed to read template file %s: %w", slashPath, err)
+		}
+		if !utils.IsBinary(content) && !ctx.copyWithoutRender(slashPath) {
+			rendered, complete := renderCookiecutter(string(content), ctx.Vars)
+			if !complete || strings.Contains(rendered, "{%") {
+				pterm.Warning.Printf("Template %s: %s uses Jinja features mkcd does not support, copying them unchanged\n", layer.Name, slashPath)
+			}
+			content = []byte(rendered)
+		}
+
+		return a.writeFile(targetPath, destRel, content, info.Mode().Perm(), layer.Name, layer.Name, owners)
+	})
+}
+
+// loadCookiecutterContext reads cookiecutter.json in dir, in order, since
+// defaults may refer to the variables before them
+func (a *Applier) loadCookiecutterContext(dir string) (*cookiecutterContext, error) {
+	contextPath := filepath.Join(dir, CookiecutterFile)
+	data, err := os.ReadFile(contextPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("not a cookiecutter template: %s not found in %s", CookiecutterFile, dir)
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", contextPath, err)
+	}
+
+	ctx := &cookiecutterContext{Vars: map[string]string{}}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("failed to parse %s: expected an object", contextPath)
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", contextPath, err)
+		}
+		name := token.(string)
+
+		var value any
+		if err := decoder.Decode(&value); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", contextPath, err)
+		}
+
+		if name == "_copy_without_render" {
+			patterns, _ := value.([]any)
+			for _, pattern := range patterns {
+				if s, ok := pattern.(string); ok {
+					ctx.CopyWithoutRender = append(ctx.CopyWithoutRender, s)
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(name, "_") {
+			continue
+		}
+
+		if given, ok := a.Vars[name]; ok {
+			ctx.Vars[name] = given
+			continue
+		}
+		if fromData, ok := cookiecutterDefaults[name]; ok && fromData(a.Data) != "" {
+			ctx.Vars[name] = fromData(a.Data)
+			continue
+		}
+
+		// A list offers choices, the first being the default
+		variable := Variable{}
+		if choices, ok := value.([]any); ok {
+			for _, choice := range choices {
+				if s, ok := cookiecutterValue(choice, ctx.Vars); ok {
+					variable.Choices = append(variable.Choices, s)
+				}
+			}
+			value = nil
+			if len(choices) > 0 {
+				value = choices[0]
+			}
+		}
+		var ok bool
+		if variable.Default, ok = cookiecutterValue(value, ctx.Vars); !ok {
+			// Dictionary variables have no single value
+			continue
+		}
+
+		ctx.Vars[name] = variable.Default
+		if a.Ask != nil {
+			if ctx.Vars[name], err = a.Ask(name, variable); err != nil {
+				return nil, fmt.Errorf("variable %s: %w", name, err)
+			}
+		}
+	}
+	return ctx, nil
+}
+
+// cookiecutterValue returns a value of cookiecutter.json as text: strings
+// are rendered with the variables before them, and booleans are printed as
+// Jinja prints Python booleans
+func cookiecutterValue(value any, vars map[string]string) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		rendered, _ := renderCookiecutter(v, vars)
+		return rendered, true
+	case bool:
+		if v {
+			return "True", true
+		}
+		return "False", true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case nil:
+		return "", true
+	default:
+		return "", false
+	}
+}
+
+// copyWithoutRender reports whether a file, or a directory containing it,
+// matches a _copy_without_render pattern
+func (c *cookiecutterContext) copyWithoutRender(slashPath string) bool {
+	for _, pattern := range c.CopyWithoutRender {
+		for p := slashPath; p != "."; p = path.Dir(p) {
+			if ok, _ := path.Match(pattern, p); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cookiecutterRoot returns the directory of a cookiecutter template that
+// becomes the project, the one named with a cookiecutter variable
+func cookiecutterRoot(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template %s: %w", dir, err)
+	}
+
+	roots := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() && strings.Contains(entry.Name(), "{{") && strings.Contains(entry.Name(), "cookiecutter.") {
+			roots = append(roots, entry.Name())
+		}
+	}
+	if len(roots) != 1 {
+		return "", fmt.Errorf("expected one project directory named like {{cookiecutter.project_slug}} in %s, found %d", dir, len(roots))
+	}
+	return filepath.Join(dir, roots[0]), nil
+}
+
+// renderCookiecutter replaces the supported expressions in text with their
+// values. It reports whether every expression was supported; the others
+// are left unchanged.
+func renderCookiecutter(text string, vars map[string]string) (string, bool) {
+	complete := true
+	rendered := cookiecutterExpression.ReplaceAllStringFunc(text, func(match string) string {
+		expression := cookiecutterExpression.FindStringSubmatch(match)[1]
+		value, ok := evalCookiecutter(expression, vars)
+		if !ok {
+			complete = false
+			return match
+		}
+		return value
+	})
+	return rendered, complete
+}
+
+// evalCookiecutter evaluates cookiecutter.<name> followed by method calls
+// such as .lower() and filters such as |replace(' ', '_')
+func evalCookiecutter(expression string, vars map[string]string) (string, bool) {
+	rest, found := strings.CutPrefix(expression, "cookiecutter.")
+	if !found {
+		return "", false
+	}
+
+	end := strings.IndexFunc(rest, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+	if end < 0 {
+		end = len(rest)
+	}
+	value, exists := vars[rest[:end]]
+	if !exists {
+		return "", false
+	}
+	rest = strings.TrimSpace(rest[end:])
+
+	for rest != "" {
+		if rest[0] != '.' && rest[0] != '|' {
+			return "", false
+		}
+		rest = strings.TrimSpace(rest[1:])
+
+		end := strings.IndexFunc(rest, func(r rune) bool { return !unicode.IsLetter(r) && r != '_' })
+		if end < 0 {
+			end = len(rest)
+		}
+		operation := rest[:end]
+		rest = strings.TrimSpace(rest[end:])
+
+		var args []string
+		if strings.HasPrefix(rest, "(") {
+			var ok bool
+			if args, rest, ok = parseCookiecutterArgs(rest[1:]); !ok {
+				return "", false
+			}
+		}
+
+		switch {
+		case operation == "lower" && len(args) == 0:
+			value = strings.ToLower(value)
+		case operation == "upper" && len(args) == 0:
+			value = strings.ToUpper(value)
+		case (operation == "strip" || operation == "trim") && len(args) == 0:
+			value = strings.TrimSpace(value)
+		case operation == "title" && len(args) == 0:
+			value = titleCase(value)
+		case operation == "capitalize" && len(args) == 0:
+			value = capitalize(value)
+		case operation == "replace" && len(args) == 2:
+			value = strings.ReplaceAll(value, args[0], args[1])
+		default:
+			return "", false
+		}
+	}
+	return value, true
+}
+
+// parseCookiecutterArgs parses quoted string arguments up to the closing
+// parenthesis and returns them with the remaining expression
+func parseCookiecutterArgs(s string) ([]string, string, bool) {
+	args := []string{}
+	for {
+		s = strings.TrimSpace(s)
+		if strings.HasPrefix(s, ")") {
+			return args, strings.TrimSpace(s[1:]), true
+		}
+		if len(args) > 0 {
+			if !strings.HasPrefix(s, ",") {
+				return nil, "", false
+			}
+			s = strings.TrimSpace(s[1:])
+		}
+		if s == "" || (s[0] != '\'' && s[0] != '"') {
+			return nil, "", false
+		}
+		end := strings.IndexByte(s[1:], s[0])
+		if end < 0 {
+			return nil, "", false
+		}
+		args = append(args, s[1:end+1])
+		s = s[end+2:]
+	}
+}
+
+// titleCase upper-cases the first letter of every word and lower-cases the
+// others, like Python's str.title
+func titleCase(s string) string {
+	var b strings.Builder
+	previous := ' '
+	for _, r := range s {
+		if unicode.IsLetter(previous) {
+			b.WriteRune(unicode.ToLower(r))
+		} else {
+			b.WriteRune(unicode.ToUpper(r))
+		}
+		previous = r
+	}
+	return b.String()
+}
+
+// capitalize upper-cases the first character and lower-cases the others,
+// like Python's str.capitalize
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + strings.ToLower(s[size:])
+}
@@ -0,0 +1,136 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package templates
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Locator returns the directory of a template by name
+type Locator func(name string) (string, error)
+
+// Chain is a template together with the templates it extends
+type Chain struct {
+	// Layers are the templates of the chain, the base template first
+	Layers []Layer
+
+	// Manifests are the manifests of Layers, empty for templates without one
+	Manifests []*Manifest
+
+	// Manifest is the merge of Manifests
+	Manifest *Manifest
+}
+
+// Resolve follows the extends settings of a template down to its base
+// template. Templates named by extends are found with locate; a template
+// extending itself, directly or through others, is an error.
+func Resolve(layer Layer, locate Locator) (*Chain, error) {
+	chain := &Chain{}
+	seen := make(map[string]bool)
+	names := []string{}
+
+	for {
+		key := layer.Name
+		if IsRemote(key) {
+			key = RemoteURL(key)
+		}
+		names = append(names, layer.Name)
+		if seen[key] {
+			return nil, fmt.Errorf("templates extend each other in a cycle: %s", strings.Join(names, " → "))
+		}
+		seen[key] = true
+
+		manifest, err := LoadManifest(layer.Dir)
+		if err != nil {
+			return nil, err
+		}
+		if manifest == nil {
+			manifest = &Manifest{}
+		}
+
+		// Collected child first, reversed below
+		chain.Layers = append(chain.Layers, layer)
+		chain.Manifests = append(chain.Manifests, manifest)
+
+		if manifest.Extends == "" {
+			break
+		}
+		if locate == nil {
+			return nil, fmt.Errorf("template %s extends %s, which cannot be located here", layer.Name, manifest.Extends)
+		}
+		dir, err := locate(manifest.Extends)
+		if err != nil {
+			return nil, fmt.Errorf("template %s extends %s: %w", layer.Name, manifest.Extends, err)
+		}
+		layer = Layer{Name: manifest.Extends, Dir: dir}
+	}
+
+	for i, j := 0, len(chain.Layers)-1; i < j; i, j = i+1, j-1 {
+		chain.Layers[i], chain.Layers[j] = chain.Layers[j], chain.Layers[i]
+		chain.Manifests[i], chain.Manifests[j] = chain.Manifests[j], chain.Manifests[i]
+	}
+	chain.Manifest = MergeManifests(chain.Manifests)
+	return chain, nil
+}
+
+// MergeManifests merges manifests in order, each overriding the previous
+// ones: the last name, description and version set win, and a variable
+// declared again overrides the description, default, choices and pattern
+// set before, while staying required once any manifest requires it. Hooks
+// add up in order. File rules are not merged, since their sources are
+// relative to the template declaring them.
+func MergeManifests(manifests []*Manifest) *Manifest {
+	merged := &Manifest{Variables: map[string]Variable{}}
+	for _, m := range manifests {
+		if m.Name != "" {
+			merged.Name = m.Name
+		}
+		if m.Description != "" {
+			merged.Description = m.Description
+		}
+		if m.Version != "" {
+			merged.Version = m.Version
+		}
+		if m.Language != "" {
+			merged.Language = m.Language
+		}
+		if m.Framework != "" {
+			merged.Framework = m.Framework
+		}
+		for _, tag := range m.Tags {
+			if !slices.Contains(merged.Tags, tag) {
+				merged.Tags = append(merged.Tags, tag)
+			}
+		}
+
+		for name, variable := range m.Variables {
+			existing, exists := merged.Variables[name]
+			if !exists {
+				merged.Variables[name] = variable
+				continue
+			}
+			if variable.Description != "" {
+				existing.Description = variable.Description
+			}
+			if variable.Default != "" {
+				existing.Default = variable.Default
+			}
+			if len(variable.Choices) > 0 {
+				existing.Choices = variable.Choices
+			}
+			if variable.Pattern != "" {
+				existing.Pattern = variable.Pattern
+			}
+			existing.Required = existing.Required || variable.Required
+			merged.Variables[name] = existing
+		}
+		merged.Hooks = append(merged.Hooks, m.Hooks...)
+	}
+	return merged
+}
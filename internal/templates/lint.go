@@ -0,0 +1,237 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/mochajutsu/mkcd/internal/utils"
+)
+
+// Problem severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Problem is an issue Lint found in a template
+type Problem struct {
+	Severity string
+
+	// Template is the template with the problem, one extended by the
+	// linted template or the linted template itself
+	Template string
+
+	// Path is the slash-separated template file, or the manifest
+	Path    string
+	Message string
+}
+
+// varReference matches the uses of template variables, {{.Vars.name}} and
+// {{index .Vars "name"}}
+var varReference = regexp.MustCompile(`\.Vars\.([A-Za-z_][A-Za-z0-9_]*)|index\s+\.Vars\s+"([^"]*)"`)
+
+// lintText is text of a template that is rendered when it is applied
+type lintText struct {
+	template string
+	path     string
+	name     string
+	text     string
+
+	// optional text is copied unchanged when it does not render
+	optional bool
+
+	// condition text decides whether a file or hook is used
+	condition bool
+}
+
+// Lint checks the template of layer without applying it: the manifests of
+// the template and those it extends, the variables the rendered text uses
+// and the manifests declare, files that are never written, and whether all
+// rendered text renders with a.Data and sample values for the variables.
+// Problems are returned in the order of the templates and their files.
+func (a *Applier) Lint(layer Layer) ([]Problem, error) {
+	if layer.Cookiecutter {
+		return nil, fmt.Errorf("cookiecutter templates cannot be linted")
+	}
+
+	partials, err := LoadPartials(a.Partials)
+	if err != nil {
+		return nil, err
+	}
+	a.partials = partials
+
+	chain, err := Resolve(layer, a.Locate)
+	if err != nil {
+		return []Problem{{Severity: SeverityError, Template: layer.Name, Path: ManifestFile, Message: err.Error()}}, nil
+	}
+
+	problems := []Problem{}
+	texts := []lintText{}
+	for i, inherited := range chain.Layers {
+		layerProblems, layerTexts, err := lintLayer(inherited, chain.Manifests[i], a.RenderAll)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, layerProblems...)
+		texts = append(texts, layerTexts...)
+	}
+
+	// Undeclared variables are reported once here rather than as render
+	// errors of every text using them
+	declared := chain.Manifest.Variables
+	vars := chain.Manifests[len(chain.Manifests)-1].Tests.SampleVars(declared)
+	for name := range declared {
+		if _, exists := vars[name]; !exists {
+			vars[name] = ""
+		}
+	}
+	used := make(map[string]bool)
+	for _, text := range texts {
+		for _, match := range varReference.FindAllStringSubmatch(text.text, -1) {
+			name := match[1] + match[2]
+			if used[name] {
+				continue
+			}
+			used[name] = true
+			if _, exists := declared[name]; !exists {
+				problems = append(problems, Problem{SeverityError, text.template, text.path, fmt.Sprintf("uses the undeclared variable '%s'", name)})
+				vars[name] = sampleValue
+			}
+		}
+	}
+
+	data := a.Data
+	data.Vars = vars
+	for _, text := range texts {
+		rendered, err := a.render(text.name, text.text, data)
+		switch {
+		case err != nil && text.optional:
+			problems = append(problems, Problem{SeverityWarning, text.template, text.path, fmt.Sprintf("is copied unchanged, as it does not render: %v", err)})
+		case err != nil:
+			problems = append(problems, Problem{SeverityError, text.template, text.path, err.Error()})
+		case text.condition && !strings.Contains(text.text, "{{") && !Holds(rendered):
+			problems = append(problems, Problem{SeverityWarning, text.template, text.path, fmt.Sprintf("%s never holds", text.name)})
+		}
+	}
+
+	for i, inherited := range chain.Layers {
+		for _, name := range utils.SortedKeys(chain.Manifests[i].Variables) {
+			if !used[name] && !declaredLater(chain.Manifests[i+1:], name) {
+				problems = append(problems, Problem{SeverityWarning, inherited.Name, ManifestFile, fmt.Sprintf("variable '%s' is never used", name)})
+			}
+		}
+	}
+	return problems, nil
+}
+
+// lintLayer checks the files and file rules of a single template and
+// returns the text rendered when it is applied
+func lintLayer(layer Layer, manifest *Manifest, renderAll bool) ([]Problem, []lintText, error) {
+	problems := []Problem{}
+	texts := []lintText{}
+	problem := func(severity, path, format string, args ...any) {
+		problems = append(problems, Problem{severity, layer.Name, path, fmt.Sprintf(format, args...)})
+	}
+
+	files, err := templateFiles(layer.Dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// File rules are used by the first rule matching a file, so a rule
+	// whose files all match earlier rules is never used
+	claimed := make(map[string]bool)
+	for i, spec := range manifest.Files {
+		matched, unclaimed := false, false
+		for _, file := range files {
+			if ok, _ := path.Match(spec.Source, file); ok {
+				matched = true
+				if !claimed[file] {
+					claimed[file], unclaimed = true, true
+				}
+			}
+		}
+		entry := fmt.Sprintf("files[%d]", i)
+		if matched && !unclaimed {
+			problem(SeverityWarning, ManifestFile, "%s is never used, as earlier rules match all of its files", entry)
+		}
+		if spec.When != "" {
+			texts = append(texts, lintText{template: layer.Name, path: ManifestFile, name: "when of " + entry, text: spec.When, condition: true})
+		}
+	}
+
+	written := make(map[string]string)
+	for _, file := range files {
+		if file == ManifestFile {
+			continue
+		}
+		p := filepath.Join(layer.Dir, filepath.FromSlash(file))
+		info, err := os.Lstat(p)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read template file %s: %w", file, err)
+		}
+		if _, err := sourceFile(layer.Dir, p, info); err != nil {
+			problem(SeverityWarning, file, "is never copied: %v", err)
+			continue
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read template file %s: %w", file, err)
+		}
+
+		target := file
+		spec := manifest.FileFor(file)
+		switch {
+		case spec != nil && spec.Render != nil && !*spec.Render:
+			// Copied verbatim under its own name
+		case spec != nil && spec.Render != nil, strings.HasSuffix(file, TemplateSuffix):
+			texts = append(texts, lintText{template: layer.Name, path: file, name: file, text: string(content)})
+			target = strings.TrimSuffix(file, TemplateSuffix)
+		case renderAll && bytes.Contains(content, []byte("{{")) && !utils.IsBinary(content):
+			texts = append(texts, lintText{template: layer.Name, path: file, name: file, text: string(content), optional: true})
+		}
+		if spec != nil && spec.Target != "" {
+			texts = append(texts, lintText{template: layer.Name, path: file, name: "target of " + file, text: spec.Target})
+			target = spec.Target
+		}
+
+		// Rendered targets may differ, so only fixed ones are compared
+		if !strings.Contains(target, "{{") {
+			if other, exists := written[target]; exists {
+				problem(SeverityWarning, file, "is written to %s like %s, which one wins depends on their order", target, other)
+			}
+			written[target] = file
+		}
+	}
+
+	for i, hook := range manifest.Hooks {
+		entry := fmt.Sprintf("hooks[%d]", i)
+		texts = append(texts, lintText{template: layer.Name, path: ManifestFile, name: entry, text: hook.Run})
+		if hook.When != "" {
+			texts = append(texts, lintText{template: layer.Name, path: ManifestFile, name: "when of " + entry, text: hook.When, condition: true})
+		}
+	}
+	return problems, texts, nil
+}
+
+// declaredLater reports whether any of manifests declares the variable
+// name, so it is reported for the last template declaring it
+func declaredLater(manifests []*Manifest, name string) bool {
+	for _, m := range manifests {
+		if _, exists := m.Variables[name]; exists {
+			return true
+		}
+	}
+	return false
+}
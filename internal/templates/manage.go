@@ -0,0 +1,196 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mochajutsu/mkcd/internal/utils"
+)
+
+// Info describes a template in the templates directory
+type Info struct {
+	Name string
+	Dir  string
+
+	// Files are the template's files relative to Dir, in lexical order
+	Files []string
+
+	// Rendered counts the files rendered with text/template
+	Rendered int
+	Size     int64
+	Version  string
+
+	// Manifest is the template's template.toml, nil without one
+	Manifest *Manifest
+
+	// Builtin is set for built-in templates not overridden in the
+	// templates directory
+	Builtin bool
+}
+
+// ValidateName checks that name can be used as a template directory name.
+// Template lists are comma-separated, so names cannot contain commas.
+func ValidateName(name string) error {
+	if err := utils.ValidateDirectoryName(name); err != nil {
+		return fmt.Errorf("invalid template name: %w", err)
+	}
+	if strings.HasPrefix(name, ".") {
+		return fmt.Errorf("invalid template name '%s': hidden directories are not listed as templates", name)
+	}
+	if strings.Contains(name, ",") {
+		return fmt.Errorf("invalid template name '%s': commas separate layered templates", name)
+	}
+	return nil
+}
+
+// Dir returns the directory of an existing template in templatesDir
+func Dir(templatesDir, name string) (string, error) {
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+	dir := filepath.Join(templatesDir, name)
+	if !utils.IsDirectory(dir) {
+		if IsBuiltin(name) {
+			return "", fmt.Errorf("template '%s' is built in; create a template named '%s' in %s to override it", name, name, templatesDir)
+		}
+		return "", fmt.Errorf("template '%s' not found in %s", name, templatesDir)
+	}
+	return dir, nil
+}
+
+// Find returns the directory of a template to apply: the template in
+// templatesDir, or else the built-in template of that name
+func Find(templatesDir, name string) (string, error) {
+	dir, err := Dir(templatesDir, name)
+	if err != nil && IsBuiltin(name) {
+		return BuiltinDir(name)
+	}
+	return dir, err
+}
+
+// Inspect returns the files, size and version of a template
+func Inspect(templatesDir, name string) (*Info, error) {
+	dir, err := Find(templatesDir, name)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := LoadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &Info{Name: name, Dir: dir, Files: []string{}, Manifest: manifest}
+	info.Builtin = dir != filepath.Join(templatesDir, name)
+	err = filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			if fi.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		slashPath := filepath.ToSlash(relPath)
+		info.Files = append(info.Files, slashPath)
+		info.Size += fi.Size()
+
+		rendered := strings.HasSuffix(relPath, TemplateSuffix)
+		if manifest != nil {
+			if spec := manifest.FileFor(slashPath); spec != nil && spec.Render != nil {
+				rendered = *spec.Render
+			}
+		}
+		if rendered {
+			info.Rendered++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template %s: %w", name, err)
+	}
+
+	if info.Version, err = Version(dir); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// Create adds an empty template, or a copy of the directory from when it
+// is not empty. Version control data in from is not copied.
+func Create(fsOps *utils.FileSystemOperations, templatesDir, name, from string) (string, error) {
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+	dir := filepath.Join(templatesDir, name)
+	if _, err := os.Stat(dir); err == nil {
+		return "", fmt.Errorf("template '%s' already exists in %s", name, templatesDir)
+	}
+
+	if from == "" {
+		return dir, fsOps.CreateDirectory(dir, 0755)
+	}
+	if !utils.IsDirectory(from) {
+		return "", fmt.Errorf("source directory does not exist: %s", from)
+	}
+	if rel, err := filepath.Rel(from, dir); err == nil && !strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("cannot create template '%s' inside its own source %s", name, from)
+	}
+
+	fsOps.SetSource("template:" + name)
+	err := filepath.Walk(from, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+
+		if fi.IsDir() {
+			if fi.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return fsOps.CreateDirectory(filepath.Join(dir, relPath), 0755)
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", relPath, err)
+		}
+		return fsOps.CreateFile(filepath.Join(dir, relPath), string(content), fi.Mode().Perm())
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to copy %s into template %s: %w", from, name, err)
+	}
+	return dir, nil
+}
+
+// Delete removes a template and all of its files
+func Delete(templatesDir, name string) error {
+	dir, err := Dir(templatesDir, name)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to delete template %s: %w", name, err)
+	}
+	return nil
+}
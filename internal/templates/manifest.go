@@ -0,0 +1,484 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/mochajutsu/mkcd/internal/utils"
+)
+
+// ManifestFile is the optional file in a template's root describing the
+// template; it is never copied into projects
+const ManifestFile = "template.toml"
+
+// variableNamePattern matches names usable as {{.Vars.name}}
+var variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Manifest is the content of a template.toml file:
+//
+//	name = "go-service"
+//	description = "HTTP service in Go"
+//	extends = "basic-dev"
+//	language = "go"
+//	framework = "chi"
+//	tags = ["http", "service"]
+//
+//	[variables.port]
+//	description = "Port the service listens on"
+//	default = "8080"
+//	pattern = "[0-9]+"
+//
+//	[variables.database]
+//	choices = ["postgres", "sqlite"]
+//
+//	[[files]]
+//	source = "main.go"
+//	target = "cmd/{{.ProjectName}}/main.go"
+//	render = true
+//
+//	[[files]]
+//	source = "scripts/*.sh"
+//	executable = true
+//
+//	[[files]]
+//	source = "Dockerfile"
+//	when = "{{.Vars.docker}}"
+//
+//	[[hooks]]
+//	run = "go mod tidy"
+//	timeout = "2m"
+//
+//	[tests]
+//	vars = { port = "9090" }
+//
+//	[[tests.assert]]
+//	exists = "go.mod"
+//
+//	[[tests.assert]]
+//	run = "go build ./..."
+type Manifest struct {
+	Name        string `toml:"name"`
+	Description string `toml:"description"`
+	Version     string `toml:"version"`
+
+	// Extends names a template whose files are applied first, so this
+	// template only provides what it adds or overrides
+	Extends string `toml:"extends"`
+
+	// Language, Framework and Tags describe the template for 'mkcd
+	// template search'
+	Language  string   `toml:"language"`
+	Framework string   `toml:"framework"`
+	Tags      []string `toml:"tags"`
+
+	// Variables are available to rendered files as {{.Vars.<name>}}
+	Variables map[string]Variable `toml:"variables"`
+
+	// Files override how template files are copied; files not listed
+	// follow the default rules
+	Files []FileSpec `toml:"files"`
+
+	// Hooks are commands run in the project once the template is applied
+	Hooks []Hook `toml:"hooks"`
+
+	// Tests describe how 'mkcd template test' checks the template
+	Tests Tests `toml:"tests"`
+}
+
+// Variable is a value a template expects from the user
+type Variable struct {
+	Description string `toml:"description"`
+	Default     string `toml:"default"`
+
+	// Required variables must have a value or a default
+	Required bool `toml:"required"`
+
+	// Choices are the only values allowed, offered as a selection
+	Choices []string `toml:"choices"`
+
+	// Pattern is a regular expression values must match as a whole
+	Pattern string `toml:"pattern"`
+}
+
+// Asker asks the user for the value of a variable that was not given one;
+// it returns the default to keep it
+type Asker func(name string, variable Variable) (string, error)
+
+// Check reports whether value is allowed by the variable's choices and
+// pattern. An empty value is only an error for required variables.
+func (v Variable) Check(value string) error {
+	if value == "" {
+		if v.Required {
+			return fmt.Errorf("a value is required")
+		}
+		return nil
+	}
+	if len(v.Choices) > 0 && !slices.Contains(v.Choices, value) {
+		return fmt.Errorf("'%s' is not one of %s", value, strings.Join(v.Choices, ", "))
+	}
+	if v.Pattern != "" {
+		re, err := regexp.Compile(`^(?:` + v.Pattern + `)$`)
+		if err != nil {
+			return fmt.Errorf("invalid pattern '%s': %w", v.Pattern, err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("'%s' does not match %s", value, v.Pattern)
+		}
+	}
+	return nil
+}
+
+// Filter selects templates by their name and manifest; empty fields match
+// every template. Comparisons ignore case.
+type Filter struct {
+	// Query is found in the name, description, language, framework or
+	// tags
+	Query string
+
+	Language  string
+	Framework string
+	Tag       string
+}
+
+// Matches reports whether the template name with manifest m, which may be
+// nil, passes the filter
+func (f Filter) Matches(name string, m *Manifest) bool {
+	if m == nil {
+		m = &Manifest{}
+	}
+	if f.Language != "" && !strings.EqualFold(f.Language, m.Language) {
+		return false
+	}
+	if f.Framework != "" && !strings.EqualFold(f.Framework, m.Framework) {
+		return false
+	}
+	if f.Tag != "" && !slices.ContainsFunc(m.Tags, func(tag string) bool { return strings.EqualFold(f.Tag, tag) }) {
+		return false
+	}
+	if f.Query == "" {
+		return true
+	}
+
+	query := strings.ToLower(f.Query)
+	for _, field := range append([]string{name, m.Description, m.Language, m.Framework}, m.Tags...) {
+		if strings.Contains(strings.ToLower(field), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// FileSpec describes how the template files matching Source are written
+type FileSpec struct {
+	// Source is a slash-separated path relative to the template, or a
+	// path.Match pattern such as "scripts/*.sh"
+	Source string `toml:"source"`
+
+	// Target is where a single source file is written in the project. It
+	// is rendered, so it may use {{.ProjectName}} and the variables.
+	Target string `toml:"target"`
+
+	// Render forces rendering (true) or a verbatim copy (false); unset
+	// follows the .tmpl suffix and templates.render
+	Render *bool `toml:"render"`
+
+	// Mode sets the permissions of the written files, e.g. "0600"; unset
+	// keeps the permissions of the template file
+	Mode string `toml:"mode"`
+
+	// Executable adds the execute permissions matching the read
+	// permissions, e.g. 0644 becomes 0755
+	Executable bool `toml:"executable"`
+
+	// When is a condition rendered like Target; the files are left out
+	// when it gives "", "false", "0" or "no". With a condition, Source may
+	// also name a directory, which is left out with everything in it.
+	When string `toml:"when"`
+}
+
+// FileMode returns the permissions of a file written for the spec from a
+// template file with the permissions source
+func (spec *FileSpec) FileMode(source os.FileMode) os.FileMode {
+	mode := source.Perm()
+	if spec == nil {
+		return mode
+	}
+	if spec.Mode != "" {
+		// The manifest is validated on load
+		mode, _ = utils.ParseFileMode(spec.Mode)
+	}
+	if spec.Executable {
+		mode |= (mode & 0444) >> 2
+	}
+	return mode
+}
+
+// Hook is a command a template runs in the project after its files are
+// written
+type Hook struct {
+	// Run is a shell command. It is rendered, so it may use
+	// {{.ProjectName}} and the variables.
+	Run string `toml:"run"`
+
+	// Timeout bounds the command, e.g. "10m"; empty uses hooks.timeout
+	Timeout string `toml:"timeout"`
+
+	// When is a condition like that of FileSpec; the hook is skipped when
+	// it does not hold
+	When string `toml:"when"`
+}
+
+// Holds reports whether a rendered condition holds: anything but "",
+// "false", "0" or "no", ignoring case and surrounding space
+func Holds(condition string) bool {
+	switch strings.ToLower(strings.TrimSpace(condition)) {
+	case "", "false", "0", "no":
+		return false
+	}
+	return true
+}
+
+// LoadManifest reads the manifest of the template in dir and validates it.
+// A template without a manifest yields nil.
+func LoadManifest(dir string) (*Manifest, error) {
+	manifestPath := filepath.Join(dir, ManifestFile)
+	var m Manifest
+	md, err := toml.DecodeFile(manifestPath, &m)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to parse %s: %w", manifestPath, err)
+	}
+
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, key := range undecoded {
+			keys[i] = key.String()
+		}
+		return nil, fmt.Errorf("%s: unknown keys %s (expected name, description, version, extends, language, framework, tags, [variables.<name>], [[files]], [[hooks]] and [tests])", manifestPath, strings.Join(keys, ", "))
+	}
+
+	if err := m.Validate(dir); err != nil {
+		return nil, fmt.Errorf("%s: %w", manifestPath, err)
+	}
+	return &m, nil
+}
+
+// Validate checks the manifest of the template in dir: variable names,
+// relative paths that stay inside the template and the project, and
+// sources that match files of the template
+func (m *Manifest) Validate(dir string) error {
+	if m.Extends != "" && !IsRemote(m.Extends) {
+		if err := ValidateName(m.Extends); err != nil {
+			return fmt.Errorf("extends: %w", err)
+		}
+	}
+
+	for name, variable := range m.Variables {
+		if !variableNamePattern.MatchString(name) {
+			return fmt.Errorf("variable '%s' must be a letter or underscore followed by letters, digits or underscores", name)
+		}
+		if _, err := regexp.Compile(variable.Pattern); err != nil {
+			return fmt.Errorf("variable '%s': invalid pattern '%s': %w", name, variable.Pattern, err)
+		}
+		if variable.Default != "" {
+			if err := variable.Check(variable.Default); err != nil {
+				return fmt.Errorf("variable '%s': default %w", name, err)
+			}
+		}
+	}
+
+	files, err := templateFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	for i, spec := range m.Files {
+		entry := fmt.Sprintf("files[%d]", i)
+		if spec.Source == "" {
+			return fmt.Errorf("%s: source is required", entry)
+		}
+		if err := checkRelative(spec.Source); err != nil {
+			return fmt.Errorf("%s: source %w", entry, err)
+		}
+		if _, err := path.Match(spec.Source, ""); err != nil {
+			return fmt.Errorf("%s: source '%s' is not a valid pattern: %w", entry, spec.Source, err)
+		}
+		if seen[spec.Source] {
+			return fmt.Errorf("%s: source '%s' is listed twice", entry, spec.Source)
+		}
+		seen[spec.Source] = true
+
+		if spec.Source == ManifestFile {
+			return fmt.Errorf("%s: %s itself is never copied", entry, ManifestFile)
+		}
+
+		if spec.Mode != "" {
+			if _, err := utils.ParseFileMode(spec.Mode); err != nil {
+				return fmt.Errorf("%s: %w", entry, err)
+			}
+		}
+
+		if spec.Target != "" {
+			if isPattern(spec.Source) {
+				return fmt.Errorf("%s: target needs a single source file, not the pattern '%s'", entry, spec.Source)
+			}
+			if err := checkRelative(spec.Target); err != nil {
+				return fmt.Errorf("%s: target %w", entry, err)
+			}
+		}
+
+		matched := false
+		for _, file := range files {
+			if ok, _ := path.Match(spec.Source, file); ok || (spec.When != "" && matchesParent(spec.Source, file)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: source '%s' matches no file in the template", entry, spec.Source)
+		}
+	}
+
+	for i, hook := range m.Hooks {
+		entry := fmt.Sprintf("hooks[%d]", i)
+		if strings.TrimSpace(hook.Run) == "" {
+			return fmt.Errorf("%s: run is required", entry)
+		}
+		if hook.Timeout != "" {
+			if timeout, err := time.ParseDuration(hook.Timeout); err != nil {
+				return fmt.Errorf("%s: invalid timeout '%s': %w", entry, hook.Timeout, err)
+			} else if timeout <= 0 {
+				return fmt.Errorf("%s: timeout must be positive", entry)
+			}
+		}
+	}
+
+	return m.Tests.Validate()
+}
+
+// ResolveVariables returns the value of every declared variable: the given
+// value if there is one, otherwise the answer of ask, or the default when
+// ask is nil. Values must pass the variable's checks; required variables
+// left without a value are reported together.
+func (m *Manifest) ResolveVariables(values map[string]string, ask Asker) (map[string]string, error) {
+	resolved := make(map[string]string)
+	missing := []string{}
+
+	names := make([]string, 0, len(m.Variables))
+	for name := range m.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		variable := m.Variables[name]
+		value, given := values[name]
+		if !given {
+			value = variable.Default
+			if ask != nil {
+				var err error
+				if value, err = ask(name, variable); err != nil {
+					return nil, fmt.Errorf("variable %s: %w", name, err)
+				}
+			}
+		}
+		if value == "" && variable.Required {
+			missing = append(missing, name)
+			continue
+		}
+		if err := variable.Check(value); err != nil {
+			return nil, fmt.Errorf("variable %s: %w", name, err)
+		}
+		resolved[name] = value
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("required template variables have no value: %s (answer the prompts on a terminal, or give them a default in %s)", strings.Join(missing, ", "), ManifestFile)
+	}
+	return resolved, nil
+}
+
+// FileFor returns the first file spec whose source matches relPath, a
+// slash-separated path relative to the template, or nil
+func (m *Manifest) FileFor(relPath string) *FileSpec {
+	for i := range m.Files {
+		if ok, _ := path.Match(m.Files[i].Source, relPath); ok {
+			return &m.Files[i]
+		}
+	}
+	return nil
+}
+
+// checkRelative reports paths that are empty, absolute or leave their root
+func checkRelative(p string) error {
+	if p == "" {
+		return fmt.Errorf("is empty")
+	}
+	if path.IsAbs(p) || filepath.IsAbs(p) || filepath.VolumeName(p) != "" {
+		return fmt.Errorf("'%s' must be relative", p)
+	}
+	if clean := path.Clean(p); clean == ".." || strings.HasPrefix(clean, "../") {
+		return fmt.Errorf("'%s' must not leave the template directory", p)
+	}
+	return nil
+}
+
+// isPattern reports whether a manifest source uses path.Match syntax
+func isPattern(source string) bool {
+	return strings.ContainsAny(source, `*?[\`)
+}
+
+// matchesParent reports whether pattern matches a directory containing the
+// slash-separated file
+func matchesParent(pattern, file string) bool {
+	for dir := path.Dir(file); dir != "."; dir = path.Dir(dir) {
+		if ok, _ := path.Match(pattern, dir); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// templateFiles returns the slash-separated paths of the files in a
+// template, skipping version control data
+func templateFiles(dir string) ([]string, error) {
+	files := []string{}
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		relPath, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(relPath))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template %s: %w", dir, err)
+	}
+	return files, nil
+}
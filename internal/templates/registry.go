@@ -0,0 +1,42 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package templates
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// ResolveSource returns the source of the template name: the Git
+// repository registry maps it to, unless templatesDir has a template of
+// that name. Other names are returned unchanged; the cookiecutter: prefix
+// is kept.
+func ResolveSource(templatesDir string, registry map[string]string, name string) string {
+	source, cookiecutter := CookiecutterName(name)
+	url, listed := registry[source]
+	if !listed || IsRemote(source) {
+		return name
+	}
+	if _, err := os.Stat(filepath.Join(templatesDir, source)); err == nil {
+		return name
+	}
+	if cookiecutter {
+		return CookiecutterPrefix + url
+	}
+	return url
+}
+
+// Installed reports whether the remote or archive template source has a
+// cached copy
+func Installed(source string) bool {
+	dir, err := LocalDir("", source)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(dir)
+	return err == nil
+}
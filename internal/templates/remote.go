@@ -0,0 +1,184 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package templates
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/mochajutsu/mkcd/internal/git"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/pterm/pterm"
+)
+
+// RemoteTemplatesTTL is how long a cached remote template is used before
+// templates.auto_update fetches it again
+const RemoteTemplatesTTL = time.Hour
+
+// remoteCloneTimeout bounds cloning a remote template
+const remoteCloneTimeout = 2 * time.Minute
+
+// GitHubPrefix is the shorthand for templates hosted on GitHub:
+// gh:org/repo stands for https://github.com/org/repo.git
+const GitHubPrefix = "gh:"
+
+// remotePrefixes mark template names that are Git repository URLs
+var remotePrefixes = []string{GitHubPrefix, "https://", "http://", "git://", "ssh://", "git@", "file://"}
+
+// IsRemote reports whether a template name is a Git repository URL rather
+// than a directory in the templates directory
+func IsRemote(name string) bool {
+	for _, prefix := range remotePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoteURL expands the gh: shorthand of a remote template into its URL
+func RemoteURL(name string) string {
+	if repo, found := strings.CutPrefix(name, GitHubPrefix); found {
+		return "https://github.com/" + strings.TrimSuffix(repo, ".git") + ".git"
+	}
+	return name
+}
+
+// RemoteCacheDir returns where a remote template is cloned, inside
+// $XDG_CACHE_HOME/mkcd/templates or ~/.cache/mkcd/templates. The directory
+// is named after the repository and a hash of its URL.
+func RemoteCacheDir(name string) (string, error) {
+	cacheHome, err := cacheHome()
+	if err != nil {
+		return "", err
+	}
+
+	url := RemoteURL(name)
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(cacheHome, "mkcd", "templates", remoteRepoName(url)+"-"+hex.EncodeToString(sum[:])[:12]), nil
+}
+
+// cacheHome returns $XDG_CACHE_HOME, or ~/.cache when it is not set
+func cacheHome() (string, error) {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return dir, nil
+	}
+	homeDir, err := homedir.Dir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".cache"), nil
+}
+
+// LocalDir returns the directory holding a template: its clone or extracted
+// copy in the cache for remote and archive templates, its directory in
+// templatesDir otherwise, or the built-in template it does not override.
+// Remote and archive templates are not fetched. CookiecutterPrefix is
+// ignored.
+func LocalDir(templatesDir, name string) (string, error) {
+	name, _ = CookiecutterName(name)
+	if IsArchive(name) {
+		return ArchiveCacheDir(name)
+	}
+	if IsRemote(name) {
+		return RemoteCacheDir(name)
+	}
+	dir := filepath.Join(templatesDir, name)
+	if !utils.IsDirectory(dir) && IsBuiltin(name) {
+		return BuiltinDir(name)
+	}
+	return dir, nil
+}
+
+// FetchRemote returns the local clone of a remote template, cloning it on
+// first use. A cached clone is fetched again when refresh is set, or with
+// autoUpdate once it is older than RemoteTemplatesTTL; if that fails, e.g.
+// offline, the cached clone is used. Progress goes to progress if not nil.
+//
+// A template pinned to ref, a commit hash, tag or branch, is cloned with
+// that ref checked out. Its cached clone is kept while it is at ref and
+// cloned again otherwise; a clone at another ref is never used.
+func FetchRemote(name, ref string, autoUpdate, refresh bool, progress io.Writer) (string, error) {
+	url := RemoteURL(name)
+	dir, err := RemoteCacheDir(name)
+	if err != nil {
+		return "", err
+	}
+
+	info, statErr := os.Stat(dir)
+	if statErr == nil {
+		stale := autoUpdate && time.Since(info.ModTime()) >= RemoteTemplatesTTL
+		if ref != "" {
+			stale = !git.AtRef(dir, ref)
+		}
+		if !refresh && !stale {
+			return dir, nil
+		}
+	}
+
+	if err := cloneRemote(url, ref, dir, progress); err != nil {
+		if statErr == nil && (ref == "" || git.AtRef(dir, ref)) {
+			pterm.Warning.Printf("Failed to update template %s, using cached copy: %v\n", url, err)
+			// Keep using the copy for another TTL instead of retrying every time
+			now := time.Now()
+			_ = os.Chtimes(dir, now, now)
+			return dir, nil
+		}
+		return "", err
+	}
+	return dir, nil
+}
+
+// cloneRemote clones url, at ref unless it is empty, next to dir and then
+// replaces dir, so a failed clone never damages the cached copy
+func cloneRemote(url, ref, dir string, progress io.Writer) error {
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return fmt.Errorf("failed to create template cache: %w", err)
+	}
+	tmpDir, err := os.MkdirTemp(filepath.Dir(dir), ".clone-")
+	if err != nil {
+		return fmt.Errorf("failed to create template cache: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), remoteCloneTimeout)
+	defer cancel()
+
+	clone := filepath.Join(tmpDir, "repo")
+	if ref != "" {
+		err = git.CloneAt(ctx, url, clone, ref, progress)
+	} else {
+		err = git.CloneShallow(ctx, url, clone, progress)
+	}
+	if err != nil {
+		return err
+	}
+
+	return replaceDir(clone, dir)
+}
+
+// remoteRepoName returns the repository name of a Git URL, e.g. "tpl" for
+// https://github.com/org/tpl.git or git@host:org/tpl.git
+func remoteRepoName(url string) string {
+	url = strings.TrimSuffix(strings.TrimRight(url, "/"), ".git")
+	if i := strings.LastIndexAny(url, "/:"); i >= 0 {
+		url = url[i+1:]
+	}
+	if url == "" {
+		return "template"
+	}
+	return path.Clean(url)
+}
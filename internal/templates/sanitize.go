@@ -0,0 +1,105 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pterm/pterm"
+)
+
+// Templates, remote ones in particular, are not trusted: a file may be a
+// symlink to ~/.ssh/id_ed25519, and a rendered path may point anywhere.
+// The entries that would read or write outside their directory are skipped
+// and reported.
+
+// sourceFile returns the file info of the template file p below root, as
+// it will be read. Symlinks are followed when they resolve to a file inside
+// root; links outside it, to directories and broken links are rejected, as
+// are special files.
+func sourceFile(root, p string, info os.FileInfo) (os.FileInfo, error) {
+	if info.Mode().IsRegular() {
+		return info, nil
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		return nil, fmt.Errorf("not a regular file")
+	}
+
+	resolved, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		return nil, fmt.Errorf("broken symlink")
+	}
+	resolvedRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return nil, err
+	}
+	if !within(resolvedRoot, resolved) {
+		return nil, fmt.Errorf("symlink to %s outside the template", resolved)
+	}
+	target, err := os.Stat(resolved)
+	if err != nil || !target.Mode().IsRegular() {
+		return nil, fmt.Errorf("symlink to a directory or special file")
+	}
+	return target, nil
+}
+
+// destination returns where the relative path relPath is written below
+// targetPath. Paths leaving targetPath, directly or through a symlink
+// already in it, are rejected.
+func destination(targetPath, relPath string) (string, error) {
+	if err := checkRelative(filepath.ToSlash(relPath)); err != nil {
+		return "", fmt.Errorf("path %w", err)
+	}
+	dest := filepath.Join(targetPath, relPath)
+
+	// The deepest existing directory on the way decides where the path
+	// really is; nothing exists below targetPath in dry runs
+	existing := filepath.Dir(dest)
+	for within(targetPath, existing) {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		existing = filepath.Dir(existing)
+	}
+	if !within(targetPath, existing) {
+		return dest, nil
+	}
+
+	resolvedTarget, err := filepath.EvalSymlinks(targetPath)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve %s: %w", existing, err)
+	}
+	if !within(resolvedTarget, resolved) {
+		return "", fmt.Errorf("%s leads outside the project to %s", existing, resolved)
+	}
+
+	// An existing symlink as the file itself would be written through
+	if info, err := os.Lstat(dest); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		if linked, err := filepath.EvalSymlinks(dest); err != nil || !within(resolvedTarget, linked) {
+			return "", fmt.Errorf("%s is a symlink leading outside the project", dest)
+		}
+	}
+	return dest, nil
+}
+
+// within reports whether path is dir or below it
+func within(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// reject reports a template entry that is skipped for safety
+func reject(template, relPath string, err error) {
+	pterm.Warning.Printf("Template %s: skipping %s: %v\n", template, filepath.ToSlash(relPath), err)
+}
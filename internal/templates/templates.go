@@ -0,0 +1,651 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+// Package templates applies project templates to a directory. Several
+// templates can be layered in order, with rules for files they both provide.
+//
+// Files ending in .tmpl are rendered with text/template (and the suffix is
+// removed); they may include shared partials with
+// {{ template "partials/header.md" . }}. With RenderAll, every other text
+// file is rendered as well, and copied unchanged if it does not render.
+//
+// A template may describe itself in a template.toml manifest declaring its
+// variables and how individual files are written, and extend another
+// template whose files it overrides; see Manifest and Resolve.
+//
+// The package is named templates, not template, so that it neither shadows
+// text/template, which it renders with, nor clashes with the --template
+// flag variable of the commands using it.
+package templates
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/mochajutsu/mkcd/internal/files"
+	"github.com/mochajutsu/mkcd/internal/git"
+	"github.com/mochajutsu/mkcd/internal/utils"
+	"github.com/pterm/pterm"
+)
+
+// Conflict resolution strategies for files provided by more than one layer
+const (
+	ConflictOverride = "override" // later templates replace earlier files
+	ConflictKeep     = "keep"     // the first template providing a file wins
+	ConflictError    = "error"    // conflicting files abort the operation
+)
+
+// GetAvailableConflictStrategies returns the supported conflict strategies
+func GetAvailableConflictStrategies() []string {
+	return []string{ConflictOverride, ConflictKeep, ConflictError}
+}
+
+// conflictAliases are alternative names of the conflict strategies
+var conflictAliases = map[string]string{
+	"overwrite": ConflictOverride,
+	"skip":      ConflictKeep,
+}
+
+// NormalizeConflict returns the strategy named by conflict, which may also
+// be one of the aliases overwrite (override) and skip (keep)
+func NormalizeConflict(conflict string) string {
+	if strategy, exists := conflictAliases[conflict]; exists {
+		return strategy
+	}
+	return conflict
+}
+
+// mergeableFiles are line-based files whose contents are always merged
+// across layers instead of being resolved by the conflict strategy
+var mergeableFiles = map[string]bool{
+	".gitignore":     true,
+	".dockerignore":  true,
+	".gitattributes": true,
+}
+
+// TemplateSuffix marks template files that are rendered instead of copied
+const TemplateSuffix = ".tmpl"
+
+// PartialsPrefix is the name prefix under which partials are included
+const PartialsPrefix = "partials/"
+
+// Data is the data available to rendered template files and partials
+type Data struct {
+	ProjectName string
+	ProjectPath string
+	Author      string
+	Email       string
+	Description string
+	License     string
+	GitRemote   string
+	Profile     string
+	Year        int
+	Date        string
+
+	// Git, Readme and Gitignore are the merged flag and profile settings
+	// of the creation, for conditions such as {{.Git}}
+	Git       bool
+	Readme    bool
+	Gitignore string
+
+	// Vars holds the variables declared in the template's manifest
+	Vars map[string]string
+}
+
+// NewData returns the template data for a project from the context shared
+// with the file generators
+func NewData(ctx *files.GenerationContext) Data {
+	return Data{
+		ProjectName: ctx.ProjectName,
+		ProjectPath: ctx.ProjectPath,
+		Author:      ctx.Author,
+		Email:       ctx.Email,
+		Description: ctx.Description,
+		License:     ctx.License,
+		GitRemote:   ctx.GitRemote,
+		Profile:     ctx.Profile,
+		Year:        ctx.CurrentYear,
+		Date:        ctx.Date,
+	}
+}
+
+// Layer is a single template applied as part of a composition
+type Layer struct {
+	Name string
+	Dir  string
+
+	// Cookiecutter layers are cookiecutter templates; see CookiecutterPrefix
+	Cookiecutter bool
+}
+
+// Applier copies template layers into a target directory
+type Applier struct {
+	fsOps    *utils.FileSystemOperations
+	Conflict string
+	Verbose  bool
+
+	// Partials is the directory of shared partials (optional)
+	Partials string
+	Data     Data
+
+	// RenderAll renders every text file, not only .tmpl files
+	RenderAll bool
+
+	// Vars are values for the variables declared by template manifests;
+	// undeclared entries are ignored
+	Vars map[string]string
+
+	// Ask asks for the variables without a value in Vars; nil keeps
+	// their defaults
+	Ask Asker
+
+	// Locate finds the templates named by extends
+	Locate Locator
+
+	// Hooks are the rendered hooks of the applied templates, in order,
+	// for the caller to run once the files are written
+	Hooks []AppliedHook
+
+	// Plan lists the written files in order with the template providing
+	// each and how conflicts with other templates were resolved
+	Plan []PlannedFile
+
+	partials *template.Template
+	planned  map[string]int
+}
+
+// PlannedFile is a file of the merged plan of a composition
+type PlannedFile struct {
+	Path string
+
+	// Template provides the content written
+	Template string
+
+	// Replaced, Skipped and Merged list the templates whose version of
+	// the file was overridden, left out, or merged line by line
+	Replaced []string
+	Skipped  []string
+	Merged   []string
+}
+
+// AppliedHook is a hook of an applied template, ready to run
+type AppliedHook struct {
+	Template string
+	Run      string
+
+	// Timeout is zero for the default timeout
+	Timeout time.Duration
+}
+
+// NewApplier creates a new Applier using the given conflict strategy
+// (empty for ConflictOverride)
+func NewApplier(fsOps *utils.FileSystemOperations, conflict string, verbose bool) (*Applier, error) {
+	conflict = NormalizeConflict(conflict)
+	if conflict == "" {
+		conflict = ConflictOverride
+	}
+	if !isValidConflict(conflict) {
+		return nil, fmt.Errorf("invalid template conflict strategy '%s' (expected %s)", conflict, strings.Join(GetAvailableConflictStrategies(), ", "))
+	}
+
+	return &Applier{
+		fsOps:    fsOps,
+		Conflict: conflict,
+		Verbose:  verbose,
+	}, nil
+}
+
+// ParseNames splits a comma-separated template list into unique names, in order
+func ParseNames(spec string) []string {
+	names := []string{}
+	seen := make(map[string]bool)
+
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// Apply copies each layer into targetPath in order, resolving files provided
+// by several layers according to the conflict strategy
+func (a *Applier) Apply(targetPath string, layers []Layer) error {
+	partials, err := LoadPartials(a.Partials)
+	if err != nil {
+		return err
+	}
+	a.partials = partials
+
+	// owners maps relative file paths to the layer that wrote them; files
+	// of the templates a layer extends belong to the layer
+	owners := make(map[string]string)
+
+	for _, layer := range layers {
+		if layer.Cookiecutter {
+			a.fsOps.SetSource("template:" + layer.Name)
+			if err := a.applyCookiecutter(targetPath, layer, owners); err != nil {
+				return fmt.Errorf("template %s: %w", layer.Name, err)
+			}
+			continue
+		}
+
+		chain, err := Resolve(layer, a.Locate)
+		if err != nil {
+			return fmt.Errorf("template %s: %w", layer.Name, err)
+		}
+
+		data := a.Data
+		if data.Vars, err = chain.Manifest.ResolveVariables(a.Vars, a.Ask); err != nil {
+			return fmt.Errorf("template %s: %w", layer.Name, err)
+		}
+
+		for i, inherited := range chain.Layers {
+			if a.Verbose {
+				pterm.Debug.Printf("Applying template %s from %s", inherited.Name, inherited.Dir)
+			}
+
+			a.fsOps.SetSource("template:" + inherited.Name)
+			if err := a.applyLayer(targetPath, inherited, layer.Name, chain.Manifests[i], data, owners); err != nil {
+				return fmt.Errorf("template %s: %w", inherited.Name, err)
+			}
+		}
+
+		for i, hook := range chain.Manifest.Hooks {
+			if hook.When != "" {
+				when, err := a.render(fmt.Sprintf("%s: when of hooks[%d]", ManifestFile, i), hook.When, data)
+				if err != nil {
+					return fmt.Errorf("template %s: %w", layer.Name, err)
+				}
+				if !Holds(when) {
+					pterm.Debug.Printf("Skipping hook %s of template %s: condition not met", hook.Run, layer.Name)
+					continue
+				}
+			}
+			run, err := a.render(fmt.Sprintf("%s: hooks[%d]", ManifestFile, i), hook.Run, data)
+			if err != nil {
+				return fmt.Errorf("template %s: %w", layer.Name, err)
+			}
+			// The manifest is validated on load
+			timeout, _ := time.ParseDuration(hook.Timeout)
+			a.Hooks = append(a.Hooks, AppliedHook{Template: layer.Name, Run: run, Timeout: timeout})
+		}
+	}
+
+	return nil
+}
+
+// applyLayer copies a single layer into targetPath on behalf of the layer
+// named owner, which is the layer itself or a template extending it
+func (a *Applier) applyLayer(targetPath string, layer Layer, owner string, manifest *Manifest, data Data, owners map[string]string) error {
+	return filepath.Walk(layer.Dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(layer.Dir, path)
+		if err != nil {
+			return err
+		}
+
+		// Never copy the template's own version control data
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			if relPath == "." {
+				return nil
+			}
+			if spec := manifest.FileFor(filepath.ToSlash(relPath)); spec != nil && spec.When != "" {
+				include, err := a.condition(filepath.ToSlash(relPath), spec.When, data)
+				if err != nil {
+					return err
+				}
+				if !include {
+					return filepath.SkipDir
+				}
+			}
+			return a.createDirectory(targetPath, relPath, layer.Name)
+		}
+
+		slashPath := filepath.ToSlash(relPath)
+		if slashPath == ManifestFile {
+			return nil
+		}
+		if info, err = sourceFile(layer.Dir, path, info); err != nil {
+			reject(layer.Name, relPath, err)
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read template file %s: %w", relPath, err)
+		}
+
+		spec := manifest.FileFor(slashPath)
+		if spec != nil && spec.When != "" {
+			include, err := a.condition(slashPath, spec.When, data)
+			if err != nil || !include {
+				return err
+			}
+		}
+
+		switch {
+		case spec != nil && spec.Render != nil && !*spec.Render:
+			// Copied verbatim under its own name
+		case spec != nil && spec.Render != nil, strings.HasSuffix(relPath, TemplateSuffix):
+			rendered, err := a.render(slashPath, string(content), data)
+			if err != nil {
+				return err
+			}
+			content = []byte(rendered)
+			relPath = strings.TrimSuffix(relPath, TemplateSuffix)
+		case a.RenderAll && bytes.Contains(content, []byte("{{")) && !utils.IsBinary(content):
+			// Plain files may contain braces meant for other tools
+			// (Helm charts, Go templates), so they are kept when they
+			// do not render
+			rendered, err := a.render(slashPath, string(content), data)
+			if err != nil {
+				pterm.Debug.Printf("Copying %s unchanged: %v", relPath, err)
+			} else {
+				content = []byte(rendered)
+			}
+		}
+
+		if spec != nil && spec.Target != "" {
+			target, err := a.render(ManifestFile+": target of "+slashPath, spec.Target, data)
+			if err != nil {
+				return err
+			}
+			if err := checkRelative(target); err != nil {
+				return fmt.Errorf("target of %s %w", slashPath, err)
+			}
+			relPath = filepath.Clean(filepath.FromSlash(target))
+			if filepath.Dir(relPath) != "." {
+				if err := a.createDirectory(targetPath, filepath.Dir(relPath), layer.Name); err != nil {
+					return err
+				}
+			}
+		}
+
+		return a.writeFile(targetPath, relPath, content, spec.FileMode(info.Mode()), layer.Name, owner, owners)
+	})
+}
+
+// writeFile writes a file of the template name into targetPath, resolving
+// files already written by other layers
+func (a *Applier) writeFile(targetPath, relPath string, content []byte, mode os.FileMode, name, owner string, owners map[string]string) error {
+	destPath, err := destination(targetPath, relPath)
+	if err != nil {
+		reject(name, relPath, err)
+		return nil
+	}
+	previous, exists := owners[relPath]
+
+	if exists {
+		planned := &a.Plan[a.planned[relPath]]
+		if mergeableFiles[filepath.Base(relPath)] {
+			existing, err := a.fsOps.FS.ReadFile(destPath)
+			if err != nil {
+				return fmt.Errorf("failed to read %s for merging: %w", relPath, err)
+			}
+			pterm.Debug.Printf("Merging %s from templates %s and %s", relPath, previous, name)
+			planned.Merged = append(planned.Merged, name)
+			return a.fsOps.CreateFile(destPath, MergeLines(string(existing), string(content)), mode)
+		}
+
+		// Templates override the files they inherit regardless of the
+		// conflict strategy, which is for layers
+		switch {
+		case previous == owner:
+			pterm.Debug.Printf("Template %s overrides inherited %s", name, relPath)
+		case a.Conflict == ConflictKeep:
+			pterm.Debug.Printf("Keeping %s from template %s over %s", relPath, previous, name)
+			planned.Skipped = append(planned.Skipped, name)
+			return nil
+		case a.Conflict == ConflictError:
+			return fmt.Errorf("file %s is also provided by template %s", relPath, previous)
+		default:
+			pterm.Debug.Printf("Template %s overrides %s from %s", name, relPath, previous)
+		}
+		planned.Replaced = append(planned.Replaced, planned.Template)
+		planned.Template = name
+	} else {
+		if a.planned == nil {
+			a.planned = make(map[string]int)
+		}
+		a.planned[relPath] = len(a.Plan)
+		a.Plan = append(a.Plan, PlannedFile{Path: filepath.ToSlash(relPath), Template: name})
+	}
+
+	owners[relPath] = owner
+	return a.fsOps.CreateFile(destPath, string(content), mode)
+}
+
+// createDirectory creates the directory relPath of the template name below
+// targetPath
+func (a *Applier) createDirectory(targetPath, relPath, name string) error {
+	dest, err := destination(targetPath, relPath)
+	if err != nil {
+		reject(name, relPath, err)
+		return nil
+	}
+	return a.fsOps.CreateDirectory(dest, 0755)
+}
+
+// condition renders the condition of the template path relPath and reports
+// whether it holds
+func (a *Applier) condition(relPath, when string, data Data) (bool, error) {
+	rendered, err := a.render(ManifestFile+": when of "+relPath, when, data)
+	if err != nil {
+		return false, err
+	}
+	if !Holds(rendered) {
+		pterm.Debug.Printf("Leaving out %s: condition not met", relPath)
+		return false, nil
+	}
+	return true, nil
+}
+
+// render executes a template file with the partials available for inclusion
+func (a *Applier) render(name, content string, data Data) (string, error) {
+	tmpl, err := a.partials.Clone()
+	if err != nil {
+		return "", fmt.Errorf("failed to prepare template %s: %w", name, err)
+	}
+
+	tmpl, err = tmpl.New(name).Parse(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+
+	return buf.String(), nil
+}
+
+// LoadPartials parses every file below dir as a partial named
+// "partials/<relative path>". A missing directory yields no partials.
+func LoadPartials(dir string) (*template.Template, error) {
+	root := template.New("partials").Option("missingkey=error")
+
+	if dir == "" || !utils.IsDirectory(dir) {
+		return root, nil
+	}
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read partial %s: %w", relPath, err)
+		}
+
+		name := PartialsPrefix + filepath.ToSlash(relPath)
+		if _, err := root.New(name).Parse(string(content)); err != nil {
+			return fmt.Errorf("failed to parse partial %s: %w", name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return root, nil
+}
+
+// MergeLines appends the lines of addition that are not already present in
+// base, preserving the order of both
+func MergeLines(base, addition string) string {
+	present := make(map[string]bool)
+	for _, line := range strings.Split(base, "\n") {
+		present[strings.TrimSpace(line)] = true
+	}
+
+	merged := base
+	if merged != "" && !strings.HasSuffix(merged, "\n") {
+		merged += "\n"
+	}
+
+	for _, line := range strings.Split(addition, "\n") {
+		trimmed := strings.TrimSpace(line)
+		// Only new entries are appended; blank lines and comments are dropped
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || present[trimmed] {
+			continue
+		}
+		present[trimmed] = true
+		merged += line + "\n"
+	}
+
+	return merged
+}
+
+// List returns the names of the templates in templatesDir, skipping hidden
+// directories and the shared partials directory. A missing directory yields
+// no templates.
+func List(templatesDir, partialsDir string) ([]string, error) {
+	names := []string{}
+	entries, err := os.ReadDir(templatesDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read templates directory %s: %w", templatesDir, err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if filepath.Join(templatesDir, entry.Name()) == partialsDir {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	return names, nil
+}
+
+// ListAll returns the templates of List together with the built-in
+// templates they do not override, sorted
+func ListAll(templatesDir, partialsDir string) ([]string, error) {
+	names, err := List(templatesDir, partialsDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range BuiltinNames() {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// isValidConflict reports whether a conflict strategy is supported
+func isValidConflict(conflict string) bool {
+	for _, strategy := range GetAvailableConflictStrategies() {
+		if conflict == strategy {
+			return true
+		}
+	}
+	return false
+}
+
+// Version identifies the state of a template directory: the HEAD commit when
+// the template is a Git checkout, otherwise a hash of its file contents
+func Version(dir string) (string, error) {
+	gitMgr := git.NewGitManager(false, false, "", "")
+	if info, err := gitMgr.GetRepositoryInfo(dir); err == nil && info.LastCommit != nil {
+		return "git:" + info.LastCommit.Hash[:12], nil
+	}
+
+	checksum, err := Checksum(dir)
+	if err != nil {
+		return "", err
+	}
+	return "sha256:" + checksum[:12], nil
+}
+
+// Checksum returns the hex SHA-256 of the paths and contents of the files
+// of a template directory, ignoring .git
+func Checksum(dir string) (string, error) {
+	hash := sha256.New()
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		// Walk visits files in lexical order, so the hash is stable
+		fmt.Fprintf(hash, "%s\x00%d\x00", filepath.ToSlash(relPath), len(content))
+		hash.Write(content)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash template %s: %w", dir, err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
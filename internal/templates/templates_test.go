@@ -0,0 +1,110 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package templates_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mochajutsu/mkcd/internal/templates"
+	"github.com/mochajutsu/mkcd/internal/testsupport"
+	"github.com/mochajutsu/mkcd/internal/utils"
+)
+
+// newApplier returns an Applier writing to disk with the given strategy
+func newApplier(t *testing.T, conflict string) *templates.Applier {
+	t.Helper()
+
+	applier, err := templates.NewApplier(utils.NewFileSystemOperations(false, false), conflict, false)
+	if err != nil {
+		t.Fatalf("failed to create applier: %v", err)
+	}
+	return applier
+}
+
+// TestApplyRendersTemplate applies a template with a manifest, a rendered
+// file and a hook
+func TestApplyRendersTemplate(t *testing.T) {
+	ws := testsupport.NewWorkspace(t)
+	ws.WriteFile("tpl/template.toml", `name = "service"
+
+[variables.port]
+default = "8080"
+
+[[hooks]]
+run = "echo {{.ProjectName}} on {{.Vars.port}}"
+when = "{{.Git}}"
+
+[[hooks]]
+run = "echo always"
+`)
+	ws.WriteFile("tpl/README.md.tmpl", "# {{.ProjectName}}\n\nListens on {{.Vars.port}}.\n")
+	ws.WriteFile("tpl/src/main.go", "package main\n")
+
+	applier := newApplier(t, "")
+	applier.Data = templates.Data{ProjectName: "api"}
+	applier.Vars = map[string]string{"port": "9090"}
+	if err := applier.Apply(ws.Path("out"), []templates.Layer{{Name: "service", Dir: ws.Path("tpl")}}); err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+
+	ws.Tree("out").
+		Entries("README.md", "src/", "src/main.go").
+		FileEquals("README.md", "# api\n\nListens on 9090.\n").
+		FileEquals("src/main.go", "package main\n").
+		NotExists("template.toml")
+
+	// The hook conditioned on Git is skipped
+	if len(applier.Hooks) != 1 || applier.Hooks[0].Run != "echo always" {
+		t.Errorf("expected only the unconditional hook, got %+v", applier.Hooks)
+	}
+}
+
+// TestApplyLayersResolveConflicts applies two layers providing the same
+// files under every conflict strategy
+func TestApplyLayersResolveConflicts(t *testing.T) {
+	tests := []struct {
+		conflict string
+		expected string
+		fails    bool
+	}{
+		{conflict: templates.ConflictOverride, expected: "second\n"},
+		{conflict: templates.ConflictKeep, expected: "first\n"},
+		{conflict: templates.ConflictError, fails: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.conflict, func(t *testing.T) {
+			ws := testsupport.NewWorkspace(t)
+			ws.WriteFile("first/notes.txt", "first\n")
+			ws.WriteFile("first/.gitignore", "bin/\n")
+			ws.WriteFile("second/notes.txt", "second\n")
+			ws.WriteFile("second/.gitignore", "dist/\n")
+
+			layers := []templates.Layer{
+				{Name: "first", Dir: ws.Path("first")},
+				{Name: "second", Dir: ws.Path("second")},
+			}
+			err := newApplier(t, tt.conflict).Apply(ws.Path("out"), layers)
+			if tt.fails {
+				if err == nil || !strings.Contains(err.Error(), "notes.txt") {
+					t.Fatalf("expected a conflict on notes.txt, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("apply failed: %v", err)
+			}
+
+			// .gitignore is merged whatever the strategy
+			ws.Tree("out").
+				Entries(".gitignore", "notes.txt").
+				FileEquals("notes.txt", tt.expected).
+				FileContains(".gitignore", "bin/", "dist/")
+		})
+	}
+}
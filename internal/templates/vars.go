@@ -0,0 +1,69 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// LoadVarsFile reads template variable values from a file: JSON for .json
+// files, TOML otherwise. Both hold a flat table of names and values;
+// numbers and booleans are taken as their text.
+func LoadVarsFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read variables file: %w", err)
+	}
+
+	raw := map[string]any{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &raw)
+	} else {
+		err = toml.Unmarshal(data, &raw)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse variables file %s: %w", path, err)
+	}
+
+	vars := make(map[string]string, len(raw))
+	for name, value := range raw {
+		if !variableNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("%s: invalid variable name '%s'", path, name)
+		}
+		switch value.(type) {
+		case string, bool, int64, float64:
+			vars[name] = fmt.Sprint(value)
+		default:
+			return nil, fmt.Errorf("%s: variable '%s' must be a string, number or boolean", path, name)
+		}
+	}
+	return vars, nil
+}
+
+// ParseVarAssignments parses name=value assignments of template variables.
+// The value may be empty and contain '='; later assignments win.
+func ParseVarAssignments(assignments []string) (map[string]string, error) {
+	vars := make(map[string]string, len(assignments))
+	for _, assignment := range assignments {
+		name, value, found := strings.Cut(assignment, "=")
+		name = strings.TrimSpace(name)
+		if !found {
+			return nil, fmt.Errorf("invalid variable '%s' (expected name=value)", assignment)
+		}
+		if !variableNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid variable name '%s'", name)
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package testsupport
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// GitAssertion asserts the state of a Git repository
+type GitAssertion struct {
+	t    testing.TB
+	path string
+	repo *git.Repository
+}
+
+// AssertGit starts a Git state assertion for the repository at path
+func AssertGit(t testing.TB, path string) *GitAssertion {
+	repo, _ := git.PlainOpen(path)
+	return &GitAssertion{
+		t:    t,
+		path: path,
+		repo: repo,
+	}
+}
+
+// IsRepository asserts that the path is a Git repository
+func (ga *GitAssertion) IsRepository() *GitAssertion {
+	ga.t.Helper()
+
+	if ga.repo == nil {
+		ga.t.Fatalf("expected %s to be a Git repository", ga.path)
+	}
+	return ga
+}
+
+// NotRepository asserts that the path is not a Git repository
+func (ga *GitAssertion) NotRepository() *GitAssertion {
+	ga.t.Helper()
+
+	if ga.repo != nil {
+		ga.t.Errorf("expected %s not to be a Git repository", ga.path)
+	}
+	return ga
+}
+
+// HasRemote asserts that a remote exists with the given URL
+func (ga *GitAssertion) HasRemote(name, url string) *GitAssertion {
+	ga.t.Helper()
+	ga.IsRepository()
+
+	remote, err := ga.repo.Remote(name)
+	if err != nil {
+		ga.t.Errorf("expected remote %s: %v", name, err)
+		return ga
+	}
+	if urls := remote.Config().URLs; len(urls) == 0 || urls[0] != url {
+		ga.t.Errorf("expected remote %s to point at %s, got %v", name, url, urls)
+	}
+	return ga
+}
+
+// CommitCount asserts the number of commits reachable from HEAD
+func (ga *GitAssertion) CommitCount(expected int) *GitAssertion {
+	ga.t.Helper()
+	ga.IsRepository()
+
+	count := 0
+	if head, err := ga.repo.Head(); err == nil {
+		commits, err := ga.repo.Log(&git.LogOptions{From: head.Hash()})
+		if err != nil {
+			ga.t.Fatalf("failed to read log: %v", err)
+		}
+		_ = commits.ForEach(func(*object.Commit) error {
+			count++
+			return nil
+		})
+	}
+
+	if count != expected {
+		ga.t.Errorf("expected %d commits, got %d", expected, count)
+	}
+	return ga
+}
+
+// HeadMessage asserts the message of the HEAD commit
+func (ga *GitAssertion) HeadMessage(expected string) *GitAssertion {
+	ga.t.Helper()
+
+	commit := ga.headCommit()
+	if commit != nil && strings.TrimSpace(commit.Message) != expected {
+		ga.t.Errorf("expected HEAD message %q, got %q", expected, strings.TrimSpace(commit.Message))
+	}
+	return ga
+}
+
+// Committed asserts that the HEAD commit contains exactly the given files
+func (ga *GitAssertion) Committed(expected ...string) *GitAssertion {
+	ga.t.Helper()
+
+	commit := ga.headCommit()
+	if commit == nil {
+		return ga
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		ga.t.Fatalf("failed to read HEAD tree: %v", err)
+	}
+
+	actual := []string{}
+	_ = tree.Files().ForEach(func(file *object.File) error {
+		actual = append(actual, file.Name)
+		return nil
+	})
+
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		ga.t.Errorf("unexpected files in HEAD commit:\n--- expected ---\n%s\n--- actual ---\n%s",
+			strings.Join(expected, "\n"), strings.Join(actual, "\n"))
+	}
+	return ga
+}
+
+// headCommit returns the HEAD commit, failing the test if there is none
+func (ga *GitAssertion) headCommit() *object.Commit {
+	ga.t.Helper()
+	ga.IsRepository()
+
+	head, err := ga.repo.Head()
+	if err != nil {
+		ga.t.Errorf("expected a HEAD commit: %v", err)
+		return nil
+	}
+
+	commit, err := ga.repo.CommitObject(head.Hash())
+	if err != nil {
+		ga.t.Fatalf("failed to read HEAD commit: %v", err)
+	}
+	return commit
+}
@@ -0,0 +1,117 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package testsupport
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// UpdateGoldenEnv is the environment variable that, when set to a non-empty
+// value, makes AssertGoldenTree rewrite golden trees from the actual output
+const UpdateGoldenEnv = "MKCD_UPDATE_GOLDEN"
+
+// Normalizer rewrites file content before it is compared with a golden file,
+// e.g. to replace the current year or a temporary path with a placeholder
+type Normalizer func(content string) string
+
+// ReplaceAll returns a Normalizer replacing every occurrence of old with new
+func ReplaceAll(old, new string) Normalizer {
+	return func(content string) string {
+		return strings.ReplaceAll(content, old, new)
+	}
+}
+
+// GoldenDir returns the path of a named golden tree under testdata/golden
+func GoldenDir(name string) string {
+	return filepath.Join(TestdataDir(), "golden", name)
+}
+
+// AssertGoldenTree compares the directory tree at root with the named golden
+// tree: both must contain the same entries and every file must have the same
+// content after normalization. Set MKCD_UPDATE_GOLDEN=1 to regenerate.
+func AssertGoldenTree(t testing.TB, root, name string, normalizers ...Normalizer) {
+	t.Helper()
+
+	goldenRoot := GoldenDir(name)
+
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		updateGoldenTree(t, root, goldenRoot, normalizers)
+		return
+	}
+
+	if _, err := os.Stat(goldenRoot); err != nil {
+		t.Fatalf("golden tree %s not found (run with %s=1 to create it): %v", name, UpdateGoldenEnv, err)
+	}
+
+	AssertTree(t, root).Entries(ListTree(t, goldenRoot)...)
+
+	for _, entry := range ListTree(t, goldenRoot) {
+		if strings.HasSuffix(entry, "/") {
+			continue
+		}
+
+		expected, err := os.ReadFile(filepath.Join(goldenRoot, filepath.FromSlash(entry)))
+		if err != nil {
+			t.Fatalf("failed to read golden file %s: %v", entry, err)
+		}
+
+		actual, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(entry)))
+		if err != nil {
+			t.Errorf("failed to read %s: %v", entry, err)
+			continue
+		}
+
+		if normalize(string(actual), normalizers) != string(expected) {
+			t.Errorf("%s differs from golden tree %s:\n--- golden ---\n%s\n--- actual ---\n%s",
+				entry, name, expected, normalize(string(actual), normalizers))
+		}
+	}
+}
+
+// updateGoldenTree replaces the golden tree with the normalized actual tree
+func updateGoldenTree(t testing.TB, root, goldenRoot string, normalizers []Normalizer) {
+	t.Helper()
+
+	if err := os.RemoveAll(goldenRoot); err != nil {
+		t.Fatalf("failed to remove golden tree %s: %v", goldenRoot, err)
+	}
+
+	for _, entry := range ListTree(t, root) {
+		target := filepath.Join(goldenRoot, filepath.FromSlash(entry))
+
+		if strings.HasSuffix(entry, "/") {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				t.Fatalf("failed to create golden directory %s: %v", entry, err)
+			}
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(entry)))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", entry, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			t.Fatalf("failed to create golden directory for %s: %v", entry, err)
+		}
+		if err := os.WriteFile(target, []byte(normalize(string(content), normalizers)), 0644); err != nil {
+			t.Fatalf("failed to write golden file %s: %v", entry, err)
+		}
+	}
+
+	t.Logf("updated golden tree %s", goldenRoot)
+}
+
+// normalize applies all normalizers to content in order
+func normalize(content string, normalizers []Normalizer) string {
+	for _, normalizer := range normalizers {
+		content = normalizer(content)
+	}
+	return content
+}
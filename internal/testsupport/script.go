@@ -0,0 +1,90 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package testsupport
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mochajutsu/mkcd/internal/shell"
+)
+
+// ScriptAssertion asserts the shell script emitted by mkcd on stdout
+type ScriptAssertion struct {
+	t     testing.TB
+	lines []string
+}
+
+// AssertScript starts an assertion on emitted script output
+func AssertScript(t testing.TB, output string) *ScriptAssertion {
+	lines := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			lines = append(lines, trimmed)
+		}
+	}
+
+	return &ScriptAssertion{
+		t:     t,
+		lines: lines,
+	}
+}
+
+// ChangesDirTo asserts that the script contains a cd command for the path,
+// accepting bare forms and the command of any supported shell, quoted as
+// shell.ChangeDirCommand quotes it
+func (sa *ScriptAssertion) ChangesDirTo(path string) *ScriptAssertion {
+	sa.t.Helper()
+
+	candidates := []string{
+		"cd " + path,
+		"cd '" + path + "'",
+		`cd "` + path + `"`,
+		"cd -- " + path,
+		"cd -- '" + path + "'",
+	}
+	for _, name := range shell.GetSupportedShells() {
+		candidates = append(candidates, shell.ChangeDirCommand(name, path))
+	}
+
+	for _, line := range sa.lines {
+		for _, candidate := range candidates {
+			if line == candidate {
+				return sa
+			}
+		}
+	}
+
+	sa.t.Errorf("expected script to change directory to %s, got:\n%s", path, strings.Join(sa.lines, "\n"))
+	return sa
+}
+
+// NoDirChange asserts that the script does not change directory
+func (sa *ScriptAssertion) NoDirChange() *ScriptAssertion {
+	sa.t.Helper()
+
+	for _, line := range sa.lines {
+		if line == "cd" || strings.HasPrefix(line, "cd ") {
+			sa.t.Errorf("expected no directory change, found: %s", line)
+		}
+	}
+	return sa
+}
+
+// Contains asserts that some line of the script contains the substring
+func (sa *ScriptAssertion) Contains(substring string) *ScriptAssertion {
+	sa.t.Helper()
+
+	for _, line := range sa.lines {
+		if strings.Contains(line, substring) {
+			return sa
+		}
+	}
+
+	sa.t.Errorf("expected script to contain %q, got:\n%s", substring, strings.Join(sa.lines, "\n"))
+	return sa
+}
@@ -0,0 +1,46 @@
+# mkcd shell integration for bash
+# Add to your shell startup file: eval "$(mkcd shell-init bash)"
+export MKCD_SHELL_INTEGRATION=bash
+mkcd() {
+  local mkcd_dir mkcd_status
+  case "$1" in
+    open|go)
+      mkcd_dir="$(command mkcd "$@" --print-path)"
+      ;;
+    ""|help|version|shell-init)
+      command mkcd "$@"
+      return
+      ;;
+    *)
+      mkcd_dir="$(command mkcd mkcd --print-path "$@")"
+      ;;
+  esac
+  mkcd_status=$?
+  if [ -n "$mkcd_dir" ] && [ -d "$mkcd_dir" ]; then
+    cd -- "$mkcd_dir" || return
+  fi
+  return $mkcd_status
+}
+
+# Complete the first argument with subcommands, recent directories and local
+# directories; later arguments use the mkcd completion when it is loaded
+_mkcd_complete() {
+  local cur="${COMP_WORDS[COMP_CWORD]}"
+  if [ "$COMP_CWORD" -gt 1 ]; then
+    if declare -F __start_mkcd >/dev/null; then
+      __start_mkcd "$@"
+    else
+      compopt -o default 2>/dev/null
+      COMPREPLY=()
+    fi
+    return
+  fi
+
+  local IFS=$'\n'
+  local -a mkcd_commands=( help version shell-init)
+  COMPREPLY=($(compgen -W "${mkcd_commands[*]}" -- "$cur")
+    $(command mkcd __complete-paths -- "$cur" 2>/dev/null)
+    $(compgen -d -- "$cur"))
+  compopt -o filenames 2>/dev/null
+}
+complete -F _mkcd_complete mkcd
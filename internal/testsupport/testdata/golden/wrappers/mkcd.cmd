@@ -0,0 +1,31 @@
+@echo off
+rem mkcd shell integration for cmd.exe
+rem Save as %USERPROFILE%\.mkcd\mkcd.cmd: mkcd shell-init cmd > "%USERPROFILE%\.mkcd\mkcd.cmd"
+rem Then load it in every prompt (AutoRun runs doskey when cmd.exe starts):
+rem   reg add "HKCU\Software\Microsoft\Command Processor" /v AutoRun /t REG_EXPAND_SZ /d "doskey mkcd=call \"%%USERPROFILE%%\.mkcd\mkcd.cmd\" $*" /f
+set "MKCD_SHELL_INTEGRATION=cmd"
+if "%~1"=="" goto mkcd_passthrough
+for %%P in (open go) do if /i "%~1"=="%%P" goto mkcd_cd
+for %%P in (help version shell-init) do if /i "%~1"=="%%P" goto mkcd_passthrough
+
+set "MKCD_OUT=%TEMP%\mkcd-%RANDOM%%RANDOM%.path"
+mkcd.exe mkcd --print-path %* > "%MKCD_OUT%"
+set "MKCD_STATUS=%ERRORLEVEL%"
+goto mkcd_change
+
+:mkcd_cd
+set "MKCD_OUT=%TEMP%\mkcd-%RANDOM%%RANDOM%.path"
+mkcd.exe %* --print-path > "%MKCD_OUT%"
+set "MKCD_STATUS=%ERRORLEVEL%"
+
+:mkcd_change
+set "MKCD_DIR="
+set /p MKCD_DIR=<"%MKCD_OUT%"
+del "%MKCD_OUT%" 2>nul
+if defined MKCD_DIR if exist "%MKCD_DIR%\" cd /d "%MKCD_DIR%"
+set "MKCD_DIR="
+set "MKCD_OUT="
+set "MKCD_STATUS=" & exit /b %MKCD_STATUS%
+
+:mkcd_passthrough
+mkcd.exe %*
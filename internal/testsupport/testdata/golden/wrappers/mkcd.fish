@@ -0,0 +1,27 @@
+# mkcd shell integration for fish
+# Add to ~/.config/fish/config.fish: mkcd shell-init fish | source
+set -gx MKCD_SHELL_INTEGRATION fish
+function mkcd --description 'Create a directory and change into it'
+    set -l mkcd_dir
+    set -l mkcd_status
+    switch "$argv[1]"
+        case 'open' 'go'
+            set mkcd_dir (command mkcd $argv --print-path)
+            set mkcd_status $status
+        case '' 'help' 'version' 'shell-init'
+            command mkcd $argv
+            return
+        case '*'
+            set mkcd_dir (command mkcd mkcd --print-path $argv)
+            set mkcd_status $status
+    end
+    if test (count $mkcd_dir) -eq 1; and test -d "$mkcd_dir"
+        cd -- $mkcd_dir
+    end
+    return $mkcd_status
+end
+
+# completions
+
+# Complete the first argument with recent directories as well
+complete -c mkcd -n 'test (count (commandline -opc)) -eq 1' -a '(command mkcd __complete-paths -- (commandline -ct) 2>/dev/null)' -d 'Recent directory'
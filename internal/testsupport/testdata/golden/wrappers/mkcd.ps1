@@ -0,0 +1,21 @@
+# mkcd shell integration for PowerShell
+# Add to your $PROFILE: Invoke-Expression (& mkcd shell-init powershell | Out-String)
+$env:MKCD_SHELL_INTEGRATION = 'powershell'
+function mkcd {
+    $mkcdPassthrough = @('', 'help', 'version', 'shell-init')
+    $mkcdCdCommands = @('open', 'go')
+    $mkcdExe = (Get-Command -Name mkcd -CommandType Application -ErrorAction Stop | Select-Object -First 1).Source
+    $mkcdFirst = if ($args.Count -gt 0) { [string]$args[0] } else { '' }
+
+    if ($mkcdCdCommands -ccontains $mkcdFirst) {
+        $mkcdDir = & $mkcdExe @args --print-path
+    } elseif ($mkcdPassthrough -ccontains $mkcdFirst) {
+        & $mkcdExe @args
+        return
+    } else {
+        $mkcdDir = & $mkcdExe mkcd --print-path @args
+    }
+    if ($mkcdDir -is [string] -and $mkcdDir -ne '' -and (Test-Path -LiteralPath $mkcdDir -PathType Container)) {
+        Set-Location -LiteralPath $mkcdDir
+    }
+}
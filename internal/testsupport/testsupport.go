@@ -0,0 +1,128 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+// Package testsupport provides an end-to-end test harness for mkcd.
+// It offers scratch workspaces, golden directory trees stored under testdata,
+// and a small assertion DSL for created file structure, Git repository state
+// and the emitted shell script, so that features like templates, hooks and
+// undo can be regression-tested from any package.
+package testsupport
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// Workspace is a scratch directory in which an mkcd operation runs
+type Workspace struct {
+	t    testing.TB
+	Root string
+}
+
+// NewWorkspace creates a new Workspace in a temporary directory that is
+// removed when the test finishes
+func NewWorkspace(t testing.TB) *Workspace {
+	t.Helper()
+
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to resolve workspace root: %v", err)
+	}
+
+	return &Workspace{
+		t:    t,
+		Root: root,
+	}
+}
+
+// Path returns the absolute path of a slash-separated path inside the workspace
+func (w *Workspace) Path(rel string) string {
+	return filepath.Join(w.Root, filepath.FromSlash(rel))
+}
+
+// WriteFile creates a file (and its parent directories) inside the workspace
+func (w *Workspace) WriteFile(rel, content string) string {
+	w.t.Helper()
+
+	path := w.Path(rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		w.t.Fatalf("failed to create parent directory for %s: %v", rel, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		w.t.Fatalf("failed to write %s: %v", rel, err)
+	}
+
+	return path
+}
+
+// Chdir changes the working directory to the workspace root for the duration
+// of the test
+func (w *Workspace) Chdir() {
+	w.t.Helper()
+
+	previous, err := os.Getwd()
+	if err != nil {
+		w.t.Fatalf("failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(w.Root); err != nil {
+		w.t.Fatalf("failed to change to workspace %s: %v", w.Root, err)
+	}
+	w.t.Cleanup(func() {
+		_ = os.Chdir(previous)
+	})
+}
+
+// Tree starts a structure assertion rooted at a path inside the workspace
+func (w *Workspace) Tree(rel string) *TreeAssertion {
+	return AssertTree(w.t, w.Path(rel))
+}
+
+// Git starts a Git state assertion for a repository inside the workspace
+func (w *Workspace) Git(rel string) *GitAssertion {
+	return AssertGit(w.t, w.Path(rel))
+}
+
+// CaptureStdout runs fn while capturing everything it writes to os.Stdout
+func CaptureStdout(t testing.TB, fn func()) string {
+	t.Helper()
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+
+	original := os.Stdout
+	os.Stdout = writer
+
+	done := make(chan []byte)
+	go func() {
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, reader)
+		done <- buf.Bytes()
+	}()
+
+	defer func() {
+		os.Stdout = original
+	}()
+
+	fn()
+
+	writer.Close()
+	output := <-done
+	reader.Close()
+
+	return string(output)
+}
+
+// TestdataDir returns the absolute path of the testsupport testdata directory
+func TestdataDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "testdata")
+}
@@ -0,0 +1,190 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package testsupport
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TreeAssertion asserts the structure and content of a directory tree.
+// All paths are slash-separated and relative to the tree root; methods
+// return the assertion so checks can be chained.
+type TreeAssertion struct {
+	t    testing.TB
+	root string
+}
+
+// AssertTree starts a structure assertion rooted at the given directory
+func AssertTree(t testing.TB, root string) *TreeAssertion {
+	return &TreeAssertion{
+		t:    t,
+		root: root,
+	}
+}
+
+// path returns the absolute path for a relative tree path
+func (ta *TreeAssertion) path(rel string) string {
+	return filepath.Join(ta.root, filepath.FromSlash(rel))
+}
+
+// HasDir asserts that a directory exists
+func (ta *TreeAssertion) HasDir(rel string) *TreeAssertion {
+	ta.t.Helper()
+
+	info, err := os.Stat(ta.path(rel))
+	if err != nil {
+		ta.t.Errorf("expected directory %s: %v", rel, err)
+	} else if !info.IsDir() {
+		ta.t.Errorf("expected %s to be a directory", rel)
+	}
+	return ta
+}
+
+// HasFile asserts that a regular file exists
+func (ta *TreeAssertion) HasFile(rel string) *TreeAssertion {
+	ta.t.Helper()
+
+	info, err := os.Stat(ta.path(rel))
+	if err != nil {
+		ta.t.Errorf("expected file %s: %v", rel, err)
+	} else if !info.Mode().IsRegular() {
+		ta.t.Errorf("expected %s to be a regular file", rel)
+	}
+	return ta
+}
+
+// HasSymlink asserts that a symbolic link exists and points at target
+func (ta *TreeAssertion) HasSymlink(rel, target string) *TreeAssertion {
+	ta.t.Helper()
+
+	actual, err := os.Readlink(ta.path(rel))
+	if err != nil {
+		ta.t.Errorf("expected symlink %s: %v", rel, err)
+	} else if actual != target {
+		ta.t.Errorf("expected symlink %s -> %s, got -> %s", rel, target, actual)
+	}
+	return ta
+}
+
+// NotExists asserts that nothing exists at the path
+func (ta *TreeAssertion) NotExists(rel string) *TreeAssertion {
+	ta.t.Helper()
+
+	if _, err := os.Lstat(ta.path(rel)); err == nil {
+		ta.t.Errorf("expected %s not to exist", rel)
+	}
+	return ta
+}
+
+// FileEquals asserts that a file has exactly the given content
+func (ta *TreeAssertion) FileEquals(rel, expected string) *TreeAssertion {
+	ta.t.Helper()
+
+	content, err := os.ReadFile(ta.path(rel))
+	if err != nil {
+		ta.t.Errorf("failed to read %s: %v", rel, err)
+	} else if string(content) != expected {
+		ta.t.Errorf("unexpected content in %s:\n--- expected ---\n%s\n--- actual ---\n%s", rel, expected, content)
+	}
+	return ta
+}
+
+// FileContains asserts that a file contains every given substring
+func (ta *TreeAssertion) FileContains(rel string, substrings ...string) *TreeAssertion {
+	ta.t.Helper()
+
+	content, err := os.ReadFile(ta.path(rel))
+	if err != nil {
+		ta.t.Errorf("failed to read %s: %v", rel, err)
+		return ta
+	}
+	for _, substring := range substrings {
+		if !strings.Contains(string(content), substring) {
+			ta.t.Errorf("expected %s to contain %q", rel, substring)
+		}
+	}
+	return ta
+}
+
+// FileMode asserts the permission bits of a file or directory
+func (ta *TreeAssertion) FileMode(rel string, mode os.FileMode) *TreeAssertion {
+	ta.t.Helper()
+
+	info, err := os.Stat(ta.path(rel))
+	if err != nil {
+		ta.t.Errorf("failed to stat %s: %v", rel, err)
+	} else if info.Mode().Perm() != mode.Perm() {
+		ta.t.Errorf("expected %s to have mode %o, got %o", rel, mode.Perm(), info.Mode().Perm())
+	}
+	return ta
+}
+
+// Empty asserts that the tree root contains no entries
+func (ta *TreeAssertion) Empty() *TreeAssertion {
+	ta.t.Helper()
+
+	if entries := ListTree(ta.t, ta.root); len(entries) > 0 {
+		ta.t.Errorf("expected %s to be empty, found: %s", ta.root, strings.Join(entries, ", "))
+	}
+	return ta
+}
+
+// Entries asserts that the tree contains exactly the given entries.
+// Directories are listed with a trailing slash; .git is ignored.
+func (ta *TreeAssertion) Entries(expected ...string) *TreeAssertion {
+	ta.t.Helper()
+
+	actual := ListTree(ta.t, ta.root)
+	sorted := append([]string{}, expected...)
+	sort.Strings(sorted)
+
+	if strings.Join(actual, "\n") != strings.Join(sorted, "\n") {
+		ta.t.Errorf("unexpected tree under %s:\n--- expected ---\n%s\n--- actual ---\n%s",
+			ta.root, strings.Join(sorted, "\n"), strings.Join(actual, "\n"))
+	}
+	return ta
+}
+
+// ListTree returns the sorted, slash-separated entries below root.
+// Directories carry a trailing slash and .git directories are skipped.
+func ListTree(t testing.TB, root string) []string {
+	t.Helper()
+
+	entries := []string{}
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+		if info.IsDir() && info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if info.IsDir() {
+			rel += "/"
+		}
+		entries = append(entries, rel)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to walk %s: %v", root, err)
+	}
+
+	sort.Strings(entries)
+	return entries
+}
@@ -0,0 +1,124 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Batch item statuses
+const (
+	BatchSucceeded = "succeeded"
+	BatchFailed    = "failed"
+	BatchSkipped   = "skipped"
+)
+
+// BatchItem is the outcome of a single item of a batch operation
+type BatchItem struct {
+	Name   string
+	Status string
+	Reason string
+}
+
+// PartialFailureError is returned when some items of a batch failed while
+// others succeeded
+type PartialFailureError struct {
+	Failed int
+	Total  int
+}
+
+func (e *PartialFailureError) Error() string {
+	return fmt.Sprintf("%d of %d items failed", e.Failed, e.Total)
+}
+
+// BatchSummary collects per-item results of a batch operation so that one
+// failing item does not stop the others. It is safe for concurrent use.
+type BatchSummary struct {
+	mu    sync.Mutex
+	items []BatchItem
+}
+
+// NewBatchSummary creates an empty BatchSummary
+func NewBatchSummary() *BatchSummary {
+	return &BatchSummary{}
+}
+
+// Succeeded records a successful item
+func (b *BatchSummary) Succeeded(name string) {
+	b.add(BatchItem{Name: name, Status: BatchSucceeded})
+}
+
+// Failed records a failed item and its error
+func (b *BatchSummary) Failed(name string, err error) {
+	b.add(BatchItem{Name: name, Status: BatchFailed, Reason: err.Error()})
+}
+
+// Skipped records an item that was not processed and why
+func (b *BatchSummary) Skipped(name, reason string) {
+	b.add(BatchItem{Name: name, Status: BatchSkipped, Reason: reason})
+}
+
+// add records an item
+func (b *BatchSummary) add(item BatchItem) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.items = append(b.items, item)
+}
+
+// Items returns the recorded items in the order they were recorded
+func (b *BatchSummary) Items() []BatchItem {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]BatchItem{}, b.items...)
+}
+
+// Counts returns the number of succeeded, failed and skipped items
+func (b *BatchSummary) Counts() (succeeded, failed, skipped int) {
+	for _, item := range b.Items() {
+		switch item.Status {
+		case BatchSucceeded:
+			succeeded++
+		case BatchFailed:
+			failed++
+		case BatchSkipped:
+			skipped++
+		}
+	}
+	return succeeded, failed, skipped
+}
+
+// Err returns nil when no item failed, a PartialFailureError when only some
+// items failed, and a plain error when every processed item failed
+func (b *BatchSummary) Err() error {
+	succeeded, failed, _ := b.Counts()
+	switch {
+	case failed == 0:
+		return nil
+	case succeeded > 0:
+		return &PartialFailureError{Failed: failed, Total: len(b.Items())}
+	default:
+		return fmt.Errorf("all %d items failed", failed)
+	}
+}
+
+// Print writes the summary table and totals
+func (b *BatchSummary) Print(outputMgr *OutputManager) {
+	rows := [][]string{}
+	for _, item := range b.Items() {
+		reason := item.Reason
+		if reason == "" {
+			reason = "-"
+		}
+		rows = append(rows, []string{item.Name, item.Status, reason})
+	}
+
+	succeeded, failed, skipped := b.Counts()
+	outputMgr.Section("Summary")
+	outputMgr.Table([]string{"Item", "Status", "Reason"}, rows)
+	outputMgr.Info(fmt.Sprintf("%d succeeded, %d failed, %d skipped", succeeded, failed, skipped))
+}
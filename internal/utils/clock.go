@@ -0,0 +1,116 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SourceDateEpochEnv is the reproducible-builds variable that overrides the
+// fixed time used in deterministic mode
+const SourceDateEpochEnv = "SOURCE_DATE_EPOCH"
+
+// defaultDeterministicTime is the fixed time used in deterministic mode when
+// SOURCE_DATE_EPOCH is not set
+var defaultDeterministicTime = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var (
+	deterministic     bool
+	deterministicTime = defaultDeterministicTime
+)
+
+// SetDeterministic enables or disables deterministic mode. In deterministic
+// mode Now returns a fixed time and durations are reported as zero, so output
+// is stable across runs for golden tests, CI and generated documentation.
+// State recorded across runs, such as the history and the registry, keeps
+// the real time.
+func SetDeterministic(enabled bool) {
+	deterministic = enabled
+	deterministicTime = defaultDeterministicTime
+
+	if epoch := os.Getenv(SourceDateEpochEnv); epoch != "" {
+		if seconds, err := strconv.ParseInt(epoch, 10, 64); err == nil {
+			deterministicTime = time.Unix(seconds, 0).UTC()
+		}
+	}
+}
+
+// IsDeterministic reports whether deterministic mode is enabled
+func IsDeterministic() bool {
+	return deterministic
+}
+
+// Now returns the current time, or the fixed time in deterministic mode
+func Now() time.Time {
+	if deterministic {
+		return deterministicTime
+	}
+	return time.Now()
+}
+
+// Since returns the time elapsed since start, or zero in deterministic mode
+func Since(start time.Time) time.Duration {
+	if deterministic {
+		return 0
+	}
+	return time.Since(start)
+}
+
+// ParseAge parses a duration that may also be given in days or weeks, such
+// as "90d" or "2w", besides the units of time.ParseDuration
+func ParseAge(value string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if number, found := strings.CutSuffix(value, suffix); found {
+			count, err := strconv.ParseFloat(number, 64)
+			if err != nil || count < 0 {
+				return 0, fmt.Errorf("invalid duration '%s'", value)
+			}
+			return time.Duration(count * float64(unit)), nil
+		}
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid duration '%s' (e.g. 12h, 7d or 2w)", value)
+	}
+	return duration, nil
+}
+
+// FormatAge formats how long ago something happened in the largest
+// fitting unit, e.g. "45m", "3h", "2d", "5w" or "4mo"
+func FormatAge(d time.Duration) string {
+	const day = 24 * time.Hour
+	switch {
+	case d < time.Minute:
+		return "now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	case d < day:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	case d < 14*day:
+		return fmt.Sprintf("%dd", int(d/day))
+	case d < 60*day:
+		return fmt.Sprintf("%dw", int(d/(7*day)))
+	default:
+		return fmt.Sprintf("%dmo", int(d/(30*day)))
+	}
+}
+
+// SortedKeys returns the keys of a string-keyed map in sorted order
+func SortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
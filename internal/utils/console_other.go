@@ -0,0 +1,15 @@
+//go:build !windows
+
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+// legacyConsole reports whether output goes to a console without escape
+// sequence support, which only exists on Windows
+func legacyConsole() bool {
+	return false
+}
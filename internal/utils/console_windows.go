@@ -0,0 +1,36 @@
+//go:build windows
+
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// legacyConsole reports whether output goes to a Windows console without
+// virtual terminal support, which prints escape sequences as text. Virtual
+// terminal processing is enabled where the console supports it.
+func legacyConsole() bool {
+	for _, f := range []*os.File{os.Stderr, os.Stdout} {
+		handle := windows.Handle(f.Fd())
+		var mode uint32
+		if err := windows.GetConsoleMode(handle, &mode); err != nil {
+			// Not a console, e.g. a pipe or a mintty pseudo terminal
+			continue
+		}
+		if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+			continue
+		}
+		if err := windows.SetConsoleMode(handle, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING); err != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// maxDiffCells bounds the size of the table used to diff two texts
+const maxDiffCells = 4 << 20
+
+// DiffLine is a single line of a diff
+type DiffLine struct {
+	Op   byte // ' ' (context), '-' (removed) or '+' (added)
+	Text string
+}
+
+// IsBinary reports whether data looks like binary content
+func IsBinary(data []byte) bool {
+	if len(data) > 8000 {
+		data = data[:8000]
+	}
+	return bytes.IndexByte(data, 0) >= 0
+}
+
+// UnifiedDiff returns a unified diff of two texts with the given number of
+// context lines, or an empty string when they are equal
+func UnifiedDiff(oldName, newName, oldText, newText string, context int) string {
+	if oldText == newText {
+		return ""
+	}
+
+	oldLines := splitLines(oldText)
+	newLines := splitLines(newText)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+
+	if (len(oldLines)+1)*(len(newLines)+1) > maxDiffCells {
+		fmt.Fprintf(&b, "@@ files differ (%d → %d lines, too large to diff) @@\n", len(oldLines), len(newLines))
+		return b.String()
+	}
+
+	lines := diffLines(oldLines, newLines)
+
+	// Group changes into hunks surrounded by context lines
+	for start := 0; start < len(lines); {
+		if lines[start].Op == ' ' {
+			start++
+			continue
+		}
+
+		hunkStart := start - context
+		if hunkStart < 0 {
+			hunkStart = 0
+		}
+		hunkEnd := start
+		for i := start; i < len(lines); i++ {
+			if lines[i].Op != ' ' {
+				hunkEnd = i + 1
+			} else if i-hunkEnd >= 2*context {
+				break
+			}
+		}
+		hunkEnd += context
+		if hunkEnd > len(lines) {
+			hunkEnd = len(lines)
+		}
+
+		oldStart, newStart := lineNumbers(lines, hunkStart)
+		oldCount, newCount := 0, 0
+		for _, line := range lines[hunkStart:hunkEnd] {
+			if line.Op != '+' {
+				oldCount++
+			}
+			if line.Op != '-' {
+				newCount++
+			}
+		}
+
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+		for _, line := range lines[hunkStart:hunkEnd] {
+			b.WriteByte(line.Op)
+			b.WriteString(line.Text)
+			b.WriteByte('\n')
+		}
+
+		start = hunkEnd
+	}
+
+	return b.String()
+}
+
+// diffLines computes a line-level edit script from the longest common subsequence
+func diffLines(a, b []string) []DiffLine {
+	n, m := len(a), len(b)
+	width := m + 1
+	lcs := make([]int32, (n+1)*width)
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i*width+j] = lcs[(i+1)*width+j+1] + 1
+			} else if lcs[(i+1)*width+j] >= lcs[i*width+j+1] {
+				lcs[i*width+j] = lcs[(i+1)*width+j]
+			} else {
+				lcs[i*width+j] = lcs[i*width+j+1]
+			}
+		}
+	}
+
+	lines := []DiffLine{}
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, DiffLine{Op: ' ', Text: a[i]})
+			i++
+			j++
+		case lcs[(i+1)*width+j] >= lcs[i*width+j+1]:
+			lines = append(lines, DiffLine{Op: '-', Text: a[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{Op: '+', Text: b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		lines = append(lines, DiffLine{Op: '-', Text: a[i]})
+	}
+	for ; j < m; j++ {
+		lines = append(lines, DiffLine{Op: '+', Text: b[j]})
+	}
+	return lines
+}
+
+// lineNumbers returns the 1-based old and new line numbers at index
+func lineNumbers(lines []DiffLine, index int) (int, int) {
+	oldLine, newLine := 1, 1
+	for _, line := range lines[:index] {
+		if line.Op != '+' {
+			oldLine++
+		}
+		if line.Op != '-' {
+			newLine++
+		}
+	}
+	return oldLine, newLine
+}
+
+// hunkRange formats a hunk range; empty ranges refer to the preceding line
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// splitLines splits text into lines without their line terminators
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
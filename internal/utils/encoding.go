@@ -0,0 +1,101 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Line ending styles for generated files
+const (
+	LineEndingsLF     = "lf"
+	LineEndingsCRLF   = "crlf"
+	LineEndingsNative = "native"
+)
+
+// utf8BOM is the UTF-8 byte order mark
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// GetAvailableLineEndings returns the supported line ending styles
+func GetAvailableLineEndings() []string {
+	return []string{LineEndingsLF, LineEndingsCRLF, LineEndingsNative}
+}
+
+// Encoding controls how generated text files are written. The zero value
+// writes content exactly as generated.
+type Encoding struct {
+	// LineEndings is "lf", "crlf", "native" or empty to keep line endings
+	LineEndings string
+
+	// BOM lists file extensions (e.g. ".cs") or base names that get a UTF-8
+	// byte order mark; "*" matches every file
+	BOM []string
+}
+
+// Apply returns content encoded for the file at path. Binary content is
+// returned unchanged.
+func (e Encoding) Apply(path string, content []byte) []byte {
+	if IsBinary(content) {
+		return content
+	}
+
+	hasBOM := bytes.HasPrefix(content, utf8BOM)
+	body := bytes.TrimPrefix(content, utf8BOM)
+
+	switch e.lineEnding() {
+	case "\n":
+		body = bytes.ReplaceAll(body, []byte("\r\n"), []byte("\n"))
+	case "\r\n":
+		body = bytes.ReplaceAll(body, []byte("\r\n"), []byte("\n"))
+		body = bytes.ReplaceAll(body, []byte("\n"), []byte("\r\n"))
+	}
+
+	if hasBOM || e.wantsBOM(path) {
+		return append(append([]byte{}, utf8BOM...), body...)
+	}
+	return body
+}
+
+// lineEnding returns the line terminator to write, or "" to keep content as is
+func (e Encoding) lineEnding() string {
+	switch e.LineEndings {
+	case LineEndingsLF:
+		return "\n"
+	case LineEndingsCRLF:
+		return "\r\n"
+	case LineEndingsNative:
+		if runtime.GOOS == "windows" {
+			return "\r\n"
+		}
+		return "\n"
+	}
+	return ""
+}
+
+// wantsBOM reports whether the file at path should start with a BOM
+func (e Encoding) wantsBOM(path string) bool {
+	base := filepath.Base(path)
+	ext := strings.ToLower(filepath.Ext(base))
+
+	for _, pattern := range e.BOM {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		switch {
+		case pattern == "*":
+			return true
+		case strings.HasPrefix(pattern, "."):
+			if pattern == ext {
+				return true
+			}
+		case pattern == strings.ToLower(base):
+			return true
+		}
+	}
+	return false
+}
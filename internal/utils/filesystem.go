@@ -0,0 +1,301 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FileSystem abstracts the filesystem calls made by FileSystemOperations so
+// that operations can run against the real disk or an in-memory overlay
+type FileSystem interface {
+	Stat(name string) (os.FileInfo, error)
+	Lstat(name string) (os.FileInfo, error)
+	MkdirAll(path string, perm os.FileMode) error
+	WriteFile(name string, data []byte, perm os.FileMode) error
+	ReadFile(name string) ([]byte, error)
+	Remove(name string) error
+	Symlink(oldname, newname string) error
+	Chmod(name string, mode os.FileMode) error
+}
+
+// OSFileSystem is a FileSystem backed by the real disk
+type OSFileSystem struct{}
+
+// NewOSFileSystem creates a new OSFileSystem instance
+func NewOSFileSystem() *OSFileSystem {
+	return &OSFileSystem{}
+}
+
+func (OSFileSystem) Stat(name string) (os.FileInfo, error)  { return os.Stat(name) }
+func (OSFileSystem) Lstat(name string) (os.FileInfo, error) { return os.Lstat(name) }
+func (OSFileSystem) MkdirAll(path string, perm os.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
+func (OSFileSystem) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+func (OSFileSystem) ReadFile(name string) ([]byte, error)      { return os.ReadFile(name) }
+func (OSFileSystem) Remove(name string) error                  { return os.Remove(name) }
+func (OSFileSystem) Symlink(oldname, newname string) error     { return os.Symlink(oldname, newname) }
+func (OSFileSystem) Chmod(name string, mode os.FileMode) error { return os.Chmod(name, mode) }
+
+// memEntry is a single file, directory or symlink held by MemFileSystem
+type memEntry struct {
+	data    []byte
+	mode    os.FileMode
+	modTime time.Time
+	target  string // symlink target
+}
+
+// MemFileSystem is an in-memory FileSystem layered over an optional read-only
+// base. Writes never reach the base, which makes it suitable for dry runs that
+// execute the full pipeline and for tests that must not touch the real disk.
+type MemFileSystem struct {
+	mu      sync.RWMutex
+	base    FileSystem
+	entries map[string]*memEntry
+	removed map[string]bool
+	changes []string
+}
+
+// NewMemFileSystem creates a new MemFileSystem over base (nil for an empty filesystem)
+func NewMemFileSystem(base FileSystem) *MemFileSystem {
+	return &MemFileSystem{
+		base:    base,
+		entries: make(map[string]*memEntry),
+		removed: make(map[string]bool),
+	}
+}
+
+// Change describes an entry created or modified in a MemFileSystem
+type Change struct {
+	Path  string
+	IsDir bool
+	Size  int64
+	Mode  os.FileMode
+
+	// Target is where a symlink points
+	Target string
+}
+
+// Changes returns every entry written to the overlay, sorted by path
+func (m *MemFileSystem) Changes() []Change {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	changes := make([]Change, 0, len(m.changes))
+	for _, path := range m.changes {
+		entry, exists := m.entries[path]
+		if !exists {
+			continue
+		}
+		changes = append(changes, Change{
+			Path:   path,
+			IsDir:  entry.mode.IsDir(),
+			Size:   int64(len(entry.data)),
+			Mode:   entry.mode,
+			Target: entry.target,
+		})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+// lookup returns the overlay entry for a path, falling back to the base
+func (m *MemFileSystem) lookup(name string, follow bool) (os.FileInfo, error) {
+	name = filepath.Clean(name)
+
+	if entry, exists := m.entries[name]; exists {
+		if follow && entry.mode&os.ModeSymlink != 0 {
+			target := entry.target
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(filepath.Dir(name), target)
+			}
+			return m.lookup(target, true)
+		}
+		return &memFileInfo{name: filepath.Base(name), entry: entry}, nil
+	}
+
+	if m.removed[name] || m.base == nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+
+	if follow {
+		return m.base.Stat(name)
+	}
+	return m.base.Lstat(name)
+}
+
+func (m *MemFileSystem) Stat(name string) (os.FileInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lookup(name, true)
+}
+
+func (m *MemFileSystem) Lstat(name string) (os.FileInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lookup(name, false)
+}
+
+func (m *MemFileSystem) MkdirAll(path string, perm os.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	path = filepath.Clean(path)
+
+	// Collect missing ancestors from the top down
+	missing := []string{}
+	for current := path; ; current = filepath.Dir(current) {
+		info, err := m.lookup(current, true)
+		if err == nil {
+			if !info.IsDir() {
+				return &fs.PathError{Op: "mkdir", Path: current, Err: fmt.Errorf("not a directory")}
+			}
+			break
+		}
+		missing = append(missing, current)
+		if filepath.Dir(current) == current {
+			break
+		}
+	}
+
+	for i := len(missing) - 1; i >= 0; i-- {
+		m.put(missing[i], &memEntry{mode: os.ModeDir | perm.Perm(), modTime: Now()})
+	}
+	return nil
+}
+
+func (m *MemFileSystem) WriteFile(name string, data []byte, perm os.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name = filepath.Clean(name)
+
+	parent, err := m.lookup(filepath.Dir(name), true)
+	if err != nil || !parent.IsDir() {
+		return &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	mode := perm.Perm()
+	if existing, err := m.lookup(name, true); err == nil {
+		if existing.IsDir() {
+			return &fs.PathError{Op: "open", Path: name, Err: fmt.Errorf("is a directory")}
+		}
+		// Like os.WriteFile, existing files keep their permissions
+		mode = existing.Mode().Perm()
+	}
+
+	content := make([]byte, len(data))
+	copy(content, data)
+	m.put(name, &memEntry{data: content, mode: mode, modTime: Now()})
+	return nil
+}
+
+func (m *MemFileSystem) ReadFile(name string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	name = filepath.Clean(name)
+
+	info, err := m.lookup(name, true)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fmt.Errorf("is a directory")}
+	}
+	if memInfo, ok := info.(*memFileInfo); ok {
+		content := make([]byte, len(memInfo.entry.data))
+		copy(content, memInfo.entry.data)
+		return content, nil
+	}
+	return m.base.ReadFile(name)
+}
+
+func (m *MemFileSystem) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name = filepath.Clean(name)
+	if _, err := m.lookup(name, false); err != nil {
+		return err
+	}
+
+	delete(m.entries, name)
+	m.removed[name] = true
+	return nil
+}
+
+func (m *MemFileSystem) Symlink(oldname, newname string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	newname = filepath.Clean(newname)
+	if _, err := m.lookup(newname, false); err == nil {
+		return &fs.PathError{Op: "symlink", Path: newname, Err: fs.ErrExist}
+	}
+
+	m.put(newname, &memEntry{mode: os.ModeSymlink | 0777, modTime: Now(), target: oldname})
+	return nil
+}
+
+func (m *MemFileSystem) Chmod(name string, mode os.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name = filepath.Clean(name)
+
+	info, err := m.lookup(name, true)
+	if err != nil {
+		return err
+	}
+
+	entry := &memEntry{mode: info.Mode()&^os.ModePerm | mode.Perm(), modTime: Now()}
+	if memInfo, ok := info.(*memFileInfo); ok {
+		entry.data = memInfo.entry.data
+	} else if !info.IsDir() {
+		// Copy the base file into the overlay before changing its mode
+		if entry.data, err = m.base.ReadFile(name); err != nil {
+			return err
+		}
+	}
+	m.put(name, entry)
+	return nil
+}
+
+// put stores an entry and records it as a change
+func (m *MemFileSystem) put(name string, entry *memEntry) {
+	if _, exists := m.entries[name]; !exists {
+		m.changes = append(m.changes, name)
+	}
+	m.entries[name] = entry
+	delete(m.removed, name)
+}
+
+// memFileInfo implements os.FileInfo for MemFileSystem entries
+type memFileInfo struct {
+	name  string
+	entry *memEntry
+}
+
+func (fi *memFileInfo) Name() string       { return fi.name }
+func (fi *memFileInfo) Size() int64        { return int64(len(fi.entry.data)) }
+func (fi *memFileInfo) Mode() os.FileMode  { return fi.entry.mode }
+func (fi *memFileInfo) ModTime() time.Time { return fi.entry.modTime }
+func (fi *memFileInfo) IsDir() bool        { return fi.entry.mode.IsDir() }
+func (fi *memFileInfo) Sys() interface{}   { return nil }
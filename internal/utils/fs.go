@@ -13,7 +13,6 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/pterm/pterm"
 )
@@ -22,52 +21,152 @@ import (
 type FileSystemOperations struct {
 	DryRun bool
 	Backup bool
+	FS     FileSystem
+
+	// Silent suppresses the message printed for every operation
+	Silent bool
+
+	// Output receives the messages of the operations, so they are written
+	// like the caller's own; nil prints them directly
+	Output *OutputManager
+
+	// Encoding sets the line endings and byte order mark of created files
+	Encoding Encoding
+
+	// EnforceModes applies the requested mode with chmod when the umask
+	// stripped permission bits from a created path
+	EnforceModes bool
+
+	source         string
+	records        []FileRecord
+	directories    []string
+	symlinks       []string
+	modeMismatches []ModeMismatch
+}
+
+// FileRecord describes a file written by FileSystemOperations and what produced it
+type FileRecord struct {
+	Path   string
+	Source string
 }
 
-// NewFileSystemOperations creates a new FileSystemOperations instance
+// NewFileSystemOperations creates a new FileSystemOperations instance.
+// In dry-run mode operations are executed against an in-memory overlay of
+// the real disk so the result of the whole run can be reported exactly.
 func NewFileSystemOperations(dryRun, backup bool) *FileSystemOperations {
+	var fileSystem FileSystem = NewOSFileSystem()
+	if dryRun {
+		fileSystem = NewMemFileSystem(fileSystem)
+	}
+
+	return NewFileSystemOperationsWithFS(fileSystem, dryRun, backup)
+}
+
+// NewFileSystemOperationsWithFS creates a new FileSystemOperations instance
+// operating on the given filesystem
+func NewFileSystemOperationsWithFS(fileSystem FileSystem, dryRun, backup bool) *FileSystemOperations {
 	return &FileSystemOperations{
 		DryRun: dryRun,
 		Backup: backup,
+		FS:     fileSystem,
+	}
+}
+
+// EnableRetry retries operations on the real disk that fail with transient
+// errors. Dry runs operate in memory and are left unchanged.
+func (fs *FileSystemOperations) EnableRetry(policy RetryPolicy) {
+	if fs.DryRun || policy.Attempts <= 0 {
+		return
+	}
+	fs.FS = NewRetryFileSystem(fs.FS, policy)
+}
+
+// DryRunChanges returns the entries a dry run would have created or
+// modified, or nil when operations are not backed by an in-memory filesystem
+func (fs *FileSystemOperations) DryRunChanges() []Change {
+	if memFS, ok := fs.FS.(*MemFileSystem); ok {
+		return memFS.Changes()
+	}
+	return nil
+}
+
+// SetSource sets the label recorded for files written from now on,
+// e.g. "template:go" or "generator:readme"
+func (fs *FileSystemOperations) SetSource(source string) {
+	fs.source = source
+}
+
+// Records returns the files written so far, in order
+func (fs *FileSystemOperations) Records() []FileRecord {
+	return fs.records
+}
+
+// Directories returns the directories created so far, including missing
+// parents, outermost first
+func (fs *FileSystemOperations) Directories() []string {
+	return fs.directories
+}
+
+// Symlinks returns the symlinks created so far, in order
+func (fs *FileSystemOperations) Symlinks() []string {
+	return fs.symlinks
+}
+
+// missingDirectories returns path and those of its parents that do not
+// exist yet, outermost first
+func (fs *FileSystemOperations) missingDirectories(path string) []string {
+	missing := []string{}
+	for dir := path; ; dir = filepath.Dir(dir) {
+		if _, err := fs.FS.Lstat(dir); err == nil {
+			break
+		}
+		missing = append([]string{dir}, missing...)
+		if filepath.Dir(dir) == dir {
+			break
+		}
 	}
+	return missing
 }
 
 // CreateDirectory creates a directory with the specified permissions
 // If the directory already exists, it returns nil (no error)
 func (fs *FileSystemOperations) CreateDirectory(path string, mode os.FileMode) error {
-	if fs.DryRun {
-		pterm.Info.Printf("[DRY RUN] Would create directory: %s (mode: %o)", path, mode)
-		return nil
-	}
-
 	// Check if directory already exists
-	if info, err := os.Stat(path); err == nil {
+	if info, err := fs.FS.Stat(path); err == nil {
 		if info.IsDir() {
-			pterm.Debug.Printf("Directory already exists: %s", path)
+			fs.debugf("Directory already exists: %s", path)
 			return nil
 		}
 		return fmt.Errorf("path exists but is not a directory: %s", path)
 	}
 
 	// Create directory with parents
-	if err := os.MkdirAll(path, mode); err != nil {
+	missing := fs.missingDirectories(path)
+	if err := fs.FS.MkdirAll(path, mode); err != nil {
 		return fmt.Errorf("failed to create directory %s: %w", path, err)
 	}
+	fs.directories = append(fs.directories, missing...)
+	if err := fs.verifyMode(path, mode); err != nil {
+		return err
+	}
 
-	pterm.Success.Printf("Created directory: %s", path)
+	if fs.Silent {
+		return nil
+	}
+	if fs.DryRun {
+		fs.infof("[DRY RUN] Would create directory: %s (mode: %o)", path, mode)
+		return nil
+	}
+
+	fs.successf("Created directory: %s", path)
 	return nil
 }
 
 // CreateFile creates a file with the specified content
 func (fs *FileSystemOperations) CreateFile(path, content string, mode os.FileMode) error {
-	if fs.DryRun {
-		pterm.Info.Printf("[DRY RUN] Would create file: %s (size: %d bytes)", path, len(content))
-		return nil
-	}
-
 	// Check if file already exists and backup if needed
 	if fs.Backup {
-		if _, err := os.Stat(path); err == nil {
+		if _, err := fs.FS.Stat(path); err == nil {
 			if err := fs.BackupFile(path); err != nil {
 				return fmt.Errorf("failed to backup existing file: %w", err)
 			}
@@ -76,42 +175,86 @@ func (fs *FileSystemOperations) CreateFile(path, content string, mode os.FileMod
 
 	// Ensure parent directory exists
 	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	missing := fs.missingDirectories(dir)
+	if err := fs.FS.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create parent directory %s: %w", dir, err)
 	}
+	fs.directories = append(fs.directories, missing...)
 
 	// Create and write file
-	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
-	if err != nil {
-		return fmt.Errorf("failed to create file %s: %w", path, err)
+	data := fs.Encoding.Apply(path, []byte(content))
+	existing, statErr := fs.FS.Lstat(path)
+	if err := fs.FS.WriteFile(path, data, mode); err != nil {
+		return fmt.Errorf("failed to write content to file %s: %w", path, err)
 	}
-	defer file.Close()
 
-	if _, err := file.WriteString(content); err != nil {
-		return fmt.Errorf("failed to write content to file %s: %w", path, err)
+	// Writing keeps the permissions of an existing file, so a file that
+	// becomes executable or private is changed explicitly
+	if statErr == nil && existing.Mode().IsRegular() && existing.Mode().Perm() != mode.Perm() {
+		if err := fs.FS.Chmod(path, mode.Perm()); err != nil {
+			return fmt.Errorf("failed to set permissions of %s: %w", path, err)
+		}
 	}
+	if err := fs.verifyMode(path, mode); err != nil {
+		return err
+	}
+	fs.records = append(fs.records, FileRecord{Path: path, Source: fs.source})
 
-	pterm.Success.Printf("Created file: %s", path)
+	if fs.Silent {
+		return nil
+	}
+	if fs.DryRun {
+		fs.infof("[DRY RUN] Would create file: %s (size: %d bytes)", path, len(data))
+		return nil
+	}
+
+	fs.successf("Created file: %s", path)
 	return nil
 }
 
 // BackupFile creates a backup of the specified file
 func (fs *FileSystemOperations) BackupFile(path string) error {
-	if fs.DryRun {
-		pterm.Info.Printf("[DRY RUN] Would backup file: %s", path)
-		return nil
-	}
-
 	// Generate backup filename with timestamp
-	timestamp := time.Now().Format("20060102-150405")
+	timestamp := Now().Format("20060102-150405")
 	backupPath := fmt.Sprintf("%s.backup-%s", path, timestamp)
 
 	// Copy file to backup location
-	if err := CopyFile(path, backupPath); err != nil {
+	if err := fs.copyFile(path, backupPath); err != nil {
 		return fmt.Errorf("failed to create backup %s: %w", backupPath, err)
 	}
 
-	pterm.Info.Printf("Created backup: %s", backupPath)
+	if fs.Silent {
+		return nil
+	}
+	if fs.DryRun {
+		fs.infof("[DRY RUN] Would backup file: %s", path)
+		return nil
+	}
+
+	fs.infof("Created backup: %s", backupPath)
+	return nil
+}
+
+// copyFile copies a file and its permissions within the operations filesystem
+func (fs *FileSystemOperations) copyFile(src, dst string) error {
+	info, err := fs.FS.Stat(src)
+	if err != nil {
+		return fmt.Errorf("failed to open source file %s: %w", src, err)
+	}
+
+	content, err := fs.FS.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("failed to read from source file: %w", err)
+	}
+
+	if err := fs.FS.WriteFile(dst, content, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to create destination file %s: %w", dst, err)
+	}
+
+	if err := fs.FS.Chmod(dst, info.Mode().Perm()); err != nil {
+		fs.warningf("Failed to copy file permissions: %v", err)
+	}
+
 	return nil
 }
 
@@ -171,6 +314,12 @@ func IsDirectory(path string) bool {
 	return info.IsDir()
 }
 
+// IsEmptyDir checks if a path is a directory without entries
+func IsEmptyDir(path string) bool {
+	entries, err := os.ReadDir(path)
+	return err == nil && len(entries) == 0
+}
+
 // IsFile checks if a path is a regular file
 func IsFile(path string) bool {
 	info, err := os.Stat(path)
@@ -220,32 +369,72 @@ func SanitizePath(path string) (string, error) {
 
 // CreateSymlink creates a symbolic link
 func (fs *FileSystemOperations) CreateSymlink(target, linkPath string) error {
-	if fs.DryRun {
-		pterm.Info.Printf("[DRY RUN] Would create symlink: %s -> %s", linkPath, target)
-		return nil
-	}
-
 	// Check if target exists
-	if !PathExists(target) {
+	if _, err := fs.FS.Stat(target); err != nil {
 		return fmt.Errorf("symlink target does not exist: %s", target)
 	}
 
 	// Remove existing link if it exists
-	if PathExists(linkPath) {
-		if err := os.Remove(linkPath); err != nil {
+	if _, err := fs.FS.Lstat(linkPath); err == nil {
+		if err := fs.FS.Remove(linkPath); err != nil {
 			return fmt.Errorf("failed to remove existing symlink %s: %w", linkPath, err)
 		}
 	}
 
 	// Create symlink
-	if err := os.Symlink(target, linkPath); err != nil {
+	if err := fs.FS.Symlink(target, linkPath); err != nil {
 		return fmt.Errorf("failed to create symlink %s -> %s: %w", linkPath, target, err)
 	}
+	fs.symlinks = append(fs.symlinks, linkPath)
 
-	pterm.Success.Printf("Created symlink: %s -> %s", linkPath, target)
+	if fs.Silent {
+		return nil
+	}
+	if fs.DryRun {
+		fs.infof("[DRY RUN] Would create symlink: %s -> %s", linkPath, target)
+		return nil
+	}
+
+	fs.successf("Created symlink: %s -> %s", linkPath, target)
 	return nil
 }
 
+// successf reports a completed operation
+func (fs *FileSystemOperations) successf(format string, args ...any) {
+	if fs.Output != nil {
+		fs.Output.Success(fmt.Sprintf(format, args...))
+		return
+	}
+	pterm.Success.Printf(format, args...)
+}
+
+// infof reports an operation that needs no attention
+func (fs *FileSystemOperations) infof(format string, args ...any) {
+	if fs.Output != nil {
+		fs.Output.Info(fmt.Sprintf(format, args...))
+		return
+	}
+	pterm.Info.Printf(format, args...)
+}
+
+// warningf reports a problem that does not fail the operation
+func (fs *FileSystemOperations) warningf(format string, args ...any) {
+	if fs.Output != nil {
+		fs.Output.Warning(fmt.Sprintf(format, args...))
+		return
+	}
+	pterm.Warning.Printf(format, args...)
+}
+
+// debugf reports a detail for debugging
+func (fs *FileSystemOperations) debugf(format string, args ...any) {
+	if fs.Output != nil {
+		fs.Output.Debug(fmt.Sprintf(format, args...))
+		return
+	}
+	pterm.Debug.Printf(format, args...)
+}
+
 // GetDirectorySize calculates the total size of a directory
 func GetDirectorySize(path string) (int64, error) {
 	var size int64
@@ -288,3 +477,33 @@ func EnsureDirectoryExists(path string) error {
 	}
 	return nil
 }
+
+// WriteFileAtomic writes data to path through a temporary file in the same
+// directory that is renamed over path, so readers see either the old or the
+// new content and a crash never leaves a truncated file
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file for %s: %w", path, err)
+	}
+	tmpPath := file.Name()
+
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpPath, perm)
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
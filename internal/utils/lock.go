@@ -0,0 +1,146 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lockPollInterval is how often a waiting invocation retries the lock
+const lockPollInterval = 100 * time.Millisecond
+
+// LockedError is returned when another invocation holds the lock of a
+// target directory for longer than the caller was willing to wait
+type LockedError struct {
+	Target string
+	PID    int
+	Waited time.Duration
+}
+
+func (e *LockedError) Error() string {
+	holder := "another mkcd invocation"
+	if e.PID > 0 {
+		holder = fmt.Sprintf("another mkcd invocation (pid %d)", e.PID)
+	}
+	if e.Waited > 0 {
+		return fmt.Sprintf("%s is still working on %s after waiting %s", holder, e.Target, FormatDuration(e.Waited))
+	}
+	return fmt.Sprintf("%s is working on %s", holder, e.Target)
+}
+
+// PathLock is an exclusive lock on a target directory, held through a lock
+// file in a lock directory rather than in the target itself, which may not
+// exist yet. The operating system releases it if the process dies.
+type PathLock struct {
+	file *os.File
+	path string
+}
+
+// LockFilePath returns the lock file for target inside lockDir, named by
+// a hash of its resolved path, so that invocations reaching the same
+// directory through different symlinks share the lock
+func LockFilePath(lockDir, target string) string {
+	sum := sha256.Sum256([]byte(resolvedPath(target)))
+	return filepath.Join(lockDir, hex.EncodeToString(sum[:8])+".lock")
+}
+
+// resolvedPath returns the absolute path with the symlinks of its deepest
+// existing ancestor resolved and the missing rest joined back on. A path
+// that cannot be resolved is only cleaned.
+func resolvedPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	ancestor, err := ExistingAncestor(path)
+	if err != nil {
+		return path
+	}
+	resolved, err := filepath.EvalSymlinks(ancestor)
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(ancestor, path)
+	if err != nil {
+		return path
+	}
+	return filepath.Join(resolved, rel)
+}
+
+// LockPath acquires the lock of target, retrying for up to wait while
+// another process holds it
+func LockPath(lockDir, target string, wait time.Duration) (*PathLock, error) {
+	if err := os.MkdirAll(lockDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+
+	path := LockFilePath(lockDir, target)
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file %s: %w", path, err)
+	}
+
+	start := time.Now()
+	for {
+		locked, err := tryLock(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if locked {
+			break
+		}
+
+		if time.Since(start) >= wait {
+			file.Close()
+			return nil, &LockedError{Target: target, PID: lockHolder(path), Waited: wait}
+		}
+		time.Sleep(lockPollInterval)
+	}
+
+	// Record the holder so a waiting invocation can name it
+	if err := file.Truncate(0); err == nil {
+		file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+
+	return &PathLock{file: file, path: path}, nil
+}
+
+// Unlock releases the lock
+func (l *PathLock) Unlock() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+
+	// Empty the file first so a stale PID is never reported
+	l.file.Truncate(0)
+	err := unlock(l.file)
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+	l.file = nil
+	return err
+}
+
+// lockHolder returns the PID recorded in a lock file, or 0 if unknown
+func lockHolder(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0
+	}
+	return pid
+}
@@ -0,0 +1,22 @@
+//go:build !unix && !windows
+
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import "os"
+
+// tryLock always succeeds where no file locking is available; concurrent
+// invocations are not serialized there
+func tryLock(file *os.File) (bool, error) {
+	return true, nil
+}
+
+// unlock is a no-op where no file locking is available
+func unlock(file *os.File) error {
+	return nil
+}
@@ -0,0 +1,47 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLockPathFollowsSymlinks checks that a directory reached through a
+// symlink shares the lock of its realDir path, also before it exists
+func TestLockPathFollowsSymlinks(t *testing.T) {
+	root := t.TempDir()
+	lockDir := filepath.Join(root, "locks")
+	realDir := filepath.Join(root, "realDir")
+	if err := os.Mkdir(realDir, 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	link := filepath.Join(root, "link")
+	if err := os.Symlink(realDir, link); err != nil {
+		t.Skipf("symlinks are not supported: %v", err)
+	}
+
+	for _, target := range []string{"", "api", filepath.Join("api", "src")} {
+		lock, err := LockPath(lockDir, filepath.Join(realDir, target), 0)
+		if err != nil {
+			t.Fatalf("failed to lock %s: %v", filepath.Join(realDir, target), err)
+		}
+
+		_, err = LockPath(lockDir, filepath.Join(link, target), 0)
+		var locked *LockedError
+		if !errors.As(err, &locked) {
+			t.Errorf("expected %s to be locked through the symlink, got %v", filepath.Join(link, target), err)
+		}
+		lock.Unlock()
+	}
+
+	if LockFilePath(lockDir, filepath.Join(realDir, "api")) == LockFilePath(lockDir, filepath.Join(realDir, "web")) {
+		t.Error("expected different directories to have different locks")
+	}
+}
@@ -0,0 +1,42 @@
+//go:build windows
+
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockOffsetHigh is the upper half of the offset of the locked byte. Windows
+// locks are mandatory, so the byte lies far past the recorded PID, which
+// other processes still read.
+const lockOffsetHigh = 1 << 30
+
+// lockRange returns the overlapped structure addressing the locked byte
+func lockRange() *windows.Overlapped {
+	return &windows.Overlapped{OffsetHigh: lockOffsetHigh}
+}
+
+// tryLock takes an exclusive LockFileEx lock on file without blocking,
+// reporting false when another process holds it
+func tryLock(file *os.File) (bool, error) {
+	flags := uint32(windows.LOCKFILE_EXCLUSIVE_LOCK | windows.LOCKFILE_FAIL_IMMEDIATELY)
+	err := windows.LockFileEx(windows.Handle(file.Fd()), flags, 0, 1, 0, lockRange())
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// unlock releases the LockFileEx lock on file
+func unlock(file *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, lockRange())
+}
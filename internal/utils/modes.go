@@ -0,0 +1,74 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// ModeMismatch describes a created path whose permissions differ from the
+// requested mode, typically because the umask stripped bits
+type ModeMismatch struct {
+	Path      string
+	Requested os.FileMode
+	Actual    os.FileMode
+	Enforced  bool // the requested mode was applied with chmod
+}
+
+// String describes the mismatch
+func (m ModeMismatch) String() string {
+	if m.Enforced {
+		return fmt.Sprintf("%s: mode %04o enforced (umask gave %04o)", m.Path, m.Requested, m.Actual)
+	}
+	return fmt.Sprintf("%s: requested mode %04o, got %04o", m.Path, m.Requested, m.Actual)
+}
+
+// ParseFileMode parses an octal permission string such as "755" or "0750"
+func ParseFileMode(mode string) (os.FileMode, error) {
+	value, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || value > 0777 {
+		return 0, fmt.Errorf("invalid mode '%s': expected octal permissions such as 755", mode)
+	}
+	return os.FileMode(value), nil
+}
+
+// verifyMode compares the permissions of a created path with the requested
+// mode, records any difference and, with EnforceModes, applies the mode
+func (fs *FileSystemOperations) verifyMode(path string, mode os.FileMode) error {
+	if fs.DryRun {
+		return nil
+	}
+
+	info, err := fs.FS.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to verify permissions of %s: %w", path, err)
+	}
+
+	actual := info.Mode().Perm()
+	if actual == mode.Perm() {
+		return nil
+	}
+
+	mismatch := ModeMismatch{Path: path, Requested: mode.Perm(), Actual: actual}
+	if fs.EnforceModes {
+		if err := fs.FS.Chmod(path, mode.Perm()); err != nil {
+			return fmt.Errorf("failed to set mode %04o on %s: %w", mode.Perm(), path, err)
+		}
+		mismatch.Enforced = true
+	}
+
+	fs.modeMismatches = append(fs.modeMismatches, mismatch)
+	return nil
+}
+
+// ModeMismatches returns the created paths whose permissions differed from
+// the requested mode, in order
+func (fs *FileSystemOperations) ModeMismatches() []ModeMismatch {
+	return fs.modeMismatches
+}
@@ -7,14 +7,22 @@ Licensed under the MIT License. See LICENSE file for details.
 package utils
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pterm/pterm"
 )
 
-// OutputManager handles formatted output for the mkcd application
+// OutputManager handles formatted output for the mkcd application. It is
+// safe for concurrent use: every message is written in one piece and never
+// interleaves with a prompt, and task managers created with Task buffer
+// their output until Flush.
 type OutputManager struct {
 	Colors       bool
 	Icons        bool
@@ -22,17 +30,61 @@ type OutputManager struct {
 	Quiet        bool
 	VerboseMode  bool
 	DebugMode    bool
+
+	// Plain output has no spinners, progress bars or redrawing prompts,
+	// for dumb terminals, CI and pipes; see IsPlainOutput
+	Plain bool
+
+	// mu serialises writes and prompts, so a prompt is never interleaved
+	// with output and two prompts never collide; task managers share it
+	// with the manager they were created from
+	mu *sync.Mutex
+
+	// task is the buffered output of a task manager, nil writes directly
+	task *taskOutput
+}
+
+// taskOutput is the output buffered by a task manager
+type taskOutput struct {
+	name string
+
+	// mu guards the buffer alone, so output captured from pterm while
+	// another manager writes does not wait for that write
+	mu     sync.Mutex
+	buffer strings.Builder
+	titled bool // the section title was written by an earlier flush
+
+	// capturing is set while the task captures pterm, guarded by the
+	// output lock
+	capturing bool
 }
 
+// output is where messages go, set by SetOutput; pterm writes there too
+// unless a task captures it
+var output io.Writer = os.Stdout
+
+// stdinReader reads the answers to plain prompts, shared so that input
+// buffered for one prompt is not lost to the next
+var stdinReader = bufio.NewReader(os.Stdin)
+
 // NewOutputManager creates a new OutputManager instance
 func NewOutputManager(colors, icons, progressBars, quiet, verbose, debug bool) *OutputManager {
+	// Spinners animate in time, which deterministic output must avoid,
+	// and redraw lines, which plain output cannot
+	plain := IsPlainOutput()
+	if IsDeterministic() || plain {
+		progressBars = false
+	}
+
 	om := &OutputManager{
-		Colors:       colors,
+		Colors:       colors && !IsDumbTerminal(),
 		Icons:        icons,
 		ProgressBars: progressBars,
 		Quiet:        quiet,
 		VerboseMode:  verbose,
 		DebugMode:    debug,
+		Plain:        plain,
+		mu:           &sync.Mutex{},
 	}
 
 	// Configure pterm based on settings
@@ -55,11 +107,130 @@ func (om *OutputManager) configurePterm() {
 		pterm.EnableDebugMessages()
 	}
 
-	if !om.VerboseMode && !om.DebugMode {
+	if (!om.VerboseMode && !om.DebugMode) || IsDumbTerminal() {
 		pterm.DisableStyling()
 	}
 }
 
+// SetOutput directs all output to w, including what is printed through
+// pterm directly
+func SetOutput(w io.Writer) {
+	output = w
+	setPtermOutput(w)
+}
+
+// setPtermOutput directs what pterm prints to w. The prefix printers keep
+// the writer they were created with, so they are redirected one by one.
+func setPtermOutput(w io.Writer) {
+	pterm.SetDefaultOutput(w)
+	for _, printer := range []*pterm.PrefixPrinter{
+		&pterm.Info, &pterm.Warning, &pterm.Success, &pterm.Error,
+		&pterm.Fatal, &pterm.Debug, &pterm.Description,
+	} {
+		printer.Writer = w
+	}
+}
+
+// Task returns an OutputManager for a task running concurrently with others.
+// Its output is buffered and written as a single section titled name by
+// Flush, so the output of parallel tasks never interleaves.
+func (om *OutputManager) Task(name string) *OutputManager {
+	task := &OutputManager{
+		Colors:       om.Colors,
+		Icons:        om.Icons,
+		ProgressBars: om.ProgressBars,
+		Quiet:        om.Quiet,
+		VerboseMode:  om.VerboseMode,
+		DebugMode:    om.DebugMode,
+		Plain:        om.Plain,
+		mu:           om.mu,
+		task:         &taskOutput{name: name},
+	}
+	return task
+}
+
+// Capture buffers what is printed through pterm directly, e.g. by the
+// Git and template packages, with the task's messages until release is
+// called, so it keeps its place among them. Only one task can capture at
+// a time, so tasks running concurrently must not.
+func (om *OutputManager) Capture() (release func()) {
+	if om.task == nil {
+		return func() {}
+	}
+
+	om.mu.Lock()
+	defer om.mu.Unlock()
+	om.task.capturing = true
+	setPtermOutput(om.Writer())
+	return func() {
+		om.mu.Lock()
+		defer om.mu.Unlock()
+		om.task.capturing = false
+		setPtermOutput(output)
+	}
+}
+
+// Flush writes the buffered output of a task manager
+func (om *OutputManager) Flush() {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+	om.flushLocked()
+}
+
+// flushLocked writes the buffered output, titled by the task's section
+// the first time; the caller holds the output lock
+func (om *OutputManager) flushLocked() {
+	if om.task == nil {
+		return
+	}
+
+	om.task.mu.Lock()
+	defer om.task.mu.Unlock()
+	if om.task.buffer.Len() == 0 {
+		return
+	}
+	if !om.task.titled {
+		fmt.Fprint(output, om.renderSection(om.task.name))
+		om.task.titled = true
+	}
+	fmt.Fprint(output, om.task.buffer.String())
+	om.task.buffer.Reset()
+}
+
+// write outputs a rendered message in one piece, or buffers it for a task
+// manager
+func (om *OutputManager) write(text string) {
+	if om.Quiet || text == "" {
+		return
+	}
+
+	if om.task != nil {
+		om.task.mu.Lock()
+		defer om.task.mu.Unlock()
+		om.task.buffer.WriteString(text)
+		return
+	}
+
+	om.mu.Lock()
+	defer om.mu.Unlock()
+	pterm.Print(text)
+}
+
+// prompt runs an interactive prompt while holding the output lock. A task's
+// buffered output is written first so the question appears with its
+// context, and the prompt is shown even while the task captures pterm.
+func (om *OutputManager) prompt(show func() error) error {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+
+	om.flushLocked()
+	if om.task != nil && om.task.capturing {
+		setPtermOutput(output)
+		defer setPtermOutput(om.Writer())
+	}
+	return show()
+}
+
 // Success prints a success message
 func (om *OutputManager) Success(message string) {
 	if om.Quiet {
@@ -67,9 +238,9 @@ func (om *OutputManager) Success(message string) {
 	}
 
 	if om.Icons {
-		pterm.Success.Println(message)
+		om.write(pterm.Success.Sprintln(message))
 	} else {
-		pterm.Println(pterm.Green(message))
+		om.write(pterm.Sprintln(pterm.Green(message)))
 	}
 }
 
@@ -80,9 +251,9 @@ func (om *OutputManager) Error(message string) {
 	}
 
 	if om.Icons {
-		pterm.Error.Println(message)
+		om.write(pterm.Error.Sprintln(message))
 	} else {
-		pterm.Println(pterm.Red(message))
+		om.write(pterm.Sprintln(pterm.Red(message)))
 	}
 }
 
@@ -93,9 +264,9 @@ func (om *OutputManager) Warning(message string) {
 	}
 
 	if om.Icons {
-		pterm.Warning.Println(message)
+		om.write(pterm.Warning.Sprintln(message))
 	} else {
-		pterm.Println(pterm.Yellow(message))
+		om.write(pterm.Sprintln(pterm.Yellow(message)))
 	}
 }
 
@@ -106,9 +277,9 @@ func (om *OutputManager) Info(message string) {
 	}
 
 	if om.Icons {
-		pterm.Info.Println(message)
+		om.write(pterm.Info.Sprintln(message))
 	} else {
-		pterm.Println(pterm.Cyan(message))
+		om.write(pterm.Sprintln(pterm.Cyan(message)))
 	}
 }
 
@@ -118,7 +289,7 @@ func (om *OutputManager) Debug(message string) {
 		return
 	}
 
-	pterm.Debug.Println(message)
+	om.write(pterm.Debug.Sprintln(message))
 }
 
 // Verbose prints a verbose message
@@ -127,7 +298,7 @@ func (om *OutputManager) Verbose(message string) {
 		return
 	}
 
-	pterm.Println(pterm.Gray(message))
+	om.write(pterm.Sprintln(pterm.Gray(message)))
 }
 
 // Print prints a regular message
@@ -136,7 +307,7 @@ func (om *OutputManager) Print(message string) {
 		return
 	}
 
-	pterm.Println(message)
+	om.write(pterm.Sprintln(message))
 }
 
 // Printf prints a formatted message
@@ -145,7 +316,24 @@ func (om *OutputManager) Printf(format string, args ...interface{}) {
 		return
 	}
 
-	pterm.Printf(format, args...)
+	om.write(pterm.Sprintf(format, args...))
+}
+
+// Writer returns a writer for the output of commands run by mkcd, such as
+// --then. What it receives is written like messages: buffered for task
+// managers and dropped when quiet.
+func (om *OutputManager) Writer() io.Writer {
+	return outputWriter{om: om}
+}
+
+// outputWriter is the io.Writer returned by Writer
+type outputWriter struct {
+	om *OutputManager
+}
+
+func (w outputWriter) Write(p []byte) (int, error) {
+	w.om.write(string(p))
+	return len(p), nil
 }
 
 // Header prints a styled header
@@ -155,10 +343,9 @@ func (om *OutputManager) Header(title string) {
 	}
 
 	if om.Icons && om.Colors {
-		pterm.DefaultHeader.WithFullWidth().Println(title)
+		om.write(pterm.DefaultHeader.WithFullWidth().Sprintln(title))
 	} else {
-		om.Print(strings.ToUpper(title))
-		om.Print(strings.Repeat("=", len(title)))
+		om.write(pterm.Sprintln(strings.ToUpper(title)) + pterm.Sprintln(strings.Repeat("=", len(title))))
 	}
 }
 
@@ -168,12 +355,15 @@ func (om *OutputManager) Section(title string) {
 		return
 	}
 
+	om.write(om.renderSection(title))
+}
+
+// renderSection renders a section header
+func (om *OutputManager) renderSection(title string) string {
 	if om.Icons && om.Colors {
-		pterm.DefaultSection.Println(title)
-	} else {
-		om.Print(title)
-		om.Print(strings.Repeat("-", len(title)))
+		return pterm.DefaultSection.Sprintln(title)
 	}
+	return pterm.Sprintln(title) + pterm.Sprintln(strings.Repeat("-", len(title)))
 }
 
 // List prints a bulleted list
@@ -188,11 +378,14 @@ func (om *OutputManager) List(items []string) {
 		for i, item := range items {
 			bulletItems[i] = pterm.BulletListItem{Text: item}
 		}
-		pterm.DefaultBulletList.WithItems(bulletItems).Render()
+		rendered, _ := pterm.DefaultBulletList.WithItems(bulletItems).Srender()
+		om.write(rendered + "\n")
 	} else {
+		var b strings.Builder
 		for _, item := range items {
-			om.Printf("• %s\n", item)
+			b.WriteString(pterm.Sprintf("• %s\n", item))
 		}
+		om.write(b.String())
 	}
 }
 
@@ -207,7 +400,8 @@ func (om *OutputManager) Table(headers []string, rows [][]string) {
 		for _, row := range rows {
 			tableData = append(tableData, row)
 		}
-		pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		rendered, _ := pterm.DefaultTable.WithHasHeader().WithData(tableData).Srender()
+		om.write(rendered + "\n")
 	} else {
 		// Simple text table
 		om.printSimpleTable(headers, rows)
@@ -216,6 +410,8 @@ func (om *OutputManager) Table(headers []string, rows [][]string) {
 
 // printSimpleTable prints a simple text-based table
 func (om *OutputManager) printSimpleTable(headers []string, rows [][]string) {
+	var b strings.Builder
+
 	// Calculate column widths
 	colWidths := make([]int, len(headers))
 	for i, header := range headers {
@@ -232,30 +428,33 @@ func (om *OutputManager) printSimpleTable(headers []string, rows [][]string) {
 
 	// Print header
 	for i, header := range headers {
-		om.Printf("%-*s", colWidths[i]+2, header)
+		fmt.Fprintf(&b, "%-*s", colWidths[i]+2, header)
 	}
-	om.Print("")
+	b.WriteString("\n")
 
 	// Print separator
 	for _, width := range colWidths {
-		om.Print(strings.Repeat("-", width+2))
+		b.WriteString(strings.Repeat("-", width+2) + "\n")
 	}
-	om.Print("")
+	b.WriteString("\n")
 
 	// Print rows
 	for _, row := range rows {
 		for i, cell := range row {
 			if i < len(colWidths) {
-				om.Printf("%-*s", colWidths[i]+2, cell)
+				fmt.Fprintf(&b, "%-*s", colWidths[i]+2, cell)
 			}
 		}
-		om.Print("")
+		b.WriteString("\n")
 	}
+
+	om.write(b.String())
 }
 
 // ProgressBar creates and returns a progress bar
 func (om *OutputManager) ProgressBar(title string, total int) *pterm.ProgressbarPrinter {
-	if om.Quiet || !om.ProgressBars {
+	// Bars redraw in place, which buffered task output cannot do
+	if om.Quiet || !om.ProgressBars || om.task != nil {
 		return nil
 	}
 
@@ -264,7 +463,7 @@ func (om *OutputManager) ProgressBar(title string, total int) *pterm.Progressbar
 
 // Spinner creates and returns a spinner
 func (om *OutputManager) Spinner(text string) *pterm.SpinnerPrinter {
-	if om.Quiet {
+	if om.Quiet || om.Plain || om.task != nil {
 		return nil
 	}
 
@@ -284,7 +483,25 @@ func (om *OutputManager) Confirm(message string, defaultValue bool) (bool, error
 		prompt += " [y/N]"
 	}
 
-	result, err := pterm.DefaultInteractiveConfirm.WithDefaultValue(defaultValue).Show(prompt)
+	if !CanPrompt() {
+		return defaultValue, nil
+	}
+
+	var result bool
+	err := om.prompt(func() (err error) {
+		if om.Plain {
+			answer, err := om.readLine(prompt + " ")
+			switch strings.ToLower(answer) {
+			case "":
+				result = defaultValue
+			case "y", "yes":
+				result = true
+			}
+			return err
+		}
+		result, err = pterm.DefaultInteractiveConfirm.WithDefaultValue(defaultValue).Show(prompt)
+		return err
+	})
 	if err != nil {
 		return defaultValue, fmt.Errorf("failed to get user confirmation: %w", err)
 	}
@@ -294,14 +511,25 @@ func (om *OutputManager) Confirm(message string, defaultValue bool) (bool, error
 
 // Select prompts the user to select from a list of options
 func (om *OutputManager) Select(message string, options []string) (string, error) {
-	if om.Quiet {
+	if om.Quiet || !CanPrompt() {
 		if len(options) > 0 {
 			return options[0], nil
 		}
 		return "", fmt.Errorf("no options available")
 	}
 
-	result, err := pterm.DefaultInteractiveSelect.WithOptions(options).Show(message)
+	var result string
+	err := om.prompt(func() (err error) {
+		if om.Plain {
+			choices, err := om.readChoices(message, options, false)
+			if len(choices) > 0 {
+				result = choices[0]
+			}
+			return err
+		}
+		result, err = pterm.DefaultInteractiveSelect.WithOptions(options).Show(message)
+		return err
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to get user selection: %w", err)
 	}
@@ -311,11 +539,25 @@ func (om *OutputManager) Select(message string, options []string) (string, error
 
 // Input prompts the user for text input
 func (om *OutputManager) Input(message string, defaultValue string) (string, error) {
-	if om.Quiet {
+	if om.Quiet || !CanPrompt() {
 		return defaultValue, nil
 	}
 
-	result, err := pterm.DefaultInteractiveTextInput.WithDefaultValue(defaultValue).Show(message)
+	var result string
+	err := om.prompt(func() (err error) {
+		if om.Plain {
+			prompt := message + ": "
+			if defaultValue != "" {
+				prompt = fmt.Sprintf("%s [%s]: ", message, defaultValue)
+			}
+			if result, err = om.readLine(prompt); result == "" {
+				result = defaultValue
+			}
+			return err
+		}
+		result, err = pterm.DefaultInteractiveTextInput.WithDefaultValue(defaultValue).Show(message)
+		return err
+	})
 	if err != nil {
 		return defaultValue, fmt.Errorf("failed to get user input: %w", err)
 	}
@@ -325,11 +567,19 @@ func (om *OutputManager) Input(message string, defaultValue string) (string, err
 
 // MultiSelect prompts the user to select multiple options
 func (om *OutputManager) MultiSelect(message string, options []string) ([]string, error) {
-	if om.Quiet {
+	if om.Quiet || !CanPrompt() {
 		return options, nil
 	}
 
-	result, err := pterm.DefaultInteractiveMultiselect.WithOptions(options).Show(message)
+	var result []string
+	err := om.prompt(func() (err error) {
+		if om.Plain {
+			result, err = om.readChoices(message, options, true)
+			return err
+		}
+		result, err = pterm.DefaultInteractiveMultiselect.WithOptions(options).Show(message)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user selection: %w", err)
 	}
@@ -337,36 +587,105 @@ func (om *OutputManager) MultiSelect(message string, options []string) ([]string
 	return result, nil
 }
 
+// readLine shows prompt and reads a line of input, for prompts on plain
+// output; the caller holds the output lock
+func (om *OutputManager) readLine(prompt string) (string, error) {
+	w := terminalWriter()
+	if w == nil {
+		w = os.Stderr
+	}
+	fmt.Fprint(w, prompt)
+
+	line, err := stdinReader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// readChoices lists numbered options and reads the numbers of the chosen
+// ones, one or, with multiple, several separated by commas or spaces; the
+// caller holds the output lock
+func (om *OutputManager) readChoices(message string, options []string, multiple bool) ([]string, error) {
+	if len(options) == 0 {
+		return nil, fmt.Errorf("no options available")
+	}
+
+	lines := []string{message}
+	for i, option := range options {
+		lines = append(lines, fmt.Sprintf("  %d) %s", i+1, option))
+	}
+	pterm.Println(strings.Join(lines, "\n"))
+
+	prompt := "Choose a number [1]: "
+	if multiple {
+		prompt = "Choose numbers separated by commas [all]: "
+	}
+	for {
+		answer, err := om.readLine(prompt)
+		if err != nil {
+			return nil, err
+		}
+		if answer == "" {
+			if multiple {
+				return options, nil
+			}
+			return options[:1], nil
+		}
+
+		chosen := []string{}
+		for _, field := range strings.FieldsFunc(answer, func(r rune) bool { return r == ',' || r == ' ' }) {
+			n, err := strconv.Atoi(field)
+			if err != nil || n < 1 || n > len(options) {
+				chosen = nil
+				break
+			}
+			chosen = append(chosen, options[n-1])
+		}
+		if len(chosen) > 0 && (multiple || len(chosen) == 1) {
+			return chosen, nil
+		}
+		pterm.Printfln("Enter a number from 1 to %d", len(options))
+	}
+}
+
 // TimedOperation executes an operation with timing information
 func (om *OutputManager) TimedOperation(name string, operation func() error) error {
 	if om.Quiet {
 		return operation()
 	}
 
-	start := time.Now()
+	start := Now()
 	
 	var spinner *pterm.SpinnerPrinter
-	if om.ProgressBars {
-		spinner = om.Spinner(fmt.Sprintf("Executing %s...", name))
-		spinner.Start()
+	// pterm never deactivates a stopped spinner in raw output mode, so every
+	// later line would be repeated once per finished spinner
+	if om.ProgressBars && om.task == nil && !pterm.RawOutput {
+		spinner, _ = om.Spinner(fmt.Sprintf("Executing %s...", name)).Start()
 	} else {
 		om.Info(fmt.Sprintf("Starting %s...", name))
 	}
 
 	err := operation()
-	duration := time.Since(start)
+	duration := Since(start)
+
+	// Durations vary between runs, so deterministic mode leaves them out
+	failed, completed := fmt.Sprintf("%s failed after %v", name, duration), fmt.Sprintf("%s completed in %v", name, duration)
+	if IsDeterministic() {
+		failed, completed = fmt.Sprintf("%s failed", name), fmt.Sprintf("%s completed", name)
+	}
 
 	if spinner != nil {
 		if err != nil {
-			spinner.Fail(fmt.Sprintf("%s failed after %v", name, duration))
+			spinner.Fail(failed)
 		} else {
-			spinner.Success(fmt.Sprintf("%s completed in %v", name, duration))
+			spinner.Success(completed)
 		}
 	} else {
 		if err != nil {
-			om.Error(fmt.Sprintf("%s failed after %v: %v", name, duration, err))
+			om.Error(fmt.Sprintf("%s: %v", failed, err))
 		} else {
-			om.Success(fmt.Sprintf("%s completed in %v", name, duration))
+			om.Success(completed)
 		}
 	}
 
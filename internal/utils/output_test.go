@@ -0,0 +1,111 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pterm/pterm"
+)
+
+// captureOutput directs all output to a buffer for the rest of the test
+func captureOutput(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	t.Cleanup(func() { SetOutput(os.Stdout) })
+	return &buf
+}
+
+// TestTasksStayGrouped checks that the output of tasks writing concurrently
+// is written as one section per task
+func TestTasksStayGrouped(t *testing.T) {
+	buf := captureOutput(t)
+	om := NewOutputManager(false, false, false, false, false, false)
+
+	const tasks, messages = 8, 20
+	var wg sync.WaitGroup
+	for i := 0; i < tasks; i++ {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			task := om.Task(name)
+			for j := 0; j < messages; j++ {
+				task.Info(fmt.Sprintf("%s message %d", name, j))
+				runtime.Gosched()
+			}
+			task.Flush()
+		}(fmt.Sprintf("task%d", i))
+	}
+	wg.Wait()
+
+	sections := map[string][]string{}
+	current := ""
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	for i := 0; i < len(lines); i++ {
+		// A section title is underlined
+		if i+1 < len(lines) && strings.HasPrefix(lines[i+1], "---") {
+			current = lines[i]
+			if _, seen := sections[current]; seen {
+				t.Fatalf("section %s written twice", current)
+			}
+			sections[current] = []string{}
+			i++
+			continue
+		}
+		sections[current] = append(sections[current], lines[i])
+	}
+
+	if len(sections) != tasks {
+		t.Fatalf("expected %d sections, got %d:\n%s", tasks, len(sections), buf.String())
+	}
+	for name, lines := range sections {
+		if len(lines) != messages {
+			t.Errorf("expected %d messages in %s, got %v", messages, name, lines)
+		}
+		for j, line := range lines {
+			if line != fmt.Sprintf("%s message %d", name, j) {
+				t.Errorf("section %s has %q at %d", name, line, j)
+			}
+		}
+	}
+}
+
+// TestTaskCapture checks that what is printed through pterm directly while
+// a task captures it keeps its place among the task's messages
+func TestTaskCapture(t *testing.T) {
+	buf := captureOutput(t)
+	om := NewOutputManager(false, false, false, false, false, false)
+
+	task := om.Task("api")
+	release := task.Capture()
+	task.Info("before")
+	pterm.Println("direct")
+	task.Info("after")
+	release()
+
+	if buf.Len() != 0 {
+		t.Fatalf("expected the task output to be buffered, got %q", buf.String())
+	}
+	task.Flush()
+	expected := "api\n---\nbefore\ndirect\nafter\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+
+	pterm.Println("released")
+	if !strings.HasSuffix(buf.String(), "released\n") {
+		t.Errorf("expected pterm to write directly after release, got %q", buf.String())
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Owner is the user and group that created paths are handed over to
+type Owner struct {
+	Name  string
+	Group string
+	UID   int
+	GID   int
+}
+
+// String returns the owner as user:group
+func (o *Owner) String() string {
+	return o.Name + ":" + o.Group
+}
+
+// LookupOwner resolves a user name (or numeric ID) to the user and their
+// default group
+func LookupOwner(name string) (*Owner, error) {
+	u, err := user.Lookup(name)
+	if err != nil {
+		var idErr error
+		if u, idErr = user.LookupId(name); idErr != nil {
+			return nil, fmt.Errorf("unknown user '%s': %w", name, err)
+		}
+	}
+
+	uid, err := strconv.Atoi(u.Uid)
+	if err != nil {
+		return nil, fmt.Errorf("user '%s' has no numeric ID (not supported on this platform)", name)
+	}
+	gid, err := strconv.Atoi(u.Gid)
+	if err != nil {
+		return nil, fmt.Errorf("user '%s' has no numeric group ID (not supported on this platform)", name)
+	}
+
+	group := u.Gid
+	if g, err := user.LookupGroupId(u.Gid); err == nil {
+		group = g.Name
+	}
+
+	return &Owner{Name: u.Username, Group: group, UID: uid, GID: gid}, nil
+}
+
+// SystemPaths are the directories where root is expected to create
+// directories, unlike home directories
+var SystemPaths = []string{"/root", "/etc", "/opt", "/srv", "/usr", "/var", "/tmp", "/mnt", "/media", "/boot", "/run"}
+
+// IsSystemPath reports whether path is in one of SystemPaths or the
+// temporary directory
+func IsSystemPath(path string) bool {
+	for _, dir := range append(SystemPaths, os.TempDir()) {
+		if path == dir || strings.HasPrefix(path, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanChangeOwner reports whether the process may give files to other users
+func CanChangeOwner() bool {
+	return os.Geteuid() == 0
+}
+
+// Chown sets the owner of a single path without following symlinks
+func (o *Owner) Chown(path string) error {
+	if err := os.Lchown(path, o.UID, o.GID); err != nil {
+		return fmt.Errorf("failed to change owner of %s to %s: %w", path, o, err)
+	}
+	return nil
+}
+
+// ChownTree sets the owner of root and everything below it, without
+// following symlinks
+func (o *Owner) ChownTree(root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		return o.Chown(path)
+	})
+}
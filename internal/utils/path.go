@@ -12,7 +12,6 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
-	"time"
 )
 
 // PathValidator provides path validation functionality
@@ -136,7 +135,7 @@ func GenerateUniquePath(basePath string) string {
 	}
 
 	// Fallback with timestamp if we can't find a unique name
-	timestamp := fmt.Sprintf("%d", time.Now().Unix())
+	timestamp := fmt.Sprintf("%d", Now().Unix())
 	newName := fmt.Sprintf("%s-%s%s", name, timestamp, ext)
 	return filepath.Join(dir, newName)
 }
@@ -0,0 +1,162 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// quotactl(2) constants from <linux/quota.h>
+const (
+	qGetQuota    = 0x800007
+	usrQuota     = 0
+	qifBlockSize = 1024 // dqb_bhardlimit and dqb_bsoftlimit are in 1 KiB blocks
+)
+
+// ifDqblk mirrors struct if_dqblk from <linux/quota.h>
+type ifDqblk struct {
+	bhardlimit uint64
+	bsoftlimit uint64
+	curspace   uint64
+	ihardlimit uint64
+	isoftlimit uint64
+	curinodes  uint64
+	btime      uint64
+	itime      uint64
+	valid      uint32
+}
+
+// userQuota returns the block quota of the current user on the filesystem
+// holding path, or nil when no quota is configured. The soft limit is used
+// when set since exceeding it starts the grace period.
+func userQuota(path string) (*quotaInfo, error) {
+	device, err := mountDevice(path)
+	if err != nil {
+		return nil, err
+	}
+
+	devicePtr, err := unix.BytePtrFromString(device)
+	if err != nil {
+		return nil, err
+	}
+
+	var dq ifDqblk
+	_, _, errno := unix.Syscall6(unix.SYS_QUOTACTL,
+		uintptr(qGetQuota<<8|usrQuota),
+		uintptr(unsafe.Pointer(devicePtr)),
+		uintptr(os.Getuid()),
+		uintptr(unsafe.Pointer(&dq)),
+		0, 0)
+	if errno != 0 {
+		// ENOSYS, ESRCH (quotas off) and friends mean there is no quota to honour
+		return nil, errno
+	}
+
+	limit := dq.bsoftlimit
+	if limit == 0 || (dq.bhardlimit != 0 && dq.bhardlimit < limit) {
+		limit = dq.bhardlimit
+	}
+	if limit == 0 {
+		return nil, nil
+	}
+
+	return &quotaInfo{limit: limit * qifBlockSize, used: dq.curspace}, nil
+}
+
+// mountDevice returns the source device of the mount containing path,
+// from /proc/self/mountinfo
+func mountDevice(path string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+	resolved, err = filepath.Abs(resolved)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	// Fields: id parent major:minor root mountpoint options ... - fstype source superoptions
+	bestMount, bestDevice := "", ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		separator := -1
+		for i, field := range fields {
+			if field == "-" {
+				separator = i
+				break
+			}
+		}
+		if len(fields) < 5 || separator < 0 || separator+2 >= len(fields) {
+			continue
+		}
+
+		mountPoint := unescapeMountField(fields[4])
+		if !pathWithin(resolved, mountPoint) || len(mountPoint) < len(bestMount) {
+			continue
+		}
+		bestMount, bestDevice = mountPoint, unescapeMountField(fields[separator+2])
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	if bestDevice == "" {
+		return "", os.ErrNotExist
+	}
+	return bestDevice, nil
+}
+
+// pathWithin reports whether path is mountPoint or below it
+func pathWithin(path, mountPoint string) bool {
+	if mountPoint == "/" || path == mountPoint {
+		return true
+	}
+	return strings.HasPrefix(path, mountPoint+"/")
+}
+
+// unescapeMountField decodes the octal escapes (\040 for space) used in mountinfo
+func unescapeMountField(field string) string {
+	if !strings.Contains(field, `\`) {
+		return field
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(field); i++ {
+		if field[i] == '\\' && i+3 < len(field) {
+			code := field[i+1 : i+4]
+			value := 0
+			valid := true
+			for _, c := range code {
+				if c < '0' || c > '7' {
+					valid = false
+					break
+				}
+				value = value*8 + int(c-'0')
+			}
+			if valid {
+				b.WriteByte(byte(value))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(field[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+// userQuota is not supported on this platform; only free space applies
+func userQuota(path string) (*quotaInfo, error) {
+	return nil, nil
+}
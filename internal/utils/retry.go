@@ -0,0 +1,153 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+)
+
+// Default retry settings for transient filesystem errors
+const (
+	DefaultRetryAttempts = 3
+	DefaultRetryDelay    = 200 * time.Millisecond
+	DefaultRetryMaxDelay = 5 * time.Second
+)
+
+// RetryPolicy retries operations that fail with transient filesystem errors,
+// as seen on NFS and SMB mounts, with exponential backoff
+type RetryPolicy struct {
+	Attempts int           // retries after the first attempt; 0 disables retrying
+	Delay    time.Duration // first backoff delay, doubled per retry
+	MaxDelay time.Duration // upper bound for a single backoff delay
+
+	// Log receives a message for every retry (optional)
+	Log func(message string)
+
+	sleep func(d time.Duration)
+}
+
+// Do runs fn, retrying it while it fails with a transient error. The
+// operation name is used in log messages.
+func (p RetryPolicy) Do(operation string, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= p.Attempts || !IsTransientError(err) {
+			return err
+		}
+
+		delay := p.backoff(attempt)
+		if p.Log != nil {
+			p.Log(fmt.Sprintf("%s failed with a transient error (%v), retrying in %s (%d/%d)",
+				operation, err, delay, attempt+1, p.Attempts))
+		}
+
+		if p.sleep != nil {
+			p.sleep(delay)
+		} else {
+			time.Sleep(delay)
+		}
+	}
+}
+
+// backoff returns the delay before the retry following attempt
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultRetryMaxDelay
+	}
+
+	delay := p.Delay << uint(attempt)
+	if delay < 0 || delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+// transientErrnos are the errors worth retrying: the resource is busy, the
+// remote file handle went stale or the call was interrupted
+var transientErrnos = []syscall.Errno{
+	syscall.EBUSY,
+	syscall.ESTALE,
+	syscall.EAGAIN,
+	syscall.EINTR,
+	syscall.ETIMEDOUT,
+}
+
+// IsTransientError reports whether err is a filesystem error that may
+// succeed when retried
+func IsTransientError(err error) bool {
+	if err == nil || errors.Is(err, os.ErrNotExist) || errors.Is(err, os.ErrExist) || errors.Is(err, os.ErrPermission) {
+		return false
+	}
+
+	for _, errno := range transientErrnos {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	return false
+}
+
+// RetryFileSystem is a FileSystem that retries the calls of another
+// FileSystem on transient errors
+type RetryFileSystem struct {
+	FS     FileSystem
+	Policy RetryPolicy
+}
+
+// NewRetryFileSystem creates a new RetryFileSystem over fileSystem
+func NewRetryFileSystem(fileSystem FileSystem, policy RetryPolicy) *RetryFileSystem {
+	return &RetryFileSystem{FS: fileSystem, Policy: policy}
+}
+
+func (r *RetryFileSystem) Stat(name string) (info os.FileInfo, err error) {
+	err = r.Policy.Do("stat "+name, func() error {
+		info, err = r.FS.Stat(name)
+		return err
+	})
+	return info, err
+}
+
+func (r *RetryFileSystem) Lstat(name string) (info os.FileInfo, err error) {
+	err = r.Policy.Do("lstat "+name, func() error {
+		info, err = r.FS.Lstat(name)
+		return err
+	})
+	return info, err
+}
+
+func (r *RetryFileSystem) MkdirAll(path string, perm os.FileMode) error {
+	return r.Policy.Do("mkdir "+path, func() error { return r.FS.MkdirAll(path, perm) })
+}
+
+func (r *RetryFileSystem) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return r.Policy.Do("write "+name, func() error { return r.FS.WriteFile(name, data, perm) })
+}
+
+func (r *RetryFileSystem) ReadFile(name string) (data []byte, err error) {
+	err = r.Policy.Do("read "+name, func() error {
+		data, err = r.FS.ReadFile(name)
+		return err
+	})
+	return data, err
+}
+
+func (r *RetryFileSystem) Remove(name string) error {
+	return r.Policy.Do("remove "+name, func() error { return r.FS.Remove(name) })
+}
+
+func (r *RetryFileSystem) Symlink(oldname, newname string) error {
+	return r.Policy.Do("symlink "+newname, func() error { return r.FS.Symlink(oldname, newname) })
+}
+
+func (r *RetryFileSystem) Chmod(name string, mode os.FileMode) error {
+	return r.Policy.Do("chmod "+name, func() error { return r.FS.Chmod(name, mode) })
+}
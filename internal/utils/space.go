@@ -0,0 +1,102 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+)
+
+// SpaceInfo describes the space available for creating files at a path,
+// from the filesystem and, where supported, the user's disk quota
+type SpaceInfo struct {
+	Path string `json:"path"`
+
+	// FilesystemAvailable is the free space available to unprivileged users
+	FilesystemAvailable uint64 `json:"filesystem_available_bytes"`
+
+	// Quota fields are set when a block quota applies to the current user
+	HasQuota       bool   `json:"has_quota"`
+	QuotaLimit     uint64 `json:"quota_limit_bytes,omitempty"`
+	QuotaUsed      uint64 `json:"quota_used_bytes,omitempty"`
+	QuotaRemaining uint64 `json:"quota_remaining_bytes,omitempty"`
+
+	// Available is the smaller of the free space and the remaining quota
+	Available uint64 `json:"available_bytes"`
+}
+
+// quotaInfo is the block quota of the current user on a filesystem
+type quotaInfo struct {
+	limit uint64
+	used  uint64
+}
+
+// GetSpaceInfo returns the space available at path, or at its nearest
+// existing parent when path does not exist yet
+func GetSpaceInfo(path string) (*SpaceInfo, error) {
+	ancestor, err := ExistingAncestor(path)
+	if err != nil {
+		return nil, err
+	}
+
+	available, err := filesystemAvailable(ancestor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read free space of %s: %w", ancestor, err)
+	}
+
+	info := &SpaceInfo{
+		Path:                ancestor,
+		FilesystemAvailable: available,
+		Available:           available,
+	}
+
+	// Quotas are optional; without support or a configured limit only the
+	// filesystem space applies
+	if quota, err := userQuota(ancestor); err == nil && quota != nil && quota.limit > 0 {
+		info.HasQuota = true
+		info.QuotaLimit = quota.limit
+		info.QuotaUsed = quota.used
+		if quota.used < quota.limit {
+			info.QuotaRemaining = quota.limit - quota.used
+		}
+		if info.QuotaRemaining < info.Available {
+			info.Available = info.QuotaRemaining
+		}
+	}
+
+	return info, nil
+}
+
+// Fits reports whether size bytes can be written
+func (s *SpaceInfo) Fits(size uint64) bool {
+	return size <= s.Available
+}
+
+// DirSize returns the total size of the regular files below dir, skipping
+// .git directories
+func DirSize(dir string) (uint64, error) {
+	var total uint64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		total += uint64(info.Size())
+		return nil
+	})
+	return total, err
+}
@@ -0,0 +1,16 @@
+//go:build !(linux || darwin || freebsd)
+
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import "errors"
+
+// filesystemAvailable is not supported on this platform
+func filesystemAvailable(path string) (uint64, error) {
+	return 0, errors.New("free space is not available on this platform")
+}
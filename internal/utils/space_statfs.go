@@ -0,0 +1,21 @@
+//go:build linux || darwin || freebsd
+
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import "golang.org/x/sys/unix"
+
+// filesystemAvailable returns the free space at path available to
+// unprivileged users
+func filesystemAvailable(path string) (uint64, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
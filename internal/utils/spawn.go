@@ -0,0 +1,27 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import (
+	"os"
+	"runtime"
+)
+
+// DefaultShell returns the user's interactive shell: $SHELL, then
+// %COMSPEC% on Windows, then /bin/sh
+func DefaultShell() string {
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return shell
+	}
+	if runtime.GOOS == "windows" {
+		if comspec := os.Getenv("COMSPEC"); comspec != "" {
+			return comspec
+		}
+		return "cmd.exe"
+	}
+	return "/bin/sh"
+}
@@ -0,0 +1,32 @@
+//go:build !unix
+
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// SpawnShell runs shell in dir and waits for it to exit, as processes
+// cannot be replaced where exec(2) is unavailable
+func SpawnShell(shell, dir string) error {
+	cmd := exec.Command(shell)
+	cmd.Dir = dir
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		if _, exited := err.(*exec.ExitError); exited {
+			return nil
+		}
+		return fmt.Errorf("failed to start %s: %w", shell, err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+//go:build unix
+
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// SpawnShell replaces the current process with shell running in dir. It
+// only returns on failure.
+func SpawnShell(shell, dir string) error {
+	path, err := exec.LookPath(shell)
+	if err != nil {
+		return fmt.Errorf("shell %s not found: %w", shell, err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		return fmt.Errorf("failed to change into %s: %w", dir, err)
+	}
+
+	// PWD is what shells show, so it must match the new directory
+	env := []string{"PWD=" + dir}
+	for _, variable := range os.Environ() {
+		if !strings.HasPrefix(variable, "PWD=") {
+			env = append(env, variable)
+		}
+	}
+	if err := unix.Exec(path, []string{shell}, env); err != nil {
+		return fmt.Errorf("failed to start %s: %w", shell, err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// IsTerminal reports whether f is connected to a terminal
+func IsTerminal(f *os.File) bool {
+	return f != nil && term.IsTerminal(int(f.Fd()))
+}
+
+// ciEnvironment lists variables set by CI services
+var ciEnvironment = []string{
+	"CI", "CONTINUOUS_INTEGRATION", "BUILD_NUMBER", "GITHUB_ACTIONS", "GITLAB_CI",
+	"BUILDKITE", "CIRCLECI", "JENKINS_URL", "TF_BUILD", "TEAMCITY_VERSION", "TRAVIS",
+}
+
+// IsCI reports whether mkcd runs in a CI service, where nobody answers
+// prompts and logs do not redraw lines
+func IsCI() bool {
+	for _, name := range ciEnvironment {
+		switch strings.ToLower(os.Getenv(name)) {
+		case "", "0", "false":
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// IsDumbTerminal reports whether the terminal shows escape sequences as
+// text: TERM=dumb, e.g. Emacs shell buffers, or a legacy Windows console
+func IsDumbTerminal() bool {
+	return os.Getenv("TERM") == "dumb" || legacyConsole()
+}
+
+// IsPlainOutput reports whether output must be plain and sequential,
+// without spinners, progress bars or interactive prompts: the terminal is
+// dumb, mkcd runs in CI, or no terminal is attached at all
+func IsPlainOutput() bool {
+	return IsDumbTerminal() || IsCI() || terminalWriter() == nil
+}
+
+// CanPrompt reports whether someone can answer prompts: stdin is a
+// terminal and mkcd does not run in CI
+func CanPrompt() bool {
+	return IsTerminal(os.Stdin) && !IsCI()
+}
+
+// terminalWriter returns the stream connected to the terminal, preferring
+// stderr because stdout may be captured (e.g. by the shell wrapper)
+func terminalWriter() io.Writer {
+	if IsTerminal(os.Stderr) {
+		return os.Stderr
+	}
+	if IsTerminal(os.Stdout) {
+		return os.Stdout
+	}
+	return nil
+}
+
+// ReportWorkingDirectory tells the terminal that path is the current
+// directory, so new tabs and splits can open there. It uses OSC 7
+// (WezTerm, iTerm2, kitty, VTE and others) or OSC 9;9 inside Windows
+// Terminal, and does nothing unless a terminal is attached. The sequences
+// are invisible, so they are written even in quiet mode.
+func (om *OutputManager) ReportWorkingDirectory(path string) {
+	w := terminalWriter()
+	if w == nil {
+		return
+	}
+
+	om.mu.Lock()
+	defer om.mu.Unlock()
+	fmt.Fprint(w, workingDirectorySequence(path))
+}
+
+// workingDirectorySequence returns the escape sequence reporting path as
+// the current directory
+func workingDirectorySequence(path string) string {
+	if os.Getenv("WT_SESSION") != "" {
+		return fmt.Sprintf("\x1b]9;9;\"%s\"\x1b\\", path)
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		host = ""
+	}
+	slashed := filepath.ToSlash(path)
+	if !strings.HasPrefix(slashed, "/") {
+		// Windows drive paths become file:///C:/...
+		slashed = "/" + slashed
+	}
+	location := url.URL{Scheme: "file", Host: host, Path: slashed}
+	return fmt.Sprintf("\x1b]7;%s\x1b\\", location.String())
+}
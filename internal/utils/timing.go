@@ -0,0 +1,162 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Timing is the measured duration of one named step
+type Timing struct {
+	Name     string
+	Duration time.Duration
+	Failed   bool
+}
+
+// Timings collects step durations for a timing breakdown. It is safe for
+// concurrent use.
+type Timings struct {
+	mu      sync.Mutex
+	entries []Timing
+}
+
+// NewTimings creates an empty Timings
+func NewTimings() *Timings {
+	return &Timings{}
+}
+
+// Wrap returns operation instrumented to record its duration under name
+func (t *Timings) Wrap(name string, operation func() error) func() error {
+	return func() error {
+		start := Now()
+		err := operation()
+		t.Add(Timing{Name: name, Duration: Since(start), Failed: err != nil})
+		return err
+	}
+}
+
+// Add records a timing
+func (t *Timings) Add(timing Timing) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entries = append(t.entries, timing)
+}
+
+// Entries returns the recorded timings in order
+func (t *Timings) Entries() []Timing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Timing{}, t.entries...)
+}
+
+// Total returns the sum of all recorded durations
+func (t *Timings) Total() time.Duration {
+	var total time.Duration
+	for _, timing := range t.Entries() {
+		total += timing.Duration
+	}
+	return total
+}
+
+// Print prints the timing breakdown as a table. Durations vary between
+// runs, so deterministic mode shows the steps only.
+func (t *Timings) Print(outputMgr *OutputManager) {
+	entries := t.Entries()
+	if len(entries) == 0 {
+		return
+	}
+
+	total := t.Total()
+	rows := [][]string{}
+	for _, timing := range entries {
+		duration, share := FormatTiming(timing.Duration), "-"
+		if total > 0 && !IsDeterministic() {
+			share = fmt.Sprintf("%.0f%%", float64(timing.Duration)*100/float64(total))
+		}
+		if timing.Failed {
+			duration += " (failed)"
+		}
+		rows = append(rows, []string{timing.Name, duration, share})
+	}
+	rows = append(rows, []string{"total", FormatTiming(total), "100%"})
+
+	outputMgr.Section("Timing")
+	outputMgr.Table([]string{"Step", "Duration", "Share"}, rows)
+}
+
+// FormatTiming formats a step duration with sub-millisecond precision
+func FormatTiming(d time.Duration) string {
+	if IsDeterministic() {
+		return "-"
+	}
+	if d < time.Second {
+		return fmt.Sprintf("%.2fms", float64(d)/float64(time.Millisecond))
+	}
+	return FormatDuration(d)
+}
+
+// TimingStats summarises the durations of one step over several runs
+type TimingStats struct {
+	Name  string        `json:"name"`
+	Runs  int           `json:"runs"`
+	Min   time.Duration `json:"min_ns"`
+	Mean  time.Duration `json:"mean_ns"`
+	P50   time.Duration `json:"p50_ns"`
+	P95   time.Duration `json:"p95_ns"`
+	Max   time.Duration `json:"max_ns"`
+	Total time.Duration `json:"total_ns"`
+}
+
+// SummarizeTimings computes per-step statistics over several runs, with
+// steps in the order they first appear
+func SummarizeTimings(runs []*Timings) []TimingStats {
+	order := []string{}
+	durations := map[string][]time.Duration{}
+	for _, run := range runs {
+		for _, timing := range run.Entries() {
+			if _, seen := durations[timing.Name]; !seen {
+				order = append(order, timing.Name)
+			}
+			durations[timing.Name] = append(durations[timing.Name], timing.Duration)
+		}
+	}
+
+	stats := []TimingStats{}
+	for _, name := range order {
+		values := durations[name]
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+		var total time.Duration
+		for _, value := range values {
+			total += value
+		}
+
+		stats = append(stats, TimingStats{
+			Name:  name,
+			Runs:  len(values),
+			Min:   values[0],
+			Mean:  total / time.Duration(len(values)),
+			P50:   percentile(values, 50),
+			P95:   percentile(values, 95),
+			Max:   values[len(values)-1],
+			Total: total,
+		})
+	}
+	return stats
+}
+
+// percentile returns the p-th percentile of sorted values (nearest rank)
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
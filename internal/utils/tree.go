@@ -0,0 +1,181 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pterm/pterm"
+)
+
+// TreeEntry is a path shown by OutputManager.Tree, relative to the root
+type TreeEntry struct {
+	Path  string
+	IsDir bool
+
+	// More marks directories whose content lies beyond the depth limit
+	More bool
+}
+
+// treeSkipDirs are directories whose content is never interesting in a tree
+var treeSkipDirs = map[string]bool{".git": true}
+
+// fileIcons are the icons shown per file extension
+var fileIcons = map[string]string{
+	".go":   "🐹",
+	".py":   "🐍",
+	".js":   "📜",
+	".ts":   "📜",
+	".rs":   "🦀",
+	".md":   "📝",
+	".txt":  "📝",
+	".json": "⚙️ ",
+	".toml": "⚙️ ",
+	".yaml": "⚙️ ",
+	".yml":  "⚙️ ",
+	".sh":   "🐚",
+}
+
+// DirectoryEntries lists the entries below root on disk, up to maxDepth
+// levels deep. The content of .git is left out.
+func DirectoryEntries(root string, maxDepth int) []TreeEntry {
+	entries := []TreeEntry{}
+	_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || path == root {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(root, path)
+		depth := strings.Count(rel, string(filepath.Separator)) + 1
+		entry := TreeEntry{Path: rel, IsDir: d.IsDir()}
+
+		if d.IsDir() && (treeSkipDirs[d.Name()] || depth >= maxDepth) {
+			children, _ := os.ReadDir(path)
+			entry.More = len(children) > 0
+			entries = append(entries, entry)
+			return filepath.SkipDir
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	return entries
+}
+
+// ChangeEntries lists the dry-run changes below root, up to maxDepth levels
+// deep
+func ChangeEntries(root string, changes []Change, maxDepth int) []TreeEntry {
+	entries := []TreeEntry{}
+	truncated := map[string]bool{}
+
+	for _, change := range changes {
+		rel, err := filepath.Rel(root, change.Path)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+
+		parts := strings.Split(rel, string(filepath.Separator))
+		if len(parts) > maxDepth {
+			truncated[filepath.Join(parts[:maxDepth]...)] = true
+			continue
+		}
+		entries = append(entries, TreeEntry{Path: rel, IsDir: change.IsDir})
+	}
+
+	for i := range entries {
+		entries[i].More = truncated[entries[i].Path]
+	}
+	return entries
+}
+
+// treeNode is a directory or file in a rendered tree
+type treeNode struct {
+	name     string
+	isDir    bool
+	more     bool
+	children map[string]*treeNode
+}
+
+// Tree prints entries as a tree below root, directories first, with an
+// icon per file type when icons are enabled
+func (om *OutputManager) Tree(root string, entries []TreeEntry) {
+	if om.Quiet {
+		return
+	}
+
+	top := &treeNode{name: root, isDir: true, children: map[string]*treeNode{}}
+	for _, entry := range entries {
+		node := top
+		parts := strings.Split(entry.Path, string(filepath.Separator))
+		for i, part := range parts {
+			child, exists := node.children[part]
+			if !exists {
+				child = &treeNode{name: part, isDir: i < len(parts)-1, children: map[string]*treeNode{}}
+				node.children[part] = child
+			}
+			node = child
+		}
+		node.isDir = node.isDir || entry.IsDir
+		node.more = node.more || entry.More
+	}
+
+	var b strings.Builder
+	b.WriteString(om.treeLabel(top) + "\n")
+	om.renderTree(&b, top, "")
+	om.write(b.String())
+}
+
+// renderTree writes the children of node with their connecting lines
+func (om *OutputManager) renderTree(b *strings.Builder, node *treeNode, prefix string) {
+	children := make([]*treeNode, 0, len(node.children))
+	for _, child := range node.children {
+		children = append(children, child)
+	}
+	sort.Slice(children, func(i, j int) bool {
+		if children[i].isDir != children[j].isDir {
+			return children[i].isDir
+		}
+		return children[i].name < children[j].name
+	})
+
+	for i, child := range children {
+		connector, indent := "├── ", "│   "
+		if i == len(children)-1 {
+			connector, indent = "└── ", "    "
+		}
+		b.WriteString(prefix + connector + om.treeLabel(child) + "\n")
+		om.renderTree(b, child, prefix+indent)
+	}
+}
+
+// treeLabel renders the name of a tree node
+func (om *OutputManager) treeLabel(node *treeNode) string {
+	name := node.name
+	if node.isDir {
+		name += "/"
+		if node.more {
+			name += " …"
+		}
+	}
+
+	if om.Colors && node.isDir {
+		name = pterm.FgBlue.Sprint(name)
+	}
+	if !om.Icons {
+		return name
+	}
+
+	icon := "📄"
+	if node.isDir {
+		icon = "📁"
+	} else if fileIcon, exists := fileIcons[strings.ToLower(filepath.Ext(node.name))]; exists {
+		icon = fileIcon
+	}
+	return icon + " " + name
+}
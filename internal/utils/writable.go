@@ -0,0 +1,126 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// NotWritableError is returned when a target cannot be created because its
+// nearest existing ancestor is not writable
+type NotWritableError struct {
+	Target     string // the path that was to be created
+	Path       string // the existing ancestor that is not writable
+	ReadOnlyFS bool   // the ancestor is on a read-only mount
+	Err        error
+}
+
+func (e *NotWritableError) Error() string {
+	if e.ReadOnlyFS {
+		return fmt.Sprintf("cannot create %s: %s is on a read-only filesystem", e.Target, e.Path)
+	}
+	return fmt.Sprintf("cannot create %s: no write permission for %s", e.Target, e.Path)
+}
+
+func (e *NotWritableError) Unwrap() error {
+	return e.Err
+}
+
+// Hints returns suggestions for resolving the error
+func (e *NotWritableError) Hints() []string {
+	if e.ReadOnlyFS {
+		return []string{
+			"Choose a location on a writable filesystem, e.g. under your home directory",
+			fmt.Sprintf("Check how %s is mounted (mount | grep ro,) and remount it read-write if appropriate", e.Path),
+			"Use --temp to create the directory under core.temp_dir instead",
+		}
+	}
+	return []string{
+		fmt.Sprintf("Check the owner and permissions of %s (ls -ld %s)", e.Path, e.Path),
+		"Choose a location you own, e.g. under your home directory",
+		"Use --temp to create the directory under core.temp_dir instead",
+	}
+}
+
+// ExistingAncestor returns path itself if it exists, otherwise its nearest
+// existing parent directory
+func ExistingAncestor(path string) (string, error) {
+	current := filepath.Clean(path)
+	for {
+		if _, err := os.Stat(current); err == nil {
+			return current, nil
+		} else if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return "", fmt.Errorf("no existing parent directory for %s", path)
+		}
+		current = parent
+	}
+}
+
+// CheckWritable reports whether path can be created (or written into when it
+// exists) by checking its nearest existing ancestor before anything is
+// created. It returns a NotWritableError for read-only mounts and missing
+// permissions.
+func CheckWritable(path string) error {
+	ancestor, err := ExistingAncestor(path)
+	if err != nil {
+		return fmt.Errorf("failed to check %s: %w", path, err)
+	}
+
+	readOnlyFS, err := checkAccess(ancestor)
+	if err != nil {
+		return &NotWritableError{Target: path, Path: ancestor, ReadOnlyFS: readOnlyFS, Err: err}
+	}
+	return nil
+}
+
+// WorkingDir returns the current directory as the shell shows it: $PWD when
+// it refers to the current directory, which keeps the symlinks the user
+// changed through (e.g. ~/work -> /mnt/big/work). With physical, every
+// symlink is resolved instead.
+func WorkingDir(physical bool) (string, error) {
+	if physical {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		return filepath.EvalSymlinks(cwd)
+	}
+
+	if pwd := os.Getenv("PWD"); filepath.IsAbs(pwd) {
+		pwdInfo, pwdErr := os.Stat(pwd)
+		dotInfo, dotErr := os.Stat(".")
+		if pwdErr == nil && dotErr == nil && os.SameFile(pwdInfo, dotInfo) {
+			return filepath.Clean(pwd), nil
+		}
+	}
+	return os.Getwd()
+}
+
+// PhysicalPath resolves the symlinks in the existing part of path; the
+// parts that do not exist yet are appended unchanged
+func PhysicalPath(path string) (string, error) {
+	ancestor, err := ExistingAncestor(path)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(ancestor)
+	if err != nil {
+		return "", err
+	}
+	rest, err := filepath.Rel(ancestor, filepath.Clean(path))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(resolved, rest), nil
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+// checkAccess is a no-op where access(2) is unavailable; creation errors
+// are reported when they occur
+func checkAccess(path string) (readOnlyFS bool, err error) {
+	return false, nil
+}
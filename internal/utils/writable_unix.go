@@ -0,0 +1,22 @@
+//go:build unix
+
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package utils
+
+import (
+	"errors"
+
+	"golang.org/x/sys/unix"
+)
+
+// checkAccess checks write access to path with access(2), which fails with
+// EROFS on read-only mounts and EACCES without permission
+func checkAccess(path string) (readOnlyFS bool, err error) {
+	err = unix.Access(path, unix.W_OK)
+	return errors.Is(err, unix.EROFS), err
+}
@@ -0,0 +1,144 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package workspaces
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/mochajutsu/mkcd/internal/history"
+	"github.com/mochajutsu/mkcd/internal/utils"
+)
+
+// BookmarksVersion is the bookmarks format written by Save
+const BookmarksVersion = 1
+
+// BookmarksFile is the name of the bookmarks file in the state directory
+const BookmarksFile = "bookmarks.json"
+
+// bookmarkNamePattern keeps bookmark names apart from paths
+var bookmarkNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Bookmarks map names to workspace paths
+type Bookmarks struct {
+	Version int               `json:"version"`
+	Paths   map[string]string `json:"paths"`
+}
+
+// DefaultBookmarksPath returns the bookmarks location inside the state directory
+func DefaultBookmarksPath() (string, error) {
+	stateDir, err := history.StateDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(stateDir, BookmarksFile), nil
+}
+
+// ValidateBookmarkName checks that name can be told apart from a path:
+// letters, digits, dots, dashes and underscores, starting with a letter or
+// digit
+func ValidateBookmarkName(name string) error {
+	if !bookmarkNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid bookmark name '%s' (use letters, digits, '.', '-' and '_', starting with a letter or digit)", name)
+	}
+	return nil
+}
+
+// LoadBookmarks reads the bookmarks at path; a missing file has none
+func LoadBookmarks(path string) (*Bookmarks, error) {
+	bookmarks := &Bookmarks{Version: BookmarksVersion, Paths: map[string]string{}}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return bookmarks, nil
+		}
+		return nil, fmt.Errorf("failed to read bookmarks: %w", err)
+	}
+
+	if err := json.Unmarshal(data, bookmarks); err != nil {
+		return nil, fmt.Errorf("failed to parse bookmarks %s: %w", path, err)
+	}
+	if bookmarks.Version > BookmarksVersion {
+		return nil, fmt.Errorf("bookmarks format %d is newer than supported format %d", bookmarks.Version, BookmarksVersion)
+	}
+	if bookmarks.Paths == nil {
+		bookmarks.Paths = map[string]string{}
+	}
+	return bookmarks, nil
+}
+
+// Save writes the bookmarks to path
+func (b *Bookmarks) Save(path string) error {
+	b.Version = BookmarksVersion
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode bookmarks: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create bookmarks directory: %w", err)
+	}
+	if err := utils.WriteFileAtomic(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write bookmarks: %w", err)
+	}
+	return nil
+}
+
+// Get returns the path bookmarked under name
+func (b *Bookmarks) Get(name string) (string, bool) {
+	if b == nil {
+		return "", false
+	}
+	path, exists := b.Paths[name]
+	return path, exists
+}
+
+// Set bookmarks path under name, replacing an existing bookmark
+func (b *Bookmarks) Set(name, path string) {
+	b.Paths[name] = path
+}
+
+// Remove deletes the bookmark name, returning whether it existed
+func (b *Bookmarks) Remove(name string) bool {
+	if _, exists := b.Paths[name]; !exists {
+		return false
+	}
+	delete(b.Paths, name)
+	return true
+}
+
+// Move points the bookmarks of oldPath and of the paths inside it to
+// newPath, or removes them when newPath is empty, and returns the names of
+// the changed bookmarks
+func (b *Bookmarks) Move(oldPath, newPath string) []string {
+	changed := []string{}
+	for _, name := range b.Names() {
+		moved, matches := movedPath(b.Paths[name], oldPath, newPath)
+		if !matches {
+			continue
+		}
+		if moved == "" {
+			delete(b.Paths, name)
+		} else {
+			b.Paths[name] = moved
+		}
+		changed = append(changed, name)
+	}
+	return changed
+}
+
+// Names returns the bookmark names in alphabetical order
+func (b *Bookmarks) Names() []string {
+	if b == nil {
+		return nil
+	}
+	return utils.SortedKeys(b.Paths)
+}
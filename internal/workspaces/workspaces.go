@@ -0,0 +1,165 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+// Package workspaces orders the directories known to mkcd for finding and
+// listing them: pinned workspaces first, then the most recently used.
+package workspaces
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mochajutsu/mkcd/internal/history"
+	"github.com/mochajutsu/mkcd/internal/utils"
+)
+
+// PinsVersion is the pins format written by Save
+const PinsVersion = 1
+
+// PinsFile is the name of the pins file in the state directory
+const PinsFile = "pins.json"
+
+// Pins are the pinned workspace paths, in the order they were pinned
+type Pins struct {
+	Version int      `json:"version"`
+	Paths   []string `json:"paths"`
+}
+
+// DefaultPinsPath returns the pins location inside the state directory
+func DefaultPinsPath() (string, error) {
+	stateDir, err := history.StateDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(stateDir, PinsFile), nil
+}
+
+// LoadPins reads the pins at path; a missing file has no pins
+func LoadPins(path string) (*Pins, error) {
+	pins := &Pins{Version: PinsVersion}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return pins, nil
+		}
+		return nil, fmt.Errorf("failed to read pins: %w", err)
+	}
+
+	if err := json.Unmarshal(data, pins); err != nil {
+		return nil, fmt.Errorf("failed to parse pins %s: %w", path, err)
+	}
+	if pins.Version > PinsVersion {
+		return nil, fmt.Errorf("pins format %d is newer than supported format %d", pins.Version, PinsVersion)
+	}
+	return pins, nil
+}
+
+// Save writes the pins to path
+func (p *Pins) Save(path string) error {
+	p.Version = PinsVersion
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode pins: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create pins directory: %w", err)
+	}
+	if err := utils.WriteFileAtomic(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write pins: %w", err)
+	}
+	return nil
+}
+
+// Pin adds path after the existing pins, returning whether it was new
+func (p *Pins) Pin(path string) bool {
+	if p.Contains(path) {
+		return false
+	}
+	p.Paths = append(p.Paths, path)
+	return true
+}
+
+// Unpin removes path, returning whether it was pinned
+func (p *Pins) Unpin(path string) bool {
+	i := slices.Index(p.Paths, path)
+	if i < 0 {
+		return false
+	}
+	p.Paths = slices.Delete(p.Paths, i, i+1)
+	return true
+}
+
+// Move points the pins of oldPath and of the paths inside it to newPath,
+// or unpins them when newPath is empty, returning whether any changed
+func (p *Pins) Move(oldPath, newPath string) bool {
+	changed := false
+	paths := []string{}
+	for _, path := range p.Paths {
+		moved, matches := movedPath(path, oldPath, newPath)
+		if matches {
+			changed = true
+			path = moved
+		}
+		// The first pin of a path keeps its place
+		if path != "" && !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	p.Paths = paths
+	return changed
+}
+
+// movedPath returns where path is after oldPath moved to newPath, and
+// whether path is oldPath or lies inside it. An empty newPath yields "".
+func movedPath(path, oldPath, newPath string) (string, bool) {
+	rel, err := filepath.Rel(oldPath, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	if newPath == "" {
+		return "", true
+	}
+	return filepath.Join(newPath, rel), true
+}
+
+// Contains reports whether path is pinned
+func (p *Pins) Contains(path string) bool {
+	return p != nil && slices.Contains(p.Paths, path)
+}
+
+// Order sorts paths in place: pinned paths first in the order they were
+// pinned, then the others most recently used first, then by path. pins may
+// be nil.
+func Order(paths []string, used map[string]time.Time, pins *Pins) {
+	rank := make(map[string]int)
+	if pins != nil {
+		for i, path := range pins.Paths {
+			rank[path] = i + 1
+		}
+	}
+
+	sort.SliceStable(paths, func(i, j int) bool {
+		a, b := paths[i], paths[j]
+		ra, rb := rank[a], rank[b]
+		switch {
+		case ra > 0 && rb > 0:
+			return ra < rb
+		case ra > 0 || rb > 0:
+			return ra > 0
+		case !used[a].Equal(used[b]):
+			return used[a].After(used[b])
+		}
+		return a < b
+	})
+}
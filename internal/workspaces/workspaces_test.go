@@ -0,0 +1,98 @@
+/*
+Copyright © 2025 mochajutsu <https://github.com/mochajutsu>
+
+Licensed under the MIT License. See LICENSE file for details.
+*/
+
+package workspaces
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestBookmarksMove(t *testing.T) {
+	bookmarks := &Bookmarks{Paths: map[string]string{
+		"api":  "/src/api",
+		"docs": "/src/api/docs",
+		"apix": "/src/apix",
+		"web":  "/src/web",
+	}}
+
+	changed := bookmarks.Move("/src/api", "/src/billing-api")
+	if !slices.Equal(changed, []string{"api", "docs"}) {
+		t.Errorf("expected api and docs to move, got %v", changed)
+	}
+	expected := map[string]string{
+		"api":  "/src/billing-api",
+		"docs": "/src/billing-api/docs",
+		"apix": "/src/apix",
+		"web":  "/src/web",
+	}
+	for name, path := range expected {
+		if bookmarks.Paths[name] != path {
+			t.Errorf("bookmark %s points to %s, expected %s", name, bookmarks.Paths[name], path)
+		}
+	}
+
+	if changed := bookmarks.Move("/src/web", ""); !slices.Equal(changed, []string{"web"}) {
+		t.Errorf("expected web to be removed, got %v", changed)
+	}
+	if _, exists := bookmarks.Get("web"); exists {
+		t.Error("expected the web bookmark to be removed")
+	}
+}
+
+func TestPinsMove(t *testing.T) {
+	pins := &Pins{Paths: []string{"/src/api", "/src/web", "/src/api/docs", "/src/billing-api"}}
+
+	if !pins.Move("/src/api", "/src/billing-api") {
+		t.Fatal("expected the pins to change")
+	}
+	expected := []string{"/src/billing-api", "/src/web", "/src/billing-api/docs"}
+	if !slices.Equal(pins.Paths, expected) {
+		t.Errorf("expected pins %v, got %v", expected, pins.Paths)
+	}
+
+	if !pins.Move("/src/web", "") || pins.Contains("/src/web") {
+		t.Errorf("expected /src/web to be unpinned, got %v", pins.Paths)
+	}
+	if pins.Move("/src/missing", "/src/other") {
+		t.Error("expected no change for a path that is not pinned")
+	}
+}
+
+// TestSaveLeavesNoTemporaryFiles checks the atomic writes of bookmarks
+// and pins
+func TestSaveLeavesNoTemporaryFiles(t *testing.T) {
+	dir := t.TempDir()
+	bookmarks := &Bookmarks{Paths: map[string]string{"api": "/src/api"}}
+	pins := &Pins{Paths: []string{"/src/api"}}
+	for i := 0; i < 3; i++ {
+		if err := bookmarks.Save(filepath.Join(dir, BookmarksFile)); err != nil {
+			t.Fatalf("failed to save bookmarks: %v", err)
+		}
+		if err := pins.Save(filepath.Join(dir, PinsFile)); err != nil {
+			t.Fatalf("failed to save pins: %v", err)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", dir, err)
+	}
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if !slices.Equal(names, []string{BookmarksFile, PinsFile}) {
+		t.Errorf("expected only the bookmarks and pins files, got %v", names)
+	}
+
+	loaded, err := LoadBookmarks(filepath.Join(dir, BookmarksFile))
+	if err != nil || loaded.Paths["api"] != "/src/api" {
+		t.Errorf("failed to load the saved bookmarks: %+v, %v", loaded, err)
+	}
+}